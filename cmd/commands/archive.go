@@ -2,7 +2,6 @@ package commands
 
 import (
 	"fmt"
-	"path/filepath"
 
 	"github.com/spf13/cobra"
 
@@ -91,8 +90,8 @@ func runArchive(cmd *cobra.Command, args []string) error {
 		relativePath := resolver.ConvertToRelativePath(itemPath)
 		archiveErr = files.ArchiveComponent(relativePath)
 	} else {
-		// ArchivePipeline expects just the filename
-		archiveErr = files.ArchivePipeline(filepath.Base(itemPath))
+		// ArchivePipeline expects a path relative to the pipelines directory
+		archiveErr = files.ArchivePipeline(resolver.PipelineRelativePath(itemPath))
 	}
 
 	if archiveErr != nil {
@@ -16,7 +16,11 @@ import (
 )
 
 var (
-	clipboardFormat string
+	clipboardFormat        string
+	clipboardExpandRefs    bool
+	clipboardSourceMarkers bool
+	clipboardVariables     []string
+	clipboardStrictVars    bool
 )
 
 // NewClipboardCommand creates the clipboard command
@@ -42,7 +46,16 @@ Examples:
   pluqqy clipboard prompts/user-story
   
   # Handle ambiguous names by specifying type
-  pluqqy clipboard prompts/123`,
+  pluqqy clipboard prompts/123
+  
+  # Inline @file references as code blocks
+  pluqqy clipboard add-cli-command --expand-refs
+  
+  # Fill {{placeholders}} in components
+  pluqqy clipboard add-cli-command --var ticket=PLQ-42
+  
+  # Copy with XML-tagged sections
+  pluqqy clipboard add-cli-command --format xml`,
 		Args:    cobra.ExactArgs(1),
 		Aliases: []string{"clip", "copy"},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -55,7 +68,11 @@ Examples:
 		RunE: runClipboard,
 	}
 
-	cmd.Flags().StringVar(&clipboardFormat, "format", "markdown", "Output format (markdown)")
+	cmd.Flags().StringVar(&clipboardFormat, "format", "", formatFlagUsage())
+	cmd.Flags().BoolVar(&clipboardExpandRefs, "expand-refs", false, "Inline @file references as fenced code blocks")
+	cmd.Flags().BoolVar(&clipboardSourceMarkers, "source-markers", false, "Precede each component with a <!-- source: path --> comment")
+	cmd.Flags().StringArrayVar(&clipboardVariables, "var", nil, "Set a template variable (name=value, repeatable)")
+	cmd.Flags().BoolVar(&clipboardStrictVars, "strict", false, "Fail if any template variable has no value")
 
 	return cmd
 }
@@ -69,6 +86,18 @@ func runClipboard(cmd *cobra.Command, args []string) error {
 		// Use default settings if can't read
 		settings = models.DefaultSettings()
 	}
+	if clipboardExpandRefs {
+		settings.Output.FileReferences.Expand = true
+	}
+	if clipboardSourceMarkers {
+		settings.Output.SourceMarkers = true
+	}
+	if err := applyVariableFlags(settings, clipboardVariables, clipboardStrictVars); err != nil {
+		return err
+	}
+	if err := applyFormatFlag(settings, clipboardFormat); err != nil {
+		return err
+	}
 
 	var content string
 	var itemType string
@@ -103,10 +132,12 @@ func runClipboard(cmd *cobra.Command, args []string) error {
 		
 		if pipelineErr == nil {
 			// It's a pipeline
-			content, err = composer.ComposePipelineWithSettings(pipeline, settings)
+			var report *composer.BudgetReport
+			content, report, err = composer.ComposePipelineWithSettingsReport(pipeline, settings)
 			if err != nil {
 				return fmt.Errorf("failed to compose pipeline: %w", err)
 			}
+			reportBudgetCuts(report)
 			itemType = "Pipeline"
 			itemName = pipeline.Name
 		} else {
@@ -172,54 +203,25 @@ func runClipboard(cmd *cobra.Command, args []string) error {
 
 // findComponentFileForClipboard finds a component file by reference
 func findComponentFileForClipboard(ref string) (string, error) {
-	// If ref contains a slash, treat it as a path hint
+	// If ref starts with a component type, look only in that type
 	if strings.Contains(ref, "/") {
 		parts := strings.SplitN(ref, "/", 2)
-		componentType := parts[0]
-		componentName := parts[1]
-		
-		// Normalize component type
-		switch strings.ToLower(componentType) {
-		case "context", "contexts":
-			componentType = "contexts"
-		case "prompt", "prompts":
-			componentType = "prompts"
-		case "rule", "rules":
-			componentType = "rules"
-		default:
-			return "", fmt.Errorf("invalid component type: %s", componentType)
-		}
-		
-		// Add .md extension if not present
-		if !strings.HasSuffix(componentName, ".md") {
-			componentName += ".md"
-		}
-		
-		path := filepath.Join(files.PluqqyDir, "components", componentType, componentName)
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
+		if ct, ok := files.LookupComponentType(parts[0]); ok {
+			matches := files.FindNestedFiles(filepath.Join(files.PluqqyDir, "components", ct.DirName()), parts[1], ".md")
+			if len(matches) == 0 {
+				return "", fmt.Errorf("component not found: %s/%s", ct.DirName(), strings.TrimSuffix(parts[1], ".md"))
+			}
+			if len(matches) > 1 {
+				return "", fmt.Errorf("multiple components found matching '%s'. Please include the folder", ref)
+			}
+			return matches[0], nil
 		}
-		return "", fmt.Errorf("component not found: %s/%s", componentType, strings.TrimSuffix(componentName, ".md"))
 	}
 
-	// Search for the component in all directories
-	componentTypes := []string{"contexts", "prompts", "rules"}
+	// Search for the component in all directories, including nested folders
 	var foundPaths []string
-
-	for _, ct := range componentTypes {
-		// Try with .md extension
-		path := filepath.Join(files.PluqqyDir, "components", ct, ref+".md")
-		if _, err := os.Stat(path); err == nil {
-			foundPaths = append(foundPaths, path)
-		}
-		
-		// Try without extension (in case user included it)
-		if strings.HasSuffix(ref, ".md") {
-			path = filepath.Join(files.PluqqyDir, "components", ct, ref)
-			if _, err := os.Stat(path); err == nil {
-				foundPaths = append(foundPaths, path)
-			}
-		}
+	for _, ct := range files.ComponentTypeDirs() {
+		foundPaths = append(foundPaths, files.FindNestedFiles(filepath.Join(files.PluqqyDir, "components", ct), ref, ".md")...)
 	}
 
 	if len(foundPaths) == 0 {
@@ -227,7 +229,7 @@ func findComponentFileForClipboard(ref string) (string, error) {
 	}
 
 	if len(foundPaths) > 1 {
-		return "", fmt.Errorf("multiple components found with name '%s'. Please specify the type (e.g., contexts/%s)", ref, ref)
+		return "", fmt.Errorf("multiple components found with name '%s'. Please specify the type or folder (e.g., contexts/%s)", ref, ref)
 	}
 
 	return foundPaths[0], nil
@@ -28,6 +28,9 @@ Types:
   prompt   - Create a prompt component  
   rule     - Create a rule component
 
+Custom component types declared under component_types in settings are
+accepted too, by their singular or plural name.
+
 The command will open your default editor ($EDITOR) to write the content.
 
 Examples:
@@ -75,9 +78,13 @@ func runCreate(cmd *cobra.Command, args []string) error {
 
 	// Determine the relative path for the new component
 	// WriteComponent expects a relative path from .pluqqy directory
+	componentDir := componentType
+	if ct, ok := files.LookupComponentType(componentType); ok {
+		componentDir = ct.DirName()
+	}
 	componentRelativePath := filepath.Join(
 		"components", 
-		componentType,
+		componentDir,
 		componentName+".md",
 	)
 	
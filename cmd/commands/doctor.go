@@ -0,0 +1,174 @@
+package commands
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pluqqy/pluqqy-terminal/internal/cli"
+	"github.com/pluqqy/pluqqy-terminal/pkg/doctor"
+)
+
+// Exit codes of the doctor command
+const (
+	DoctorExitErrors   = 1 // Errors were found
+	DoctorExitWarnings = 2 // Only warnings were found and --strict was given
+)
+
+var (
+	doctorFix    bool
+	doctorStrict bool
+)
+
+// ExitCodeError asks main to exit with Code once the command's output has been
+// written, without printing anything else
+type ExitCodeError struct {
+	Code    int
+	Message string
+}
+
+func (e *ExitCodeError) Error() string {
+	return e.Message
+}
+
+// NewDoctorCommand creates the doctor command
+func NewDoctorCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "doctor",
+		Short: "Check the whole library for problems",
+		Long: `Check every component, pipeline and tag in the project for problems.
+
+Errors:
+  unreadable          a file, its YAML or settings.yaml can't be read
+  oversized           a component or pipeline is over the 10MB limit
+  frontmatter         malformed frontmatter, so the name and tags are ignored
+  invalid-pipeline    the pipeline fails validation
+  component-order     component orders are duplicated or not positive
+  missing-component   a pipeline includes a component that doesn't exist
+  archived-component  a pipeline includes a component that was archived
+  broken-extends      a pipeline extends a missing pipeline or forms a cycle
+  file-reference      an @file reference points at nothing
+
+Warnings:
+  oversized           an @file reference will be cut when expanded
+  unregistered-tag    a tag is used but missing from tags.yaml
+  orphaned-tag        a tag in tags.yaml isn't used anywhere
+  stale-output        a pipeline's output_path or target file is older than
+                      the pipeline or one of its components
+
+--fix applies the safe repairs: renumbering component orders in the order
+they are composed, registering used tags and removing unused tags that have
+no description. Everything else is left for you to decide.
+
+Exit codes:
+  0  no errors (warnings are allowed unless --strict is given)
+  1  errors were found
+  2  only warnings were found and --strict was given
+
+Examples:
+  pluqqy doctor
+  pluqqy doctor --fix
+  pluqqy doctor --strict -o json`,
+		Args:    cobra.NoArgs,
+		PreRunE: validateTagsProject,
+		RunE:    runDoctor,
+	}
+
+	cmd.Flags().BoolVar(&doctorFix, "fix", false, "Apply the safe repairs")
+	cmd.Flags().BoolVar(&doctorStrict, "strict", false, "Exit with code 2 when only warnings are found")
+
+	return cmd
+}
+
+func runDoctor(cmd *cobra.Command, args []string) error {
+	report, err := doctor.Run()
+	if err != nil {
+		return err
+	}
+
+	var fixErr error
+	if doctorFix {
+		fixErr = report.Fix()
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	switch outputFormat {
+	case "json", "yaml":
+		if err := cli.OutputResults(cmd.OutOrStdout(), outputFormat, report); err != nil {
+			return err
+		}
+	default:
+		printDoctorReport(cmd, report)
+	}
+
+	if fixErr != nil {
+		return fixErr
+	}
+	return doctorExitError(cmd, report)
+}
+
+// doctorExitError returns the error that makes the command exit with the code
+// CI expects, or nil when the library passes
+func doctorExitError(cmd *cobra.Command, report *doctor.Report) error {
+	code := 0
+	switch {
+	case report.Errors > 0:
+		code = DoctorExitErrors
+	case report.Warnings > 0 && doctorStrict:
+		code = DoctorExitWarnings
+	}
+	if code == 0 {
+		return nil
+	}
+
+	// The report already says what's wrong
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	return &ExitCodeError{
+		Code:    code,
+		Message: fmt.Sprintf("doctor found %s and %s", countNoun(report.Errors, "error"), countNoun(report.Warnings, "warning")),
+	}
+}
+
+// printDoctorReport prints the problems grouped by file, then a summary
+func printDoctorReport(cmd *cobra.Command, report *doctor.Report) {
+	out := cmd.OutOrStdout()
+	checked := fmt.Sprintf("%s and %s", countNoun(report.Components, "component"), countNoun(report.Pipelines, "pipeline"))
+
+	if len(report.Problems) == 0 {
+		fmt.Fprintf(out, "✓ No problems found in %s\n", checked)
+		return
+	}
+
+	lastPath := ""
+	for _, problem := range report.Problems {
+		if problem.Path != lastPath {
+			if lastPath != "" {
+				fmt.Fprintln(out)
+			}
+			fmt.Fprintln(out, problem.Path)
+			lastPath = problem.Path
+		}
+
+		symbol, note := "✗", problem.Check
+		switch {
+		case problem.Fixed:
+			symbol, note = "✓", problem.Check+", fixed"
+		case problem.Severity == doctor.SeverityWarning:
+			symbol = "⚠"
+		}
+		if problem.Fixable && !problem.Fixed {
+			note += ", fixable"
+		}
+		fmt.Fprintf(out, "  %s %s [%s]\n", symbol, problem.Message, note)
+	}
+
+	fmt.Fprintf(out, "\nChecked %s: %s, %s", checked, countNoun(report.Errors, "error"), countNoun(report.Warnings, "warning"))
+	if report.Fixed > 0 {
+		fmt.Fprintf(out, ", %d fixed", report.Fixed)
+	}
+	fmt.Fprintln(out)
+	if fixable := report.Fixable(); fixable > 0 {
+		fmt.Fprintf(out, "Run 'pluqqy doctor --fix' to repair %s\n", countNoun(fixable, "problem"))
+	}
+}
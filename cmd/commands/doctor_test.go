@@ -0,0 +1,71 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/doctor"
+)
+
+// runDoctorCommand runs doctor with the given output format and returns what it printed
+func runDoctorCommand(t *testing.T, format string, args ...string) (string, error) {
+	t.Helper()
+	doctorFix, doctorStrict = false, false
+	cmd := NewDoctorCommand()
+	cmd.Flags().StringP("output", "o", format, "")
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestDoctor(t *testing.T) {
+	setupTagsProject(t)
+	require.NoError(t, os.WriteFile(".pluqqy/pipelines/review.yaml",
+		[]byte("name: Review\ncomponents:\n  - type: prompts\n    path: ../components/prompts/schema.md\n    order: 1\ntags: [style]\n"), 0644))
+
+	// The only problems are tags: style isn't registered and unused isn't used
+	output, err := runDoctorCommand(t, "json")
+	require.NoError(t, err)
+
+	var report doctor.Report
+	require.NoError(t, json.Unmarshal([]byte(output), &report))
+	assert.Equal(t, 2, report.Warnings)
+	assert.Equal(t, 0, report.Errors)
+
+	_, err = runDoctorCommand(t, "json", "--strict")
+	var exitErr *ExitCodeError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, DoctorExitWarnings, exitErr.Code)
+
+	output, err = runDoctorCommand(t, "text", "--fix")
+	require.NoError(t, err)
+	assert.Contains(t, output, "2 fixed")
+
+	output, err = runDoctorCommand(t, "text", "--strict")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No problems found in 2 components and 1 pipeline")
+}
+
+func TestDoctor_ExitsWithErrors(t *testing.T) {
+	setupTagsProject(t)
+	require.NoError(t, os.WriteFile(".pluqqy/pipelines/broken.yaml",
+		[]byte("name: Broken\ncomponents:\n  - type: prompts\n    path: ../components/prompts/gone.md\n    order: 1\n"), 0644))
+
+	output, err := runDoctorCommand(t, "text")
+	var exitErr *ExitCodeError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, DoctorExitErrors, exitErr.Code)
+	assert.Contains(t, output, "pipelines/broken.yaml\n  ✗ component 1 (prompts/gone.md) doesn't exist [missing-component]")
+	assert.NotContains(t, output, "Usage:")
+}
@@ -12,9 +12,19 @@ import (
 )
 
 var (
-	exportToFile string
+	exportToFile        string
+	exportExpandRefs    bool
+	exportSourceMarkers bool
+	exportVariables     []string
+	exportStrictVars    bool
+	exportFormat        string
+	exportAs            string
+	exportChatShape     string
 )
 
+// exportAsChatJSON is the --as value for chat-completions payloads
+const exportAsChatJSON = "chat-json"
+
 // NewExportCommand creates the export command
 func NewExportCommand() *cobra.Command {
 	cmd := &cobra.Command{
@@ -45,7 +55,21 @@ Examples:
   pluqqy export contexts/api-docs --file context.md
   
   # Export as YAML format (pipelines only)
-  pluqqy export my-assistant -o yaml`,
+  pluqqy export my-assistant -o yaml
+  
+  # Inline @file references as code blocks
+  pluqqy export my-assistant --expand-refs
+  
+  # Fill {{placeholders}} in components
+  pluqqy export my-assistant --var ticket=PLQ-42
+  
+  # Render the composed content as plain text or JSON
+  pluqqy export my-assistant --format plain
+  pluqqy export my-assistant --format json
+  
+  # Export a chat-completions payload for a model runner
+  pluqqy export my-assistant --as chat-json
+  pluqqy export my-assistant --as chat-json --shape openai --file payload.json`,
 		Args: cobra.ExactArgs(1),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			ctx, err := cli.NewCommandContext()
@@ -58,6 +82,13 @@ Examples:
 	}
 
 	cmd.Flags().StringVarP(&exportToFile, "file", "f", "", "Export to file instead of stdout")
+	cmd.Flags().BoolVar(&exportExpandRefs, "expand-refs", false, "Inline @file references as fenced code blocks")
+	cmd.Flags().BoolVar(&exportSourceMarkers, "source-markers", false, "Precede each component with a <!-- source: path --> comment")
+	cmd.Flags().StringArrayVar(&exportVariables, "var", nil, "Set a template variable (name=value, repeatable)")
+	cmd.Flags().BoolVar(&exportStrictVars, "strict", false, "Fail if any template variable has no value")
+	cmd.Flags().StringVar(&exportFormat, "format", "", formatFlagUsage())
+	cmd.Flags().StringVar(&exportAs, "as", "", "Export as a request payload instead of a document (chat-json)")
+	cmd.Flags().StringVar(&exportChatShape, "shape", "", "Payload shape for --as chat-json (anthropic, openai; default from settings)")
 
 	return cmd
 }
@@ -71,10 +102,37 @@ func runExport(cmd *cobra.Command, args []string) error {
 		return err
 	}
 	settings := ctx.LoadSettingsWithDefault()
+	if exportExpandRefs {
+		settings.Output.FileReferences.Expand = true
+	}
+	if exportSourceMarkers {
+		settings.Output.SourceMarkers = true
+	}
+	if err := applyVariableFlags(settings, exportVariables, exportStrictVars); err != nil {
+		return err
+	}
+	if err := applyFormatFlag(settings, exportFormat); err != nil {
+		return err
+	}
 
 	// Get output format
 	outputFormat, _ := cmd.Flags().GetString("output")
 
+	if exportAs != "" {
+		if exportAs != exportAsChatJSON {
+			return fmt.Errorf("unknown export type '%s', must be %s", exportAs, exportAsChatJSON)
+		}
+		if outputFormat == "json" || outputFormat == "yaml" {
+			return fmt.Errorf("--as %s cannot be combined with -o %s", exportAs, outputFormat)
+		}
+	}
+	if exportChatShape != "" {
+		if exportAs == "" {
+			return fmt.Errorf("--shape requires --as %s", exportAsChatJSON)
+		}
+		settings.Output.Chat.Shape = exportChatShape
+	}
+
 	var output string
 	var itemType string
 	var itemName string
@@ -101,11 +159,19 @@ func runExport(cmd *cobra.Command, args []string) error {
 		exportData = pipeline
 
 		// Compose the pipeline for text output
-		if outputFormat != "json" && outputFormat != "yaml" {
-			composed, err := composer.ComposePipelineWithSettings(pipeline, settings)
+		if exportAs == exportAsChatJSON {
+			payload, report, err := composer.ComposeChatPayload(pipeline, settings)
 			if err != nil {
 				return fmt.Errorf("failed to compose pipeline: %w", err)
 			}
+			reportBudgetCuts(report)
+			output = payload
+		} else if outputFormat != "json" && outputFormat != "yaml" {
+			composed, report, err := composer.ComposePipelineWithSettingsReport(pipeline, settings)
+			if err != nil {
+				return fmt.Errorf("failed to compose pipeline: %w", err)
+			}
+			reportBudgetCuts(report)
 			output = composed
 		}
 
@@ -115,6 +181,10 @@ func runExport(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to load component: %w", err)
 		}
 
+		if exportAs != "" {
+			return fmt.Errorf("--as %s is only available for pipelines", exportAs)
+		}
+
 		itemType = "Component"
 		itemName = component.Name
 		exportData = component
@@ -0,0 +1,141 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pluqqy/pluqqy-terminal/internal/cli"
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/lint"
+)
+
+// LintExitFindings is the exit code of lint when it finds anything
+const LintExitFindings = 1
+
+var (
+	lintDisable []string
+)
+
+// LintResult represents the output structure for the lint command
+type LintResult struct {
+	Pipeline   string         `json:"pipeline" yaml:"pipeline"`
+	Components int            `json:"components" yaml:"components"`
+	Count      int            `json:"count" yaml:"count"`
+	Findings   []lint.Finding `json:"findings" yaml:"findings"`
+}
+
+// NewLintCommand creates the lint command
+func NewLintCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "lint <pipeline>",
+		Short: "Check a pipeline's component text for prompt problems",
+		Long: `Check the text of every component a pipeline includes for:
+
+  contradiction  an instruction in one component says to do what another
+                 says not to, e.g. "always use tabs" and "never use tabs"
+  duplicate      the same instruction is given more than once
+  vague          a phrase from the vague_phrases deny-list, e.g. "as needed"
+  long-section   a section is over max_section_tokens
+  code-fence     a code fence is never closed
+
+Rules are configured in the lint section of .pluqqy/settings.yaml:
+
+  lint:
+    disabled: [long-section]
+    vague_phrases: [as needed, if possible, etc]
+    max_section_tokens: 1500
+
+The same findings are shown at the top of the pipeline builder's preview.
+lint exits with code 1 when it finds anything.
+
+Examples:
+  pluqqy lint code-review
+  pluqqy lint code-review --disable duplicate
+  pluqqy lint code-review -o json`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: validateTagsProject,
+		RunE:    runLint,
+	}
+
+	cmd.Flags().StringSliceVar(&lintDisable, "disable", nil, "Rules to skip, in addition to those disabled in settings")
+
+	return cmd
+}
+
+func runLint(cmd *cobra.Command, args []string) error {
+	for _, rule := range lintDisable {
+		if !cli.Contains(lint.Rules, rule) {
+			return fmt.Errorf("unknown rule '%s': use %s", rule, strings.Join(lint.Rules, ", "))
+		}
+	}
+
+	ctx, err := cli.NewCommandContext()
+	if err != nil {
+		return err
+	}
+	settings := ctx.LoadSettingsWithDefault()
+	settings.Lint.Disabled = append(settings.Lint.Disabled, lintDisable...)
+
+	pipelinePath, err := cli.NewItemResolver(ctx.ProjectPath).FindPipeline(args[0])
+	if err != nil {
+		return err
+	}
+	pipeline, err := files.LoadPipeline(pipelinePath)
+	if err != nil {
+		return fmt.Errorf("failed to load pipeline: %w", err)
+	}
+
+	findings, linted, err := lint.LintPipeline(pipeline, settings.Lint)
+	if err != nil {
+		return fmt.Errorf("failed to lint pipeline '%s': %w", pipeline.Name, err)
+	}
+
+	result := LintResult{
+		Pipeline:   pipeline.Name,
+		Components: linted,
+		Count:      len(findings),
+		Findings:   findings,
+	}
+	if result.Findings == nil {
+		result.Findings = []lint.Finding{}
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	switch outputFormat {
+	case "json", "yaml":
+		if err := cli.OutputResults(cmd.OutOrStdout(), outputFormat, result); err != nil {
+			return err
+		}
+	default:
+		printLintResult(cmd, result)
+	}
+
+	if len(findings) == 0 {
+		return nil
+	}
+	// The findings already say what's wrong
+	cmd.SilenceUsage = true
+	cmd.SilenceErrors = true
+	return &ExitCodeError{
+		Code:    LintExitFindings,
+		Message: fmt.Sprintf("lint found %s in '%s'", countNoun(len(findings), "problem"), pipeline.Name),
+	}
+}
+
+// printLintResult prints one line per finding, then a summary
+func printLintResult(cmd *cobra.Command, result LintResult) {
+	out := cmd.OutOrStdout()
+	checked := countNoun(result.Components, "component")
+
+	if result.Count == 0 {
+		fmt.Fprintf(out, "✓ No problems found in %s of '%s'\n", checked, result.Pipeline)
+		return
+	}
+
+	for _, finding := range result.Findings {
+		fmt.Fprintf(out, "⚠ %s [%s]\n", finding, finding.Rule)
+	}
+	fmt.Fprintf(out, "\n%s in %s of '%s'\n", countNoun(result.Count, "problem"), checked, result.Pipeline)
+}
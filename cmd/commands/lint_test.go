@@ -0,0 +1,62 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/lint"
+)
+
+// runLintCommand runs lint with the given output format and returns what it printed
+func runLintCommand(t *testing.T, format string, args ...string) (string, error) {
+	t.Helper()
+	lintDisable = nil
+	cmd := NewLintCommand()
+	cmd.Flags().StringP("output", "o", format, "")
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestLint(t *testing.T) {
+	setupTagsProject(t)
+	require.NoError(t, os.WriteFile(".pluqqy/components/rules/tabs.md",
+		[]byte("Never describe the GraphQL schema.\nUse tabs if possible."), 0644))
+	require.NoError(t, os.WriteFile(".pluqqy/pipelines/review.yaml",
+		[]byte("name: Review\ncomponents:\n  - type: prompts\n    path: ../components/prompts/schema.md\n    order: 1\n  - type: rules\n    path: ../components/rules/tabs.md\n    order: 2\n"), 0644))
+
+	output, err := runLintCommand(t, "json", "review")
+	var exitErr *ExitCodeError
+	require.True(t, errors.As(err, &exitErr))
+	assert.Equal(t, LintExitFindings, exitErr.Code)
+
+	var result LintResult
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "Review", result.Pipeline)
+	assert.Equal(t, 2, result.Components)
+	require.Len(t, result.Findings, 2)
+	assert.Equal(t, lint.RuleContradiction, result.Findings[0].Rule)
+	assert.Equal(t, "rules/tabs.md", result.Findings[0].Component)
+	assert.Equal(t, lint.RuleVague, result.Findings[1].Rule)
+
+	output, err = runLintCommand(t, "text", "review", "--disable", "vague", "--disable", "contradiction")
+	require.NoError(t, err)
+	assert.Contains(t, output, "No problems found in 2 components of 'Review'")
+
+	_, err = runLintCommand(t, "text", "review", "--disable", "spelling")
+	assert.ErrorContains(t, err, "unknown rule 'spelling'")
+
+	_, err = runLintCommand(t, "text", "missing")
+	assert.ErrorContains(t, err, "not found")
+}
@@ -11,6 +11,7 @@ import (
 	"github.com/pluqqy/pluqqy-terminal/internal/cli"
 	"github.com/pluqqy/pluqqy-terminal/pkg/files"
 	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
 )
 
 // ListResult represents the output structure for list command
@@ -34,6 +35,14 @@ type ListItem struct {
 var (
 	listShowArchived bool
 	listShowPaths    bool
+	listCollection   string
+
+	// listCollectionPaths holds the files in the --collection being listed,
+	// relative to .pluqqy; nil lists every file
+	listCollectionPaths map[string]bool
+	// listCollectionArchived is set when the collection's query asks for
+	// archived items, so they are listed alongside active ones
+	listCollectionArchived bool
 )
 
 // NewListCommand creates the list command
@@ -68,7 +77,11 @@ Examples:
   pluqqy list --archived
   
   # Show file paths
-  pluqqy list --paths`,
+  pluqqy list --paths
+  
+  # List the items matching a saved search
+  pluqqy list --collection api-contexts
+  pluqqy list contexts --collection api-contexts`,
 		Args:      cobra.MaximumNArgs(1),
 		ValidArgs: []string{"pipelines", "components", "contexts", "prompts", "rules", "all"},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
@@ -83,6 +96,7 @@ Examples:
 
 	cmd.Flags().BoolVarP(&listShowArchived, "archived", "a", false, "Show only archived items")
 	cmd.Flags().BoolVar(&listShowPaths, "paths", false, "Show file paths")
+	cmd.Flags().StringVar(&listCollection, "collection", "", "List only the items matching a saved search")
 
 	return cmd
 }
@@ -99,6 +113,14 @@ func runList(cmd *cobra.Command, args []string) error {
 	var result ListResult
 	result.Type = listType
 
+	listCollectionPaths, listCollectionArchived = nil, false
+	if listCollection != "" {
+		var err error
+		if listCollectionPaths, listCollectionArchived, err = collectionPaths(listCollection); err != nil {
+			return err
+		}
+	}
+
 	// List pipelines if requested
 	if listType == "all" || listType == "pipelines" {
 		pipelines, err := listPipelines()
@@ -114,13 +136,14 @@ func runList(cmd *cobra.Command, args []string) error {
 	}
 
 	// List components if requested
-	if listType == "all" || listType == "components" || listType == "contexts" || listType == "prompts" || listType == "rules" {
+	componentType, isComponentType := files.LookupComponentType(listType)
+	if listType == "all" || listType == "components" || isComponentType {
 		var components []ListItem
 		var err error
 		
 		// If a specific component type was requested, filter to just that type
-		if listType == "contexts" || listType == "prompts" || listType == "rules" {
-			components, err = listSpecificComponentType(listType)
+		if isComponentType {
+			components, err = listSpecificComponentType(componentType)
 		} else {
 			components, err = listComponents()
 		}
@@ -150,55 +173,59 @@ func runList(cmd *cobra.Command, args []string) error {
 func listPipelines() ([]ListItem, error) {
 	var items []ListItem
 
-	if listShowArchived {
-		// Show ONLY archived pipelines when --archived flag is used
-		archiveDir := filepath.Join(files.PluqqyDir, "archive", "pipelines")
-		archivedPipelines, err := listPipelinesFromDir(archiveDir, true)
-		if err != nil && !os.IsNotExist(err) {
-			return nil, err
-		}
-		items = append(items, archivedPipelines...)
-	} else {
+	if !listShowArchived {
 		// Show only regular (non-archived) pipelines by default
-		pipelineDir := filepath.Join(files.PluqqyDir, "pipelines")
-		regularPipelines, err := listPipelinesFromDir(pipelineDir, false)
+		regularPipelines, err := listPipelineFiles(false)
 		if err != nil {
 			return nil, err
 		}
 		items = append(items, regularPipelines...)
 	}
+	if listShowArchived || listCollectionArchived {
+		// Show archived pipelines with --archived, or when the collection asks for them
+		archivedPipelines, err := listPipelineFiles(true)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, archivedPipelines...)
+	}
 
 	return items, nil
 }
 
-func listPipelinesFromDir(dir string, isArchived bool) ([]ListItem, error) {
+// listPipelineFiles lists the active or archived pipelines, including those in
+// nested folders (e.g. pipelines/release/hotfix.yaml)
+func listPipelineFiles(isArchived bool) ([]ListItem, error) {
 	var items []ListItem
 
-	entries, err := os.ReadDir(dir)
+	var paths []string
+	var err error
+	prefix := files.PipelinesDir + "/"
+	if isArchived {
+		paths, err = files.ListArchivedPipelines()
+		prefix = files.ArchiveDir + "/" + prefix
+	} else {
+		paths, err = files.ListPipelines()
+	}
 	if err != nil {
-		if os.IsNotExist(err) {
-			return items, nil
-		}
 		return nil, err
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".yaml") {
+	for _, path := range paths {
+		// The filename keeps its folder, e.g. release/hotfix
+		name := strings.TrimSuffix(strings.TrimPrefix(path, prefix), ".yaml")
+		pipelinePath := filepath.Join(files.PluqqyDir, filepath.FromSlash(path))
+		if !inListCollection(pipelinePath) {
 			continue
 		}
-
-		name := strings.TrimSuffix(entry.Name(), ".yaml")
-		pipelinePath := filepath.Join(dir, entry.Name())
 		
 		var pipeline *models.Pipeline
 		var err error
 		
 		if isArchived {
-			// For archived pipelines, use ReadArchivedPipeline with just the filename
-			pipeline, err = files.ReadArchivedPipeline(entry.Name())
+			pipeline, err = files.ReadArchivedPipeline(path)
 		} else {
-			// For regular pipelines, use LoadPipeline
-			pipeline, err = files.LoadPipeline(pipelinePath)
+			pipeline, err = files.ReadPipeline(path)
 		}
 		
 		if err != nil {
@@ -208,7 +235,7 @@ func listPipelinesFromDir(dir string, isArchived bool) ([]ListItem, error) {
 
 		item := ListItem{
 			Name:       pipeline.Name,
-			Filename:   name,  // The filename without .yaml extension
+			Filename:   name,  // The path below pipelines/ without .yaml extension
 			Type:       "pipeline",
 			Tags:       pipeline.Tags,
 			Components: len(pipeline.Components),
@@ -225,30 +252,25 @@ func listPipelinesFromDir(dir string, isArchived bool) ([]ListItem, error) {
 	return items, nil
 }
 
-func listSpecificComponentType(componentType string) ([]ListItem, error) {
+func listSpecificComponentType(componentType models.ComponentTypeConfig) ([]ListItem, error) {
 	var items []ListItem
 	
-	// componentType comes in as singular (e.g., "contexts", "prompts", "rules")
-	// Get singular form for display
-	componentTypeSingular := strings.TrimSuffix(componentType, "s")
-	
-	if listShowArchived {
-		// Show ONLY archived components when --archived flag is used
-		archiveDir := filepath.Join(files.PluqqyDir, "archive", "components", componentType)
-		archivedComponents, err := listComponentsFromDir(archiveDir, componentTypeSingular, true)
-		if err != nil && !os.IsNotExist(err) {
-			return nil, err
-		}
-		items = append(items, archivedComponents...)
-	} else {
+	if !listShowArchived {
 		// Show only regular (non-archived) components by default
-		componentDir := filepath.Join(files.PluqqyDir, "components", componentType)
-		regularComponents, err := listComponentsFromDir(componentDir, componentTypeSingular, false)
+		regularComponents, err := listComponentFiles(componentType, false)
 		if err != nil {
 			return nil, err
 		}
 		items = append(items, regularComponents...)
 	}
+	if listShowArchived || listCollectionArchived {
+		// Show archived components with --archived, or when the collection asks for them
+		archivedComponents, err := listComponentFiles(componentType, true)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, archivedComponents...)
+	}
 	
 	return items, nil
 }
@@ -256,57 +278,46 @@ func listSpecificComponentType(componentType string) ([]ListItem, error) {
 func listComponents() ([]ListItem, error) {
 	var items []ListItem
 
-	// List all component types
-	componentTypes := []string{
-		"contexts",
-		"prompts",
-		"rules",
-	}
-
-	for _, ct := range componentTypes {
-		// Get singular form for display
-		componentTypeSingular := strings.TrimSuffix(ct, "s")
-		
-		if listShowArchived {
-			// Show ONLY archived components when --archived flag is used
-			archiveDir := filepath.Join(files.PluqqyDir, "archive", "components", ct)
-			archivedComponents, err := listComponentsFromDir(archiveDir, componentTypeSingular, true)
-			if err != nil && !os.IsNotExist(err) {
-				return nil, err
-			}
-			items = append(items, archivedComponents...)
-		} else {
-			// Show only regular (non-archived) components by default
-			componentDir := filepath.Join(files.PluqqyDir, "components", ct)
-			regularComponents, err := listComponentsFromDir(componentDir, componentTypeSingular, false)
-			if err != nil {
-				return nil, err
-			}
-			items = append(items, regularComponents...)
+	// List all component types, including custom types
+	for _, componentType := range files.ComponentTypes() {
+		typeItems, err := listSpecificComponentType(componentType)
+		if err != nil {
+			return nil, err
 		}
+		items = append(items, typeItems...)
 	}
 
 	return items, nil
 }
 
-func listComponentsFromDir(dir string, componentType string, isArchived bool) ([]ListItem, error) {
+// listComponentFiles lists the active or archived components of a type,
+// including those in nested folders (e.g. contexts/backend/db.md)
+func listComponentFiles(componentType models.ComponentTypeConfig, isArchived bool) ([]ListItem, error) {
 	var items []ListItem
 
-	entries, err := os.ReadDir(dir)
+	// Get singular form for display
+	componentTypeSingular := strings.TrimSuffix(componentType.Name, "s")
+
+	var paths []string
+	var err error
+	dir := filepath.Join(files.PluqqyDir, files.ComponentsDir, componentType.DirName())
+	if isArchived {
+		paths, err = files.ListArchivedComponents(componentType.Name)
+		dir = filepath.Join(files.PluqqyDir, files.ArchiveDir, files.ComponentsDir, componentType.DirName())
+	} else {
+		paths, err = files.ListComponents(componentType.Name)
+	}
 	if err != nil {
-		if os.IsNotExist(err) {
-			return items, nil
-		}
 		return nil, err
 	}
 
-	for _, entry := range entries {
-		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".md") {
+	for _, path := range paths {
+		// The filename keeps its folder, e.g. backend/db
+		name := strings.TrimSuffix(path, ".md")
+		componentPath := filepath.Join(dir, filepath.FromSlash(path))
+		if !inListCollection(componentPath) {
 			continue
 		}
-
-		name := strings.TrimSuffix(entry.Name(), ".md")
-		componentPath := filepath.Join(dir, entry.Name())
 		
 		component, err := files.LoadComponent(componentPath)
 		if err != nil {
@@ -316,8 +327,8 @@ func listComponentsFromDir(dir string, componentType string, isArchived bool) ([
 
 		item := ListItem{
 			Name:       component.Name,
-			Filename:   name,  // The filename without .md extension
-			Type:       componentType,
+			Filename:   name,  // The path below the type's folder without .md extension
+			Type:       componentTypeSingular,
 			Tags:       component.Tags,
 			IsArchived: isArchived,
 		}
@@ -332,6 +343,74 @@ func listComponentsFromDir(dir string, componentType string, isArchived bool) ([
 	return items, nil
 }
 
+// collectionPaths runs a saved search and returns the files it matches,
+// relative to .pluqqy, and whether its query asks for archived items
+func collectionPaths(name string) (map[string]bool, bool, error) {
+	searches, err := unified.LoadSavedSearches()
+	if err != nil {
+		return nil, false, err
+	}
+	search, ok := unified.FindSavedSearch(searches, name)
+	if !ok {
+		return nil, false, fmt.Errorf("saved search '%s' not found; see 'pluqqy searches list'", name)
+	}
+
+	query, err := unified.ExpandSavedSearches("@" + search.Name)
+	if err != nil {
+		return nil, false, err
+	}
+	if err := validateQuery(query); err != nil {
+		return nil, false, err
+	}
+	wantsArchived := unified.ShouldIncludeArchived(query)
+	includeArchived := listShowArchived || wantsArchived
+
+	index := openSearchIndex()
+	prompts, contexts, rules, err := loadComponents(index, includeArchived)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load components: %w", err)
+	}
+	pipelines, err := loadPipelines(index, includeArchived)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to load pipelines: %w", err)
+	}
+
+	searchHelper := unified.NewSearchHelper()
+	searchHelper.SetSearchOptions(includeArchived, 0, "relevance")
+	searchHelper.GetUnifiedManager().SetIndex(index)
+	filteredPrompts, filteredContexts, filteredRules, filteredPipelines, err := searchHelper.UnifiedFilterAll(query, prompts, contexts, rules, pipelines)
+	if err != nil {
+		return nil, false, fmt.Errorf("search failed: %w", err)
+	}
+
+	paths := make(map[string]bool)
+	for _, c := range unified.CombineComponentsByType(filteredPrompts, filteredContexts, filteredRules) {
+		paths[collectionPath(c.Path, c.IsArchived)] = true
+	}
+	for _, p := range filteredPipelines {
+		// Pipeline paths already include archive/ when archived
+		paths[filepath.Clean(p.Path)] = true
+	}
+	return paths, wantsArchived, nil
+}
+
+// collectionPath is the path of a component's file relative to .pluqqy
+func collectionPath(path string, archived bool) string {
+	if archived {
+		return filepath.Join(files.ArchiveDir, path)
+	}
+	return filepath.Clean(path)
+}
+
+// inListCollection reports whether a file under .pluqqy belongs in the listing
+func inListCollection(path string) bool {
+	if listCollectionPaths == nil {
+		return true
+	}
+	rel, err := filepath.Rel(files.PluqqyDir, path)
+	return err == nil && listCollectionPaths[rel]
+}
+
 func outputListText(cmd *cobra.Command, result ListResult) error {
 	if result.Count == 0 {
 		cli.PrintInfo("No items found")
@@ -0,0 +1,59 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runListCommand runs list with JSON output and returns the listed items
+func runListCommand(t *testing.T, args ...string) ListResult {
+	t.Helper()
+	cmd := NewListCommand()
+	cmd.Flags().StringP("output", "o", "json", "")
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs(args)
+	require.NoError(t, cmd.Execute())
+
+	var result ListResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	return result
+}
+
+func TestList_NestedFolders(t *testing.T) {
+	setupTagsProject(t)
+	require.NoError(t, os.MkdirAll(".pluqqy/components/contexts/api", 0755))
+	require.NoError(t, os.WriteFile(".pluqqy/components/contexts/api/auth.md",
+		[]byte("---\ntags: [api]\n---\nToken auth"), 0644))
+	require.NoError(t, os.MkdirAll(".pluqqy/pipelines/team", 0755))
+	require.NoError(t, os.WriteFile(".pluqqy/pipelines/team/sub.yaml",
+		[]byte("name: Sub\ncomponents: []\n"), 0644))
+	require.NoError(t, os.MkdirAll(".pluqqy/archive/pipelines/old", 0755))
+	require.NoError(t, os.WriteFile(".pluqqy/archive/pipelines/old/legacy.yaml",
+		[]byte("name: Legacy\ncomponents: []\n"), 0644))
+
+	// The flags are bound to package variables; don't leak them into other tests
+	t.Cleanup(func() { listShowArchived, listShowPaths = false, false })
+
+	var names []string
+	for _, item := range runListCommand(t).Items {
+		names = append(names, item.Filename)
+	}
+	assert.ElementsMatch(t, []string{"review", "team/sub", "api/auth", "schema", "style"}, names)
+
+	contexts := runListCommand(t, "contexts", "--paths").Items
+	require.Len(t, contexts, 1)
+	assert.Equal(t, "api/auth", contexts[0].Filename)
+	assert.Equal(t, ".pluqqy/components/contexts/api/auth.md", contexts[0].Path)
+
+	archived := runListCommand(t, "pipelines", "--archived").Items
+	require.Len(t, archived, 1)
+	assert.Equal(t, "Legacy", archived[0].Name)
+	assert.Equal(t, "old/legacy", archived[0].Filename)
+	assert.True(t, archived[0].IsArchived)
+}
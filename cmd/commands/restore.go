@@ -52,12 +52,11 @@ func runRestore(cmd *cobra.Command, args []string) error {
 	var archivedPath string
 	var itemType string
 
-	// Check component archives
-	componentTypes := []string{"contexts", "prompts", "rules"}
-	for _, ct := range componentTypes {
-		path := filepath.Join(files.PluqqyDir, "archive", "components", ct, itemRef+".md")
-		if _, err := os.Stat(path); err == nil {
-			archivedPath = path
+	// Check component archives, including nested folders
+	for _, ct := range files.ComponentTypeDirs() {
+		matches := files.FindNestedFiles(filepath.Join(files.PluqqyDir, "archive", "components", ct), itemRef, ".md")
+		if len(matches) > 0 {
+			archivedPath = matches[0]
 			itemType = "component"
 			break
 		}
@@ -65,9 +64,9 @@ func runRestore(cmd *cobra.Command, args []string) error {
 
 	// Check pipeline archive if not found as component
 	if archivedPath == "" {
-		path := filepath.Join(files.PluqqyDir, "archive", "pipelines", itemRef+".yaml")
-		if _, err := os.Stat(path); err == nil {
-			archivedPath = path
+		matches := files.FindNestedFiles(filepath.Join(files.PluqqyDir, "archive", "pipelines"), itemRef, ".yaml")
+		if len(matches) > 0 {
+			archivedPath = matches[0]
 			itemType = "pipeline"
 		}
 	}
@@ -77,21 +76,14 @@ func runRestore(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("archived item '%s' not found\n\nUse 'pluqqy list --archived' to see available archived items", itemRef)
 	}
 
-	// Determine target path
-	var targetPath string
-	if itemType == "component" {
-		// Extract component type from path
-		parts := strings.Split(archivedPath, string(os.PathSeparator))
-		for i, part := range parts {
-			if part == "components" && i+1 < len(parts) {
-				componentType := parts[i+1]
-				targetPath = filepath.Join(files.PluqqyDir, "components", componentType, filepath.Base(archivedPath))
-				break
-			}
-		}
-	} else {
-		targetPath = filepath.Join(files.PluqqyDir, "pipelines", filepath.Base(archivedPath))
+	// The path below the archive directory mirrors the active location,
+	// e.g. components/contexts/backend/db.md or pipelines/release/hotfix.yaml
+	relativePath, err := filepath.Rel(filepath.Join(files.PluqqyDir, "archive"), archivedPath)
+	if err != nil {
+		return fmt.Errorf("could not determine location of archived item: %s", archivedPath)
 	}
+	relativePath = filepath.ToSlash(relativePath)
+	targetPath := filepath.Join(files.PluqqyDir, filepath.FromSlash(relativePath))
 
 	// Check if target already exists
 	if _, err := os.Stat(targetPath); err == nil {
@@ -112,26 +104,12 @@ func runRestore(cmd *cobra.Command, args []string) error {
 	}
 
 	// Perform restore
-	var err error
 	if itemType == "component" {
 		// UnarchiveComponent expects a relative path like "components/contexts/item.md"
-		// Extract the relative path from the archived path
-		parts := strings.Split(archivedPath, string(os.PathSeparator))
-		var relativePath string
-		for i, part := range parts {
-			if part == "components" && i+2 < len(parts) {
-				// Join components/type/filename
-				relativePath = filepath.Join("components", parts[i+1], parts[i+2])
-				break
-			}
-		}
-		if relativePath == "" {
-			return fmt.Errorf("could not determine component type from path: %s", archivedPath)
-		}
 		err = files.UnarchiveComponent(relativePath)
 	} else {
-		// UnarchivePipeline expects just the filename
-		err = files.UnarchivePipeline(filepath.Base(archivedPath))
+		// UnarchivePipeline expects a path relative to the pipelines directory
+		err = files.UnarchivePipeline(strings.TrimPrefix(relativePath, "pipelines/"))
 	}
 
 	if err != nil {
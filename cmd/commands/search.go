@@ -1,17 +1,20 @@
 package commands
 
 import (
+	"errors"
 	"fmt"
+	"os"
 	"path/filepath"
 	"strings"
-	"time"
 
 	"github.com/spf13/cobra"
 
 	"github.com/pluqqy/pluqqy-terminal/internal/cli"
+	"github.com/pluqqy/pluqqy-terminal/pkg/composer"
 	"github.com/pluqqy/pluqqy-terminal/pkg/files"
 	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
+	"github.com/pluqqy/pluqqy-terminal/pkg/utils"
 )
 
 // SearchResultOutput represents the formatted search results
@@ -29,6 +32,13 @@ type SearchItemOutput struct {
 	Path     string   `json:"path,omitempty" yaml:"path,omitempty"`
 	Archived bool     `json:"archived,omitempty" yaml:"archived,omitempty"`
 	Excerpt  string   `json:"excerpt,omitempty" yaml:"excerpt,omitempty"`
+	Matches  []SearchMatchOutput `json:"matches,omitempty" yaml:"matches,omitempty"`
+}
+
+// SearchMatchOutput is a line of a component's file matching the query
+type SearchMatchOutput struct {
+	Line int    `json:"line" yaml:"line"`
+	Text string `json:"text" yaml:"text"`
 }
 
 func NewSearchCommand() *cobra.Command {
@@ -51,51 +61,116 @@ Examples:
   # Search archived items
   pluqqy search "status:archived"
   
-  # Complex searches
-  pluqqy search "tag:api AND type:context"`,
+  # Search by date: modified, created or archived
+  pluqqy search "modified:>90d sort:-modified"
+  pluqqy search "created:2026-01..2026-03"
+  pluqqy search "archived:today"
+  
+  # Find unused or oversized components and pipelines
+  pluqqy search "usage:0 type:context"
+  pluqqy search "tokens:>2000"
+  pluqqy search "components:>10"
+  
+  # Find pipelines using a component, or with missing references
+  pluqqy search "uses:contexts/api-docs"
+  pluqqy search "broken:true"
+  
+  # Combine terms with AND, OR, NOT (or -) and parentheses
+  pluqqy search "tag:api AND type:context"
+  pluqqy search "(tag:api OR tag:graphql) -status:archived"
+  pluqqy search '"error handling" NOT tag:deprecated'
+  
+  # Regular expressions, printed grep-style with 2 lines of context
+  pluqqy search "content:/TODO|FIXME/" --context 2
+  pluqqy search "name:/^api-/"
+  pluqqy search 'content:/retry \d+ times/i'
+  
+  # Run a saved search, on its own or narrowed further
+  pluqqy search @api-contexts
+  pluqqy search "@api-contexts -tag:draft"
+
+Filters and phrases next to each other must all match, and AND binds tighter
+than OR. Plain words typed together match any of them, ranked by relevance.
+
+Dates accept YYYY-MM-DD, YYYY-MM, today, yesterday, ages such as 7d, 2w, 3m
+and 1y, comparisons (>, <, >=, <=) and ranges (A..B). On ages > means older:
+modified:>90d was last changed more than 90 days ago. sort:name, sort:modified,
+sort:created or sort:usage orders the results; a leading - reverses it.
+
+Counts for tokens:, usage: and components: take a number, a comparison
+(>2000, >=3, <10) or a range (100..500); 2k is shorthand for 2000.
+
+content: and name: take a Go regular expression written as /pattern/flags,
+where the flags i, m, s and U ignore case, make ^ and $ match at lines, let .
+match newlines and make repeats lazy. Quoted phrases match whole words.
+
+When the query matches component content, each matching line is printed as
+path:line: text, as grep does; --context shows lines around it. Items that
+matched on something else, such as a tag, are printed as their path.
+
+@name stands for a saved search's query; see pluqqy searches.`,
 		Args: cobra.MinimumNArgs(1),
 		RunE: runSearch,
 	}
 
 	// Add output format flag
 	cmd.Flags().StringP("output", "o", "text", "Output format (text, json, yaml)")
+	cmd.Flags().IntP("context", "C", 0, "Lines of context to show around content matches")
 
 	return cmd
 }
 
 func runSearch(cmd *cobra.Command, args []string) error {
-	query := strings.Join(args, " ")
+	input := strings.Join(args, " ")
+	query, err := unified.ExpandSavedSearches(input)
+	if err != nil {
+		return err
+	}
+	if err := validateQuery(query); err != nil {
+		return err
+	}
 	
-	// Use unified search engine directly
+	// Use unified search engine directly, backed by the project's search index
 	searchHelper := unified.NewSearchHelper()
 	includeArchived := unified.ShouldIncludeArchived(query)
 	searchHelper.SetSearchOptions(includeArchived, 1000, "relevance")
+	index := openSearchIndex()
+	searchHelper.GetUnifiedManager().SetIndex(index)
 	
 	// Load all items
-	prompts, contexts, rules, err := loadComponents(includeArchived)
+	prompts, contexts, rules, err := loadComponents(index, includeArchived)
 	if err != nil {
 		return fmt.Errorf("failed to load components: %w", err)
 	}
 	
-	pipelines, err := loadPipelines(includeArchived)
+	pipelines, err := loadPipelines(index, includeArchived)
 	if err != nil {
 		return fmt.Errorf("failed to load pipelines: %w", err)
 	}
 	
-	// Perform unified search
-	filteredPrompts, filteredContexts, filteredRules, filteredPipelines, err := searchHelper.UnifiedFilterAll(
-		query, prompts, contexts, rules, pipelines,
-	)
+	// Perform unified search, keeping the results' matched lines
+	manager := searchHelper.GetUnifiedManager()
+	manager.LoadComponentItems(prompts, contexts, rules)
+	manager.LoadPipelineItems(pipelines)
+	componentResults, pipelineResults, err := manager.SearchAll(query)
 	if err != nil {
 		return fmt.Errorf("search failed: %w", err)
 	}
+	filteredPrompts, filteredContexts, filteredRules := unified.FilterSearchResultsByType(componentResults)
+	filteredPipelines := unified.ConvertPipelineResults(pipelineResults)
+	
+	// Matched content lines, by component path
+	excerpts := make(map[string][]unified.Excerpt)
+	for _, result := range componentResults {
+		excerpts[result.Item.GetPath()] = result.Relevance.Excerpts
+	}
 	
 	// Get output format
 	outputFormat, _ := cmd.Flags().GetString("output")
 	
 	// Format results for output
 	searchResult := SearchResultOutput{
-		Query:   query,
+		Query:   input,
 		Count:   len(filteredPrompts) + len(filteredContexts) + len(filteredRules) + len(filteredPipelines),
 		Results: []SearchItemOutput{},
 	}
@@ -113,24 +188,68 @@ func runSearch(cmd *cobra.Command, args []string) error {
 	}
 	
 	// Add component results
-	addComponentResults(&searchResult, filteredPrompts, "prompt")
-	addComponentResults(&searchResult, filteredContexts, "context")
-	addComponentResults(&searchResult, filteredRules, "rule")
+	addComponentResults(&searchResult, filteredPrompts, excerpts)
+	addComponentResults(&searchResult, filteredContexts, excerpts)
+	addComponentResults(&searchResult, filteredRules, excerpts)
 	
 	// Output results
 	switch outputFormat {
 	case "json", "yaml":
 		return cli.OutputResults(cmd.OutOrStdout(), outputFormat, searchResult)
 	default:
+		for _, item := range searchResult.Results {
+			if len(item.Matches) > 0 {
+				contextLines, _ := cmd.Flags().GetInt("context")
+				return outputSearchMatches(cmd, searchResult, contextLines)
+			}
+		}
 		return outputSearchText(cmd, searchResult)
 	}
 }
 
-func loadComponents(includeArchived bool) ([]unified.ComponentItem, []unified.ComponentItem, []unified.ComponentItem, error) {
+// validateQuery checks the syntax of a search query. Malformed queries get an
+// error that shows the query with the offending token marked.
+func validateQuery(query string) error {
+	_, err := unified.ParseQueryExpression(query)
+	if err == nil {
+		return nil
+	}
+	
+	var syntaxErr *unified.QuerySyntaxError
+	if errors.As(err, &syntaxErr) {
+		pointer := "  " + strings.ReplaceAll(syntaxErr.Pointer(), "\n", "\n  ")
+		return fmt.Errorf("invalid query: %s\n\n%s", syntaxErr.Error(), pointer)
+	}
+	return fmt.Errorf("invalid query: %w", err)
+}
+
+// openSearchIndex opens the project's search index for a command's search. A
+// search without one reads every file instead, so failing to open it isn't fatal.
+func openSearchIndex() *unified.SearchIndex {
+	index, err := unified.OpenSearchIndex()
+	if err != nil {
+		return nil
+	}
+	return index
+}
+
+// loadComponents loads the project's components for searching, taking their tags,
+// token and usage counts from index when it isn't nil
+func loadComponents(index *unified.SearchIndex, includeArchived bool) ([]unified.ComponentItem, []unified.ComponentItem, []unified.ComponentItem, error) {
 	var prompts, contexts, rules []unified.ComponentItem
 	
-	// Load each component type
-	for _, compType := range []string{models.ComponentTypePrompt, models.ComponentTypeContext, models.ComponentTypeRules} {
+	// Usage counts are keyed by the path pipelines use, e.g. ../components/prompts/x.md
+	usageCount := index.ComponentUsage
+	if index == nil {
+		usageMap, _ := files.CountComponentUsage()
+		usageCount = func(compPath string) int {
+			return usageMap["../"+compPath]
+		}
+	}
+	
+	// Load each component type; custom types are carried with the contexts
+	for _, ct := range files.ComponentTypes() {
+		compType := ct.Name
 		// Load active components
 		componentFiles, err := files.ListComponents(compType)
 		if err != nil {
@@ -138,29 +257,21 @@ func loadComponents(includeArchived bool) ([]unified.ComponentItem, []unified.Co
 		}
 		
 		for _, compFile := range componentFiles {
-			compPath := filepath.Join(files.ComponentsDir, compType, compFile)
-			comp, err := files.ReadComponent(compPath)
-			if err != nil {
+			compPath := filepath.Join(files.ComponentsDir, ct.DirName(), compFile)
+			item, ok := loadComponentItem(index, compPath, compType, false)
+			if !ok {
 				continue
 			}
-			
-			item := unified.ComponentItem{
-				Name:         strings.TrimSuffix(compFile, ".md"),
-				Path:         compPath,
-				CompType:     compType,
-				LastModified: comp.Modified,
-				Tags:         comp.Tags,
-				TokenCount:   len(comp.Content) / 4, // Rough estimate
-				IsArchived:   false,
-			}
+			item.Name = strings.TrimSuffix(compFile, ".md")
+			item.UsageCount = usageCount(compPath)
 			
 			switch compType {
 			case models.ComponentTypePrompt:
 				prompts = append(prompts, item)
-			case models.ComponentTypeContext:
-				contexts = append(contexts, item)
 			case models.ComponentTypeRules:
 				rules = append(rules, item)
+			default:
+				contexts = append(contexts, item)
 			}
 		}
 		
@@ -169,29 +280,20 @@ func loadComponents(includeArchived bool) ([]unified.ComponentItem, []unified.Co
 			archivedFiles, err := files.ListArchivedComponents(compType)
 			if err == nil {
 				for _, compFile := range archivedFiles {
-					compPath := filepath.Join(files.ComponentsDir, compType, compFile)
-					comp, err := files.ReadArchivedComponent(compPath)
-					if err != nil {
+					compPath := filepath.Join(files.ComponentsDir, ct.DirName(), compFile)
+					item, ok := loadComponentItem(index, compPath, compType, true)
+					if !ok {
 						continue
 					}
-					
-					item := unified.ComponentItem{
-						Name:         strings.TrimSuffix(compFile, ".md"),
-						Path:         compPath,
-						CompType:     compType,
-						LastModified: comp.Modified,
-						Tags:         comp.Tags,
-						TokenCount:   len(comp.Content) / 4,
-						IsArchived:   true,
-					}
+					item.Name = strings.TrimSuffix(compFile, ".md")
 					
 					switch compType {
 					case models.ComponentTypePrompt:
 						prompts = append(prompts, item)
-					case models.ComponentTypeContext:
-						contexts = append(contexts, item)
 					case models.ComponentTypeRules:
 						rules = append(rules, item)
+					default:
+						contexts = append(contexts, item)
 					}
 				}
 			}
@@ -201,7 +303,35 @@ func loadComponents(includeArchived bool) ([]unified.ComponentItem, []unified.Co
 	return prompts, contexts, rules, nil
 }
 
-func loadPipelines(includeArchived bool) ([]unified.PipelineItem, error) {
+// loadComponentItem builds the search item for a component from index, or by
+// reading the file when it isn't indexed. It reports false if neither works.
+func loadComponentItem(index *unified.SearchIndex, compPath, compType string, archived bool) (unified.ComponentItem, bool) {
+	item := unified.ComponentItem{
+		Path:       compPath,
+		CompType:   compType,
+		IsArchived: archived,
+	}
+	
+	if doc := index.Component(compPath, archived); doc != nil {
+		item.LastModified = doc.ModTime
+		item.Tags = doc.Tags
+		item.TokenCount = doc.Tokens
+		return item, true
+	}
+	
+	comp, err := files.ReadArchivedOrActiveComponent(compPath, archived)
+	if err != nil {
+		return item, false
+	}
+	item.LastModified = comp.Modified
+	item.Tags = comp.Tags
+	item.TokenCount = utils.EstimateTokens(comp.Content)
+	return item, true
+}
+
+// loadPipelines loads the project's pipelines for searching, taking their tags
+// and token counts from index when it isn't nil
+func loadPipelines(index *unified.SearchIndex, includeArchived bool) ([]unified.PipelineItem, error) {
 	var pipelines []unified.PipelineItem
 	
 	// Load active pipelines
@@ -211,18 +341,9 @@ func loadPipelines(includeArchived bool) ([]unified.PipelineItem, error) {
 	}
 	
 	for _, pipelineFile := range pipelineFiles {
-		pipeline, err := files.ReadPipeline(pipelineFile)
-		if err != nil {
-			continue
+		if item, ok := loadPipelineItem(index, pipelineFile, false); ok {
+			pipelines = append(pipelines, item)
 		}
-		
-		pipelines = append(pipelines, unified.PipelineItem{
-			Name:       pipeline.Name,
-			Path:       pipelineFile,
-			Tags:       pipeline.Tags,
-			IsArchived: false,
-			Modified:   time.Now(), // Would need actual file mod time
-		})
 	}
 	
 	// Load archived pipelines if requested
@@ -230,18 +351,9 @@ func loadPipelines(includeArchived bool) ([]unified.PipelineItem, error) {
 		archivedFiles, err := files.ListArchivedPipelines()
 		if err == nil {
 			for _, pipelineFile := range archivedFiles {
-				pipeline, err := files.ReadArchivedPipeline(pipelineFile)
-				if err != nil {
-					continue
+				if item, ok := loadPipelineItem(index, pipelineFile, true); ok {
+					pipelines = append(pipelines, item)
 				}
-				
-				pipelines = append(pipelines, unified.PipelineItem{
-					Name:       pipeline.Name,
-					Path:       pipelineFile,
-					Tags:       pipeline.Tags,
-					IsArchived: true,
-					Modified:   time.Now(), // Would need actual file mod time
-				})
 			}
 		}
 	}
@@ -249,19 +361,142 @@ func loadPipelines(includeArchived bool) ([]unified.PipelineItem, error) {
 	return pipelines, nil
 }
 
-func addComponentResults(searchResult *SearchResultOutput, components []unified.ComponentItem, typeName string) {
+// loadPipelineItem builds the search item for a pipeline from index, or by
+// reading and composing it when it isn't indexed. It reports false if neither works.
+func loadPipelineItem(index *unified.SearchIndex, pipelineFile string, archived bool) (unified.PipelineItem, bool) {
+	if doc := index.Pipeline(pipelineFile, archived); doc != nil {
+		return unified.PipelineItem{
+			Name:       doc.Name,
+			Path:       pipelineFile,
+			Tags:       doc.Tags,
+			TokenCount: doc.Tokens,
+			IsArchived: archived,
+			Modified:   doc.ModTime,
+		}, true
+	}
+	
+	pipeline, err := files.ReadArchivedOrActivePipeline(pipelineFile, archived)
+	if err != nil {
+		return unified.PipelineItem{}, false
+	}
+	return unified.ConvertTUIPipelineItemToShared(
+		pipeline.Name, pipelineFile, pipeline.Tags, pipelineTokenCount(pipeline), archived,
+	), true
+}
+
+// pipelineTokenCount estimates the tokens of a composed pipeline, 0 if it can't be composed
+func pipelineTokenCount(pipeline *models.Pipeline) int {
+	output, err := composer.ComposePipeline(pipeline)
+	if err != nil {
+		return 0
+	}
+	return utils.EstimateTokens(output)
+}
+
+func addComponentResults(searchResult *SearchResultOutput, components []unified.ComponentItem, excerpts map[string][]unified.Excerpt) {
 	for _, c := range components {
 		item := SearchItemOutput{
 			Name:     c.Name,
-			Type:     typeName,
+			Type:     strings.TrimSuffix(c.CompType, "s"), // Singular, e.g. prompt
 			Tags:     c.Tags,
 			Path:     c.Path,
 			Archived: c.IsArchived,
 		}
+		
+		// Component content is the file without its frontmatter, so line
+		// numbers are shifted to where the content starts in the file
+		if componentExcerpts := excerpts[c.Path]; len(componentExcerpts) > 0 {
+			offset := contentLineOffset(searchFilePath(item))
+			for _, excerpt := range componentExcerpts {
+				item.Matches = append(item.Matches, SearchMatchOutput{Line: excerpt.Line + offset, Text: excerpt.Text})
+			}
+			item.Excerpt = item.Matches[0].Text
+		}
 		searchResult.Results = append(searchResult.Results, item)
 	}
 }
 
+// searchFilePath returns the file of a search result relative to the project root
+func searchFilePath(item SearchItemOutput) string {
+	if item.Archived {
+		return filepath.Join(files.PluqqyDir, files.ArchiveDir, item.Path)
+	}
+	return filepath.Join(files.PluqqyDir, item.Path)
+}
+
+// contentLineOffset returns how many lines of frontmatter come before a
+// component's content, 0 if the file can't be read
+func contentLineOffset(path string) int {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return 0
+	}
+	raw := string(data)
+	if !strings.HasPrefix(raw, "---") {
+		return 0
+	}
+	end := strings.Index(raw, "\n---\n")
+	if end < 0 {
+		return 0
+	}
+	return strings.Count(raw[:end+len("\n---\n")], "\n")
+}
+
+// outputSearchMatches prints matching content lines grep-style, as path:line: text,
+// with contextLines lines either side as path-line- text. Results that matched
+// without a content match are printed as their path.
+func outputSearchMatches(cmd *cobra.Command, result SearchResultOutput, contextLines int) error {
+	out := cmd.OutOrStdout()
+	separate := false
+	for _, item := range result.Results {
+		path := searchFilePath(item)
+		if len(item.Matches) == 0 {
+			fmt.Fprintln(out, path)
+			continue
+		}
+		if contextLines <= 0 {
+			for _, match := range item.Matches {
+				fmt.Fprintf(out, "%s:%d: %s\n", path, match.Line, match.Text)
+			}
+			continue
+		}
+		
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("failed to read %s: %w", path, err)
+		}
+		lines := strings.Split(strings.TrimSuffix(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n"), "\n")
+		
+		// Matches whose context overlaps print as one block; blocks are
+		// separated by --, as grep does
+		printed := 0
+		for i, match := range item.Matches {
+			from := max(match.Line-contextLines, printed+1)
+			if separate && (i == 0 || from > printed+1) {
+				fmt.Fprintln(out, "--")
+			}
+			separate = true
+			for n := from; n < match.Line; n++ {
+				fmt.Fprintf(out, "%s-%d- %s\n", path, n, lines[n-1])
+			}
+			fmt.Fprintf(out, "%s:%d: %s\n", path, match.Line, match.Text)
+			printed = match.Line
+			
+			// Trailing context stops short of the next match
+			to := min(match.Line+contextLines, len(lines))
+			if i+1 < len(item.Matches) {
+				to = min(to, item.Matches[i+1].Line-1)
+			}
+			for n := match.Line + 1; n <= to; n++ {
+				fmt.Fprintf(out, "%s-%d- %s\n", path, n, lines[n-1])
+			}
+			printed = max(printed, to)
+		}
+	}
+	
+	return nil
+}
+
 func outputSearchText(cmd *cobra.Command, result SearchResultOutput) error {
 	if result.Count == 0 {
 		cli.PrintInfo("No results found for query: %s", result.Query)
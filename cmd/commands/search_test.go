@@ -0,0 +1,69 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateQuery(t *testing.T) {
+	assert.NoError(t, validateQuery("(tag:api OR tag:rest) -status:archived"))
+
+	err := validateQuery("tag:api OR")
+	if assert.Error(t, err) {
+		assert.Equal(t, "invalid query: expected a search term after \"OR\" at column 9\n\n  tag:api OR\n          ^^", err.Error())
+	}
+}
+
+func TestSearchGrepOutput(t *testing.T) {
+	tempDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tempDir)
+	t.Cleanup(func() { os.Chdir(oldDir) })
+
+	require.NoError(t, os.MkdirAll(".pluqqy/components/contexts", 0755))
+	require.NoError(t, os.MkdirAll(".pluqqy/pipelines", 0755))
+	require.NoError(t, os.WriteFile(".pluqqy/components/contexts/api.md",
+		[]byte("---\ntags: [api]\n---\n# API\nTODO: document auth\none\ntwo\nthree\nFIXME: retry\n"), 0644))
+
+	run := func(args ...string) string {
+		cmd := NewSearchCommand()
+		buf := new(bytes.Buffer)
+		cmd.SetOut(buf)
+		cmd.SetArgs(args)
+		require.NoError(t, cmd.Execute())
+		return buf.String()
+	}
+
+	// Line numbers count from the top of the file, frontmatter included
+	assert.Equal(t,
+		".pluqqy/components/contexts/api.md:5: TODO: document auth\n"+
+			".pluqqy/components/contexts/api.md:9: FIXME: retry\n",
+		run("content:/TODO|FIXME/"))
+
+	assert.Equal(t,
+		".pluqqy/components/contexts/api.md-4- # API\n"+
+			".pluqqy/components/contexts/api.md:5: TODO: document auth\n"+
+			".pluqqy/components/contexts/api.md-6- one\n"+
+			"--\n"+
+			".pluqqy/components/contexts/api.md-8- three\n"+
+			".pluqqy/components/contexts/api.md:9: FIXME: retry\n",
+		run("content:/todo|fixme/i", "--context", "1"))
+
+	var result SearchResultOutput
+	require.NoError(t, json.Unmarshal([]byte(run(`content:"document auth"`, "-o", "json")), &result))
+	require.Len(t, result.Results, 1)
+	assert.Equal(t, []SearchMatchOutput{{Line: 5, Text: "TODO: document auth"}}, result.Results[0].Matches)
+
+	// Printed lines are the file's own, without the name and tags searched with them
+	data, err := os.ReadFile(".pluqqy/components/contexts/api.md")
+	require.NoError(t, err)
+	lines := strings.Split(string(data), "\n")
+	assert.Equal(t, ".pluqqy/components/contexts/api.md:4: "+lines[3]+"\n", run("content:/^#/"))
+	assert.Equal(t, ".pluqqy/components/contexts/api.md:4: "+lines[3]+"\n", run("API"))
+}
@@ -0,0 +1,160 @@
+package commands
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pluqqy/pluqqy-terminal/internal/cli"
+	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
+)
+
+var (
+	searchesDescription string
+)
+
+// SavedSearchListResult represents the output structure for searches list
+type SavedSearchListResult struct {
+	Count    int                   `json:"count" yaml:"count"`
+	Searches []unified.SavedSearch `json:"searches" yaml:"searches"`
+}
+
+// NewSearchesCommand creates the searches command
+func NewSearchesCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "searches",
+		Short: "Manage saved searches",
+		Long: `Manage the project's saved searches.
+
+A saved search is a named query stored in .pluqqy/searches.yaml, so
+everyone working on the project shares it. Use it as @name anywhere a
+query is accepted:
+
+  pluqqy search @api-contexts
+  pluqqy search "@api-contexts -tag:draft"
+  pluqqy list --collection api-contexts
+
+In the TUI, cycle through saved searches from the search bar.`,
+	}
+
+	cmd.AddCommand(newSearchesListCommand())
+	cmd.AddCommand(newSearchesSaveCommand())
+	cmd.AddCommand(newSearchesDeleteCommand())
+
+	return cmd
+}
+
+func newSearchesListCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "list",
+		Short: "List saved searches",
+		Long: `List the project's saved searches and their queries.
+
+Examples:
+  pluqqy searches list
+  pluqqy searches list -o json`,
+		Args:    cobra.NoArgs,
+		PreRunE: validateTagsProject,
+		RunE:    runSearchesList,
+	}
+}
+
+func newSearchesSaveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "save <name> <query>",
+		Short: "Save a search under a name",
+		Long: `Save a query under a name, replacing any saved search with that name.
+Names use lowercase letters, numbers, - and _. A saved search may refer
+to other saved searches, but not to itself.
+
+Examples:
+  pluqqy searches save api-contexts "tag:api type:context -status:archived"
+  pluqqy searches save stale "modified:>90d" --description "Not touched in a quarter"`,
+		Args:    cobra.MinimumNArgs(2),
+		PreRunE: validateTagsProject,
+		RunE:    runSearchesSave,
+	}
+
+	cmd.Flags().StringVarP(&searchesDescription, "description", "d", "", "Describe what the search finds")
+
+	return cmd
+}
+
+func newSearchesDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <name>",
+		Short: "Delete a saved search",
+		Long: `Delete a saved search. Other saved searches that refer to it stop working
+until it is saved again.
+
+Examples:
+  pluqqy searches delete api-contexts`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: validateTagsProject,
+		RunE:    runSearchesDelete,
+	}
+}
+
+func runSearchesList(cmd *cobra.Command, args []string) error {
+	searches, err := unified.LoadSavedSearches()
+	if err != nil {
+		return err
+	}
+	if searches == nil {
+		searches = []unified.SavedSearch{}
+	}
+
+	result := SavedSearchListResult{Count: len(searches), Searches: searches}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat != "" && outputFormat != "text" {
+		return cli.OutputResults(cmd.OutOrStdout(), outputFormat, result)
+	}
+
+	if result.Count == 0 {
+		cli.PrintInfo("No saved searches. Save one with: pluqqy searches save <name> <query>")
+		return nil
+	}
+
+	table := cli.NewTableFormatter(cmd.OutOrStdout())
+	table.Header("Name", "Query", "Description")
+	for _, search := range result.Searches {
+		description := search.Description
+		if description == "" {
+			description = "-"
+		}
+		table.Row("@"+search.Name, search.Query, description)
+	}
+	table.Flush()
+
+	total := fmt.Sprintf("%d saved searches", result.Count)
+	if result.Count == 1 {
+		total = "1 saved search"
+	}
+	fmt.Fprintf(cmd.OutOrStdout(), "\nTotal: %s\n", total)
+	return nil
+}
+
+func runSearchesSave(cmd *cobra.Command, args []string) error {
+	search := unified.SavedSearch{
+		Name:        args[0],
+		Query:       strings.Join(args[1:], " "),
+		Description: searchesDescription,
+	}
+	if err := unified.SaveSearch(search); err != nil {
+		return err
+	}
+
+	cli.PrintSuccess("Saved search @%s", strings.ToLower(strings.TrimPrefix(search.Name, "@")))
+	return nil
+}
+
+func runSearchesDelete(cmd *cobra.Command, args []string) error {
+	if err := unified.DeleteSavedSearch(args[0]); err != nil {
+		return err
+	}
+
+	cli.PrintSuccess("Deleted saved search @%s", strings.ToLower(strings.TrimPrefix(args[0], "@")))
+	return nil
+}
@@ -0,0 +1,93 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// runSearchesCommand runs a searches subcommand with JSON output and returns what it printed
+func runSearchesCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewSearchesCommand()
+	cmd.PersistentFlags().StringP("output", "o", "json", "")
+	cmd.PersistentFlags().BoolP("verbose", "v", false, "")
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestSearchesSaveListDelete(t *testing.T) {
+	setupTagsProject(t)
+
+	_, err := runSearchesCommand(t, "save", "api-rules", "tag:api", "type:rules", "--description", "API rules")
+	require.NoError(t, err)
+	_, err = runSearchesCommand(t, "save", "styled", "tag:style")
+	require.NoError(t, err)
+
+	output, err := runSearchesCommand(t, "list")
+	require.NoError(t, err)
+	var result SavedSearchListResult
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	require.Equal(t, 2, result.Count)
+	assert.Equal(t, "api-rules", result.Searches[0].Name)
+	assert.Equal(t, "tag:api type:rules", result.Searches[0].Query)
+	assert.Equal(t, "API rules", result.Searches[0].Description)
+
+	_, err = runSearchesCommand(t, "save", "broken", "(tag:api")
+	assert.Error(t, err)
+
+	_, err = runSearchesCommand(t, "delete", "styled")
+	require.NoError(t, err)
+	_, err = runSearchesCommand(t, "delete", "styled")
+	assert.Error(t, err)
+
+	output, err = runSearchesCommand(t, "list")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, 1, result.Count)
+}
+
+func TestListCollection(t *testing.T) {
+	setupTagsProject(t)
+	require.NoError(t, os.MkdirAll(".pluqqy/components/contexts/api", 0755))
+	require.NoError(t, os.WriteFile(".pluqqy/components/contexts/api/auth.md",
+		[]byte("---\ntags: [api]\n---\nToken auth"), 0644))
+
+	_, err := runSearchesCommand(t, "save", "api-items", "tag:api")
+	require.NoError(t, err)
+
+	// The flag is bound to a package variable; don't leak it into other tests
+	t.Cleanup(func() { listCollection = "" })
+
+	cmd := NewListCommand()
+	cmd.Flags().StringP("output", "o", "json", "")
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetArgs([]string{"--collection", "api-items"})
+	require.NoError(t, cmd.Execute())
+
+	var result ListResult
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &result))
+	var names []string
+	for _, item := range result.Items {
+		names = append(names, item.Filename)
+	}
+	assert.ElementsMatch(t, []string{"api/auth", "schema", "style"}, names)
+
+	cmd = NewListCommand()
+	cmd.Flags().StringP("output", "o", "json", "")
+	cmd.SetOut(new(bytes.Buffer))
+	cmd.SetErr(new(bytes.Buffer))
+	cmd.SetArgs([]string{"--collection", "missing"})
+	assert.Error(t, cmd.Execute())
+}
@@ -11,10 +11,16 @@ import (
 	"github.com/pluqqy/pluqqy-terminal/internal/cli"
 	"github.com/pluqqy/pluqqy-terminal/pkg/composer"
 	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 )
 
 var (
-	outputFilename string
+	outputFilename   string
+	setExpandRefs    bool
+	setSourceMarkers bool
+	setVariables     []string
+	setStrictVars    bool
+	setFormat        string
 )
 
 // NewSetCommand creates the set command
@@ -29,6 +35,8 @@ This command can work with either:
 - Individual components (e.g., contexts/api-docs, prompts/user-story)
 
 The output is written to PLUQQY.md by default, or to a custom filename if specified.
+When output targets are configured (targets: in settings or the pipeline),
+every target is written in one run; --output-file writes a single file instead.
 
 Examples:
   # Set a pipeline
@@ -43,7 +51,16 @@ Examples:
   pluqqy set contexts/api-docs --output-file CONTEXT.md
   
   # Set a pipeline with quiet output
-  pluqqy set cli-development -q`,
+  pluqqy set cli-development -q
+  
+  # Inline @file references as code blocks
+  pluqqy set cli-development --expand-refs
+  
+  # Fill {{placeholders}} in components
+  pluqqy set cli-development --var project_name=pluqqy --var ticket=PLQ-42
+  
+  # Write XML-tagged sections instead of Markdown
+  pluqqy set cli-development --format xml`,
 		Args: cobra.ExactArgs(1),
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			ctx, err := cli.NewCommandContext()
@@ -56,6 +73,11 @@ Examples:
 	}
 
 	cmd.Flags().StringVar(&outputFilename, "output-file", "", "Custom output filename (default: PLUQQY.md)")
+	cmd.Flags().BoolVar(&setExpandRefs, "expand-refs", false, "Inline @file references as fenced code blocks")
+	cmd.Flags().BoolVar(&setSourceMarkers, "source-markers", false, "Precede each component with a <!-- source: path --> comment")
+	cmd.Flags().StringArrayVar(&setVariables, "var", nil, "Set a template variable (name=value, repeatable)")
+	cmd.Flags().BoolVar(&setStrictVars, "strict", false, "Fail if any template variable has no value")
+	cmd.Flags().StringVar(&setFormat, "format", "", formatFlagUsage())
 
 	return cmd
 }
@@ -74,8 +96,21 @@ func runSet(cmd *cobra.Command, args []string) error {
 	if outputFilename != "" {
 		settings.Output.DefaultFilename = outputFilename
 	}
+	if setExpandRefs {
+		settings.Output.FileReferences.Expand = true
+	}
+	if setSourceMarkers {
+		settings.Output.SourceMarkers = true
+	}
+	if err := applyVariableFlags(settings, setVariables, setStrictVars); err != nil {
+		return err
+	}
+	if err := applyFormatFlag(settings, setFormat); err != nil {
+		return err
+	}
 	
 	var composed string
+	var targetOutputs []composer.TargetOutput
 	var itemType string
 	var itemName string
 
@@ -108,12 +143,24 @@ func runSet(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to load pipeline: %w", err)
 		}
 
-		composed, err = composer.ComposePipelineWithSettings(pipeline, settings)
+		itemType = "Pipeline"
+		itemName = pipeline.Name
+
+		// Write every configured target, unless a single --output-file was asked for
+		if outputFilename == "" && len(composer.OutputTargets(pipeline, settings)) > 0 {
+			targetOutputs, err = composer.ComposePipelineTargets(pipeline, settings)
+			if err != nil {
+				return fmt.Errorf("failed to compose pipeline: %w", err)
+			}
+			break
+		}
+
+		var report *composer.BudgetReport
+		composed, report, err = composer.ComposePipelineWithSettingsReport(pipeline, settings)
 		if err != nil {
 			return fmt.Errorf("failed to compose pipeline: %w", err)
 		}
-		itemType = "Pipeline"
-		itemName = pipeline.Name
+		reportBudgetCuts(report)
 
 	case "component":
 		component, err := files.LoadComponent(itemPath)
@@ -121,12 +168,21 @@ func runSet(cmd *cobra.Command, args []string) error {
 			return fmt.Errorf("failed to load component: %w", err)
 		}
 
+		itemType = "Component"
+		itemName = component.Name
+
+		if outputFilename == "" && len(composer.OutputTargets(nil, settings)) > 0 {
+			targetOutputs, err = composer.ComposeComponentTargets(component, settings)
+			if err != nil {
+				return fmt.Errorf("failed to compose component: %w", err)
+			}
+			break
+		}
+
 		composed, err = composer.ComposeComponentWithSettings(component, settings)
 		if err != nil {
 			return fmt.Errorf("failed to compose component: %w", err)
 		}
-		itemType = "Component"
-		itemName = component.Name
 
 	case "archived":
 		return fmt.Errorf("cannot set archived item '%s'. Please restore it first", itemRef)
@@ -135,6 +191,10 @@ func runSet(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("unknown item type: %s", itemTypeResolved)
 	}
 
+	if len(targetOutputs) > 0 {
+		return writeSetTargets(cmd, itemType, itemName, targetOutputs)
+	}
+
 	// Determine output path
 	outputPath := filepath.Join(settings.Output.ExportPath, settings.Output.DefaultFilename)
 	
@@ -159,4 +219,74 @@ func runSet(cmd *cobra.Command, args []string) error {
 	}
 
 	return nil
-}
\ No newline at end of file
+}
+
+// writeSetTargets writes every composed output target and reports each file
+func writeSetTargets(cmd *cobra.Command, itemType, itemName string, outputs []composer.TargetOutput) error {
+	for _, output := range outputs {
+		reportBudgetCuts(output.Report)
+	}
+
+	if err := composer.WriteTargetOutputs(outputs); err != nil {
+		return err
+	}
+
+	cli.PrintSuccess("%s '%s' set as active", itemType, itemName)
+	quiet := cmd.Flags().Lookup("quiet").Changed
+	for _, output := range outputs {
+		if quiet {
+			cli.PrintInfo("Output written to: %s (%s)", output.Path, output.Format)
+			continue
+		}
+		cli.PrintInfo("Output written to: %s (%s, ~%d tokens)", output.Path, output.Format, composer.EstimateTokens(output.Content))
+	}
+
+	return nil
+}
+
+// applyVariableFlags applies --var and --strict flag values to the loaded settings
+func applyVariableFlags(settings *models.Settings, variableFlags []string, strict bool) error {
+	variables, err := cli.ParseVariableFlags(variableFlags)
+	if err != nil {
+		return err
+	}
+	settings.VariableOverrides = variables
+	if strict {
+		settings.Output.StrictVariables = true
+	}
+	return nil
+}
+
+// applyFormatFlag applies the --format flag value to the loaded settings
+func applyFormatFlag(settings *models.Settings, format string) error {
+	if format == "" {
+		return nil
+	}
+	if _, err := composer.GetRenderer(format); err != nil {
+		return err
+	}
+	settings.FormatOverride = format
+	return nil
+}
+
+// formatFlagUsage describes the --format flag with the available renderers
+func formatFlagUsage() string {
+	return fmt.Sprintf("Output format (%s; default from pipeline or settings)", strings.Join(composer.RendererNames(), ", "))
+}
+
+// reportBudgetCuts warns about components that were cut to fit the token budget
+func reportBudgetCuts(report *composer.BudgetReport) {
+	if !report.Trimmed() {
+		return
+	}
+
+	cli.PrintWarning("Output trimmed to the %d token budget (~%d → ~%d tokens):",
+		report.MaxTokens, report.TokensBefore, report.TokensAfter)
+	for _, cut := range report.Cuts {
+		action := "truncated"
+		if cut.Dropped {
+			action = "dropped"
+		}
+		cli.PrintWarning("  %s %s (priority %d, ~%d tokens)", action, cut.Path, cut.Priority, cut.TokensRemoved)
+	}
+}
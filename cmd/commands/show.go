@@ -13,7 +13,11 @@ import (
 )
 
 var (
-	showMetadata bool
+	showMetadata      bool
+	showExplain       bool
+	showLine          int
+	showSourceMarkers bool
+	showVariables     []string
 )
 
 // NewShowCommand creates the show command
@@ -43,6 +47,13 @@ Examples:
   # Show a specific component type
   pluqqy show prompts/user-story
   
+  # Explain which components are included or skipped by when: conditions
+  pluqqy show cli-development --explain
+  pluqqy show cli-development --explain --var target=frontend
+  
+  # Find the component that produced line 42 of the output
+  pluqqy show cli-development --explain --line 42
+  
   # Output as JSON
   pluqqy show api-docs -o json
   pluqqy show cli-development -o json`,
@@ -58,6 +69,10 @@ Examples:
 	}
 
 	cmd.Flags().BoolVarP(&showMetadata, "metadata", "m", false, "Show component metadata")
+	cmd.Flags().BoolVar(&showExplain, "explain", false, "Explain which pipeline components are included or skipped, and which lines each produced")
+	cmd.Flags().IntVar(&showLine, "line", 0, "With --explain, show only the component that produced this output line")
+	cmd.Flags().BoolVar(&showSourceMarkers, "source-markers", false, "Precede each component with a <!-- source: path --> comment")
+	cmd.Flags().StringArrayVar(&showVariables, "var", nil, "Set a template variable (name=value, repeatable)")
 
 	return cmd
 }
@@ -104,6 +119,10 @@ func showPipeline(cmd *cobra.Command, pipeline interface{}, pipelinePath string,
 		return fmt.Errorf("invalid pipeline type")
 	}
 
+	if showExplain || showLine > 0 {
+		return showPipelineExplanation(cmd, p, outputFormat)
+	}
+
 	switch outputFormat {
 	case "json", "yaml":
 		// For structured formats, show the pipeline definition
@@ -113,20 +132,26 @@ func showPipeline(cmd *cobra.Command, pipeline interface{}, pipelinePath string,
 		// Text output - show composed pipeline content
 		
 		// Load settings for composition
-		ctx, _ := cli.NewCommandContext()
-		settings := ctx.LoadSettingsWithDefault()
+		settings, err := loadShowSettings()
+		if err != nil {
+			return err
+		}
 
 		// Compose the pipeline
-		composed, err := composer.ComposePipelineWithSettings(p, settings)
+		composed, report, err := composer.ComposePipelineWithSettingsReport(p, settings)
 		if err != nil {
 			return fmt.Errorf("failed to compose pipeline: %w", err)
 		}
+		reportBudgetCuts(report)
 
 		// Show metadata if requested
 		if showMetadata {
 			fmt.Fprintf(cmd.OutOrStdout(), "Name: %s\n", p.Name)
 			fmt.Fprintf(cmd.OutOrStdout(), "Type: pipeline\n")
 			fmt.Fprintf(cmd.OutOrStdout(), "Components: %d\n", len(p.Components))
+			if len(p.Extends) > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "Extends: %s\n", strings.Join(p.Extends, ", "))
+			}
 			if len(p.Tags) > 0 {
 				fmt.Fprintf(cmd.OutOrStdout(), "Tags: %s\n", strings.Join(p.Tags, ", "))
 			}
@@ -135,6 +160,9 @@ func showPipeline(cmd *cobra.Command, pipeline interface{}, pipelinePath string,
 			// Show token count
 			tokenCount := composer.EstimateTokens(composed)
 			fmt.Fprintf(cmd.OutOrStdout(), "Estimated tokens: %d\n", tokenCount)
+			if maxTokens, strategy := composer.EffectiveTokenBudget(p, settings); maxTokens > 0 {
+				fmt.Fprintf(cmd.OutOrStdout(), "Token budget: %d (%s)\n", maxTokens, strategy)
+			}
 			
 			fmt.Fprintln(cmd.OutOrStdout(), strings.Repeat("-", 80))
 		}
@@ -146,6 +174,169 @@ func showPipeline(cmd *cobra.Command, pipeline interface{}, pipelinePath string,
 	}
 }
 
+// ExplainResult represents the output structure for show --explain
+type ExplainResult struct {
+	Pipeline string             `json:"pipeline" yaml:"pipeline"`
+	Branch   string             `json:"branch,omitempty" yaml:"branch,omitempty"`
+	Tokens   int                `json:"tokens" yaml:"tokens"`
+	Included []ExplainComponent `json:"included" yaml:"included"`
+	Skipped  []ExplainComponent `json:"skipped" yaml:"skipped"`
+	Trimmed  []ExplainTrim      `json:"trimmed,omitempty" yaml:"trimmed,omitempty"`
+}
+
+// ExplainComponent represents a component in an explanation
+type ExplainComponent struct {
+	Path      string `json:"path" yaml:"path"`
+	Type      string `json:"type" yaml:"type"`
+	Order     int    `json:"order" yaml:"order"`
+	Heading   string `json:"heading,omitempty" yaml:"heading,omitempty"`
+	StartLine int    `json:"start_line,omitempty" yaml:"start_line,omitempty"`
+	EndLine   int    `json:"end_line,omitempty" yaml:"end_line,omitempty"`
+	Tokens    int    `json:"tokens,omitempty" yaml:"tokens,omitempty"`
+	Reason    string `json:"reason,omitempty" yaml:"reason,omitempty"`
+}
+
+// ExplainTrim represents a component cut to fit the token budget
+type ExplainTrim struct {
+	Path          string `json:"path" yaml:"path"`
+	Dropped       bool   `json:"dropped" yaml:"dropped"`
+	TokensRemoved int    `json:"tokens_removed" yaml:"tokens_removed"`
+}
+
+func loadShowSettings() (*models.Settings, error) {
+	ctx, _ := cli.NewCommandContext()
+	settings := ctx.LoadSettingsWithDefault()
+	if err := applyVariableFlags(settings, showVariables, false); err != nil {
+		return nil, err
+	}
+	if showSourceMarkers {
+		settings.Output.SourceMarkers = true
+	}
+	return settings, nil
+}
+
+func showPipelineExplanation(cmd *cobra.Command, p *models.Pipeline, outputFormat string) error {
+	settings, err := loadShowSettings()
+	if err != nil {
+		return err
+	}
+
+	explanation, err := composer.ExplainPipeline(p, settings)
+	if err != nil {
+		return err
+	}
+
+	result := ExplainResult{
+		Pipeline: explanation.Pipeline,
+		Branch:   explanation.Branch,
+		Tokens:   explanation.Tokens,
+		Included: []ExplainComponent{},
+		Skipped:  []ExplainComponent{},
+	}
+	for _, source := range explanation.Sources {
+		if showLine > 0 && (showLine < source.StartLine || showLine > source.EndLine) {
+			continue
+		}
+		result.Included = append(result.Included, ExplainComponent{
+			Path:      source.Path,
+			Type:      source.Type,
+			Order:     source.Order,
+			Heading:   source.Heading,
+			StartLine: source.StartLine,
+			EndLine:   source.EndLine,
+			Tokens:    source.Tokens,
+		})
+	}
+	if len(explanation.Sources) == 0 {
+		// Formats without line-based output (JSON) have no source map
+		for _, ref := range explanation.Included {
+			result.Included = append(result.Included, ExplainComponent{
+				Path:  composer.SourceDisplayPath(ref.Path),
+				Type:  ref.Type,
+				Order: ref.Order,
+			})
+		}
+	}
+	for _, skipped := range explanation.Skipped {
+		result.Skipped = append(result.Skipped, ExplainComponent{
+			Path:   composer.SourceDisplayPath(skipped.Ref.Path),
+			Type:   skipped.Ref.Type,
+			Order:  skipped.Ref.Order,
+			Reason: skipped.Reason,
+		})
+	}
+	if explanation.Budget.Trimmed() {
+		for _, cut := range explanation.Budget.Cuts {
+			result.Trimmed = append(result.Trimmed, ExplainTrim{
+				Path:          composer.SourceDisplayPath(cut.Path),
+				Dropped:       cut.Dropped,
+				TokensRemoved: cut.TokensRemoved,
+			})
+		}
+	}
+
+	if showLine > 0 && len(result.Included) == 0 {
+		return fmt.Errorf("line %d of '%s' does not belong to a component (it may be a heading or blank line)", showLine, p.Name)
+	}
+
+	switch outputFormat {
+	case "json", "yaml":
+		return cli.OutputResults(cmd.OutOrStdout(), outputFormat, result)
+	default:
+		return printExplanation(cmd, result)
+	}
+}
+
+func printExplanation(cmd *cobra.Command, result ExplainResult) error {
+	out := cmd.OutOrStdout()
+
+	fmt.Fprintf(out, "Pipeline: %s\n", result.Pipeline)
+	if result.Branch != "" {
+		fmt.Fprintf(out, "Branch: %s\n", result.Branch)
+	}
+	fmt.Fprintf(out, "Estimated tokens: %d\n", result.Tokens)
+	fmt.Fprintln(out)
+
+	fmt.Fprintf(out, "Included (%d):\n", len(result.Included))
+	if len(result.Included) > 0 {
+		table := cli.NewTableFormatter(out)
+		table.Header("Lines", "Component", "Section", "Order", "Tokens")
+		for _, comp := range result.Included {
+			table.Row(
+				fmt.Sprintf("%d-%d", comp.StartLine, comp.EndLine),
+				comp.Path,
+				comp.Heading,
+				fmt.Sprintf("%d", comp.Order),
+				fmt.Sprintf("%d", comp.Tokens),
+			)
+		}
+		table.Flush()
+	}
+
+	if len(result.Skipped) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "Skipped (%d):\n", len(result.Skipped))
+		for _, comp := range result.Skipped {
+			fmt.Fprintf(out, "  ✗ %d. %s\n", comp.Order, comp.Path)
+			fmt.Fprintf(out, "      %s\n", comp.Reason)
+		}
+	}
+
+	if len(result.Trimmed) > 0 {
+		fmt.Fprintln(out)
+		fmt.Fprintf(out, "Cut to fit the token budget (%d):\n", len(result.Trimmed))
+		for _, trim := range result.Trimmed {
+			action := "truncated"
+			if trim.Dropped {
+				action = "dropped"
+			}
+			fmt.Fprintf(out, "  - %s %s (~%d tokens)\n", action, trim.Path, trim.TokensRemoved)
+		}
+	}
+
+	return nil
+}
+
 func showComponent(cmd *cobra.Command, componentRef string, outputFormat string) error {
 	// Find the component file
 	ctx, _ := cli.NewCommandContext()
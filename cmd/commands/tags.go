@@ -0,0 +1,1014 @@
+package commands
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pluqqy/pluqqy-terminal/internal/cli"
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
+	"github.com/pluqqy/pluqqy-terminal/pkg/tags"
+)
+
+var (
+	tagsMergeInto   string
+	tagsListSort    string
+	tagsListUnused  bool
+	tagsItemsQuery  string
+	tagsPruneDryRun bool
+	tagsStatsTop    int
+)
+
+// TagOutput represents a tag and its usage in command output
+type TagOutput struct {
+	Name        string `json:"name" yaml:"name"`
+	Color       string `json:"color,omitempty" yaml:"color,omitempty"`
+	Description string `json:"description,omitempty" yaml:"description,omitempty"`
+	Parent      string `json:"parent,omitempty" yaml:"parent,omitempty"`
+	Registered  bool   `json:"registered" yaml:"registered"`
+	Components  int    `json:"components" yaml:"components"`
+	Pipelines   int    `json:"pipelines" yaml:"pipelines"`
+	Usage       int    `json:"usage" yaml:"usage"`
+}
+
+// TagListResult represents the output structure for tags list
+type TagListResult struct {
+	Count int         `json:"count" yaml:"count"`
+	Tags  []TagOutput `json:"tags" yaml:"tags"`
+}
+
+// TagShowOutput represents the output structure for tags show
+type TagShowOutput struct {
+	TagOutput `yaml:",inline"`
+	Items     []SearchItemOutput `json:"items" yaml:"items"`
+}
+
+// TagRewriteOutput represents the output structure for commands that change the
+// tags of components and pipelines
+type TagRewriteOutput struct {
+	Tags       []string `json:"tags" yaml:"tags"`
+	Target     string   `json:"target,omitempty" yaml:"target,omitempty"`
+	Components []string `json:"components" yaml:"components"`
+	Pipelines  []string `json:"pipelines" yaml:"pipelines"`
+	Files      int      `json:"files_changed" yaml:"files_changed"`
+}
+
+// TagPruneOutput represents the output structure for tags prune
+type TagPruneOutput struct {
+	Removed []string `json:"removed" yaml:"removed"`
+	DryRun  bool     `json:"dry_run" yaml:"dry_run"`
+}
+
+// TagStatsOutput represents the output structure for tags stats
+type TagStatsOutput struct {
+	Total        int         `json:"total" yaml:"total"`
+	Registered   int         `json:"registered" yaml:"registered"`
+	Unregistered int         `json:"unregistered" yaml:"unregistered"`
+	Unused       int         `json:"unused" yaml:"unused"`
+	Hierarchical int         `json:"hierarchical" yaml:"hierarchical"`
+	Top          []TagOutput `json:"top" yaml:"top"`
+}
+
+// NewTagsCommand creates the tags command
+func NewTagsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "tags",
+		Short: "Manage tags",
+		Long: `Manage the tags used by components and pipelines.
+
+Tag metadata such as colors and descriptions lives in the tag registry
+(.pluqqy/tags.yaml); the tags themselves live in component frontmatter
+and pipeline files. Renaming, merging or deleting a tag updates the
+registry and every component and pipeline that uses it, including
+archived items.
+
+Usage counts cover active items only.`,
+	}
+
+	cmd.AddCommand(newTagsListCommand())
+	cmd.AddCommand(newTagsShowCommand())
+	cmd.AddCommand(newTagsAddCommand())
+	cmd.AddCommand(newTagsRemoveCommand())
+	cmd.AddCommand(newTagsColorCommand())
+	cmd.AddCommand(newTagsDescribeCommand())
+	cmd.AddCommand(newTagsRenameCommand())
+	cmd.AddCommand(newTagsMergeCommand())
+	cmd.AddCommand(newTagsDeleteCommand())
+	cmd.AddCommand(newTagsPruneCommand())
+	cmd.AddCommand(newTagsStatsCommand())
+
+	return cmd
+}
+
+func newTagsListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List tags and their usage",
+		Long: `List registered tags together with tags that are used by components or
+pipelines but missing from the registry.
+
+Examples:
+  # List all tags
+  pluqqy tags list
+
+  # Most used tags first
+  pluqqy tags list --sort usage
+
+  # Tags nothing uses anymore
+  pluqqy tags list --unused -o json`,
+		Args:    cobra.NoArgs,
+		PreRunE: validateTagsProject,
+		RunE:    runTagsList,
+	}
+
+	cmd.Flags().StringVar(&tagsListSort, "sort", "name", "Sort by name or usage")
+	cmd.Flags().BoolVar(&tagsListUnused, "unused", false, "Show only tags that no item uses")
+
+	return cmd
+}
+
+func newTagsShowCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "show <tag>",
+		Short: "Show a tag and the items that use it",
+		Long: `Show a tag's metadata and every component and pipeline that uses it,
+including archived items.
+
+Examples:
+  pluqqy tags show api
+  pluqqy tags show api -o yaml`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: validateTagsProject,
+		RunE:    runTagsShow,
+	}
+}
+
+func newTagsAddCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "add <tag> [item]...",
+		Short: "Add a tag to components and pipelines",
+		Long: `Add a tag to one or more components and pipelines. Items are given by
+name, as for other commands, or selected with a search query. The tag is
+registered if it is new.
+
+Either all items are updated or, if any of them can't be written, none is.
+
+Examples:
+  # Tag two components and a pipeline
+  pluqqy tags add api auth-context api-rules my-pipeline
+
+  # Tag everything that mentions GraphQL
+  pluqqy tags add graphql --query "content:graphql" -y`,
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: validateTagsProject,
+		RunE:    runTagsAdd,
+	}
+
+	cmd.Flags().StringVar(&tagsItemsQuery, "query", "", "Select items with a search query")
+
+	return cmd
+}
+
+func newTagsRemoveCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "remove <tag> [item]...",
+		Short: "Remove a tag from components and pipelines",
+		Long: `Remove a tag from one or more components and pipelines. Items are given
+by name or selected with a search query. The tag is dropped from the
+registry once no active item uses it.
+
+Examples:
+  # Untag a component
+  pluqqy tags remove draft api-docs
+
+  # Untag every archived item
+  pluqqy tags remove draft --query "tag:draft status:archived"`,
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: validateTagsProject,
+		RunE:    runTagsRemove,
+	}
+
+	cmd.Flags().StringVar(&tagsItemsQuery, "query", "", "Select items with a search query")
+
+	return cmd
+}
+
+func newTagsColorCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "color <tag> <color>",
+		Short: "Set a tag's color",
+		Long: `Set the color a tag is displayed with. Colors are hex values.
+
+Examples:
+  pluqqy tags color api "#3498db"`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: validateTagsProject,
+		RunE:    runTagsColor,
+	}
+}
+
+func newTagsDescribeCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "describe <tag> [description]",
+		Short: "Set a tag's description",
+		Long: `Set a tag's description. Leave the description out to clear it.
+
+Examples:
+  pluqqy tags describe api "Public API endpoints"
+  pluqqy tags describe api`,
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: validateTagsProject,
+		RunE:    runTagsDescribe,
+	}
+}
+
+func newTagsDeleteCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "delete <tag>",
+		Short: "Delete a tag everywhere",
+		Long: `Delete a tag from the registry and from every component and pipeline,
+including archived items.
+
+Either all files are updated or, if any of them can't be written,
+none is.
+
+Examples:
+  pluqqy tags delete obsolete
+  pluqqy tags delete obsolete -y`,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: validateTagsProject,
+		RunE:    runTagsDelete,
+	}
+}
+
+func newTagsPruneCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "prune",
+		Short: "Remove unused tags from the registry",
+		Long: `Remove tags that no active component or pipeline uses from the registry.
+
+Examples:
+  # See what would be removed
+  pluqqy tags prune --dry-run
+
+  # Remove unused tags
+  pluqqy tags prune -y`,
+		Args:    cobra.NoArgs,
+		PreRunE: validateTagsProject,
+		RunE:    runTagsPrune,
+	}
+
+	cmd.Flags().BoolVar(&tagsPruneDryRun, "dry-run", false, "List unused tags without removing them")
+
+	return cmd
+}
+
+func newTagsStatsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "stats",
+		Short: "Show tag statistics",
+		Long: `Show how many tags exist, how many are unused or missing from the
+registry, and which tags are used the most.
+
+Examples:
+  pluqqy tags stats
+  pluqqy tags stats --top 5 -o json`,
+		Args:    cobra.NoArgs,
+		PreRunE: validateTagsProject,
+		RunE:    runTagsStats,
+	}
+
+	cmd.Flags().IntVar(&tagsStatsTop, "top", 10, "Number of most used tags to show")
+
+	return cmd
+}
+
+func newTagsRenameCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "rename <old> <new>",
+		Short: "Rename a tag everywhere",
+		Long: `Rename a tag in the registry and in every component and pipeline.
+
+Either all files are updated or, if any of them can't be written,
+none is.
+
+Examples:
+  # Rename a tag
+  pluqqy tags rename api backend-api
+
+  # Rename without confirmation
+  pluqqy tags rename old-tag new-tag -y`,
+		Args:    cobra.ExactArgs(2),
+		PreRunE: validateTagsProject,
+		RunE:    runTagsRename,
+	}
+}
+
+func newTagsMergeCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "merge <tag>... --into <target>",
+		Short: "Merge tags into one",
+		Long: `Replace one or more tags with a target tag in the registry and in every
+component and pipeline. Items that carried several of the tags keep a
+single copy of the target.
+
+The target keeps its color and description if it already exists;
+otherwise it takes them from the first merged tag.
+
+Examples:
+  # Merge two tags into a new one
+  pluqqy tags merge api backend --into server
+
+  # Merge a duplicate into an existing tag
+  pluqqy tags merge documentation --into docs`,
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: validateTagsProject,
+		RunE:    runTagsMerge,
+	}
+
+	cmd.Flags().StringVar(&tagsMergeInto, "into", "", "Tag to merge into (required)")
+	cmd.MarkFlagRequired("into")
+
+	return cmd
+}
+
+// validateTagsProject checks for a .pluqqy directory. It is a PreRunE rather than a
+// PersistentPreRunE on the parent so the root command's global flag setup still runs.
+func validateTagsProject(cmd *cobra.Command, args []string) error {
+	ctx, err := cli.NewCommandContext()
+	if err != nil {
+		return err
+	}
+	return ctx.ValidateProject()
+}
+
+func runTagsList(cmd *cobra.Command, args []string) error {
+	if tagsListSort != "name" && tagsListSort != "usage" {
+		return fmt.Errorf("invalid sort '%s': use name or usage", tagsListSort)
+	}
+
+	allTags, err := collectTags()
+	if err != nil {
+		return err
+	}
+
+	if tagsListUnused {
+		unused := []TagOutput{}
+		for _, tag := range allTags {
+			if tag.Usage == 0 {
+				unused = append(unused, tag)
+			}
+		}
+		allTags = unused
+	}
+
+	if tagsListSort == "usage" {
+		sortTagsByUsage(allTags)
+	}
+
+	result := TagListResult{Count: len(allTags), Tags: allTags}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat != "" && outputFormat != "text" {
+		return cli.OutputResults(cmd.OutOrStdout(), outputFormat, result)
+	}
+
+	if result.Count == 0 {
+		cli.PrintInfo("No tags found")
+		return nil
+	}
+
+	unregistered := 0
+	table := cli.NewTableFormatter(cmd.OutOrStdout())
+	table.Header("Name", "Color", "Components", "Pipelines", "Description")
+	for _, tag := range result.Tags {
+		name := cli.ColorizeTag(tag.Name, tag.Color)
+		if !tag.Registered {
+			name += " *"
+			unregistered++
+		}
+		description := tag.Description
+		if description == "" {
+			description = "-"
+		}
+		table.Row(name, tag.Color, fmt.Sprintf("%d", tag.Components), fmt.Sprintf("%d", tag.Pipelines), description)
+	}
+	table.Flush()
+
+	fmt.Fprintf(cmd.OutOrStdout(), "\nTotal: %s\n", countNoun(result.Count, "tag"))
+	if unregistered > 0 {
+		fmt.Fprintf(cmd.OutOrStdout(), "* used but not in the tag registry\n")
+	}
+	return nil
+}
+
+func runTagsShow(cmd *cobra.Command, args []string) error {
+	name := models.NormalizeTagName(args[0])
+
+	allTags, err := collectTags()
+	if err != nil {
+		return err
+	}
+
+	index := openSearchIndex()
+	prompts, contexts, rules, err := loadComponents(index, true)
+	if err != nil {
+		return fmt.Errorf("failed to load components: %w", err)
+	}
+	pipelines, err := loadPipelines(index, true)
+	if err != nil {
+		return fmt.Errorf("failed to load pipelines: %w", err)
+	}
+
+	result := TagShowOutput{TagOutput: TagOutput{Name: name}, Items: []SearchItemOutput{}}
+	found := false
+	for _, tag := range allTags {
+		if tag.Name == name {
+			result.TagOutput = tag
+			found = true
+			break
+		}
+	}
+
+	for _, p := range pipelines {
+		if hasTag(p.Tags, name) {
+			result.Items = append(result.Items, SearchItemOutput{
+				Name:     p.Name,
+				Type:     "pipeline",
+				Tags:     p.Tags,
+				Path:     p.Path,
+				Archived: p.IsArchived,
+			})
+		}
+	}
+	for _, group := range [][]unified.ComponentItem{contexts, prompts, rules} {
+		for _, c := range group {
+			if hasTag(c.Tags, name) {
+				result.Items = append(result.Items, SearchItemOutput{
+					Name:     c.Name,
+					Type:     strings.TrimSuffix(c.CompType, "s"),
+					Tags:     c.Tags,
+					Path:     c.Path,
+					Archived: c.IsArchived,
+				})
+			}
+		}
+	}
+
+	// Tags used only by archived items aren't counted, but are still worth showing
+	if !found && len(result.Items) == 0 {
+		return fmt.Errorf("tag '%s' not found", args[0])
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat != "" && outputFormat != "text" {
+		return cli.OutputResults(cmd.OutOrStdout(), outputFormat, result)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Tag: %s\n", cli.ColorizeTag(result.Name, result.Color))
+	if result.Color != "" {
+		fmt.Fprintf(out, "Color: %s\n", result.Color)
+	}
+	if result.Description != "" {
+		fmt.Fprintf(out, "Description: %s\n", result.Description)
+	}
+	if result.Parent != "" {
+		fmt.Fprintf(out, "Parent: %s\n", result.Parent)
+	}
+	if !result.Registered {
+		fmt.Fprintln(out, "Registered: no")
+	}
+	fmt.Fprintf(out, "Usage: %s, %s\n", countNoun(result.Components, "component"), countNoun(result.Pipelines, "pipeline"))
+
+	if len(result.Items) == 0 {
+		return nil
+	}
+
+	fmt.Fprintln(out)
+	table := cli.NewTableFormatter(out)
+	table.Header("Name", "Type", "Path")
+	for _, item := range result.Items {
+		itemType := item.Type
+		if item.Archived {
+			itemType += " (archived)"
+		}
+		table.Row(item.Name, itemType, item.Path)
+	}
+	table.Flush()
+	return nil
+}
+
+func runTagsAdd(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := models.ValidateTagName(name); err != nil {
+		return fmt.Errorf("invalid tag name: %w", err)
+	}
+
+	paths, err := resolveTagItems(args[1:], tagsItemsQuery)
+	if err != nil {
+		return err
+	}
+
+	registry, err := tags.NewRegistry()
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := confirmTagRewrite(cmd, fmt.Sprintf("Add tag '%s' to %s?", name, countNoun(len(paths), "item")))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		cli.PrintInfo("Add cancelled")
+		return nil
+	}
+
+	result, err := registry.AddTagToItems(name, paths)
+	if err != nil {
+		return fmt.Errorf("failed to add tag: %w", err)
+	}
+
+	return outputTagRewrite(cmd, []string{models.NormalizeTagName(name)}, "", result,
+		fmt.Sprintf("Added tag '%s'", models.NormalizeTagName(name)))
+}
+
+func runTagsRemove(cmd *cobra.Command, args []string) error {
+	name := models.NormalizeTagName(args[0])
+
+	paths, err := resolveTagItems(args[1:], tagsItemsQuery)
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := confirmTagRewrite(cmd, fmt.Sprintf("Remove tag '%s' from %s?", name, countNoun(len(paths), "item")))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		cli.PrintInfo("Remove cancelled")
+		return nil
+	}
+
+	result, err := tags.RemoveTagFromItems(name, paths)
+	if err != nil {
+		return fmt.Errorf("failed to remove tag: %w", err)
+	}
+
+	// Drop the tag from the registry if that was its last use
+	if result.FilesChanged() > 0 {
+		if _, err := tags.CleanupOrphanedTags([]string{name}); err != nil {
+			cli.PrintWarning("Failed to clean up tag registry: %v", err)
+		}
+	}
+
+	return outputTagRewrite(cmd, []string{name}, "", result, fmt.Sprintf("Removed tag '%s'", name))
+}
+
+func runTagsColor(cmd *cobra.Command, args []string) error {
+	color := args[1]
+	if err := models.ValidateTagColor(color); err != nil {
+		return err
+	}
+
+	return updateTagMetadata(args[0], func(tag *models.Tag) {
+		tag.Color = color
+	}, fmt.Sprintf("Set color of tag '%s' to %s", models.NormalizeTagName(args[0]), color))
+}
+
+func runTagsDescribe(cmd *cobra.Command, args []string) error {
+	description := strings.Join(args[1:], " ")
+
+	summary := fmt.Sprintf("Updated description of tag '%s'", models.NormalizeTagName(args[0]))
+	if description == "" {
+		summary = fmt.Sprintf("Cleared description of tag '%s'", models.NormalizeTagName(args[0]))
+	}
+
+	return updateTagMetadata(args[0], func(tag *models.Tag) {
+		tag.Description = description
+	}, summary)
+}
+
+func runTagsDelete(cmd *cobra.Command, args []string) error {
+	name := models.NormalizeTagName(args[0])
+
+	registry, err := tags.NewRegistry()
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := confirmTagRewrite(cmd, fmt.Sprintf("Delete tag '%s' from the registry and all files?", name))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		cli.PrintInfo("Deletion cancelled")
+		return nil
+	}
+
+	result, err := registry.DeleteTagEverywhere(name)
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	return outputTagRewrite(cmd, []string{name}, "", result, fmt.Sprintf("Deleted tag '%s'", name))
+}
+
+func runTagsPrune(cmd *cobra.Command, args []string) error {
+	allTags, err := collectTags()
+	if err != nil {
+		return err
+	}
+
+	unused := []string{}
+	for _, tag := range allTags {
+		if tag.Registered && tag.Usage == 0 {
+			unused = append(unused, tag.Name)
+		}
+	}
+
+	result := TagPruneOutput{Removed: unused, DryRun: tagsPruneDryRun}
+	if !tagsPruneDryRun && len(unused) > 0 {
+		confirmed, err := confirmTagRewrite(cmd, fmt.Sprintf("Remove %s from the registry?", countNoun(len(unused), "unused tag")))
+		if err != nil {
+			return err
+		}
+		if !confirmed {
+			cli.PrintInfo("Prune cancelled")
+			return nil
+		}
+
+		removed, err := tags.CleanupOrphanedTags(unused)
+		if err != nil {
+			return fmt.Errorf("failed to prune tags: %w", err)
+		}
+		result.Removed = removed
+		if result.Removed == nil {
+			result.Removed = []string{}
+		}
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat != "" && outputFormat != "text" {
+		return cli.OutputResults(cmd.OutOrStdout(), outputFormat, result)
+	}
+
+	if len(result.Removed) == 0 {
+		cli.PrintInfo("No unused tags")
+		return nil
+	}
+
+	if result.DryRun {
+		cli.PrintInfo("Would remove %s: %s", countNoun(len(result.Removed), "unused tag"), strings.Join(result.Removed, ", "))
+	} else {
+		cli.PrintSuccess("Removed %s: %s", countNoun(len(result.Removed), "unused tag"), strings.Join(result.Removed, ", "))
+	}
+	return nil
+}
+
+func runTagsStats(cmd *cobra.Command, args []string) error {
+	allTags, err := collectTags()
+	if err != nil {
+		return err
+	}
+
+	stats := TagStatsOutput{Total: len(allTags), Top: []TagOutput{}}
+	for _, tag := range allTags {
+		if tag.Registered {
+			stats.Registered++
+		} else {
+			stats.Unregistered++
+		}
+		if tag.Usage == 0 {
+			stats.Unused++
+		}
+		if models.IsHierarchicalTag(tag.Name) {
+			stats.Hierarchical++
+		}
+	}
+
+	sortTagsByUsage(allTags)
+	for _, tag := range allTags {
+		if len(stats.Top) >= tagsStatsTop || tag.Usage == 0 {
+			break
+		}
+		stats.Top = append(stats.Top, tag)
+	}
+
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat != "" && outputFormat != "text" {
+		return cli.OutputResults(cmd.OutOrStdout(), outputFormat, stats)
+	}
+
+	out := cmd.OutOrStdout()
+	fmt.Fprintf(out, "Tags:          %d\n", stats.Total)
+	fmt.Fprintf(out, "Registered:    %d\n", stats.Registered)
+	fmt.Fprintf(out, "Unregistered:  %d\n", stats.Unregistered)
+	fmt.Fprintf(out, "Unused:        %d\n", stats.Unused)
+	fmt.Fprintf(out, "Hierarchical:  %d\n", stats.Hierarchical)
+
+	if len(stats.Top) > 0 {
+		fmt.Fprintln(out, "\nMOST USED")
+		fmt.Fprintln(out, strings.Repeat("-", 80))
+		table := cli.NewTableFormatter(out)
+		table.Header("Name", "Components", "Pipelines", "Total")
+		for _, tag := range stats.Top {
+			table.Row(cli.ColorizeTag(tag.Name, tag.Color), fmt.Sprintf("%d", tag.Components), fmt.Sprintf("%d", tag.Pipelines), fmt.Sprintf("%d", tag.Usage))
+		}
+		table.Flush()
+	}
+	return nil
+}
+
+func runTagsRename(cmd *cobra.Command, args []string) error {
+	oldName, newName := args[0], args[1]
+
+	registry, err := tags.NewRegistry()
+	if err != nil {
+		return err
+	}
+
+	confirmed, err := confirmTagRewrite(cmd, fmt.Sprintf("Rename tag '%s' to '%s' in all files?", oldName, newName))
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		cli.PrintInfo("Rename cancelled")
+		return nil
+	}
+
+	result, err := registry.RenameTagEverywhere(oldName, newName)
+	if err != nil {
+		return fmt.Errorf("failed to rename tag: %w", err)
+	}
+
+	return outputTagRewrite(cmd, []string{oldName}, newName, result,
+		fmt.Sprintf("Renamed tag '%s' to '%s'", oldName, newName))
+}
+
+func runTagsMerge(cmd *cobra.Command, args []string) error {
+	registry, err := tags.NewRegistry()
+	if err != nil {
+		return err
+	}
+
+	prompt := fmt.Sprintf("Merge %s into '%s' in all files?", quoteTags(args), tagsMergeInto)
+	confirmed, err := confirmTagRewrite(cmd, prompt)
+	if err != nil {
+		return err
+	}
+	if !confirmed {
+		cli.PrintInfo("Merge cancelled")
+		return nil
+	}
+
+	result, err := registry.MergeTagsEverywhere(args, tagsMergeInto)
+	if err != nil {
+		return fmt.Errorf("failed to merge tags: %w", err)
+	}
+
+	return outputTagRewrite(cmd, args, tagsMergeInto, result,
+		fmt.Sprintf("Merged %s into '%s'", quoteTags(args), tagsMergeInto))
+}
+
+// confirmTagRewrite asks before rewriting files unless --yes was given
+func confirmTagRewrite(cmd *cobra.Command, prompt string) (bool, error) {
+	skipConfirm, _ := cmd.Flags().GetBool("yes")
+	if skipConfirm {
+		return true, nil
+	}
+	return cli.Confirm(prompt, false)
+}
+
+func outputTagRewrite(cmd *cobra.Command, sources []string, target string, result *files.TagRewriteResult, summary string) error {
+	outputFormat, _ := cmd.Flags().GetString("output")
+	if outputFormat != "" && outputFormat != "text" {
+		return cli.OutputResults(cmd.OutOrStdout(), outputFormat, TagRewriteOutput{
+			Tags:       sources,
+			Target:     target,
+			Components: result.Components,
+			Pipelines:  result.Pipelines,
+			Files:      result.FilesChanged(),
+		})
+	}
+
+	cli.PrintSuccess("%s: %s changed (%s, %s)",
+		summary,
+		countNoun(result.FilesChanged(), "file"),
+		countNoun(len(result.Components), "component"),
+		countNoun(len(result.Pipelines), "pipeline"))
+
+	verbose, _ := cmd.Flags().GetBool("verbose")
+	if verbose {
+		for _, path := range result.Components {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", path)
+		}
+		for _, path := range result.Pipelines {
+			fmt.Fprintf(cmd.OutOrStdout(), "  %s\n", path)
+		}
+	}
+	return nil
+}
+
+// collectTags returns registered tags and tags in use but missing from the registry,
+// sorted by name, with their usage by active items
+func collectTags() ([]TagOutput, error) {
+	registry, err := tags.NewRegistry()
+	if err != nil {
+		return nil, err
+	}
+
+	usage, err := tags.GetAllTagUsage()
+	if err != nil {
+		return nil, fmt.Errorf("failed to count tag usage: %w", err)
+	}
+
+	result := []TagOutput{}
+	for name, stats := range usage {
+		tag := TagOutput{
+			Name:       name,
+			Components: stats.ComponentCount,
+			Pipelines:  stats.PipelineCount,
+			Usage:      stats.TotalCount,
+		}
+		if registered, exists := registry.GetTag(name); exists {
+			tag.Registered = true
+			tag.Color = registry.TagColor(name)
+			tag.Description = registered.Description
+			tag.Parent = registered.Parent
+		}
+		result = append(result, tag)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+	return result, nil
+}
+
+// sortTagsByUsage sorts tags by usage, most used first, then by name
+func sortTagsByUsage(tagList []TagOutput) {
+	sort.SliceStable(tagList, func(i, j int) bool {
+		if tagList[i].Usage != tagList[j].Usage {
+			return tagList[i].Usage > tagList[j].Usage
+		}
+		return tagList[i].Name < tagList[j].Name
+	})
+}
+
+// updateTagMetadata changes a tag's registry entry, registering tags that are in
+// use but not yet in the registry
+func updateTagMetadata(name string, update func(tag *models.Tag), summary string) error {
+	normalized := models.NormalizeTagName(name)
+
+	registry, err := tags.NewRegistry()
+	if err != nil {
+		return err
+	}
+
+	tag, exists := registry.GetTag(normalized)
+	if !exists {
+		usage, err := tags.CountTagUsage(normalized)
+		if err != nil || usage.TotalCount == 0 {
+			return fmt.Errorf("tag '%s' not found", name)
+		}
+		newTag := models.NewTag(normalized)
+		tag = &newTag
+	}
+
+	update(tag)
+	if err := registry.AddTag(*tag); err != nil {
+		return err
+	}
+	if err := registry.Save(); err != nil {
+		return err
+	}
+
+	cli.PrintSuccess("%s", summary)
+	return nil
+}
+
+// resolveTagItems turns item references and a search query into paths relative to
+// the .pluqqy directory, without duplicates
+func resolveTagItems(refs []string, query string) ([]string, error) {
+	if len(refs) == 0 && query == "" {
+		return nil, fmt.Errorf("no items given: name items or select them with --query")
+	}
+
+	var paths []string
+	seen := make(map[string]bool)
+	addPath := func(path string) {
+		path = filepath.ToSlash(path)
+		if !seen[path] {
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	ctx, err := cli.NewCommandContext()
+	if err != nil {
+		return nil, err
+	}
+	resolver := cli.NewItemResolver(ctx.ProjectPath)
+	for _, ref := range refs {
+		_, itemPath, err := resolver.ResolveItem(ref)
+		if err != nil {
+			return nil, err
+		}
+		addPath(resolver.ConvertToRelativePath(itemPath))
+	}
+
+	if query != "" {
+		matches, err := searchTagItems(query)
+		if err != nil {
+			return nil, err
+		}
+		for _, path := range matches {
+			addPath(path)
+		}
+	}
+
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no items match query: %s", query)
+	}
+	return paths, nil
+}
+
+// searchTagItems returns the paths, relative to the .pluqqy directory, of every
+// item matching a search query
+func searchTagItems(query string) ([]string, error) {
+	query, err := unified.ExpandSavedSearches(query)
+	if err != nil {
+		return nil, err
+	}
+	if err := validateQuery(query); err != nil {
+		return nil, err
+	}
+
+	searchHelper := unified.NewSearchHelper()
+	includeArchived := unified.ShouldIncludeArchived(query)
+	searchHelper.SetSearchOptions(includeArchived, 100000, "relevance")
+	index := openSearchIndex()
+	searchHelper.GetUnifiedManager().SetIndex(index)
+
+	prompts, contexts, rules, err := loadComponents(index, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load components: %w", err)
+	}
+	pipelines, err := loadPipelines(index, includeArchived)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load pipelines: %w", err)
+	}
+
+	prompts, contexts, rules, pipelines, err = searchHelper.UnifiedFilterAll(query, prompts, contexts, rules, pipelines)
+	if err != nil {
+		return nil, fmt.Errorf("search failed: %w", err)
+	}
+
+	var paths []string
+	for _, group := range [][]unified.ComponentItem{prompts, contexts, rules} {
+		for _, c := range group {
+			path := c.Path
+			if c.IsArchived {
+				path = filepath.Join(files.ArchiveDir, path)
+			}
+			paths = append(paths, path)
+		}
+	}
+	for _, p := range pipelines {
+		// Pipeline paths already include the pipelines or archive directory
+		paths = append(paths, p.Path)
+	}
+	return paths, nil
+}
+
+// hasTag reports whether a tag list contains the normalized tag name
+func hasTag(tagList []string, normalizedName string) bool {
+	for _, tag := range tagList {
+		if models.NormalizeTagName(tag) == normalizedName {
+			return true
+		}
+	}
+	return false
+}
+
+func quoteTags(names []string) string {
+	quoted := make([]string, len(names))
+	for i, name := range names {
+		quoted[i] = "'" + name + "'"
+	}
+	return strings.Join(quoted, ", ")
+}
+
+// countNoun formats a count with a singular or plural noun, e.g. "1 file" or "3 files"
+func countNoun(count int, noun string) string {
+	if count == 1 {
+		return fmt.Sprintf("%d %s", count, noun)
+	}
+	return fmt.Sprintf("%d %ss", count, noun)
+}
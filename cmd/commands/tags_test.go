@@ -0,0 +1,194 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setupTagsProject creates a project with tagged components and pipelines
+func setupTagsProject(t *testing.T) {
+	tempDir := t.TempDir()
+	oldDir, _ := os.Getwd()
+	os.Chdir(tempDir)
+	t.Cleanup(func() { os.Chdir(oldDir) })
+
+	require.NoError(t, os.MkdirAll(".pluqqy/components/prompts", 0755))
+	require.NoError(t, os.MkdirAll(".pluqqy/components/rules", 0755))
+	require.NoError(t, os.MkdirAll(".pluqqy/pipelines", 0755))
+
+	require.NoError(t, os.WriteFile(".pluqqy/components/prompts/schema.md",
+		[]byte("---\ntags: [api]\n---\nDescribe the GraphQL schema"), 0644))
+	require.NoError(t, os.WriteFile(".pluqqy/components/rules/style.md",
+		[]byte("---\ntags: [api, style]\n---\nFollow the style guide"), 0644))
+	require.NoError(t, os.WriteFile(".pluqqy/pipelines/review.yaml",
+		[]byte("name: Review\ncomponents: []\ntags: [style]\n"), 0644))
+	require.NoError(t, os.WriteFile(".pluqqy/tags.yaml",
+		[]byte("tags:\n  - name: api\n    color: '#3498db'\n  - name: unused\n"), 0644))
+}
+
+// runTagsCommand runs a tags subcommand with JSON output and returns what it printed
+func runTagsCommand(t *testing.T, args ...string) (string, error) {
+	t.Helper()
+	cmd := NewTagsCommand()
+	cmd.PersistentFlags().StringP("output", "o", "json", "")
+	cmd.PersistentFlags().BoolP("yes", "y", true, "")
+	cmd.PersistentFlags().BoolP("verbose", "v", false, "")
+
+	buf := new(bytes.Buffer)
+	cmd.SetOut(buf)
+	cmd.SetErr(buf)
+	cmd.SetArgs(args)
+
+	err := cmd.Execute()
+	return buf.String(), err
+}
+
+func TestTagsList(t *testing.T) {
+	setupTagsProject(t)
+
+	output, err := runTagsCommand(t, "list")
+	require.NoError(t, err)
+
+	var result TagListResult
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	require.Len(t, result.Tags, 3)
+
+	byName := map[string]TagOutput{}
+	for _, tag := range result.Tags {
+		byName[tag.Name] = tag
+	}
+	assert.Equal(t, 2, byName["api"].Components)
+	assert.True(t, byName["api"].Registered)
+	assert.Equal(t, 1, byName["style"].Pipelines)
+	assert.False(t, byName["style"].Registered)
+	assert.Equal(t, 0, byName["unused"].Usage)
+
+	output, err = runTagsCommand(t, "list", "--unused")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	require.Len(t, result.Tags, 1)
+	assert.Equal(t, "unused", result.Tags[0].Name)
+}
+
+func TestTagsList_InheritedColor(t *testing.T) {
+	setupTagsProject(t)
+	require.NoError(t, os.WriteFile(".pluqqy/components/prompts/v2.md",
+		[]byte("---\ntags: [api/v2]\n---\nDescribe version 2"), 0644))
+	require.NoError(t, os.WriteFile(".pluqqy/tags.yaml",
+		[]byte("tags:\n  - name: api\n    color: '#3498db'\n  - name: api/v2\n    parent: api\n"), 0644))
+
+	output, err := runTagsCommand(t, "show", "api/v2")
+	require.NoError(t, err)
+
+	var result TagShowOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.True(t, result.Registered)
+	assert.Equal(t, "#3498db", result.Color, "a child tag without a color of its own shows its parent's")
+}
+
+func TestTagsAddAndRemove(t *testing.T) {
+	setupTagsProject(t)
+
+	output, err := runTagsCommand(t, "add", "graphql", "--query", "content:graphql")
+	require.NoError(t, err)
+
+	var result TagRewriteOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, []string{"components/prompts/schema.md"}, result.Components)
+
+	output, err = runTagsCommand(t, "add", "reviewed", "style", "review")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, 2, result.Files)
+
+	content, _ := os.ReadFile(".pluqqy/pipelines/review.yaml")
+	assert.Contains(t, string(content), "reviewed")
+
+	output, err = runTagsCommand(t, "remove", "reviewed", "style", "review")
+	require.NoError(t, err)
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, 2, result.Files)
+
+	// The tag is gone from the registry once nothing uses it
+	registry, _ := os.ReadFile(".pluqqy/tags.yaml")
+	assert.NotContains(t, string(registry), "reviewed")
+	assert.Contains(t, string(registry), "graphql")
+
+	_, err = runTagsCommand(t, "add", "reviewed")
+	assert.Error(t, err, "add without items or query should fail")
+}
+
+func TestTagsColorAndDescribe(t *testing.T) {
+	setupTagsProject(t)
+
+	_, err := runTagsCommand(t, "color", "style", "#ff0000")
+	require.NoError(t, err)
+	_, err = runTagsCommand(t, "describe", "style", "Style", "rules")
+	require.NoError(t, err)
+
+	output, err := runTagsCommand(t, "show", "style")
+	require.NoError(t, err)
+
+	var result TagShowOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, "#ff0000", result.Color)
+	assert.Equal(t, "Style rules", result.Description)
+	assert.True(t, result.Registered)
+	assert.Len(t, result.Items, 2)
+
+	_, err = runTagsCommand(t, "color", "style", "red")
+	assert.Error(t, err)
+	_, err = runTagsCommand(t, "color", "missing", "#ffffff")
+	assert.Error(t, err)
+}
+
+func TestTagsDeleteAndPrune(t *testing.T) {
+	setupTagsProject(t)
+
+	output, err := runTagsCommand(t, "delete", "api")
+	require.NoError(t, err)
+
+	var result TagRewriteOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &result))
+	assert.Equal(t, 2, result.Files)
+
+	content, _ := os.ReadFile(".pluqqy/components/rules/style.md")
+	assert.NotContains(t, string(content), "api")
+
+	output, err = runTagsCommand(t, "prune", "--dry-run")
+	require.NoError(t, err)
+
+	var prune TagPruneOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &prune))
+	assert.Equal(t, []string{"unused"}, prune.Removed)
+	assert.True(t, prune.DryRun)
+
+	registry, _ := os.ReadFile(".pluqqy/tags.yaml")
+	assert.Contains(t, string(registry), "unused")
+
+	_, err = runTagsCommand(t, "prune")
+	require.NoError(t, err)
+	registry, _ = os.ReadFile(".pluqqy/tags.yaml")
+	assert.NotContains(t, string(registry), "unused")
+}
+
+func TestTagsStats(t *testing.T) {
+	setupTagsProject(t)
+
+	output, err := runTagsCommand(t, "stats", "--top", "1")
+	require.NoError(t, err)
+
+	var stats TagStatsOutput
+	require.NoError(t, json.Unmarshal([]byte(output), &stats))
+	assert.Equal(t, 3, stats.Total)
+	assert.Equal(t, 2, stats.Registered)
+	assert.Equal(t, 1, stats.Unregistered)
+	assert.Equal(t, 1, stats.Unused)
+	require.Len(t, stats.Top, 1)
+	assert.Equal(t, "api", stats.Top[0].Name)
+}
@@ -29,6 +29,7 @@ type PipelineUsage struct {
 	Position        int    `json:"position" yaml:"position"`
 	TotalComponents int    `json:"total_components" yaml:"total_components"`
 	IsArchived      bool   `json:"is_archived,omitempty" yaml:"is_archived,omitempty"`
+	IncludedVia     string `json:"included_via,omitempty" yaml:"included_via,omitempty"`
 }
 
 var (
@@ -117,57 +118,36 @@ func findComponentByName(componentName string) (string, string, error) {
 	var searchTypes []string
 	var searchName string
 	
-	// Check if the user specified a type prefix (e.g., "prompts/greeting" or "contexts/api-docs")
-	if strings.Contains(componentName, "/") {
-		parts := strings.SplitN(componentName, "/", 2)
-		typePrefix := parts[0]
-		searchName = parts[1]
-		
-		// Map singular or plural forms to the correct directory name
-		switch typePrefix {
-		case "prompt", "prompts":
-			searchTypes = []string{"prompts"}
-		case "context", "contexts":
-			searchTypes = []string{"contexts"}
-		case "rule", "rules":
-			searchTypes = []string{"rules"}
-		default:
-			return "", "", fmt.Errorf("unknown component type: %s", typePrefix)
+	// Check if the user specified a type prefix (e.g., "prompts/greeting" or "contexts/backend/api-docs")
+	if parts := strings.SplitN(componentName, "/", 2); len(parts) == 2 {
+		if ct, ok := files.LookupComponentType(parts[0]); ok {
+			searchTypes = []string{ct.DirName()}
+			searchName = parts[1]
 		}
-	} else {
-		// No type specified, search all types
-		searchTypes = []string{"prompts", "contexts", "rules"}
-		searchName = componentName
 	}
-	
-	// Add .md extension if not present
-	if !strings.HasSuffix(searchName, ".md") {
-		searchName = searchName + ".md"
+	if searchTypes == nil {
+		// No type specified, search all types (the name may still include a folder)
+		searchTypes = files.ComponentTypeDirs()
+		searchName = componentName
 	}
 	
-	// Search in specified component types
+	// Search in specified component types, including nested folders
 	for _, compType := range searchTypes {
 		// Check regular components
 		componentDir := filepath.Join(files.PluqqyDir, "components", compType)
-		fullPath := filepath.Join(componentDir, searchName)
-		
-		if _, err := os.Stat(fullPath); err == nil {
+		if matches := files.FindNestedFiles(componentDir, searchName, ".md"); len(matches) > 0 {
 			// Found it!
-			relativePath := filepath.Join("components", compType, searchName)
-			typeSingular := strings.TrimSuffix(compType, "s")
-			return relativePath, typeSingular, nil
+			relativePath, _ := filepath.Rel(files.PluqqyDir, matches[0])
+			return relativePath, componentTypeSingular(compType), nil
 		}
 		
 		// Check archived components if --all flag is set
 		if usageShowAll {
 			archivedDir := filepath.Join(files.PluqqyDir, "archive", "components", compType)
-			archivedPath := filepath.Join(archivedDir, searchName)
-			
-			if _, err := os.Stat(archivedPath); err == nil {
+			if matches := files.FindNestedFiles(archivedDir, searchName, ".md"); len(matches) > 0 {
 				// Found in archive
-				relativePath := filepath.Join("archive", "components", compType, searchName)
-				typeSingular := strings.TrimSuffix(compType, "s")
-				return relativePath, typeSingular, nil
+				relativePath, _ := filepath.Rel(files.PluqqyDir, matches[0])
+				return relativePath, componentTypeSingular(compType), nil
 			}
 		}
 	}
@@ -179,6 +159,14 @@ func findComponentByName(componentName string) (string, string, error) {
 	return "", "", fmt.Errorf("component not found: %s", componentName)
 }
 
+// componentTypeSingular returns the singular type name for a component directory
+func componentTypeSingular(dir string) string {
+	if ct, ok := models.ComponentTypeForDir(dir); ok {
+		return strings.TrimSuffix(ct.Name, "s")
+	}
+	return strings.TrimSuffix(dir, "s")
+}
+
 func getComponentType(path string) string {
 	if strings.Contains(path, "prompts") {
 		return "prompt"
@@ -227,29 +215,74 @@ func findComponentUsage(componentPath string, includeArchived bool) ([]PipelineU
 			continue // Skip pipelines that can't be read
 		}
 		
-		// Check if this pipeline uses the component
-		for _, comp := range pipeline.Components {
-			// Normalize both paths - remove any ../ prefix and clean
-			normalizedCompPath := filepath.Clean(comp.Path)
-			if strings.HasPrefix(normalizedCompPath, "../") {
-				normalizedCompPath = strings.TrimPrefix(normalizedCompPath, "../")
-			}
-			if normalizedCompPath == componentPath {
-				usage = append(usage, PipelineUsage{
-					Name:            pipeline.Name,
-					Path:            pipelinePath,
-					Position:        comp.Order,
-					TotalComponents: len(pipeline.Components),
-					IsArchived:      isArchived,
-				})
-				break
-			}
+		// Check if this pipeline uses the component directly
+		if comp, found := findComponentRef(pipeline.Components, componentPath); found {
+			usage = append(usage, PipelineUsage{
+				Name:            pipeline.Name,
+				Path:            pipelinePath,
+				Position:        comp.Order,
+				TotalComponents: len(pipeline.Components),
+				IsArchived:      isArchived,
+			})
+			continue
+		}
+		
+		// Check if the component comes from an included pipeline
+		if len(pipeline.Extends) == 0 {
+			continue
+		}
+		resolved, err := files.ResolvePipelineIncludes(pipeline)
+		if err != nil {
+			continue // Skip pipelines with broken includes
+		}
+		if comp, found := findComponentRef(resolved.Components, componentPath); found {
+			usage = append(usage, PipelineUsage{
+				Name:            pipeline.Name,
+				Path:            pipelinePath,
+				Position:        comp.Order,
+				TotalComponents: len(resolved.Components),
+				IsArchived:      isArchived,
+				IncludedVia:     findIncludeSource(pipeline, componentPath),
+			})
 		}
 	}
 	
 	return usage, nil
 }
 
+// findComponentRef finds the reference to a component in a component list
+func findComponentRef(components []models.ComponentRef, componentPath string) (models.ComponentRef, bool) {
+	for _, comp := range components {
+		// Normalize both paths - remove any ../ prefix and clean
+		normalizedCompPath := filepath.Clean(comp.Path)
+		if strings.HasPrefix(normalizedCompPath, "../") {
+			normalizedCompPath = strings.TrimPrefix(normalizedCompPath, "../")
+		}
+		if normalizedCompPath == componentPath {
+			return comp, true
+		}
+	}
+	return models.ComponentRef{}, false
+}
+
+// findIncludeSource returns the name of the first included pipeline that provides the component
+func findIncludeSource(pipeline *models.Pipeline, componentPath string) string {
+	for _, ref := range pipeline.Extends {
+		included, err := files.ReadPipeline(files.PipelineRefFilename(ref))
+		if err != nil {
+			continue
+		}
+		resolved, err := files.ResolvePipelineIncludes(included)
+		if err != nil {
+			continue
+		}
+		if _, found := findComponentRef(resolved.Components, componentPath); found {
+			return strings.TrimSuffix(files.PipelineRefFilename(ref), ".yaml")
+		}
+	}
+	return ""
+}
+
 func printUsageTable(cmd *cobra.Command, result UsageResult) error {
 	out := cmd.OutOrStdout()
 	
@@ -275,6 +308,9 @@ func printUsageTable(cmd *cobra.Command, result UsageResult) error {
 		
 		fmt.Fprintf(out, "  • %s%s\n", usage.Name, status)
 		fmt.Fprintf(out, "    Position: %d of %d\n", usage.Position, usage.TotalComponents)
+		if usage.IncludedVia != "" {
+			fmt.Fprintf(out, "    Included via: %s\n", usage.IncludedVia)
+		}
 		fmt.Fprintf(out, "    Path: %s\n", usage.Path)
 		fmt.Fprintln(out)
 	}
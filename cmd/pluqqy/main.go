@@ -1,6 +1,7 @@
 package main
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -166,8 +167,16 @@ func init() {
 	rootCmd.AddCommand(commands.NewDeleteCommand())
 	rootCmd.AddCommand(commands.NewUsageCommand())
 	
+	// Tag commands
+	rootCmd.AddCommand(commands.NewTagsCommand())
+	
 	// Search commands
 	rootCmd.AddCommand(commands.NewSearchCommand())
+	rootCmd.AddCommand(commands.NewSearchesCommand())
+	
+	// Maintenance commands
+	rootCmd.AddCommand(commands.NewDoctorCommand())
+	rootCmd.AddCommand(commands.NewLintCommand())
 	
 	// Examples command
 	rootCmd.AddCommand(commands.NewExamplesCommand())
@@ -175,6 +184,11 @@ func init() {
 
 func main() {
 	if err := rootCmd.Execute(); err != nil {
+		// Commands such as doctor report their own findings and only set the exit code
+		var exitErr *commands.ExitCodeError
+		if errors.As(err, &exitErr) {
+			os.Exit(exitErr.Code)
+		}
 		fmt.Fprintf(os.Stderr, "Error: Command execution failed: %v\n", err)
 		os.Exit(1)
 	}
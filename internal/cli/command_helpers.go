@@ -149,6 +149,15 @@ func (r *ItemResolver) FindPipeline(name string) (string, error) {
 		return pipelinePath, nil
 	}
 	
+	// Search nested folders by name (hotfix finds release/hotfix.yaml)
+	matches := files.FindNestedFiles(filepath.Join(r.ProjectPath, "pipelines"), name, ".yaml")
+	if len(matches) == 1 {
+		return matches[0], nil
+	}
+	if len(matches) > 1 {
+		return "", fmt.Errorf("multiple pipelines found with name '%s'. Please include the folder (e.g., release/%s)", name, name)
+	}
+	
 	return "", fmt.Errorf("pipeline '%s' not found", name)
 }
 
@@ -183,6 +192,16 @@ func (r *ItemResolver) ConvertToRelativePath(absolutePath string) string {
 	return strings.TrimPrefix(absolutePath, r.ProjectPath+string(os.PathSeparator))
 }
 
+// PipelineRelativePath converts a pipeline path to one relative to the pipelines
+// directory, keeping nested folders (release/hotfix.yaml)
+func (r *ItemResolver) PipelineRelativePath(pipelinePath string) string {
+	rel, err := filepath.Rel(filepath.Join(r.ProjectPath, "pipelines"), pipelinePath)
+	if err != nil || strings.HasPrefix(rel, "..") {
+		return filepath.Base(pipelinePath)
+	}
+	return filepath.ToSlash(rel)
+}
+
 // ComponentFinder specialized component search logic
 type ComponentFinder struct {
 	ProjectPath string
@@ -195,36 +214,25 @@ func NewComponentFinder(projectPath string) *ComponentFinder {
 	}
 }
 
-// FindByReference finds a component by reference (name or path)
+// FindByReference finds a component by reference: a name (db), a path inside the
+// type directory (backend/db) or a type-qualified path (contexts/backend/db)
 func (f *ComponentFinder) FindByReference(ref string) (string, error) {
-	// If ref contains a slash, treat it as a path hint
+	// If ref starts with a component type, look only in that type
 	if strings.Contains(ref, "/") {
 		parts := strings.SplitN(ref, "/", 2)
-		componentType := parts[0]
-		componentName := parts[1]
-		
-		// Normalize component type
-		switch strings.ToLower(componentType) {
-		case "context", "contexts":
-			componentType = "contexts"
-		case "prompt", "prompts":
-			componentType = "prompts"
-		case "rule", "rules":
-			componentType = "rules"
-		default:
-			// Try as-is for other types
-		}
-		
-		// Add .md extension if not present
-		if !strings.HasSuffix(componentName, ".md") {
-			componentName += ".md"
-		}
-		
-		path := filepath.Join(f.ProjectPath, "components", componentType, componentName)
-		if _, err := os.Stat(path); err == nil {
-			return path, nil
+		if ct, ok := lookupComponentType(parts[0]); ok {
+			typeDir := filepath.Join(f.ProjectPath, "components", ct.DirName())
+			matches := files.FindNestedFiles(typeDir, parts[1], ".md")
+			switch len(matches) {
+			case 0:
+				return "", fmt.Errorf("component not found: %s", ref)
+			case 1:
+				return matches[0], nil
+			default:
+				return "", fmt.Errorf("multiple components found matching '%s'. Please include the folder (e.g., %s/%s)",
+					ref, parts[0], f.relativeToType(matches[0]))
+			}
 		}
-		return "", fmt.Errorf("component not found: %s", ref)
 	}
 	
 	// Search all component types
@@ -238,72 +246,46 @@ func (f *ComponentFinder) FindByReference(ref string) (string, error) {
 	}
 	
 	if len(matches) > 1 {
-		return "", fmt.Errorf("multiple components found with name '%s'. Please specify the type (e.g., contexts/%s)", ref, ref)
+		return "", fmt.Errorf("multiple components found with name '%s'. Please specify the type or folder (e.g., contexts/%s)", ref, ref)
 	}
 	
 	return matches[0], nil
 }
 
-// SearchAllTypes searches for a component across all types
+// relativeToType returns a component path relative to its type directory without the extension
+func (f *ComponentFinder) relativeToType(path string) string {
+	rel, err := filepath.Rel(filepath.Join(f.ProjectPath, "components"), path)
+	if err != nil {
+		return path
+	}
+	parts := strings.SplitN(filepath.ToSlash(rel), "/", 2)
+	return strings.TrimSuffix(parts[len(parts)-1], ".md")
+}
+
+// SearchAllTypes searches for a component across all types, including nested folders
 func (f *ComponentFinder) SearchAllTypes(name string) ([]string, error) {
 	var matches []string
-	componentTypes := []string{"contexts", "prompts", "rules"}
 	
-	for _, ct := range componentTypes {
-		// Try with .md extension
-		path := filepath.Join(f.ProjectPath, "components", ct, name+".md")
-		if _, err := os.Stat(path); err == nil {
-			matches = append(matches, path)
-		}
-		
-		// Try without extension (in case user included it)
-		if strings.HasSuffix(name, ".md") {
-			path = filepath.Join(f.ProjectPath, "components", ct, name)
-			if _, err := os.Stat(path); err == nil {
-				// Avoid duplicates
-				alreadyAdded := false
-				for _, m := range matches {
-					if m == path {
-						alreadyAdded = true
-						break
-					}
-				}
-				if !alreadyAdded {
-					matches = append(matches, path)
-				}
-			}
-		}
+	for _, ct := range files.ComponentTypeDirs() {
+		typeDir := filepath.Join(f.ProjectPath, "components", ct)
+		matches = append(matches, files.FindNestedFiles(typeDir, name, ".md")...)
 	}
 	
 	return matches, nil
 }
 
-// SearchInArchive searches for items in the archive directory
+// SearchInArchive searches for items in the archive directory, including nested folders
 func (f *ComponentFinder) SearchInArchive(name string) ([]string, error) {
 	var matches []string
-	componentTypes := []string{"contexts", "prompts", "rules"}
 	
 	// Check archived components
-	for _, ct := range componentTypes {
-		archivePath := filepath.Join(f.ProjectPath, "archive", "components", ct, name+".md")
-		if _, err := os.Stat(archivePath); err == nil {
-			matches = append(matches, archivePath)
-		}
-		
-		// Also check without extension if user included it
-		if strings.HasSuffix(name, ".md") {
-			archivePath = filepath.Join(f.ProjectPath, "archive", "components", ct, name)
-			if _, err := os.Stat(archivePath); err == nil {
-				matches = append(matches, archivePath)
-			}
-		}
+	for _, ct := range files.ComponentTypeDirs() {
+		typeDir := filepath.Join(f.ProjectPath, "archive", "components", ct)
+		matches = append(matches, files.FindNestedFiles(typeDir, name, ".md")...)
 	}
 	
 	// Check archived pipelines
-	pipelineArchivePath := filepath.Join(f.ProjectPath, "archive", "pipelines", name+".yaml")
-	if _, err := os.Stat(pipelineArchivePath); err == nil {
-		matches = append(matches, pipelineArchivePath)
-	}
+	matches = append(matches, files.FindNestedFiles(filepath.Join(f.ProjectPath, "archive", "pipelines"), name, ".yaml")...)
 	
 	return matches, nil
 }
\ No newline at end of file
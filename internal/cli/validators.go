@@ -5,35 +5,36 @@ import (
 	"os"
 	"path/filepath"
 	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 )
 
-// ValidateComponentType validates a component type string
+// ValidateComponentType validates a component type string, including custom
+// types declared in the project settings
 func ValidateComponentType(t string) error {
-	validTypes := []string{"context", "contexts", "prompt", "prompts", "rule", "rules"}
-	normalized := strings.ToLower(t)
-	
-	for _, valid := range validTypes {
-		if normalized == valid {
-			return nil
-		}
+	if _, ok := lookupComponentType(t); ok {
+		return nil
 	}
-	
-	return fmt.Errorf("invalid component type: %s (must be: context, prompt, or rule)", t)
+
+	var names []string
+	for _, ct := range files.ComponentTypes() {
+		names = append(names, strings.TrimSuffix(ct.Name, "s"))
+	}
+	return fmt.Errorf("invalid component type: %s (must be one of: %s)", t, strings.Join(names, ", "))
 }
 
 // NormalizeComponentType converts type variants to standard form
 func NormalizeComponentType(t string) string {
-	normalized := strings.ToLower(t)
-	switch normalized {
-	case "context", "contexts":
-		return "contexts"
-	case "prompt", "prompts":
-		return "prompts"
-	case "rule", "rules":
-		return "rules"
-	default:
-		return "contexts" // default fallback
+	if ct, ok := lookupComponentType(t); ok {
+		return ct.Name
 	}
+	return "contexts" // default fallback
+}
+
+// lookupComponentType finds a built-in or custom component type by singular or plural name
+func lookupComponentType(t string) (models.ComponentTypeConfig, bool) {
+	return files.LookupComponentType(t)
 }
 
 // ValidateFilePath validates that a file path exists and is a file
@@ -131,4 +132,18 @@ func Contains(slice []string, item string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
+
+// ParseVariableFlags parses repeated --var key=value flags into a map
+func ParseVariableFlags(flags []string) (map[string]string, error) {
+	variables := make(map[string]string)
+	for _, flag := range flags {
+		name, value, found := strings.Cut(flag, "=")
+		name = strings.TrimSpace(name)
+		if !found || name == "" {
+			return nil, fmt.Errorf("invalid variable %q (expected name=value)", flag)
+		}
+		variables[name] = value
+	}
+	return variables, nil
+}
@@ -0,0 +1,202 @@
+package composer
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// truncationNote marks a component that was shortened to fit the token budget
+const truncationNote = "_[Truncated to fit the token budget]_"
+
+// BudgetCut records a component that was dropped or truncated to fit the token budget
+type BudgetCut struct {
+	Path          string
+	Priority      int
+	TokensRemoved int
+	Dropped       bool // false when the component was truncated
+}
+
+// BudgetReport describes how a pipeline was fitted to its token budget
+type BudgetReport struct {
+	MaxTokens    int
+	Strategy     string
+	TokensBefore int
+	TokensAfter  int
+	Cuts         []BudgetCut
+}
+
+// Trimmed reports whether any component was cut
+func (r *BudgetReport) Trimmed() bool {
+	return r != nil && len(r.Cuts) > 0
+}
+
+// Summary describes the cuts in a single line, e.g. for status messages
+func (r *BudgetReport) Summary() string {
+	if !r.Trimmed() {
+		return ""
+	}
+
+	parts := make([]string, len(r.Cuts))
+	for i, cut := range r.Cuts {
+		action := "truncated"
+		if cut.Dropped {
+			action = "dropped"
+		}
+		parts[i] = fmt.Sprintf("%s %s (~%d tokens)", action, cut.Path, cut.TokensRemoved)
+	}
+	return fmt.Sprintf("Token budget %d: %s", r.MaxTokens, strings.Join(parts, ", "))
+}
+
+// EffectiveTokenBudget returns the token budget and strategy for a pipeline.
+// The pipeline's own values take precedence over the settings defaults.
+// A budget of 0 means the pipeline has no budget.
+func EffectiveTokenBudget(pipeline *models.Pipeline, settings *models.Settings) (int, string) {
+	maxTokens := settings.Output.Budget.MaxTokens
+	strategy := settings.Output.Budget.Strategy
+
+	if pipeline != nil {
+		if pipeline.MaxTokens != 0 {
+			maxTokens = pipeline.MaxTokens
+		}
+		if pipeline.BudgetStrategy != "" {
+			strategy = pipeline.BudgetStrategy
+		}
+	}
+
+	if maxTokens < 0 {
+		maxTokens = 0
+	}
+	if strategy == "" {
+		strategy = models.BudgetStrategyFail
+	}
+	return maxTokens, strategy
+}
+
+// applyTokenBudget fits the components into the pipeline's token budget. render
+// produces the composed output for a set of components so that headings and
+// spacing are counted too. Components are cut lowest priority first; among equal
+// priorities the one that comes last in the pipeline goes first.
+func applyTokenBudget(pipeline *models.Pipeline, settings *models.Settings, components []componentWithContent,
+	render func([]componentWithContent) string) ([]componentWithContent, *BudgetReport, error) {
+
+	maxTokens, strategy := EffectiveTokenBudget(pipeline, settings)
+	if maxTokens == 0 {
+		return components, nil, nil
+	}
+
+	tokens := EstimateTokens(render(components))
+	report := &BudgetReport{
+		MaxTokens:    maxTokens,
+		Strategy:     strategy,
+		TokensBefore: tokens,
+		TokensAfter:  tokens,
+	}
+	if tokens <= maxTokens {
+		return components, report, nil
+	}
+
+	if strategy == models.BudgetStrategyFail {
+		return nil, report, fmt.Errorf("pipeline '%s' is ~%d tokens, over its budget of %d "+
+			"(set budget_strategy to drop or truncate, or raise max_tokens)", pipeline.Name, tokens, maxTokens)
+	}
+	if !models.IsValidBudgetStrategy(strategy) {
+		return nil, report, fmt.Errorf("unknown budget strategy '%s'", strategy)
+	}
+
+	result := make([]componentWithContent, len(components))
+	copy(result, components)
+
+	for _, idx := range cutOrder(result) {
+		original := EstimateTokens(result[idx].content)
+
+		if strategy == models.BudgetStrategyTruncate {
+			if truncated, ok := truncateToFit(result, idx, maxTokens, render); ok {
+				result[idx].content = truncated
+				report.Cuts = append(report.Cuts, BudgetCut{
+					Path:          result[idx].ref.Path,
+					Priority:      result[idx].ref.Priority,
+					TokensRemoved: original - EstimateTokens(truncated),
+				})
+				report.TokensAfter = EstimateTokens(render(result))
+				return result, report, nil
+			}
+		}
+
+		// Drop the component entirely; an empty entry keeps indices stable
+		result[idx].content = ""
+		report.Cuts = append(report.Cuts, BudgetCut{
+			Path:          result[idx].ref.Path,
+			Priority:      result[idx].ref.Priority,
+			TokensRemoved: original,
+			Dropped:       true,
+		})
+
+		report.TokensAfter = EstimateTokens(render(withoutEmpty(result)))
+		if report.TokensAfter <= maxTokens {
+			return withoutEmpty(result), report, nil
+		}
+	}
+
+	return nil, report, fmt.Errorf("pipeline '%s' cannot fit its budget of %d tokens", pipeline.Name, maxTokens)
+}
+
+// cutOrder returns component indices in the order they should be cut
+func cutOrder(components []componentWithContent) []int {
+	order := make([]int, len(components))
+	for i := range order {
+		order[i] = i
+	}
+	sort.SliceStable(order, func(a, b int) bool {
+		ca, cb := components[order[a]].ref, components[order[b]].ref
+		if ca.Priority != cb.Priority {
+			return ca.Priority < cb.Priority
+		}
+		return order[a] > order[b]
+	})
+	return order
+}
+
+// truncateToFit keeps as many leading lines of one component as the budget allows.
+// It returns false when not even a single line fits.
+func truncateToFit(components []componentWithContent, idx int, maxTokens int,
+	render func([]componentWithContent) string) (string, bool) {
+
+	lines := strings.Split(strings.TrimSpace(components[idx].content), "\n")
+	trial := make([]componentWithContent, len(components))
+	copy(trial, components)
+
+	fits := func(n int) (string, bool) {
+		content := strings.TrimSpace(strings.Join(lines[:n], "\n")) + "\n\n" + truncationNote
+		trial[idx].content = content
+		return content, EstimateTokens(render(withoutEmpty(trial))) <= maxTokens
+	}
+
+	// Binary search for the largest number of lines that fits
+	best := ""
+	lo, hi := 1, len(lines)-1
+	for lo <= hi {
+		mid := (lo + hi) / 2
+		if content, ok := fits(mid); ok {
+			best = content
+			lo = mid + 1
+		} else {
+			hi = mid - 1
+		}
+	}
+
+	return best, best != ""
+}
+
+// withoutEmpty removes components whose content was dropped
+func withoutEmpty(components []componentWithContent) []componentWithContent {
+	var result []componentWithContent
+	for _, comp := range components {
+		if comp.content != "" {
+			result = append(result, comp)
+		}
+	}
+	return result
+}
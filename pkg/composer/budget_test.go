@@ -0,0 +1,126 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestEffectiveTokenBudget(t *testing.T) {
+	settings := models.DefaultSettings()
+	settings.Output.Budget = models.TokenBudgetSettings{MaxTokens: 8000, Strategy: models.BudgetStrategyDrop}
+
+	tests := []struct {
+		name         string
+		pipeline     *models.Pipeline
+		wantTokens   int
+		wantStrategy string
+	}{
+		{"settings default", &models.Pipeline{}, 8000, models.BudgetStrategyDrop},
+		{"pipeline override", &models.Pipeline{MaxTokens: 2000, BudgetStrategy: models.BudgetStrategyTruncate}, 2000, models.BudgetStrategyTruncate},
+		{"disabled for pipeline", &models.Pipeline{MaxTokens: -1}, 0, models.BudgetStrategyDrop},
+		{"nil pipeline", nil, 8000, models.BudgetStrategyDrop},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tokens, strategy := EffectiveTokenBudget(tt.pipeline, settings)
+			if tokens != tt.wantTokens || strategy != tt.wantStrategy {
+				t.Errorf("EffectiveTokenBudget() = %d, %s, want %d, %s", tokens, strategy, tt.wantTokens, tt.wantStrategy)
+			}
+		})
+	}
+}
+
+func TestComposePipelineWithSettingsReport_TokenBudget(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatalf("Failed to initialize project structure: %v", err)
+	}
+
+	longContent := strings.Repeat("Background detail that can be shortened when space is tight.\n", 40)
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.RulesDir, "core.md"), "Core rules must always be kept.")
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.ContextsDir, "background.md"), longContent)
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.ContextsDir, "extra.md"), longContent)
+
+	newPipeline := func(maxTokens int, strategy string) *models.Pipeline {
+		return &models.Pipeline{
+			Name:           "budgeted",
+			MaxTokens:      maxTokens,
+			BudgetStrategy: strategy,
+			Components: []models.ComponentRef{
+				{Type: models.ComponentTypeRules, Path: "../components/rules/core.md", Order: 1, Priority: 10},
+				{Type: models.ComponentTypeContext, Path: "../components/contexts/background.md", Order: 2, Priority: 5},
+				{Type: models.ComponentTypeContext, Path: "../components/contexts/extra.md", Order: 3},
+			},
+		}
+	}
+	settings := models.DefaultSettings()
+
+	t.Run("within budget", func(t *testing.T) {
+		output, report, err := ComposePipelineWithSettingsReport(newPipeline(100000, models.BudgetStrategyFail), settings)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if report.Trimmed() {
+			t.Errorf("nothing should be cut: %+v", report.Cuts)
+		}
+		if strings.Count(output, "Background detail") != 80 {
+			t.Error("expected both contexts in full")
+		}
+	})
+
+	t.Run("fail strategy", func(t *testing.T) {
+		_, _, err := ComposePipelineWithSettingsReport(newPipeline(300, models.BudgetStrategyFail), settings)
+		if err == nil || !strings.Contains(err.Error(), "over its budget of 300") {
+			t.Errorf("expected over budget error, got %v", err)
+		}
+	})
+
+	t.Run("drop strategy cuts lowest priority first", func(t *testing.T) {
+		output, report, err := ComposePipelineWithSettingsReport(newPipeline(700, models.BudgetStrategyDrop), settings)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(report.Cuts) != 1 || report.Cuts[0].Path != "../components/contexts/extra.md" || !report.Cuts[0].Dropped {
+			t.Fatalf("expected extra.md to be dropped, got %+v", report.Cuts)
+		}
+		if !strings.Contains(output, "Core rules") || strings.Count(output, "Background detail") != 40 {
+			t.Errorf("unexpected output:\n%s", output)
+		}
+		if report.TokensAfter > 700 || EstimateTokens(output) > 700 {
+			t.Errorf("output is over budget: %d tokens", EstimateTokens(output))
+		}
+	})
+
+	t.Run("truncate strategy shortens instead of dropping", func(t *testing.T) {
+		output, report, err := ComposePipelineWithSettingsReport(newPipeline(400, models.BudgetStrategyTruncate), settings)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !report.Trimmed() {
+			t.Fatal("expected components to be cut")
+		}
+		last := report.Cuts[len(report.Cuts)-1]
+		if last.Dropped || last.Path != "../components/contexts/background.md" {
+			t.Errorf("expected background.md to be truncated last, got %+v", report.Cuts)
+		}
+		if !strings.Contains(output, "Core rules") || !strings.Contains(output, truncationNote) {
+			t.Errorf("unexpected output:\n%s", output)
+		}
+		if EstimateTokens(output) > 400 {
+			t.Errorf("output is over budget: %d tokens", EstimateTokens(output))
+		}
+		if !strings.Contains(report.Summary(), "truncated ../components/contexts/background.md") {
+			t.Errorf("unexpected summary: %s", report.Summary())
+		}
+	})
+}
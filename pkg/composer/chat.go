@@ -0,0 +1,166 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// ChatMessage is one message of a chat-completions request
+type ChatMessage struct {
+	Role    string `json:"role"`
+	Content string `json:"content"`
+}
+
+// anthropicPayload is the Anthropic Messages shape: a top-level system prompt plus messages
+type anthropicPayload struct {
+	System   string        `json:"system,omitempty"`
+	Messages []ChatMessage `json:"messages"`
+}
+
+// openAIPayload is the OpenAI chat-completions shape: the system prompt is the first message
+type openAIPayload struct {
+	Messages []ChatMessage `json:"messages"`
+}
+
+// ChatRole returns the message role for a component type, from settings or the defaults
+func ChatRole(settings *models.Settings, componentType string) string {
+	if role, ok := settings.Output.Chat.Roles[componentType]; ok {
+		return role
+	}
+	if role, ok := models.DefaultChatRoles[componentType]; ok {
+		return role
+	}
+	return models.ChatRoleSystem
+}
+
+// ComposeChatPayload composes a pipeline into a chat-completions request body.
+// Components become system, user or assistant messages according to their type;
+// the shape (anthropic or openai) comes from settings.
+func ComposeChatPayload(pipeline *models.Pipeline, settings *models.Settings) (string, *BudgetReport, error) {
+	if pipeline == nil {
+		return "", nil, fmt.Errorf("cannot compose pipeline: nil pipeline provided")
+	}
+
+	renderer, err := newChatRenderer(settings)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot compose pipeline '%s': %w", pipeline.Name, err)
+	}
+
+	// A payload has no way to resolve @imports, so components are always inlined
+	inline := *pipeline
+	inline.Mode = models.OutputModeInline
+
+	output, report, _, err := composePipelineWithRenderer(&inline, settings, renderer)
+	if err == nil && renderer.err != nil {
+		return "", report, fmt.Errorf("cannot compose pipeline '%s' as a chat payload: %w", pipeline.Name, renderer.err)
+	}
+	return output, report, err
+}
+
+// chatRenderer renders a document as a chat-completions JSON payload
+type chatRenderer struct {
+	shape string
+	roles map[string]string // Section type to message role
+	err   error             // Why the last payload rendered can't be sent, if it can't
+}
+
+// newChatRenderer validates the chat settings and resolves the role for every section
+func newChatRenderer(settings *models.Settings) (*chatRenderer, error) {
+	shape := settings.Output.Chat.Shape
+	if shape == "" {
+		shape = models.ChatShapeAnthropic
+	}
+	if shape != models.ChatShapeAnthropic && shape != models.ChatShapeOpenAI {
+		return nil, fmt.Errorf("invalid chat shape '%s', must be %s or %s", shape, models.ChatShapeAnthropic, models.ChatShapeOpenAI)
+	}
+
+	renderer := &chatRenderer{shape: shape, roles: make(map[string]string)}
+	for _, section := range settings.OutputSections() {
+		role := ChatRole(settings, section.Type)
+		switch role {
+		case models.ChatRoleSystem, models.ChatRoleUser, models.ChatRoleAssistant:
+		default:
+			return nil, fmt.Errorf("invalid chat role '%s' for %s, must be %s, %s or %s",
+				role, section.Type, models.ChatRoleSystem, models.ChatRoleUser, models.ChatRoleAssistant)
+		}
+		renderer.roles[section.Type] = role
+	}
+	return renderer, nil
+}
+
+func (r *chatRenderer) Render(doc *Document) (string, []SourceRange) {
+	var system []string
+	var messages []ChatMessage
+
+	for _, section := range doc.Sections {
+		var parts []string
+		if doc.ShowHeadings {
+			parts = append(parts, section.Heading)
+		}
+		for _, comp := range section.Components {
+			parts = append(parts, strings.TrimSpace(comp.Content))
+		}
+		text := strings.Join(parts, "\n\n")
+
+		role := r.roles[section.Type]
+		if role == models.ChatRoleSystem {
+			system = append(system, text)
+			continue
+		}
+
+		// Merge consecutive sections with the same role into one message
+		if last := len(messages) - 1; last >= 0 && messages[last].Role == role {
+			messages[last].Content += "\n\n" + text
+			continue
+		}
+		messages = append(messages, ChatMessage{Role: role, Content: text})
+	}
+
+	// Keep the model informed about missing content, as the Markdown output does
+	if len(doc.MissingComponents) > 0 {
+		system = append(system, "Warning: The following components could not be loaded:\n- "+strings.Join(doc.MissingComponents, "\n- "))
+	}
+	if len(doc.MissingFiles) > 0 {
+		system = append(system, "Warning: The following file references could not be loaded:\n- "+strings.Join(doc.MissingFiles, "\n- "))
+	}
+
+	// Neither API accepts a conversation without a user message, and the
+	// Anthropic Messages API needs it to open with one
+	r.err = nil
+	hasUser := false
+	for _, message := range messages {
+		hasUser = hasUser || message.Role == models.ChatRoleUser
+	}
+	switch {
+	case !hasUser:
+		r.err = fmt.Errorf("no component becomes a %s message; add a prompt or give a type the %s role", models.ChatRoleUser, models.ChatRoleUser)
+	case r.shape == models.ChatShapeAnthropic && messages[0].Role != models.ChatRoleUser:
+		r.err = fmt.Errorf("the first message is from the %s, but the %s shape needs it to be from the %s", messages[0].Role, models.ChatShapeAnthropic, models.ChatRoleUser)
+	}
+
+	if messages == nil {
+		messages = []ChatMessage{}
+	}
+	systemText := strings.Join(system, "\n\n")
+
+	var payload interface{}
+	switch r.shape {
+	case models.ChatShapeOpenAI:
+		if systemText != "" {
+			messages = append([]ChatMessage{{Role: models.ChatRoleSystem, Content: systemText}}, messages...)
+		}
+		payload = openAIPayload{Messages: messages}
+	default:
+		payload = anthropicPayload{System: systemText, Messages: messages}
+	}
+
+	data, err := json.MarshalIndent(payload, "", "  ")
+	if err != nil {
+		// Only unsupported values can fail to marshal; the payload holds plain strings
+		return "", nil
+	}
+	return string(data) + "\n", nil
+}
@@ -0,0 +1,109 @@
+package composer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestComposeChatPayload_Anthropic(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+	settings := models.DefaultSettings()
+
+	output, _, err := ComposeChatPayload(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ComposeChatPayload failed: %v", err)
+	}
+
+	var payload anthropicPayload
+	if err := json.Unmarshal([]byte(output), &payload); err != nil {
+		t.Fatalf("payload does not parse: %v\n%s", err, output)
+	}
+
+	expectedSystem := "## RULES\n\nUse gofmt.\nKeep functions short.\n\n## CONTEXTS\n\nThe API is REST."
+	if payload.System != expectedSystem {
+		t.Errorf("system = %q, want %q", payload.System, expectedSystem)
+	}
+	if len(payload.Messages) != 1 || payload.Messages[0].Role != "user" || payload.Messages[0].Content != "## PROMPTS\n\nAdd pagination." {
+		t.Errorf("unexpected messages: %+v", payload.Messages)
+	}
+}
+
+func TestComposeChatPayload_OpenAIWithRoles(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+	settings := models.DefaultSettings()
+	settings.Output.Formatting.ShowHeadings = false
+	settings.Output.Chat.Shape = models.ChatShapeOpenAI
+	settings.Output.Chat.Roles = map[string]string{models.ComponentTypeContext: models.ChatRoleUser}
+
+	output, _, err := ComposeChatPayload(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ComposeChatPayload failed: %v", err)
+	}
+	if strings.Contains(output, `"system":`) {
+		t.Errorf("openai shape should not have a top-level system field:\n%s", output)
+	}
+
+	var payload openAIPayload
+	if err := json.Unmarshal([]byte(output), &payload); err != nil {
+		t.Fatalf("payload does not parse: %v\n%s", err, output)
+	}
+
+	// Contexts and prompts are both user messages now, so they merge into one
+	expected := []ChatMessage{
+		{Role: "system", Content: "Use gofmt.\nKeep functions short."},
+		{Role: "user", Content: "The API is REST.\n\nAdd pagination."},
+	}
+	if len(payload.Messages) != len(expected) {
+		t.Fatalf("expected %d messages, got %+v", len(expected), payload.Messages)
+	}
+	for i, msg := range expected {
+		if payload.Messages[i] != msg {
+			t.Errorf("message %d = %+v, want %+v", i, payload.Messages[i], msg)
+		}
+	}
+}
+
+func TestComposeChatPayload_InvalidSettings(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+
+	settings := models.DefaultSettings()
+	settings.Output.Chat.Shape = "gemini"
+	if _, _, err := ComposeChatPayload(pipeline, settings); err == nil || !strings.Contains(err.Error(), "invalid chat shape") {
+		t.Errorf("expected invalid shape error, got %v", err)
+	}
+
+	settings = models.DefaultSettings()
+	settings.Output.Chat.Roles = map[string]string{models.ComponentTypeRules: "developer"}
+	if _, _, err := ComposeChatPayload(pipeline, settings); err == nil || !strings.Contains(err.Error(), "invalid chat role 'developer' for rules") {
+		t.Errorf("expected invalid role error, got %v", err)
+	}
+}
+
+func TestComposeChatPayload_NeedsUserMessage(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+
+	// Without the prompt nothing becomes a user message
+	withoutPrompt := *pipeline
+	withoutPrompt.Components = pipeline.Components[:2]
+	for _, shape := range []string{models.ChatShapeAnthropic, models.ChatShapeOpenAI} {
+		settings := models.DefaultSettings()
+		settings.Output.Chat.Shape = shape
+		if _, _, err := ComposeChatPayload(&withoutPrompt, settings); err == nil || !strings.Contains(err.Error(), "no component becomes a user message") {
+			t.Errorf("%s: expected missing user message error, got %v", shape, err)
+		}
+	}
+
+	// Anthropic needs the user to speak first; OpenAI doesn't
+	settings := models.DefaultSettings()
+	settings.Output.Chat.Roles = map[string]string{models.ComponentTypeContext: models.ChatRoleAssistant}
+	if _, _, err := ComposeChatPayload(pipeline, settings); err == nil || !strings.Contains(err.Error(), "the first message is from the assistant") {
+		t.Errorf("expected first message error, got %v", err)
+	}
+	settings.Output.Chat.Shape = models.ChatShapeOpenAI
+	if _, _, err := ComposeChatPayload(pipeline, settings); err != nil {
+		t.Errorf("openai shape with an assistant message first: %v", err)
+	}
+}
@@ -2,8 +2,11 @@ package composer
 
 import (
 	"fmt"
+	"os"
+	"path/filepath"
 	"strings"
 
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
 	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 )
 
@@ -13,12 +16,19 @@ func ComposeComponentWithSettings(component *models.Component, settings *models.
 		return "", fmt.Errorf("cannot compose component: nil component provided")
 	}
 
+	if ResolveOutputMode(nil, settings) == models.OutputModeReference {
+		return composeComponentReference(component, settings)
+	}
+	if format := ResolveOutputFormat(nil, settings); format != models.OutputFormatMarkdown {
+		return composeComponentWithRenderer(component, settings, format)
+	}
+
 	var output strings.Builder
 
 	// Find the section settings for this component type
 	var sectionHeading string
 	if settings.Output.Formatting.ShowHeadings {
-		for _, section := range settings.Output.Formatting.Sections {
+		for _, section := range settings.OutputSections() {
 			if strings.ToLower(section.Type) == strings.ToLower(component.Type) ||
 			   strings.ToLower(section.Type) == strings.ToLower(component.Type)+"s" {
 				sectionHeading = section.Heading
@@ -33,10 +43,85 @@ func ComposeComponentWithSettings(component *models.Component, settings *models.
 	}
 
 	// Add the component content
-	output.WriteString(component.Content)
-	if !strings.HasSuffix(component.Content, "\n") {
+	unresolved := make(map[string]bool)
+	content := substituteComponentContent(component.Content, ResolveVariables(nil, settings), unresolved)
+	if err := checkUnresolvedVariables(component.Name, unresolved, settings); err != nil {
+		return "", err
+	}
+
+	content, missingFiles := expandComponentContent(content, settings)
+	output.WriteString(content)
+	if !strings.HasSuffix(content, "\n") {
 		output.WriteString("\n")
 	}
 
+	// Add warning about file references that could not be expanded
+	if len(missingFiles) > 0 {
+		output.WriteString("\n---\n")
+		output.WriteString("⚠️  Warning: The following file references could not be loaded:\n")
+		for _, ref := range missingFiles {
+			output.WriteString(fmt.Sprintf("   - %s\n", ref))
+		}
+	}
+
 	return output.String(), nil
-}
\ No newline at end of file
+}
+
+// composeComponentWithRenderer composes a single component with a non-Markdown renderer
+func composeComponentWithRenderer(component *models.Component, settings *models.Settings, format string) (string, error) {
+	renderer, err := GetRenderer(format)
+	if err != nil {
+		return "", fmt.Errorf("cannot compose component '%s': %w", component.Name, err)
+	}
+
+	unresolved := make(map[string]bool)
+	content := substituteComponentContent(component.Content, ResolveVariables(nil, settings), unresolved)
+	if err := checkUnresolvedVariables(component.Name, unresolved, settings); err != nil {
+		return "", err
+	}
+	content, missingFiles := expandComponentContent(content, settings)
+
+	doc := componentDocument(component, content, settings)
+	doc.MissingFiles = missingFiles
+	output, _ := renderer.Render(doc)
+	return output, nil
+}
+
+// composeComponentReference writes an @import for a single component in its section
+func composeComponentReference(component *models.Component, settings *models.Settings) (string, error) {
+	renderer, err := GetRenderer(ResolveOutputFormat(nil, settings))
+	if err != nil {
+		return "", fmt.Errorf("cannot compose component '%s': %w", component.Name, err)
+	}
+	if _, err := os.Stat(filepath.Join(files.PluqqyDir, component.Path)); err != nil {
+		return "", fmt.Errorf("cannot reference component '%s', imported file not found: %s", component.Name, component.Path)
+	}
+
+	output, _ := renderer.Render(componentDocument(component, ImportPath(component.Path, settings), settings))
+	return output, nil
+}
+
+// componentDocument places a single component's content under its configured section,
+// or one named after its type
+func componentDocument(component *models.Component, content string, settings *models.Settings) *Document {
+	section := DocumentSection{Type: component.Type, Heading: fmt.Sprintf("## %s", capitalizeType(component.Type))}
+	for _, configured := range settings.OutputSections() {
+		if strings.ToLower(configured.Type) == strings.ToLower(component.Type) ||
+			strings.ToLower(configured.Type) == strings.ToLower(component.Type)+"s" {
+			section = DocumentSection{Type: configured.Type, Heading: configured.Heading}
+			break
+		}
+	}
+	section.Components = []DocumentComponent{{
+		Path:    component.Path,
+		Type:    component.Type,
+		Content: content,
+	}}
+
+	return &Document{
+		Title:         component.Name,
+		ShowHeadings:  settings.Output.Formatting.ShowHeadings,
+		SourceMarkers: settings.Output.SourceMarkers,
+		Sections:      []DocumentSection{section},
+	}
+}
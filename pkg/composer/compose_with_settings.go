@@ -12,12 +12,42 @@ import (
 
 // ComposePipelineWithSettings composes a pipeline using provided settings
 func ComposePipelineWithSettings(pipeline *models.Pipeline, settings *models.Settings) (string, error) {
+	output, _, err := ComposePipelineWithSettingsReport(pipeline, settings)
+	return output, err
+}
+
+// ComposePipelineWithSettingsReport composes a pipeline using provided settings and
+// reports the components that were cut to fit the token budget (nil without a budget)
+func ComposePipelineWithSettingsReport(pipeline *models.Pipeline, settings *models.Settings) (string, *BudgetReport, error) {
+	output, report, _, err := composePipelineWithSettings(pipeline, settings)
+	return output, report, err
+}
+
+// composePipelineWithSettings composes a pipeline and records the budget report
+// and the line range each component occupies in the output
+func composePipelineWithSettings(pipeline *models.Pipeline, settings *models.Settings) (string, *BudgetReport, []SourceRange, error) {
 	if pipeline == nil {
-		return "", fmt.Errorf("cannot compose pipeline: nil pipeline provided")
+		return "", nil, nil, fmt.Errorf("cannot compose pipeline: nil pipeline provided")
+	}
+
+	renderer, err := GetRenderer(ResolveOutputFormat(pipeline, settings))
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("cannot compose pipeline '%s': %w", pipeline.Name, err)
+	}
+
+	return composePipelineWithRenderer(pipeline, settings, renderer)
+}
+
+// composePipelineWithRenderer composes a pipeline with the given renderer
+func composePipelineWithRenderer(pipeline *models.Pipeline, settings *models.Settings, renderer Renderer) (string, *BudgetReport, []SourceRange, error) {
+	// Flatten included pipelines into a single component list
+	pipeline, err := files.ResolvePipelineIncludes(pipeline)
+	if err != nil {
+		return "", nil, nil, fmt.Errorf("cannot compose pipeline: %w", err)
 	}
 
 	if len(pipeline.Components) == 0 {
-		return "", fmt.Errorf("cannot compose pipeline '%s': no components defined", pipeline.Name)
+		return "", nil, nil, fmt.Errorf("cannot compose pipeline '%s': no components defined", pipeline.Name)
 	}
 
 	// Sort components by order field
@@ -27,14 +57,22 @@ func ComposePipelineWithSettings(pipeline *models.Pipeline, settings *models.Set
 		return sortedComponents[i].Order < sortedComponents[j].Order
 	})
 
-	// Group components by type while maintaining order
-	var output strings.Builder
-	
-	// Track which types we've seen and their components
-	typeGroups := make(map[string][]componentWithContentSettings)
+	// Leave out components whose when: conditions do not hold
+	sortedComponents, _ = FilterComponents(sortedComponents, NewConditionContext(pipeline, settings))
+
+	// Reference mode writes an @import per component instead of its content
+	if ResolveOutputMode(pipeline, settings) == models.OutputModeReference {
+		output, sources, err := composeReferences(pipeline.Name, sortedComponents, settings, renderer)
+		return output, nil, sources, err
+	}
+
+	var loaded []componentWithContent
 	var missingComponents []string
+	var missingFiles []string
+	variables := ResolveVariables(pipeline, settings)
+	unresolved := make(map[string]bool)
 
-	// Load all components and group by type
+	// Load all components
 	for _, compRef := range sortedComponents {
 		// Component paths in YAML are relative to the pipelines directory
 		// We need to resolve them from the .pluqqy directory
@@ -50,48 +88,35 @@ func ComposePipelineWithSettings(pipeline *models.Pipeline, settings *models.Set
 			continue
 		}
 
-		typeGroups[component.Type] = append(typeGroups[component.Type], componentWithContentSettings{
+		content := substituteComponentContent(component.Content, variables, unresolved)
+		content, unresolvedFiles := expandComponentContent(content, settings)
+		missingFiles = append(missingFiles, unresolvedFiles...)
+
+		loaded = append(loaded, componentWithContent{
 			ref:     compRef,
-			content: component.Content,
+			group:   component.Type,
+			content: content,
 		})
 	}
 
-	// Build output according to settings
-	for _, section := range settings.Output.Formatting.Sections {
-		components, exists := typeGroups[strings.ToLower(section.Type)]
-		if !exists || len(components) == 0 {
-			continue
-		}
-
-		// Add section heading if enabled
-		if settings.Output.Formatting.ShowHeadings {
-			output.WriteString(fmt.Sprintf("%s\n\n", section.Heading))
-		}
-
-		// Add components
-		for _, comp := range components {
-			output.WriteString(comp.content)
-			if !strings.HasSuffix(comp.content, "\n") {
-				output.WriteString("\n")
-			}
-			output.WriteString("\n")
-		}
+	if err := checkUnresolvedVariables(pipeline.Name, unresolved, settings); err != nil {
+		return "", nil, nil, err
 	}
 
-	// Add warning about missing components
-	if len(missingComponents) > 0 {
-		output.WriteString("\n---\n")
-		output.WriteString("⚠️  Warning: The following components could not be loaded:\n")
-		for _, path := range missingComponents {
-			output.WriteString(fmt.Sprintf("   - %s\n", path))
-		}
+	// Fit the components into the token budget
+	render := func(components []componentWithContent) string {
+		body, _ := renderer.Render(buildDocument(pipeline.Name, components, settings))
+		return body
+	}
+	loaded, report, err := applyTokenBudget(pipeline, settings, loaded, render)
+	if err != nil {
+		return "", report, nil, err
 	}
 
-	return output.String(), nil
-}
+	doc := buildDocument(pipeline.Name, loaded, settings)
+	doc.MissingComponents = missingComponents
+	doc.MissingFiles = missingFiles
+	output, sources := renderer.Render(doc)
 
-// componentWithContentSettings is the version used with settings
-type componentWithContentSettings struct {
-	ref     models.ComponentRef
-	content string
-}
\ No newline at end of file
+	return output, report, sources, nil
+}
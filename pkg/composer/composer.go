@@ -11,12 +11,31 @@ import (
 )
 
 func ComposePipeline(pipeline *models.Pipeline) (string, error) {
+	return ComposePipelineWithVariables(pipeline, nil)
+}
+
+// ComposePipelineWithVariables composes a pipeline using the project settings,
+// with variables taking precedence over pipeline and project values
+func ComposePipelineWithVariables(pipeline *models.Pipeline, variables map[string]string) (string, error) {
+	output, _, err := ComposePipelineWithVariablesReport(pipeline, variables)
+	return output, err
+}
+
+// ComposePipelineWithVariablesReport composes a pipeline like ComposePipelineWithVariables
+// and reports the components that were cut to fit the token budget (nil without a budget)
+func ComposePipelineWithVariablesReport(pipeline *models.Pipeline, variables map[string]string) (string, *BudgetReport, error) {
 	if pipeline == nil {
-		return "", fmt.Errorf("cannot compose pipeline: nil pipeline provided")
+		return "", nil, fmt.Errorf("cannot compose pipeline: nil pipeline provided")
+	}
+
+	// Flatten included pipelines into a single component list
+	pipeline, err := files.ResolvePipelineIncludes(pipeline)
+	if err != nil {
+		return "", nil, fmt.Errorf("cannot compose pipeline: %w", err)
 	}
 
 	if len(pipeline.Components) == 0 {
-		return "", fmt.Errorf("cannot compose pipeline '%s': no components defined", pipeline.Name)
+		return "", nil, fmt.Errorf("cannot compose pipeline '%s': no components defined", pipeline.Name)
 	}
 
 	// Load settings
@@ -25,6 +44,13 @@ func ComposePipeline(pipeline *models.Pipeline) (string, error) {
 		// Use defaults if settings can't be loaded
 		settings = models.DefaultSettings()
 	}
+	settings.VariableOverrides = variables
+
+	// The pipeline's own output file is written instead of the default one, and
+	// @imports are resolved relative to the file that contains them
+	if pipeline.OutputPath != "" {
+		settings.Output.ExportPath = filepath.Dir(pipeline.OutputPath)
+	}
 
 	// Sort components by order field
 	sortedComponents := make([]models.ComponentRef, len(pipeline.Components))
@@ -33,16 +59,29 @@ func ComposePipeline(pipeline *models.Pipeline) (string, error) {
 		return sortedComponents[i].Order < sortedComponents[j].Order
 	})
 
-	// Group components by type while maintaining order
+	// Leave out components whose when: conditions do not hold
+	sortedComponents, _ = FilterComponents(sortedComponents, NewConditionContext(pipeline, settings))
+
+	// Reference mode writes an @import per component instead of its content
+	if ResolveOutputMode(pipeline, settings) == models.OutputModeReference {
+		renderer, err := GetRenderer(ResolveOutputFormat(pipeline, settings))
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot compose pipeline '%s': %w", pipeline.Name, err)
+		}
+		output, _, err := composeReferences(pipeline.Name, sortedComponents, settings, renderer)
+		return output, nil, err
+	}
+
 	var output strings.Builder
 	output.WriteString(fmt.Sprintf("# %s\n\n", pipeline.Name))
 
-	// Track which types we've seen and their components
-	typeGroups := make(map[string][]componentWithContent)
-	typeOrder := []string{}
+	var loaded []componentWithContent
 	var missingComponents []string
+	var missingFiles []string
+	values := ResolveVariables(pipeline, settings)
+	unresolved := make(map[string]bool)
 
-	// Load all components and group by type
+	// Load all components
 	for _, compRef := range sortedComponents {
 		// Component paths in YAML are relative to the pipelines directory
 		// We need to resolve them from the .pluqqy directory
@@ -56,16 +95,51 @@ func ComposePipeline(pipeline *models.Pipeline) (string, error) {
 			continue
 		}
 
-		if _, exists := typeGroups[compRef.Type]; !exists {
-			typeOrder = append(typeOrder, compRef.Type)
-		}
+		content := substituteComponentContent(component.Content, values, unresolved)
+		content, unresolvedFiles := expandComponentContent(content, settings)
+		missingFiles = append(missingFiles, unresolvedFiles...)
 
-		typeGroups[compRef.Type] = append(typeGroups[compRef.Type], componentWithContent{
+		loaded = append(loaded, componentWithContent{
 			ref:     compRef,
-			content: component.Content,
+			group:   compRef.Type,
+			content: content,
 		})
 	}
 
+	if err := checkUnresolvedVariables(pipeline.Name, unresolved, settings); err != nil {
+		return "", nil, err
+	}
+
+	// Other formats go through their renderer; Markdown keeps the pipeline title and warnings on top
+	if format := ResolveOutputFormat(pipeline, settings); format != models.OutputFormatMarkdown {
+		renderer, err := GetRenderer(format)
+		if err != nil {
+			return "", nil, fmt.Errorf("cannot compose pipeline '%s': %w", pipeline.Name, err)
+		}
+		render := func(components []componentWithContent) string {
+			body, _ := renderer.Render(buildDocument(pipeline.Name, components, settings))
+			return body
+		}
+		loaded, report, err := applyTokenBudget(pipeline, settings, loaded, render)
+		if err != nil {
+			return "", report, err
+		}
+		doc := buildDocument(pipeline.Name, loaded, settings)
+		doc.MissingComponents = missingComponents
+		doc.MissingFiles = missingFiles
+		body, _ := renderer.Render(doc)
+		return body, report, nil
+	}
+
+	// Fit the components into the token budget
+	render := func(components []componentWithContent) string {
+		return renderSections(components, settings)
+	}
+	loaded, report, err := applyTokenBudget(pipeline, settings, loaded, render)
+	if err != nil {
+		return "", report, err
+	}
+
 	// If there are missing components, add a warning section
 	if len(missingComponents) > 0 {
 		output.WriteString("⚠️ **Warning: Missing Components**\n\n")
@@ -77,9 +151,38 @@ func ComposePipeline(pipeline *models.Pipeline) (string, error) {
 		output.WriteString("---\n\n")
 	}
 
-	// Write components grouped by type, ordered by settings.Sections
+	// If there are unresolved file references, add a warning section
+	if len(missingFiles) > 0 {
+		output.WriteString("⚠️ **Warning: Missing Referenced Files**\n\n")
+		output.WriteString("The following file references could not be expanded:\n")
+		for _, ref := range missingFiles {
+			output.WriteString(fmt.Sprintf("- %s\n", ref))
+		}
+		output.WriteString("\nThese files may have been deleted or moved. Consider updating the referencing components.\n\n")
+		output.WriteString("---\n\n")
+	}
+
+	output.WriteString(render(loaded))
+
+	return output.String(), report, nil
+}
+
+// renderSections writes components grouped by type, ordered by settings.Sections
+func renderSections(components []componentWithContent, settings *models.Settings) string {
+	var output strings.Builder
+
+	// Group components by type while maintaining order
+	typeGroups := make(map[string][]componentWithContent)
+	typeOrder := []string{}
+	for _, comp := range components {
+		if _, exists := typeGroups[comp.group]; !exists {
+			typeOrder = append(typeOrder, comp.group)
+		}
+		typeGroups[comp.group] = append(typeGroups[comp.group], comp)
+	}
+
 	// First write sections in the configured order
-	for _, section := range settings.Output.Formatting.Sections {
+	for _, section := range settings.OutputSections() {
 		components, exists := typeGroups[section.Type]
 		if !exists || len(components) == 0 {
 			// Skip sections that have no components
@@ -93,6 +196,9 @@ func ComposePipeline(pipeline *models.Pipeline) (string, error) {
 
 		// Write components of this type
 		for _, comp := range components {
+			if settings.Output.SourceMarkers {
+				output.WriteString(sourceMarker(comp.ref.Path))
+			}
 			// Write content
 			output.WriteString(strings.TrimSpace(comp.content))
 			output.WriteString("\n\n")
@@ -105,7 +211,7 @@ func ComposePipeline(pipeline *models.Pipeline) (string, error) {
 	for _, componentType := range typeOrder {
 		// Skip if already written
 		written := false
-		for _, section := range settings.Output.Formatting.Sections {
+		for _, section := range settings.OutputSections() {
 			if componentType == section.Type {
 				written = true
 				break
@@ -125,6 +231,9 @@ func ComposePipeline(pipeline *models.Pipeline) (string, error) {
 
 		// Write components of this type
 		for _, comp := range components {
+			if settings.Output.SourceMarkers {
+				output.WriteString(sourceMarker(comp.ref.Path))
+			}
 			// Write content
 			output.WriteString(strings.TrimSpace(comp.content))
 			output.WriteString("\n\n")
@@ -133,11 +242,13 @@ func ComposePipeline(pipeline *models.Pipeline) (string, error) {
 		output.WriteString("\n")
 	}
 
-	return output.String(), nil
+	return output.String()
 }
 
+// componentWithContent is a loaded component ready to be written to the output
 type componentWithContent struct {
 	ref     models.ComponentRef
+	group   string // Section type the component is grouped under
 	content string
 }
 
@@ -0,0 +1,183 @@
+package composer
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// SkippedComponent is a component left out of a composition because its condition failed
+type SkippedComponent struct {
+	Ref    models.ComponentRef
+	Reason string
+}
+
+// ConditionContext holds the facts that component conditions are evaluated against
+type ConditionContext struct {
+	RootDir   string            // Project root used for exists checks
+	Branch    string            // Current git branch, empty when unknown
+	Variables map[string]string // Resolved template variables
+	LookupEnv func(string) (string, bool)
+}
+
+// NewConditionContext builds the context for a pipeline from the project root
+func NewConditionContext(pipeline *models.Pipeline, settings *models.Settings) ConditionContext {
+	rootDir := filepath.Dir(files.PluqqyDir)
+	return ConditionContext{
+		RootDir:   rootDir,
+		Branch:    CurrentGitBranch(rootDir),
+		Variables: ResolveVariables(pipeline, settings),
+		LookupEnv: os.LookupEnv,
+	}
+}
+
+// EvaluateCondition reports whether a condition holds. When it does not, the
+// returned reason describes the first check that failed.
+func EvaluateCondition(cond *models.Condition, ctx ConditionContext) (bool, string) {
+	if cond.IsEmpty() {
+		return true, ""
+	}
+
+	if cond.Env != "" {
+		if ok, reason := evaluateKeyValue("env", cond.Env, ctx.LookupEnv); !ok {
+			return false, reason
+		}
+	}
+
+	if cond.Branch != "" {
+		pattern, negate := splitNegation(cond.Branch)
+		matched, _ := path.Match(pattern, ctx.Branch)
+		if matched == negate {
+			branch := ctx.Branch
+			if branch == "" {
+				branch = "unknown"
+			}
+			return false, fmt.Sprintf("branch: %s does not match %s", branch, cond.Branch)
+		}
+	}
+
+	if cond.Exists != "" {
+		target, negate := splitNegation(cond.Exists)
+		_, err := os.Stat(filepath.Join(ctx.RootDir, target))
+		if (err == nil) == negate {
+			if negate {
+				return false, fmt.Sprintf("exists: %s is present", target)
+			}
+			return false, fmt.Sprintf("exists: %s not found", target)
+		}
+	}
+
+	if cond.Var != "" {
+		lookup := func(name string) (string, bool) {
+			value, ok := ctx.Variables[name]
+			return value, ok
+		}
+		if ok, reason := evaluateKeyValue("var", cond.Var, lookup); !ok {
+			return false, reason
+		}
+	}
+
+	return true, ""
+}
+
+// evaluateKeyValue checks a NAME or NAME=pattern expression against a lookup function
+func evaluateKeyValue(kind string, expr string, lookup func(string) (string, bool)) (bool, string) {
+	spec, negate := splitNegation(expr)
+	name, pattern, hasPattern := strings.Cut(spec, "=")
+	name = strings.TrimSpace(name)
+
+	value, _ := lookup(name)
+	var matched bool
+	if hasPattern {
+		matched, _ = path.Match(strings.TrimSpace(pattern), value)
+	} else {
+		matched = value != ""
+	}
+
+	if matched != negate {
+		return true, ""
+	}
+
+	switch {
+	case negate:
+		return false, fmt.Sprintf("%s: %s matched (value %q)", kind, spec, value)
+	case value == "":
+		return false, fmt.Sprintf("%s: %s is not set", kind, name)
+	default:
+		return false, fmt.Sprintf("%s: %s is %q, expected %s", kind, name, value, strings.TrimSpace(pattern))
+	}
+}
+
+// splitNegation strips a leading ! and reports whether it was present
+func splitNegation(value string) (string, bool) {
+	value = strings.TrimSpace(value)
+	if strings.HasPrefix(value, "!") {
+		return strings.TrimSpace(value[1:]), true
+	}
+	return value, false
+}
+
+// FilterComponents splits components into those whose conditions hold and those that are skipped
+func FilterComponents(components []models.ComponentRef, ctx ConditionContext) ([]models.ComponentRef, []SkippedComponent) {
+	var included []models.ComponentRef
+	var skipped []SkippedComponent
+
+	for _, comp := range components {
+		if ok, reason := EvaluateCondition(comp.When, ctx); !ok {
+			skipped = append(skipped, SkippedComponent{Ref: comp, Reason: reason})
+			continue
+		}
+		included = append(included, comp)
+	}
+
+	return included, skipped
+}
+
+// CurrentGitBranch returns the branch checked out in the repository containing
+// dir, or an empty string when it cannot be determined (detached HEAD, no repo)
+func CurrentGitBranch(dir string) string {
+	absDir, err := filepath.Abs(dir)
+	if err != nil {
+		return ""
+	}
+
+	for {
+		gitPath := filepath.Join(absDir, ".git")
+		if info, err := os.Stat(gitPath); err == nil {
+			gitDir := gitPath
+			if !info.IsDir() {
+				// Worktrees and submodules use a .git file pointing at the real git dir
+				data, err := os.ReadFile(gitPath)
+				if err != nil {
+					return ""
+				}
+				target := strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(string(data)), "gitdir:"))
+				if !filepath.IsAbs(target) {
+					target = filepath.Join(absDir, target)
+				}
+				gitDir = target
+			}
+
+			head, err := os.ReadFile(filepath.Join(gitDir, "HEAD"))
+			if err != nil {
+				return ""
+			}
+			ref := strings.TrimSpace(string(head))
+			if !strings.HasPrefix(ref, "ref: refs/heads/") {
+				return ""
+			}
+			return strings.TrimPrefix(ref, "ref: refs/heads/")
+		}
+
+		parent := filepath.Dir(absDir)
+		if parent == absDir {
+			return ""
+		}
+		absDir = parent
+	}
+}
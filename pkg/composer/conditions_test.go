@@ -0,0 +1,137 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestEvaluateCondition(t *testing.T) {
+	root := t.TempDir()
+	os.WriteFile(filepath.Join(root, "package.json"), []byte("{}"), 0644)
+
+	env := map[string]string{"NODE_ENV": "production", "CI": "true"}
+	ctx := ConditionContext{
+		RootDir:   root,
+		Branch:    "feature/login",
+		Variables: map[string]string{"target": "frontend"},
+		LookupEnv: func(name string) (string, bool) {
+			value, ok := env[name]
+			return value, ok
+		},
+	}
+
+	tests := []struct {
+		name       string
+		cond       *models.Condition
+		want       bool
+		wantReason string
+	}{
+		{"nil condition", nil, true, ""},
+		{"env set", &models.Condition{Env: "CI"}, true, ""},
+		{"env unset", &models.Condition{Env: "DEBUG"}, false, "env: DEBUG is not set"},
+		{"env value", &models.Condition{Env: "NODE_ENV=production"}, true, ""},
+		{"env value mismatch", &models.Condition{Env: "NODE_ENV=dev*"}, false, `env: NODE_ENV is "production", expected dev*`},
+		{"negated env", &models.Condition{Env: "!DEBUG"}, true, ""},
+		{"branch glob", &models.Condition{Branch: "feature/*"}, true, ""},
+		{"branch mismatch", &models.Condition{Branch: "main"}, false, "branch: feature/login does not match main"},
+		{"negated branch", &models.Condition{Branch: "!main"}, true, ""},
+		{"path exists", &models.Condition{Exists: "package.json"}, true, ""},
+		{"path missing", &models.Condition{Exists: "go.mod"}, false, "exists: go.mod not found"},
+		{"negated path", &models.Condition{Exists: "!package.json"}, false, "exists: package.json is present"},
+		{"variable value", &models.Condition{Var: "target=frontend"}, true, ""},
+		{"variable mismatch", &models.Condition{Var: "target=backend"}, false, `var: target is "frontend", expected backend`},
+		{"variable unset", &models.Condition{Var: "region"}, false, "var: region is not set"},
+		{"all must hold", &models.Condition{Env: "CI", Branch: "main"}, false, "branch: feature/login does not match main"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, reason := EvaluateCondition(tt.cond, ctx)
+			if got != tt.want {
+				t.Errorf("EvaluateCondition() = %v, want %v (reason %q)", got, tt.want, reason)
+			}
+			if reason != tt.wantReason {
+				t.Errorf("reason = %q, want %q", reason, tt.wantReason)
+			}
+		})
+	}
+}
+
+func TestCurrentGitBranch(t *testing.T) {
+	root := t.TempDir()
+	if branch := CurrentGitBranch(root); branch != "" {
+		t.Errorf("expected no branch outside a repository, got %q", branch)
+	}
+
+	os.MkdirAll(filepath.Join(root, ".git"), 0755)
+	os.WriteFile(filepath.Join(root, ".git", "HEAD"), []byte("ref: refs/heads/feature/api\n"), 0644)
+	os.MkdirAll(filepath.Join(root, "sub", "dir"), 0755)
+
+	if branch := CurrentGitBranch(filepath.Join(root, "sub", "dir")); branch != "feature/api" {
+		t.Errorf("CurrentGitBranch() = %q, want %q", branch, "feature/api")
+	}
+
+	os.WriteFile(filepath.Join(root, ".git", "HEAD"), []byte("3f2a1b0c\n"), 0644)
+	if branch := CurrentGitBranch(root); branch != "" {
+		t.Errorf("expected no branch for detached HEAD, got %q", branch)
+	}
+}
+
+func TestComposePipelineWithSettings_SkipsFailedConditions(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatalf("Failed to initialize project structure: %v", err)
+	}
+
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.ContextsDir, "frontend.md"), "Frontend context")
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.ContextsDir, "backend.md"), "Backend context")
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.RulesDir, "shared.md"), "Shared rules")
+
+	pipeline := &models.Pipeline{
+		Name: "adaptive",
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeContext, Path: "../components/contexts/frontend.md", Order: 1,
+				When: &models.Condition{Var: "target=frontend"}},
+			{Type: models.ComponentTypeContext, Path: "../components/contexts/backend.md", Order: 2,
+				When: &models.Condition{Var: "target=backend"}},
+			{Type: models.ComponentTypeRules, Path: "../components/rules/shared.md", Order: 3},
+		},
+	}
+
+	settings := models.DefaultSettings()
+	settings.VariableOverrides = map[string]string{"target": "backend"}
+
+	output, err := ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ComposePipelineWithSettings failed: %v", err)
+	}
+	if strings.Contains(output, "Frontend context") {
+		t.Error("frontend component should be skipped")
+	}
+	if !strings.Contains(output, "Backend context") || !strings.Contains(output, "Shared rules") {
+		t.Errorf("expected backend and shared components:\n%s", output)
+	}
+
+	explanation, err := ExplainPipeline(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ExplainPipeline failed: %v", err)
+	}
+	if len(explanation.Included) != 2 || len(explanation.Skipped) != 1 {
+		t.Fatalf("expected 2 included and 1 skipped, got %+v", explanation)
+	}
+	if explanation.Skipped[0].Ref.Path != "../components/contexts/frontend.md" {
+		t.Errorf("unexpected skipped component: %s", explanation.Skipped[0].Ref.Path)
+	}
+	if !strings.Contains(explanation.Skipped[0].Reason, "expected frontend") {
+		t.Errorf("unexpected reason: %s", explanation.Skipped[0].Reason)
+	}
+}
@@ -0,0 +1,63 @@
+package composer
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// PipelineExplanation describes how a pipeline is assembled when composed
+type PipelineExplanation struct {
+	Pipeline string
+	Branch   string
+	Included []models.ComponentRef
+	Skipped  []SkippedComponent
+	Sources  []SourceRange // Where each included component ended up in the output
+	Budget   *BudgetReport // Nil when the pipeline has no token budget
+	Tokens   int           // Estimated tokens of the composed output
+}
+
+// ExplainPipeline composes a pipeline and reports which components were included
+// or skipped, and which lines of the output each component produced
+func ExplainPipeline(pipeline *models.Pipeline, settings *models.Settings) (*PipelineExplanation, error) {
+	if pipeline == nil {
+		return nil, fmt.Errorf("cannot explain pipeline: nil pipeline provided")
+	}
+
+	resolved, err := files.ResolvePipelineIncludes(pipeline)
+	if err != nil {
+		return nil, fmt.Errorf("cannot explain pipeline: %w", err)
+	}
+
+	sortedComponents := make([]models.ComponentRef, len(resolved.Components))
+	copy(sortedComponents, resolved.Components)
+	sort.Slice(sortedComponents, func(i, j int) bool {
+		return sortedComponents[i].Order < sortedComponents[j].Order
+	})
+
+	ctx := NewConditionContext(resolved, settings)
+	included, skipped := FilterComponents(sortedComponents, ctx)
+
+	explanation := &PipelineExplanation{
+		Pipeline: resolved.Name,
+		Branch:   ctx.Branch,
+		Included: included,
+		Skipped:  skipped,
+	}
+
+	if len(included) == 0 {
+		return explanation, nil
+	}
+
+	output, report, sources, err := composePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		return nil, err
+	}
+	explanation.Sources = sources
+	explanation.Budget = report
+	explanation.Tokens = EstimateTokens(output)
+
+	return explanation, nil
+}
@@ -0,0 +1,450 @@
+package composer
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// FileReference is a parsed @file reference found in component content
+type FileReference struct {
+	Raw       string // The reference as written, including the leading @
+	Path      string // Repository-relative path or glob pattern
+	StartLine int    // First line to include (1-based, 0 means from the start)
+	EndLine   int    // Last line to include (0 means to the end)
+}
+
+// IsGlob reports whether the reference path is a glob pattern
+func (r FileReference) IsGlob() bool {
+	return strings.ContainsAny(r.Path, "*?[")
+}
+
+// ParseFileReference parses a reference such as @src/main.go#L10-40
+func ParseFileReference(ref string) (FileReference, error) {
+	if !strings.HasPrefix(ref, "@") {
+		return FileReference{}, fmt.Errorf("reference must start with @")
+	}
+
+	parsed := FileReference{Raw: ref}
+	path := strings.TrimPrefix(ref, "@")
+
+	// Split off an optional #L10 or #L10-40 line range
+	if idx := strings.LastIndex(path, "#L"); idx >= 0 {
+		start, end, err := parseLineRange(path[idx+2:])
+		if err != nil {
+			return FileReference{}, fmt.Errorf("invalid line range in %s: %w", ref, err)
+		}
+		parsed.StartLine = start
+		parsed.EndLine = end
+		path = path[:idx]
+	}
+
+	if path == "" {
+		return FileReference{}, fmt.Errorf("empty path")
+	}
+	// A cleaned path only keeps .. segments that climb out of the repository;
+	// names such as a..b.md are fine
+	if clean := filepath.ToSlash(filepath.Clean(path)); filepath.IsAbs(path) || clean == ".." || strings.HasPrefix(clean, "../") {
+		return FileReference{}, fmt.Errorf("path traversal not allowed")
+	}
+
+	parsed.Path = path
+	return parsed, nil
+}
+
+// parseLineRange parses "10", "10-40" or "10-L40"
+func parseLineRange(spec string) (int, int, error) {
+	parts := strings.SplitN(spec, "-", 2)
+
+	start, err := strconv.Atoi(parts[0])
+	if err != nil || start <= 0 {
+		return 0, 0, fmt.Errorf("start line must be a positive number")
+	}
+
+	end := start
+	if len(parts) == 2 {
+		end, err = strconv.Atoi(strings.TrimPrefix(parts[1], "L"))
+		if err != nil || end < start {
+			return 0, 0, fmt.Errorf("end line must be a number not less than the start line")
+		}
+	}
+
+	return start, end, nil
+}
+
+// ExtractFileReferences finds @file references in content, skipping fenced code blocks
+func ExtractFileReferences(content string) []string {
+	var refs []string
+	inFence := false
+
+	for _, line := range strings.Split(content, "\n") {
+		if isFenceLine(line) {
+			inFence = !inFence
+			continue
+		}
+		if inFence {
+			continue
+		}
+		for _, tok := range findReferenceTokens(line) {
+			refs = append(refs, tok.text)
+		}
+	}
+
+	return refs
+}
+
+// referenceToken locates a reference inside a single line
+type referenceToken struct {
+	text  string
+	start int
+	end   int
+}
+
+// findReferenceTokens scans a line for @path tokens. A reference must start the
+// line or follow whitespace or an opening bracket, and must look like a path, so
+// that e-mail addresses and @mentions are left alone.
+func findReferenceTokens(line string) []referenceToken {
+	var tokens []referenceToken
+
+	for i := 0; i < len(line); i++ {
+		if line[i] != '@' {
+			continue
+		}
+		if i > 0 && !strings.ContainsRune(" \t([", rune(line[i-1])) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(line) && !strings.ContainsRune(" \t)]`\"'", rune(line[end])) {
+			end++
+		}
+		// Trailing punctuation belongs to the sentence, not the path
+		for end > i+1 && strings.ContainsRune(".,;:!?", rune(line[end-1])) {
+			end--
+		}
+
+		text := line[i:end]
+		if len(text) > 1 && strings.ContainsAny(text[1:], "/.*") {
+			tokens = append(tokens, referenceToken{text: text, start: i, end: end})
+		}
+		i = end - 1
+	}
+
+	return tokens
+}
+
+// isFenceLine reports whether the line opens or closes a fenced code block
+func isFenceLine(line string) bool {
+	trimmed := strings.TrimSpace(line)
+	return strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~")
+}
+
+// ExpandFileReferences inlines every @file reference in content as a fenced code
+// block. Files are resolved relative to rootDir. References that cannot be
+// resolved are left as written and returned so callers can report them.
+func ExpandFileReferences(content string, rootDir string, settings models.FileReferenceSettings) (string, []string) {
+	maxSize := settings.MaxFileSize
+	if maxSize <= 0 {
+		maxSize = models.DefaultMaxReferencedFileSize
+	}
+
+	var output strings.Builder
+	var missing []string
+	inFence := false
+
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if i > 0 {
+			output.WriteString("\n")
+		}
+
+		if isFenceLine(line) {
+			inFence = !inFence
+		}
+		if inFence || isFenceLine(line) {
+			output.WriteString(line)
+			continue
+		}
+
+		tokens := findReferenceTokens(line)
+		if len(tokens) == 0 {
+			output.WriteString(line)
+			continue
+		}
+
+		var blocks []string
+		var lineMissing []string
+		rewritten := line
+		// Rewrite from the end so earlier token offsets stay valid
+		for t := len(tokens) - 1; t >= 0; t-- {
+			tok := tokens[t]
+			ref, err := ParseFileReference(tok.text)
+			if err != nil {
+				lineMissing = append([]string{tok.text}, lineMissing...)
+				continue
+			}
+
+			expanded, err := renderFileReference(ref, rootDir, maxSize)
+			if err != nil {
+				lineMissing = append([]string{tok.text}, lineMissing...)
+				continue
+			}
+
+			blocks = append([]string{expanded}, blocks...)
+			rewritten = rewritten[:tok.start] + "`" + tok.text[1:] + "`" + rewritten[tok.end:]
+		}
+
+		missing = append(missing, lineMissing...)
+
+		if len(blocks) == 0 {
+			output.WriteString(line)
+			continue
+		}
+
+		// A line holding nothing but the reference is replaced by the file itself
+		if len(tokens) == 1 && strings.TrimSpace(line) == tokens[0].text {
+			output.WriteString(strings.Join(blocks, "\n\n"))
+			continue
+		}
+
+		output.WriteString(rewritten)
+		output.WriteString("\n\n")
+		output.WriteString(strings.Join(blocks, "\n\n"))
+	}
+
+	return output.String(), missing
+}
+
+// expandComponentContent applies @file expansion when it is enabled in settings
+func expandComponentContent(content string, settings *models.Settings) (string, []string) {
+	if !settings.Output.FileReferences.Expand {
+		return content, nil
+	}
+	return ExpandFileReferences(content, filepath.Dir(files.PluqqyDir), settings.Output.FileReferences)
+}
+
+// ResolveFileReference returns the files a reference points to, resolved
+// relative to rootDir, or an error when nothing can be expanded
+func ResolveFileReference(ref FileReference, rootDir string) ([]string, error) {
+	if !ref.IsGlob() {
+		path := filepath.Join(rootDir, ref.Path)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, fmt.Errorf("file not found: %s", ref.Path)
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("path is a directory: %s", ref.Path)
+		}
+		return []string{path}, nil
+	}
+
+	matches, err := filepath.Glob(filepath.Join(rootDir, ref.Path))
+	if err != nil {
+		return nil, fmt.Errorf("invalid glob pattern %s: %w", ref.Path, err)
+	}
+	var paths []string
+	for _, match := range matches {
+		if info, err := os.Stat(match); err == nil && !info.IsDir() {
+			paths = append(paths, match)
+		}
+	}
+	if len(paths) == 0 {
+		return nil, fmt.Errorf("no files match %s", ref.Path)
+	}
+	return paths, nil
+}
+
+// renderFileReference resolves a reference and renders each matched file
+func renderFileReference(ref FileReference, rootDir string, maxSize int64) (string, error) {
+	paths, err := ResolveFileReference(ref, rootDir)
+	if err != nil {
+		return "", err
+	}
+
+	var blocks []string
+	for _, path := range paths {
+		rel, err := filepath.Rel(rootDir, path)
+		if err != nil {
+			rel = path
+		}
+		block, err := renderFileBlock(filepath.ToSlash(rel), path, ref, maxSize)
+		if err != nil {
+			return "", err
+		}
+		blocks = append(blocks, block)
+	}
+
+	return strings.Join(blocks, "\n\n"), nil
+}
+
+// renderFileBlock renders a single file as a captioned, language-tagged code block
+func renderFileBlock(displayPath string, path string, ref FileReference, maxSize int64) (string, error) {
+	body, total, end, err := readFileBody(path, ref, maxSize)
+	if err != nil {
+		return "", fmt.Errorf("failed to read %s: %w", displayPath, err)
+	}
+	if ref.StartLine > 0 && end < ref.StartLine {
+		return "", fmt.Errorf("line %d is beyond the end of %s", ref.StartLine, displayPath)
+	}
+
+	caption := displayPath
+	if ref.StartLine > 0 {
+		caption = fmt.Sprintf("%s (lines %d-%d)", displayPath, ref.StartLine, end)
+	}
+
+	truncatedNote := ""
+	if total > maxSize {
+		// Cut at the last full line, or failing that on a rune boundary
+		size := int(maxSize)
+		for size > 0 && !utf8.RuneStart(body[size]) {
+			size--
+		}
+		cut := body[:size]
+		if idx := strings.LastIndex(cut, "\n"); idx > 0 {
+			cut = cut[:idx]
+		}
+		truncatedNote = fmt.Sprintf("\n_Truncated: showing %d of %d bytes._", len(cut), total)
+		body = cut
+	}
+	body = strings.TrimRight(body, "\n")
+
+	// Use a fence longer than any backtick run in the file
+	fence := "```"
+	for strings.Contains(body, fence) {
+		fence += "`"
+	}
+
+	var block strings.Builder
+	block.WriteString(fmt.Sprintf("`%s`:\n\n", caption))
+	block.WriteString(fence + languageForPath(displayPath) + "\n")
+	block.WriteString(body)
+	block.WriteString("\n" + fence)
+	block.WriteString(truncatedNote)
+
+	return block.String(), nil
+}
+
+// readFileBody reads the lines of a file a reference asks for, keeping no more
+// than maxSize+1 bytes of them so a large file isn't read into memory whole. It
+// returns what was kept, the size of the whole selection and, for a line range,
+// the last line included (less than the start line if the file is shorter).
+func readFileBody(path string, ref FileReference, maxSize int64) (string, int64, int, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, 0, err
+	}
+	defer f.Close()
+
+	if ref.StartLine == 0 {
+		info, err := f.Stat()
+		if err != nil {
+			return "", 0, 0, err
+		}
+		data, err := io.ReadAll(io.LimitReader(f, maxSize+1))
+		if err != nil {
+			return "", 0, 0, err
+		}
+		return string(data), info.Size(), 0, nil
+	}
+
+	// Lines are counted as strings.Split counts them, so a trailing newline
+	// starts an empty last line
+	var kept strings.Builder
+	var total int64
+	reader := bufio.NewReader(f)
+	line := 0
+	for {
+		text, err := reader.ReadString('\n')
+		if err != nil && err != io.EOF {
+			return "", 0, 0, err
+		}
+		line++
+		if line >= ref.StartLine {
+			last := err == io.EOF || line == ref.EndLine
+			if last {
+				text = strings.TrimSuffix(text, "\n")
+			}
+			total += int64(len(text))
+			if room := maxSize + 1 - int64(kept.Len()); room > 0 {
+				kept.WriteString(text[:min(int64(len(text)), room)])
+			}
+			if last {
+				return kept.String(), total, line, nil
+			}
+		}
+		if err == io.EOF {
+			return "", 0, line, nil
+		}
+	}
+}
+
+// languageForPath returns the code fence language tag for a file
+func languageForPath(path string) string {
+	switch strings.ToLower(filepath.Base(path)) {
+	case "makefile":
+		return "makefile"
+	case "dockerfile":
+		return "dockerfile"
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".go":
+		return "go"
+	case ".js", ".mjs", ".cjs":
+		return "javascript"
+	case ".jsx":
+		return "jsx"
+	case ".ts":
+		return "typescript"
+	case ".tsx":
+		return "tsx"
+	case ".py":
+		return "python"
+	case ".rb":
+		return "ruby"
+	case ".rs":
+		return "rust"
+	case ".java":
+		return "java"
+	case ".kt":
+		return "kotlin"
+	case ".swift":
+		return "swift"
+	case ".c", ".h":
+		return "c"
+	case ".cpp", ".cc", ".hpp":
+		return "cpp"
+	case ".cs":
+		return "csharp"
+	case ".php":
+		return "php"
+	case ".sh", ".bash":
+		return "bash"
+	case ".sql":
+		return "sql"
+	case ".html":
+		return "html"
+	case ".css":
+		return "css"
+	case ".json":
+		return "json"
+	case ".yaml", ".yml":
+		return "yaml"
+	case ".toml":
+		return "toml"
+	case ".xml":
+		return "xml"
+	case ".md":
+		return "markdown"
+	default:
+		return ""
+	}
+}
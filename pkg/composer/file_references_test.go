@@ -0,0 +1,212 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestParseFileReference(t *testing.T) {
+	tests := []struct {
+		name      string
+		ref       string
+		wantPath  string
+		wantStart int
+		wantEnd   int
+		wantErr   bool
+	}{
+		{"plain path", "@src/main.go", "src/main.go", 0, 0, false},
+		{"line range", "@src/main.go#L10-40", "src/main.go", 10, 40, false},
+		{"line range with L on end", "@src/main.go#L10-L40", "src/main.go", 10, 40, false},
+		{"single line", "@src/main.go#L7", "src/main.go", 7, 7, false},
+		{"glob", "@docs/*.md", "docs/*.md", 0, 0, false},
+		{"missing @", "src/main.go", "", 0, 0, true},
+		{"empty path", "@", "", 0, 0, true},
+		{"traversal", "@../secret.txt", "", 0, 0, true},
+		{"traversal through a folder", "@docs/../../secret.txt", "", 0, 0, true},
+		{"dots in a name", "@docs/a..b.md", "docs/a..b.md", 0, 0, false},
+		{"inverted range", "@main.go#L40-10", "", 0, 0, true},
+		{"zero start", "@main.go#L0", "", 0, 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, err := ParseFileReference(tt.ref)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseFileReference(%q) error = %v, wantErr %v", tt.ref, err, tt.wantErr)
+			}
+			if tt.wantErr {
+				return
+			}
+			if ref.Path != tt.wantPath || ref.StartLine != tt.wantStart || ref.EndLine != tt.wantEnd {
+				t.Errorf("ParseFileReference(%q) = %+v, want path %q lines %d-%d",
+					tt.ref, ref, tt.wantPath, tt.wantStart, tt.wantEnd)
+			}
+		})
+	}
+}
+
+func TestExtractFileReferences(t *testing.T) {
+	content := "See @src/main.go, and @docs/*.md.\n" +
+		"Mail me at dev@example.com or ping @alice\n" +
+		"```\n@ignored/in/fence.go\n```\n" +
+		"(@config.yaml)"
+
+	refs := ExtractFileReferences(content)
+	expected := []string{"@src/main.go", "@docs/*.md", "@config.yaml"}
+
+	if len(refs) != len(expected) {
+		t.Fatalf("ExtractFileReferences() = %v, want %v", refs, expected)
+	}
+	for i := range expected {
+		if refs[i] != expected[i] {
+			t.Errorf("ref %d = %q, want %q", i, refs[i], expected[i])
+		}
+	}
+}
+
+func TestExpandFileReferences(t *testing.T) {
+	root := t.TempDir()
+	os.MkdirAll(filepath.Join(root, "src"), 0755)
+	os.MkdirAll(filepath.Join(root, "docs"), 0755)
+	os.WriteFile(filepath.Join(root, "src", "main.go"), []byte("package main\n\nfunc main() {\n\tprintln(\"hi\")\n}\n"), 0644)
+	os.WriteFile(filepath.Join(root, "docs", "a.md"), []byte("Doc A"), 0644)
+	os.WriteFile(filepath.Join(root, "docs", "b.md"), []byte("Doc B"), 0644)
+	os.WriteFile(filepath.Join(root, "big.txt"), []byte(strings.Repeat("line\n", 100)), 0644)
+	os.WriteFile(filepath.Join(root, "wide.txt"), []byte(strings.Repeat("é", 100)), 0644)
+
+	settings := models.FileReferenceSettings{Expand: true, MaxFileSize: 1024}
+
+	t.Run("whole file on its own line", func(t *testing.T) {
+		output, missing := ExpandFileReferences("Intro\n@src/main.go\nOutro", root, settings)
+		if len(missing) != 0 {
+			t.Fatalf("unexpected missing references: %v", missing)
+		}
+		if strings.Contains(output, "@src/main.go") {
+			t.Error("standalone reference should be replaced")
+		}
+		if !strings.Contains(output, "```go\npackage main") {
+			t.Errorf("expected go code block, got:\n%s", output)
+		}
+		if !strings.HasPrefix(output, "Intro\n") || !strings.HasSuffix(output, "\nOutro") {
+			t.Errorf("surrounding text not preserved:\n%s", output)
+		}
+	})
+
+	t.Run("inline reference with line range", func(t *testing.T) {
+		output, _ := ExpandFileReferences("Entry point is @src/main.go#L3-5 here", root, settings)
+		if !strings.Contains(output, "Entry point is `src/main.go#L3-5` here") {
+			t.Errorf("inline reference should be rewritten as code span:\n%s", output)
+		}
+		if !strings.Contains(output, "(lines 3-5)") {
+			t.Errorf("expected line range caption:\n%s", output)
+		}
+		if strings.Contains(output, "package main") {
+			t.Error("lines outside the range should not be included")
+		}
+	})
+
+	t.Run("glob expands every match", func(t *testing.T) {
+		output, _ := ExpandFileReferences("@docs/*.md", root, settings)
+		if !strings.Contains(output, "Doc A") || !strings.Contains(output, "Doc B") {
+			t.Errorf("expected both glob matches:\n%s", output)
+		}
+		if strings.Index(output, "Doc A") > strings.Index(output, "Doc B") {
+			t.Error("glob matches should be sorted")
+		}
+	})
+
+	t.Run("size cap truncates", func(t *testing.T) {
+		small := models.FileReferenceSettings{Expand: true, MaxFileSize: 50}
+		output, _ := ExpandFileReferences("@big.txt", root, small)
+		if !strings.Contains(output, "_Truncated:") {
+			t.Errorf("expected truncation note:\n%s", output)
+		}
+	})
+
+	t.Run("size cap applies to a line range", func(t *testing.T) {
+		small := models.FileReferenceSettings{Expand: true, MaxFileSize: 50}
+		output, _ := ExpandFileReferences("@big.txt#L2-100", root, small)
+		if !strings.Contains(output, "(lines 2-100)") || !strings.Contains(output, "_Truncated: showing 49 of 494 bytes._") {
+			t.Errorf("expected the range cut to 10 lines:\n%s", output)
+		}
+	})
+
+	t.Run("line range past the end", func(t *testing.T) {
+		output, missing := ExpandFileReferences("@src/main.go#L3-99", root, settings)
+		if !strings.Contains(output, "(lines 3-6)") || !strings.Contains(output, "func main() {\n\tprintln(\"hi\")\n}\n```") {
+			t.Errorf("expected the range to stop at the last line:\n%s", output)
+		}
+		if _, missing = ExpandFileReferences("@src/main.go#L20", root, settings); len(missing) != 1 {
+			t.Errorf("a range starting past the end should be reported, got %v", missing)
+		}
+	})
+
+	t.Run("size cap keeps whole runes", func(t *testing.T) {
+		small := models.FileReferenceSettings{Expand: true, MaxFileSize: 51}
+		output, _ := ExpandFileReferences("@wide.txt", root, small)
+		if !utf8.ValidString(output) {
+			t.Errorf("truncated output is not valid UTF-8:\n%q", output)
+		}
+		if !strings.Contains(output, "_Truncated: showing 50 of 200 bytes._") {
+			t.Errorf("expected truncation at a rune boundary:\n%s", output)
+		}
+	})
+
+	t.Run("missing files are reported and left as written", func(t *testing.T) {
+		output, missing := ExpandFileReferences("Use @nope/gone.go and @docs/*.txt", root, settings)
+		if len(missing) != 2 || missing[0] != "@nope/gone.go" || missing[1] != "@docs/*.txt" {
+			t.Errorf("missing = %v", missing)
+		}
+		if output != "Use @nope/gone.go and @docs/*.txt" {
+			t.Errorf("unresolved references should be untouched, got %q", output)
+		}
+	})
+}
+
+func TestComposePipelineWithSettings_ExpandsFileReferences(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatalf("Failed to initialize project structure: %v", err)
+	}
+
+	os.WriteFile("schema.sql", []byte("CREATE TABLE users (id INT);"), 0644)
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.ContextsDir, "db.md"), "Schema:\n@schema.sql\n@missing.sql")
+
+	pipeline := &models.Pipeline{
+		Name: "refs",
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeContext, Path: "../components/contexts/db.md", Order: 1},
+		},
+	}
+
+	settings := models.DefaultSettings()
+	output, err := ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ComposePipelineWithSettings failed: %v", err)
+	}
+	if !strings.Contains(output, "@schema.sql") || strings.Contains(output, "CREATE TABLE") {
+		t.Error("references should be copied literally when expansion is disabled")
+	}
+
+	settings.Output.FileReferences.Expand = true
+	output, err = ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ComposePipelineWithSettings failed: %v", err)
+	}
+	if !strings.Contains(output, "```sql\nCREATE TABLE users (id INT);\n```") {
+		t.Errorf("expected expanded sql block:\n%s", output)
+	}
+	if !strings.Contains(output, "file references could not be loaded") || !strings.Contains(output, "- @missing.sql") {
+		t.Errorf("expected missing file warning:\n%s", output)
+	}
+}
@@ -0,0 +1,78 @@
+package composer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// ResolveOutputMode returns whether a pipeline is written inline or as @imports.
+// The pipeline's mode wins over the settings.
+func ResolveOutputMode(pipeline *models.Pipeline, settings *models.Settings) string {
+	if pipeline != nil && pipeline.Mode != "" {
+		return pipeline.Mode
+	}
+	if settings.Output.Mode != "" {
+		return settings.Output.Mode
+	}
+	return models.OutputModeInline
+}
+
+// ImportPath returns the @import for a component path relative to the .pluqqy
+// directory, as seen from the directory the output is written to
+// (e.g. @.pluqqy/components/contexts/api-docs.md)
+func ImportPath(componentPath string, settings *models.Settings) string {
+	target := filepath.Join(files.PluqqyDir, componentPath)
+
+	base := settings.Output.ExportPath
+	if base == "" {
+		base = "."
+	}
+	if rel, err := filepath.Rel(base, target); err == nil {
+		target = rel
+	}
+	return "@" + filepath.ToSlash(target)
+}
+
+// referenceComponents turns component refs into @imports, failing if any imported file is missing
+func referenceComponents(pipelineName string, refs []models.ComponentRef, settings *models.Settings) ([]componentWithContent, error) {
+	var imports []componentWithContent
+	var missing []string
+
+	for _, ref := range refs {
+		// Component paths in YAML are relative to the pipelines directory
+		componentPath := filepath.Clean(filepath.Join(files.PipelinesDir, ref.Path))
+		if _, err := os.Stat(filepath.Join(files.PluqqyDir, componentPath)); err != nil {
+			missing = append(missing, ref.Path)
+			continue
+		}
+
+		imports = append(imports, componentWithContent{
+			ref:     ref,
+			group:   ref.Type,
+			content: ImportPath(componentPath, settings),
+		})
+	}
+
+	if len(missing) > 0 {
+		return nil, fmt.Errorf("cannot reference components in '%s', imported files not found: %s",
+			pipelineName, strings.Join(missing, ", "))
+	}
+
+	return imports, nil
+}
+
+// composeReferences renders the @imports for a pipeline's components under their section headings
+func composeReferences(pipelineName string, refs []models.ComponentRef, settings *models.Settings, renderer Renderer) (string, []SourceRange, error) {
+	imports, err := referenceComponents(pipelineName, refs, settings)
+	if err != nil {
+		return "", nil, err
+	}
+
+	output, sources := renderer.Render(buildDocument(pipelineName, imports, settings))
+	return output, sources, nil
+}
@@ -0,0 +1,99 @@
+package composer
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestImportPath(t *testing.T) {
+	settings := models.DefaultSettings()
+	if got := ImportPath("components/contexts/api-docs.md", settings); got != "@.pluqqy/components/contexts/api-docs.md" {
+		t.Errorf("ImportPath from project root = %q", got)
+	}
+
+	settings.Output.ExportPath = ".github"
+	if got := ImportPath("components/rules/style.md", settings); got != "@../.pluqqy/components/rules/style.md" {
+		t.Errorf("ImportPath from .github = %q", got)
+	}
+}
+
+func TestComposePipeline_ReferenceMode(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+	pipeline.Mode = models.OutputModeReference
+
+	output, err := ComposePipelineWithSettings(pipeline, models.DefaultSettings())
+	if err != nil {
+		t.Fatalf("ComposePipelineWithSettings failed: %v", err)
+	}
+
+	expected := "## RULES\n\n@.pluqqy/components/rules/style.md\n\n" +
+		"## CONTEXTS\n\n@.pluqqy/components/contexts/api-docs.md\n\n" +
+		"## PROMPTS\n\n@.pluqqy/components/prompts/task.md\n\n"
+	if output != expected {
+		t.Errorf("unexpected reference output:\n%s\nwant:\n%s", output, expected)
+	}
+
+	// Editing a component's content does not change the output
+	files.WriteComponent("components/rules/style.md", "Use tabs.")
+	again, _ := ComposePipelineWithSettings(pipeline, models.DefaultSettings())
+	if again != output {
+		t.Error("reference output should only change when pipeline membership changes")
+	}
+}
+
+func TestComposePipeline_ReferenceModeFromSettings(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+
+	settings := models.DefaultSettings()
+	settings.Output.Mode = models.OutputModeReference
+	settings.Output.Formatting.ShowHeadings = false
+	if err := files.WriteSettings(settings); err != nil {
+		t.Fatalf("WriteSettings failed: %v", err)
+	}
+
+	output, err := ComposePipeline(pipeline)
+	if err != nil {
+		t.Fatalf("ComposePipeline failed: %v", err)
+	}
+	if !strings.HasPrefix(output, "@.pluqqy/components/rules/style.md\n") || strings.Contains(output, "Use gofmt.") {
+		t.Errorf("expected imports from project settings:\n%s", output)
+	}
+
+	// A pipeline can opt back into inlined content
+	pipeline.Mode = models.OutputModeInline
+	output, _ = ComposePipeline(pipeline)
+	if !strings.Contains(output, "Use gofmt.") {
+		t.Errorf("expected inline content for inline pipeline:\n%s", output)
+	}
+}
+
+func TestComposePipeline_ReferenceModeOutputPath(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+	pipeline.Mode = models.OutputModeReference
+	pipeline.OutputPath = "docs/AI.md"
+
+	// Imports are relative to the pipeline's own output file, which is written instead
+	output, err := ComposePipeline(pipeline)
+	if err != nil {
+		t.Fatalf("ComposePipeline failed: %v", err)
+	}
+	if !strings.Contains(output, "@../.pluqqy/components/rules/style.md\n") {
+		t.Errorf("expected imports relative to docs/:\n%s", output)
+	}
+}
+
+func TestComposePipeline_ReferenceModeMissingFile(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+	pipeline.Mode = models.OutputModeReference
+	pipeline.Components = append(pipeline.Components, models.ComponentRef{
+		Type: models.ComponentTypeContext, Path: "../components/contexts/gone.md", Order: 4,
+	})
+
+	_, err := ComposePipelineWithSettings(pipeline, models.DefaultSettings())
+	if err == nil || !strings.Contains(err.Error(), "imported files not found: ../components/contexts/gone.md") {
+		t.Errorf("expected missing import error, got %v", err)
+	}
+}
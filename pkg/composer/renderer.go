@@ -0,0 +1,358 @@
+package composer
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// Document is the composed content of a pipeline or component, ready to be rendered
+type Document struct {
+	Title             string // Pipeline or component name
+	ShowHeadings      bool
+	SourceMarkers     bool
+	Sections          []DocumentSection
+	MissingComponents []string
+	MissingFiles      []string
+}
+
+// DocumentSection groups the components of one type
+type DocumentSection struct {
+	Type       string
+	Heading    string
+	Components []DocumentComponent
+}
+
+// DocumentComponent is a single component's content within a section
+type DocumentComponent struct {
+	Path    string // Component path as written in the pipeline
+	Type    string
+	Order   int
+	Content string
+}
+
+// Renderer turns a document into output text. Renderers also return the line
+// range each component occupies, or nil when lines are not meaningful (JSON).
+type Renderer interface {
+	Render(doc *Document) (string, []SourceRange)
+}
+
+var (
+	renderersMu sync.RWMutex
+	renderers   = map[string]Renderer{
+		models.OutputFormatMarkdown: markdownRenderer{},
+		models.OutputFormatXML:      xmlRenderer{},
+		models.OutputFormatPlain:    plainRenderer{},
+		models.OutputFormatJSON:     jsonRenderer{},
+	}
+)
+
+// RegisterRenderer makes a renderer available under a format name
+func RegisterRenderer(format string, renderer Renderer) {
+	renderersMu.Lock()
+	defer renderersMu.Unlock()
+	renderers[format] = renderer
+}
+
+// GetRenderer returns the renderer for a format
+func GetRenderer(format string) (Renderer, error) {
+	if format == "" {
+		format = models.OutputFormatMarkdown
+	}
+	renderersMu.RLock()
+	renderer, ok := renderers[format]
+	renderersMu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unknown output format '%s', must be one of: %s", format, strings.Join(RendererNames(), ", "))
+	}
+	return renderer, nil
+}
+
+// RendererNames returns the sorted names of all available formats
+func RendererNames() []string {
+	renderersMu.RLock()
+	names := make([]string, 0, len(renderers))
+	for name := range renderers {
+		names = append(names, name)
+	}
+	renderersMu.RUnlock()
+	sort.Strings(names)
+	return names
+}
+
+// ResolveOutputFormat returns the format for a pipeline. A runtime override
+// (--format) wins over the pipeline's format, which wins over the settings.
+func ResolveOutputFormat(pipeline *models.Pipeline, settings *models.Settings) string {
+	if settings.FormatOverride != "" {
+		return settings.FormatOverride
+	}
+	if pipeline != nil && pipeline.Format != "" {
+		return pipeline.Format
+	}
+	if settings.Output.Format != "" {
+		return settings.Output.Format
+	}
+	return models.OutputFormatMarkdown
+}
+
+// buildDocument groups loaded components into the sections configured in settings
+func buildDocument(title string, components []componentWithContent, settings *models.Settings) *Document {
+	doc := &Document{
+		Title:         title,
+		ShowHeadings:  settings.Output.Formatting.ShowHeadings,
+		SourceMarkers: settings.Output.SourceMarkers,
+	}
+
+	// Group components by type while maintaining order
+	typeGroups := make(map[string][]componentWithContent)
+	for _, comp := range components {
+		typeGroups[comp.group] = append(typeGroups[comp.group], comp)
+	}
+
+	for _, section := range settings.OutputSections() {
+		group, exists := typeGroups[strings.ToLower(section.Type)]
+		if !exists || len(group) == 0 {
+			continue
+		}
+
+		docSection := DocumentSection{Type: section.Type, Heading: section.Heading}
+		for _, comp := range group {
+			docSection.Components = append(docSection.Components, DocumentComponent{
+				Path:    comp.ref.Path,
+				Type:    comp.ref.Type,
+				Order:   comp.ref.Order,
+				Content: comp.content,
+			})
+		}
+		doc.Sections = append(doc.Sections, docSection)
+	}
+
+	return doc
+}
+
+// documentWriter tracks lines while writing so renderers can build source maps
+type documentWriter struct {
+	strings.Builder
+	sources []SourceRange
+}
+
+// writeComponent writes a component's content, ensuring it ends with a newline, and records its lines
+func (w *documentWriter) writeComponent(comp DocumentComponent, heading string) {
+	startLine := lineCount(w.String()) + 1
+	w.WriteString(comp.Content)
+	if !strings.HasSuffix(comp.Content, "\n") {
+		w.WriteString("\n")
+	}
+	w.sources = append(w.sources, SourceRange{
+		Path:      SourceDisplayPath(comp.Path),
+		Type:      comp.Type,
+		Heading:   heading,
+		Order:     comp.Order,
+		StartLine: startLine,
+		EndLine:   lineCount(w.String()),
+		Tokens:    EstimateTokens(comp.Content),
+	})
+}
+
+// markdownRenderer writes sections under their configured Markdown headings
+type markdownRenderer struct{}
+
+func (markdownRenderer) Render(doc *Document) (string, []SourceRange) {
+	var w documentWriter
+
+	for _, section := range doc.Sections {
+		// Add section heading if enabled
+		heading := ""
+		if doc.ShowHeadings {
+			heading = section.Heading
+			w.WriteString(fmt.Sprintf("%s\n\n", section.Heading))
+		}
+
+		// Add components
+		for _, comp := range section.Components {
+			if doc.SourceMarkers {
+				w.WriteString(sourceMarker(comp.Path))
+			}
+			w.writeComponent(comp, heading)
+			w.WriteString("\n")
+		}
+	}
+
+	// Add warning about missing components
+	if len(doc.MissingComponents) > 0 {
+		w.WriteString("\n---\n")
+		w.WriteString("⚠️  Warning: The following components could not be loaded:\n")
+		for _, path := range doc.MissingComponents {
+			w.WriteString(fmt.Sprintf("   - %s\n", path))
+		}
+	}
+
+	// Add warning about file references that could not be expanded
+	if len(doc.MissingFiles) > 0 {
+		w.WriteString("\n---\n")
+		w.WriteString("⚠️  Warning: The following file references could not be loaded:\n")
+		for _, ref := range doc.MissingFiles {
+			w.WriteString(fmt.Sprintf("   - %s\n", ref))
+		}
+	}
+
+	return w.String(), w.sources
+}
+
+// xmlTagPattern matches characters that are not allowed in the tag names we generate
+var xmlTagPattern = regexp.MustCompile(`[^a-z0-9_-]+`)
+
+// xmlTagName turns a section type into a tag name, e.g. "rules" or "api_docs"
+func xmlTagName(sectionType string) string {
+	tag := strings.Trim(xmlTagPattern.ReplaceAllString(strings.ToLower(sectionType), "_"), "_")
+	if tag == "" {
+		return "section"
+	}
+	return tag
+}
+
+// xmlRenderer wraps each section in a tag named after its type, e.g. <rules>...</rules>
+type xmlRenderer struct{}
+
+// xmlTextEscaper escapes the characters that are markup in XML text
+var xmlTextEscaper = strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;")
+
+func (xmlRenderer) Render(doc *Document) (string, []SourceRange) {
+	var w documentWriter
+
+	for i, section := range doc.Sections {
+		if i > 0 {
+			w.WriteString("\n")
+		}
+		tag := xmlTagName(section.Type)
+		w.WriteString(fmt.Sprintf("<%s>\n", tag))
+
+		for j, comp := range section.Components {
+			if j > 0 {
+				w.WriteString("\n")
+			}
+			if doc.SourceMarkers {
+				w.WriteString(sourceMarker(comp.Path))
+			}
+			// Content like "<T>" or "a && b" must not open tags or entities of its own
+			comp.Content = xmlTextEscaper.Replace(comp.Content)
+			w.writeComponent(comp, tag)
+		}
+
+		w.WriteString(fmt.Sprintf("</%s>\n", tag))
+	}
+
+	if len(doc.MissingComponents) > 0 || len(doc.MissingFiles) > 0 {
+		w.WriteString("\n<warnings>\n")
+		for _, path := range doc.MissingComponents {
+			w.WriteString(fmt.Sprintf("Component could not be loaded: %s\n", xmlTextEscaper.Replace(path)))
+		}
+		for _, ref := range doc.MissingFiles {
+			w.WriteString(fmt.Sprintf("File reference could not be loaded: %s\n", xmlTextEscaper.Replace(ref)))
+		}
+		w.WriteString("</warnings>\n")
+	}
+
+	return w.String(), w.sources
+}
+
+// plainHeading strips Markdown heading markers, e.g. "## RULES" becomes "RULES"
+func plainHeading(heading string) string {
+	return strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(heading), "#"))
+}
+
+// plainRenderer writes section titles and content without any added markup
+type plainRenderer struct{}
+
+func (plainRenderer) Render(doc *Document) (string, []SourceRange) {
+	var w documentWriter
+
+	for _, section := range doc.Sections {
+		heading := ""
+		if doc.ShowHeadings {
+			heading = plainHeading(section.Heading)
+			w.WriteString(fmt.Sprintf("%s\n\n", heading))
+		}
+
+		for _, comp := range section.Components {
+			if doc.SourceMarkers {
+				w.WriteString(fmt.Sprintf("[source: %s]\n", SourceDisplayPath(comp.Path)))
+			}
+			w.writeComponent(comp, heading)
+			w.WriteString("\n")
+		}
+	}
+
+	if len(doc.MissingComponents) > 0 || len(doc.MissingFiles) > 0 {
+		w.WriteString("Warnings:\n")
+		for _, path := range doc.MissingComponents {
+			w.WriteString(fmt.Sprintf("- Component could not be loaded: %s\n", path))
+		}
+		for _, ref := range doc.MissingFiles {
+			w.WriteString(fmt.Sprintf("- File reference could not be loaded: %s\n", ref))
+		}
+	}
+
+	return w.String(), w.sources
+}
+
+// jsonDocument is the JSON shape written by the JSON renderer
+type jsonDocument struct {
+	Name              string        `json:"name,omitempty"`
+	Sections          []jsonSection `json:"sections"`
+	MissingComponents []string      `json:"missing_components,omitempty"`
+	MissingFiles      []string      `json:"missing_files,omitempty"`
+}
+
+type jsonSection struct {
+	Type       string          `json:"type"`
+	Heading    string          `json:"heading,omitempty"`
+	Components []jsonComponent `json:"components"`
+}
+
+type jsonComponent struct {
+	Path    string `json:"path"`
+	Type    string `json:"type"`
+	Order   int    `json:"order"`
+	Content string `json:"content"`
+}
+
+// jsonRenderer writes the sections and components as structured JSON
+type jsonRenderer struct{}
+
+func (jsonRenderer) Render(doc *Document) (string, []SourceRange) {
+	out := jsonDocument{
+		Name:              doc.Title,
+		Sections:          []jsonSection{},
+		MissingComponents: doc.MissingComponents,
+		MissingFiles:      doc.MissingFiles,
+	}
+
+	for _, section := range doc.Sections {
+		js := jsonSection{Type: section.Type, Components: []jsonComponent{}}
+		if doc.ShowHeadings {
+			js.Heading = section.Heading
+		}
+		for _, comp := range section.Components {
+			js.Components = append(js.Components, jsonComponent{
+				Path:    SourceDisplayPath(comp.Path),
+				Type:    comp.Type,
+				Order:   comp.Order,
+				Content: strings.TrimSpace(comp.Content),
+			})
+		}
+		out.Sections = append(out.Sections, js)
+	}
+
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		// Only unsupported values can fail to marshal; the document holds plain strings
+		return "", nil
+	}
+	return string(data) + "\n", nil
+}
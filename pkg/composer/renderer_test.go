@@ -0,0 +1,225 @@
+package composer
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestResolveOutputFormat(t *testing.T) {
+	settings := models.DefaultSettings()
+	pipeline := &models.Pipeline{Name: "test"}
+
+	if got := ResolveOutputFormat(pipeline, settings); got != models.OutputFormatMarkdown {
+		t.Errorf("default format = %q, want markdown", got)
+	}
+
+	settings.Output.Format = models.OutputFormatPlain
+	if got := ResolveOutputFormat(pipeline, settings); got != models.OutputFormatPlain {
+		t.Errorf("settings format = %q, want plain", got)
+	}
+
+	pipeline.Format = models.OutputFormatXML
+	if got := ResolveOutputFormat(pipeline, settings); got != models.OutputFormatXML {
+		t.Errorf("pipeline format = %q, want xml", got)
+	}
+
+	settings.FormatOverride = models.OutputFormatJSON
+	if got := ResolveOutputFormat(pipeline, settings); got != models.OutputFormatJSON {
+		t.Errorf("override format = %q, want json", got)
+	}
+}
+
+func TestGetRenderer_Unknown(t *testing.T) {
+	if _, err := GetRenderer("html"); err == nil || !strings.Contains(err.Error(), "markdown, plain, xml") {
+		t.Errorf("expected unknown format error listing formats, got %v", err)
+	}
+}
+
+func TestRenderers_ComposePipeline(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+	settings := models.DefaultSettings()
+
+	markdown, err := ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("markdown compose failed: %v", err)
+	}
+	if !strings.HasPrefix(markdown, "## RULES\n\nUse gofmt.") {
+		t.Errorf("unexpected markdown output:\n%s", markdown)
+	}
+
+	settings.FormatOverride = models.OutputFormatXML
+	xml, err := ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("xml compose failed: %v", err)
+	}
+	if !strings.Contains(xml, "<rules>\nUse gofmt.\nKeep functions short.\n</rules>") {
+		t.Errorf("expected rules wrapped in tags:\n%s", xml)
+	}
+	if strings.Contains(xml, "## RULES") {
+		t.Errorf("xml output should not contain markdown headings:\n%s", xml)
+	}
+
+	settings.FormatOverride = models.OutputFormatPlain
+	plain, err := ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("plain compose failed: %v", err)
+	}
+	if !strings.HasPrefix(plain, "RULES\n\nUse gofmt.") {
+		t.Errorf("expected plain heading without markers:\n%s", plain)
+	}
+
+	settings.FormatOverride = models.OutputFormatJSON
+	output, err := ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("json compose failed: %v", err)
+	}
+	var doc jsonDocument
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("json output does not parse: %v\n%s", err, output)
+	}
+	if doc.Name != "mapped" || len(doc.Sections) != 3 {
+		t.Fatalf("unexpected json document: %+v", doc)
+	}
+	if comp := doc.Sections[0].Components[0]; comp.Path != "rules/style.md" || comp.Content != "Use gofmt.\nKeep functions short." {
+		t.Errorf("unexpected first component: %+v", comp)
+	}
+}
+
+func TestRenderers_SourceMapAndMissing(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+	pipeline.Components = append(pipeline.Components, models.ComponentRef{
+		Type: models.ComponentTypeContext, Path: "../components/contexts/gone.md", Order: 4,
+	})
+	settings := models.DefaultSettings()
+	settings.FormatOverride = models.OutputFormatXML
+
+	output, _, sources, err := composePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("compose failed: %v", err)
+	}
+	if len(sources) != 3 {
+		t.Fatalf("expected 3 source ranges, got %d", len(sources))
+	}
+	lines := strings.Split(output, "\n")
+	if first := lines[sources[0].StartLine-1]; first != "Use gofmt." {
+		t.Errorf("source range starts at %q", first)
+	}
+	if !strings.Contains(output, "<warnings>\nComponent could not be loaded: ../components/contexts/gone.md\n</warnings>") {
+		t.Errorf("expected missing component warning:\n%s", output)
+	}
+}
+
+func TestRenderers_PipelineFormatFromProjectSettings(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+
+	settings := models.DefaultSettings()
+	settings.Output.Format = models.OutputFormatXML
+	if err := files.WriteSettings(settings); err != nil {
+		t.Fatalf("WriteSettings failed: %v", err)
+	}
+
+	output, err := ComposePipeline(pipeline)
+	if err != nil {
+		t.Fatalf("ComposePipeline failed: %v", err)
+	}
+	if !strings.HasPrefix(output, "<rules>\n") {
+		t.Errorf("expected xml output from project settings:\n%s", output)
+	}
+
+	// The pipeline's own format wins over the settings
+	pipeline.Format = models.OutputFormatMarkdown
+	output, _ = ComposePipeline(pipeline)
+	if !strings.HasPrefix(output, "# mapped\n") {
+		t.Errorf("expected markdown output for pipeline format:\n%s", output)
+	}
+
+	pipeline.Format = "html"
+	if _, err := ComposePipeline(pipeline); err == nil {
+		t.Error("expected error for unknown pipeline format")
+	}
+}
+
+func TestComposeComponentWithSettings_Format(t *testing.T) {
+	settings := models.DefaultSettings()
+	settings.FormatOverride = models.OutputFormatXML
+	component := &models.Component{
+		Name:    "style",
+		Path:    "components/rules/style.md",
+		Type:    models.ComponentTypeRules,
+		Content: "Use gofmt.",
+	}
+
+	output, err := ComposeComponentWithSettings(component, settings)
+	if err != nil {
+		t.Fatalf("ComposeComponentWithSettings failed: %v", err)
+	}
+	if output != "<rules>\nUse gofmt.\n</rules>\n" {
+		t.Errorf("unexpected xml component output: %q", output)
+	}
+}
+
+func TestXMLTagName(t *testing.T) {
+	tests := map[string]string{
+		"rules":     "rules",
+		"API Docs":  "api_docs",
+		"contexts!": "contexts",
+		"***":       "section",
+	}
+	for input, expected := range tests {
+		if got := xmlTagName(input); got != expected {
+			t.Errorf("xmlTagName(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func TestComposePipeline_CustomComponentType(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+	files.WriteComponent("components/examples/good-pr.md", "Small, focused diffs.")
+	pipeline.Components = append(pipeline.Components, models.ComponentRef{
+		Type: "examples", Path: "../components/examples/good-pr.md", Order: 4,
+	})
+
+	settings := models.DefaultSettings()
+	settings.ComponentTypes = []models.ComponentTypeConfig{{Name: "examples", Order: 3}}
+	if err := files.WriteSettings(settings); err != nil {
+		t.Fatalf("WriteSettings failed: %v", err)
+	}
+
+	output, err := ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ComposePipelineWithSettings failed: %v", err)
+	}
+
+	// The custom section is placed third, before the prompts
+	expected := "## RULES\n\nUse gofmt.\nKeep functions short.\n\n" +
+		"## CONTEXTS\n\nThe API is REST.\n\n" +
+		"## EXAMPLES\n\nSmall, focused diffs.\n\n" +
+		"## PROMPTS\n\nAdd pagination.\n\n"
+	if output != expected {
+		t.Errorf("unexpected output:\n%s\nwant:\n%s", output, expected)
+	}
+}
+
+func TestXMLRenderer_EscapesContent(t *testing.T) {
+	doc := &Document{
+		Sections: []DocumentSection{{
+			Type: models.ComponentTypeRules,
+			Components: []DocumentComponent{
+				{Path: "rules/generics.md", Content: "Use List<T> & keep </rules> out of prose."},
+			},
+		}},
+		MissingFiles: []string{"@docs/a<b>.md"},
+	}
+
+	output, _ := xmlRenderer{}.Render(doc)
+	if !strings.Contains(output, "<rules>\nUse List&lt;T&gt; &amp; keep &lt;/rules&gt; out of prose.\n</rules>") {
+		t.Errorf("expected escaped component content:\n%s", output)
+	}
+	if !strings.Contains(output, "@docs/a&lt;b&gt;.md") {
+		t.Errorf("expected escaped warnings:\n%s", output)
+	}
+}
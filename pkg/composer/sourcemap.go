@@ -0,0 +1,47 @@
+package composer
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// SourceRange maps a range of lines in composed output back to the component that produced it
+type SourceRange struct {
+	Path      string // Component path relative to .pluqqy, e.g. components/contexts/api-docs.md
+	Type      string
+	Heading   string // Section heading the component was written under, empty when headings are off
+	Order     int
+	StartLine int // First line of the component in the output (1-based)
+	EndLine   int // Last line of the component in the output
+	Tokens    int
+}
+
+// SourceDisplayPath turns a component path as written in a pipeline
+// (../components/contexts/api-docs.md) into the short form used in source
+// markers and explanations (contexts/api-docs.md)
+func SourceDisplayPath(refPath string) string {
+	path := filepath.ToSlash(filepath.Clean(refPath))
+	path = strings.TrimPrefix(path, "../")
+	return strings.TrimPrefix(path, "components/")
+}
+
+// sourceMarker returns the HTML comment that precedes a component when source markers are enabled
+func sourceMarker(refPath string) string {
+	return fmt.Sprintf("<!-- source: %s -->\n", SourceDisplayPath(refPath))
+}
+
+// lineCount returns the number of complete lines written so far
+func lineCount(text string) int {
+	return strings.Count(text, "\n")
+}
+
+// SourceForLine returns the source range containing a line of composed output
+func SourceForLine(sources []SourceRange, line int) (SourceRange, bool) {
+	for _, source := range sources {
+		if line >= source.StartLine && line <= source.EndLine {
+			return source, true
+		}
+	}
+	return SourceRange{}, false
+}
@@ -0,0 +1,140 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestSourceDisplayPath(t *testing.T) {
+	tests := map[string]string{
+		"../components/contexts/api-docs.md":          "contexts/api-docs.md",
+		"components/rules/style.md":                   "rules/style.md",
+		"../archive/components/prompts/old-task.md":   "archive/components/prompts/old-task.md",
+		"../components/contexts/../contexts/again.md": "contexts/again.md",
+	}
+
+	for input, expected := range tests {
+		if got := SourceDisplayPath(input); got != expected {
+			t.Errorf("SourceDisplayPath(%q) = %q, want %q", input, got, expected)
+		}
+	}
+}
+
+func setupSourceMapPipeline(t *testing.T) *models.Pipeline {
+	t.Helper()
+
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatalf("Failed to initialize project structure: %v", err)
+	}
+
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.RulesDir, "style.md"), "Use gofmt.\nKeep functions short.")
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.ContextsDir, "api-docs.md"), "The API is REST.")
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.PromptsDir, "task.md"), "Add pagination.")
+
+	return &models.Pipeline{
+		Name: "mapped",
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeContext, Path: "../components/contexts/api-docs.md", Order: 1},
+			{Type: models.ComponentTypeRules, Path: "../components/rules/style.md", Order: 2},
+			{Type: models.ComponentTypePrompt, Path: "../components/prompts/task.md", Order: 3},
+		},
+	}
+}
+
+func TestExplainPipeline_SourceMap(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+	settings := models.DefaultSettings()
+
+	output, err := ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ComposePipelineWithSettings failed: %v", err)
+	}
+	lines := strings.Split(output, "\n")
+
+	explanation, err := ExplainPipeline(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ExplainPipeline failed: %v", err)
+	}
+	if len(explanation.Sources) != 3 {
+		t.Fatalf("expected 3 source ranges, got %d", len(explanation.Sources))
+	}
+
+	// Rules come first because of the configured section order
+	style := explanation.Sources[0]
+	if style.Path != "rules/style.md" || style.Heading != "## RULES" || style.Order != 2 {
+		t.Errorf("unexpected first source: %+v", style)
+	}
+	if style.StartLine != 3 || style.EndLine != 4 {
+		t.Errorf("style.md lines = %d-%d, want 3-4", style.StartLine, style.EndLine)
+	}
+	if style.Tokens == 0 {
+		t.Error("expected a token count")
+	}
+
+	for _, source := range explanation.Sources {
+		text := strings.Join(lines[source.StartLine-1:source.EndLine], "\n")
+		component, _ := files.ReadComponent(filepath.Join("components", source.Path))
+		if strings.TrimSpace(text) != strings.TrimSpace(component.Content) {
+			t.Errorf("lines %d-%d = %q, want content of %s", source.StartLine, source.EndLine, text, source.Path)
+		}
+	}
+
+	if source, ok := SourceForLine(explanation.Sources, 4); !ok || source.Path != "rules/style.md" {
+		t.Errorf("SourceForLine(4) = %+v, %v", source, ok)
+	}
+	if _, ok := SourceForLine(explanation.Sources, 1); ok {
+		t.Error("heading line should not map to a component")
+	}
+}
+
+func TestSourceMarkers(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+
+	settings := models.DefaultSettings()
+	settings.Output.SourceMarkers = true
+
+	output, err := ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ComposePipelineWithSettings failed: %v", err)
+	}
+	if !strings.Contains(output, "<!-- source: rules/style.md -->\nUse gofmt.") {
+		t.Errorf("expected source marker before component:\n%s", output)
+	}
+	if strings.Count(output, "<!-- source: ") != 3 {
+		t.Errorf("expected one marker per component:\n%s", output)
+	}
+
+	// Source ranges still point at the component content, not the marker
+	explanation, _ := ExplainPipeline(pipeline, settings)
+	lines := strings.Split(output, "\n")
+	if first := lines[explanation.Sources[0].StartLine-1]; first != "Use gofmt." {
+		t.Errorf("source range starts at %q", first)
+	}
+
+	// The project-settings composer honours the setting too
+	settings.Output.SourceMarkers = false
+	if err := files.WriteSettings(settings); err != nil {
+		t.Fatalf("WriteSettings failed: %v", err)
+	}
+	output, _ = ComposePipeline(pipeline)
+	if strings.Contains(output, "<!-- source:") {
+		t.Error("markers should be off by default")
+	}
+
+	settings.Output.SourceMarkers = true
+	files.WriteSettings(settings)
+	output, _ = ComposePipeline(pipeline)
+	if !strings.Contains(output, "<!-- source: prompts/task.md -->") {
+		t.Errorf("expected source markers from project settings:\n%s", output)
+	}
+}
@@ -0,0 +1,135 @@
+package composer
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// TargetOutput is the composed content for one output target
+type TargetOutput struct {
+	Path    string
+	Format  string
+	Content string
+	Report  *BudgetReport // Nil when the pipeline has no token budget
+}
+
+// OutputTargets returns the files set writes: the pipeline's own targets if it
+// has any, then its output_path, otherwise the project targets. Nil means the
+// single default output file.
+func OutputTargets(pipeline *models.Pipeline, settings *models.Settings) []models.OutputTarget {
+	if pipeline != nil && len(pipeline.Targets) > 0 {
+		return pipeline.Targets
+	}
+	if pipeline != nil && pipeline.OutputPath != "" {
+		return []models.OutputTarget{{Path: pipeline.OutputPath}}
+	}
+	return settings.Output.Targets
+}
+
+// SettingsForTarget returns a copy of settings with the target's format and
+// section layout applied. A target without a format keeps the runtime override,
+// so --format still applies to it.
+func SettingsForTarget(settings *models.Settings, target models.OutputTarget) *models.Settings {
+	targetSettings := *settings
+	if target.Format != "" {
+		targetSettings.FormatOverride = target.Format
+	}
+	if len(target.Sections) > 0 {
+		targetSettings.Output.Formatting.Sections = append([]models.Section(nil), target.Sections...)
+	}
+	if target.ShowHeadings != nil {
+		targetSettings.Output.Formatting.ShowHeadings = *target.ShowHeadings
+	}
+	// @imports are resolved relative to the file that contains them
+	targetSettings.Output.ExportPath = filepath.Dir(TargetPath(target.Path))
+	return &targetSettings
+}
+
+// ComposePipelineTargets composes a pipeline once for each of its output targets.
+// Every target is composed before any is written, so a failure leaves no target out of date.
+func ComposePipelineTargets(pipeline *models.Pipeline, settings *models.Settings) ([]TargetOutput, error) {
+	if pipeline == nil {
+		return nil, fmt.Errorf("cannot compose pipeline: nil pipeline provided")
+	}
+
+	targets := OutputTargets(pipeline, settings)
+	if err := models.ValidateOutputTargets(targets); err != nil {
+		return nil, fmt.Errorf("invalid output targets for '%s': %w", pipeline.Name, err)
+	}
+
+	var outputs []TargetOutput
+	for _, target := range targets {
+		targetSettings := SettingsForTarget(settings, target)
+		content, report, err := ComposePipelineWithSettingsReport(pipeline, targetSettings)
+		if err != nil {
+			return nil, fmt.Errorf("target '%s': %w", target.Path, err)
+		}
+		outputs = append(outputs, TargetOutput{
+			Path:    target.Path,
+			Format:  ResolveOutputFormat(pipeline, targetSettings),
+			Content: content,
+			Report:  report,
+		})
+	}
+
+	return outputs, nil
+}
+
+// ComposeComponentTargets composes a single component once for each project output target
+func ComposeComponentTargets(component *models.Component, settings *models.Settings) ([]TargetOutput, error) {
+	if component == nil {
+		return nil, fmt.Errorf("cannot compose component: nil component provided")
+	}
+
+	targets := OutputTargets(nil, settings)
+	if err := models.ValidateOutputTargets(targets); err != nil {
+		return nil, fmt.Errorf("invalid output targets: %w", err)
+	}
+
+	var outputs []TargetOutput
+	for _, target := range targets {
+		targetSettings := SettingsForTarget(settings, target)
+		content, err := ComposeComponentWithSettings(component, targetSettings)
+		if err != nil {
+			return nil, fmt.Errorf("target '%s': %w", target.Path, err)
+		}
+		outputs = append(outputs, TargetOutput{
+			Path:    target.Path,
+			Format:  ResolveOutputFormat(nil, targetSettings),
+			Content: content,
+		})
+	}
+
+	return outputs, nil
+}
+
+// WriteTargetOutputs writes every composed target or, if any can't be written,
+// none of them, creating parent directories as needed. Target paths are
+// relative to the project root.
+func WriteTargetOutputs(outputs []TargetOutput) error {
+	var paths, contents []string
+	for _, output := range outputs {
+		path := TargetPath(output.Path)
+		if dir := filepath.Dir(path); dir != "" && dir != "." {
+			if err := os.MkdirAll(dir, 0755); err != nil {
+				return fmt.Errorf("failed to create output directory '%s': %w", dir, err)
+			}
+		}
+		paths = append(paths, path)
+		contents = append(contents, output.Content)
+	}
+	return files.WriteFiles(paths, contents)
+}
+
+// TargetPath resolves an output target's path against the project root, the
+// directory that holds .pluqqy, rather than the working directory
+func TargetPath(path string) string {
+	if filepath.IsAbs(path) {
+		return path
+	}
+	return filepath.Join(filepath.Dir(files.PluqqyDir), path)
+}
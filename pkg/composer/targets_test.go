@@ -0,0 +1,156 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestOutputTargets_PipelineReplacesSettings(t *testing.T) {
+	settings := models.DefaultSettings()
+	pipeline := &models.Pipeline{Name: "test"}
+
+	if targets := OutputTargets(pipeline, settings); targets != nil {
+		t.Errorf("expected no targets by default, got %+v", targets)
+	}
+
+	settings.Output.Targets = []models.OutputTarget{{Path: "CLAUDE.md"}, {Path: "AGENTS.md"}}
+	if targets := OutputTargets(pipeline, settings); len(targets) != 2 {
+		t.Errorf("expected project targets, got %+v", targets)
+	}
+
+	pipeline.OutputPath = "docs/REVIEW.md"
+	if targets := OutputTargets(pipeline, settings); len(targets) != 1 || targets[0].Path != "docs/REVIEW.md" {
+		t.Errorf("expected the pipeline's output path to replace project targets, got %+v", targets)
+	}
+
+	pipeline.Targets = []models.OutputTarget{{Path: ".cursorrules"}}
+	if targets := OutputTargets(pipeline, settings); len(targets) != 1 || targets[0].Path != ".cursorrules" {
+		t.Errorf("expected pipeline targets to replace project targets, got %+v", targets)
+	}
+}
+
+func TestSettingsForTarget(t *testing.T) {
+	settings := models.DefaultSettings()
+	settings.FormatOverride = models.OutputFormatPlain
+	noHeadings := false
+
+	targetSettings := SettingsForTarget(settings, models.OutputTarget{
+		Path:         "CLAUDE.md",
+		Format:       models.OutputFormatXML,
+		Sections:     []models.Section{{Type: "prompts", Heading: "## TASK"}},
+		ShowHeadings: &noHeadings,
+	})
+	if targetSettings.FormatOverride != models.OutputFormatXML {
+		t.Errorf("target format should win, got %q", targetSettings.FormatOverride)
+	}
+	if len(targetSettings.Output.Formatting.Sections) != 1 || targetSettings.Output.Formatting.ShowHeadings {
+		t.Errorf("expected target section layout, got %+v", targetSettings.Output.Formatting)
+	}
+	if len(settings.Output.Formatting.Sections) != 3 || !settings.Output.Formatting.ShowHeadings {
+		t.Error("original settings should not change")
+	}
+
+	// Without its own format the runtime override still applies
+	if got := SettingsForTarget(settings, models.OutputTarget{Path: "AGENTS.md"}).FormatOverride; got != models.OutputFormatPlain {
+		t.Errorf("expected runtime format for target without format, got %q", got)
+	}
+}
+
+func TestComposePipelineTargets(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+	pipeline.Targets = []models.OutputTarget{
+		{Path: "CLAUDE.md"},
+		{Path: ".cursorrules", Format: models.OutputFormatPlain},
+		{Path: ".github/copilot-instructions.md", Sections: []models.Section{{Type: "prompts", Heading: "## TASK"}}},
+	}
+
+	outputs, err := ComposePipelineTargets(pipeline, models.DefaultSettings())
+	if err != nil {
+		t.Fatalf("ComposePipelineTargets failed: %v", err)
+	}
+	if len(outputs) != 3 {
+		t.Fatalf("expected 3 outputs, got %d", len(outputs))
+	}
+
+	if !strings.HasPrefix(outputs[0].Content, "## RULES\n") || outputs[0].Format != models.OutputFormatMarkdown {
+		t.Errorf("unexpected CLAUDE.md output (%s):\n%s", outputs[0].Format, outputs[0].Content)
+	}
+	if !strings.HasPrefix(outputs[1].Content, "RULES\n") || outputs[1].Format != models.OutputFormatPlain {
+		t.Errorf("unexpected .cursorrules output (%s):\n%s", outputs[1].Format, outputs[1].Content)
+	}
+	if outputs[2].Content != "## TASK\n\nAdd pagination.\n\n" {
+		t.Errorf("expected only the configured section in copilot output, got %q", outputs[2].Content)
+	}
+
+	if err := WriteTargetOutputs(outputs); err != nil {
+		t.Fatalf("WriteTargetOutputs failed: %v", err)
+	}
+	for _, output := range outputs {
+		data, err := os.ReadFile(output.Path)
+		if err != nil {
+			t.Fatalf("expected %s to be written: %v", output.Path, err)
+		}
+		if string(data) != output.Content {
+			t.Errorf("%s content does not match composed output", output.Path)
+		}
+	}
+}
+
+func TestComposePipelineTargets_Errors(t *testing.T) {
+	pipeline := setupSourceMapPipeline(t)
+
+	pipeline.Targets = []models.OutputTarget{{Path: "CLAUDE.md"}, {Path: "./CLAUDE.md"}}
+	if _, err := ComposePipelineTargets(pipeline, models.DefaultSettings()); err == nil || !strings.Contains(err.Error(), "duplicate path") {
+		t.Errorf("expected duplicate path error, got %v", err)
+	}
+
+	// A failing target means nothing is returned to write
+	pipeline.Targets = []models.OutputTarget{{Path: "CLAUDE.md"}, {Path: "AGENTS.md", Format: "html"}}
+	outputs, err := ComposePipelineTargets(pipeline, models.DefaultSettings())
+	if err == nil || !strings.Contains(err.Error(), "target 'AGENTS.md'") {
+		t.Errorf("expected error naming the failing target, got %v", err)
+	}
+	if outputs != nil {
+		t.Errorf("expected no outputs on failure, got %d", len(outputs))
+	}
+}
+
+func TestWriteTargetOutputs_AllOrNothing(t *testing.T) {
+	setupSourceMapPipeline(t)
+	if err := os.WriteFile("CLAUDE.md", []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	// The second target is too large to write, so the first must not change
+	outputs := []TargetOutput{
+		{Path: "CLAUDE.md", Content: "new"},
+		{Path: "docs/AGENTS.md", Content: strings.Repeat("x", files.MaxFileSize+1)},
+	}
+	if err := WriteTargetOutputs(outputs); err == nil {
+		t.Fatal("expected an error for the oversized target")
+	}
+	if data, _ := os.ReadFile("CLAUDE.md"); string(data) != "old" {
+		t.Errorf("CLAUDE.md = %q, want it left as it was", data)
+	}
+	for _, dir := range []string{".", "docs"} {
+		temps, _ := filepath.Glob(filepath.Join(dir, ".tmp-*"))
+		if len(temps) > 0 {
+			t.Errorf("temp files left behind: %v", temps)
+		}
+	}
+
+	outputs[1].Content = "agents"
+	if err := WriteTargetOutputs(outputs); err != nil {
+		t.Fatalf("WriteTargetOutputs failed: %v", err)
+	}
+	for _, output := range outputs {
+		if data, _ := os.ReadFile(output.Path); string(data) != output.Content {
+			t.Errorf("%s = %q, want %q", output.Path, data, output.Content)
+		}
+	}
+}
@@ -0,0 +1,142 @@
+package composer
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// variablePattern matches {{name}} placeholders, allowing spaces inside the braces
+var variablePattern = regexp.MustCompile(`\{\{\s*([A-Za-z_][A-Za-z0-9_.-]*)\s*\}\}`)
+
+// ExtractVariables returns the unique variable names used in content, in order of first use
+func ExtractVariables(content string) []string {
+	var names []string
+	seen := make(map[string]bool)
+
+	for _, match := range variablePattern.FindAllStringSubmatch(content, -1) {
+		name := match[1]
+		if !seen[name] {
+			seen[name] = true
+			names = append(names, name)
+		}
+	}
+
+	return names
+}
+
+// SubstituteVariables replaces placeholders with their values. Placeholders without
+// a value are left visible and their names are returned.
+func SubstituteVariables(content string, values map[string]string) (string, []string) {
+	var unresolved []string
+	seen := make(map[string]bool)
+
+	result := variablePattern.ReplaceAllStringFunc(content, func(placeholder string) string {
+		name := variablePattern.FindStringSubmatch(placeholder)[1]
+		if value, ok := values[name]; ok {
+			return value
+		}
+		if !seen[name] {
+			seen[name] = true
+			unresolved = append(unresolved, name)
+		}
+		return placeholder
+	})
+
+	return result, unresolved
+}
+
+// ResolveVariables merges variable values for a pipeline. Project settings provide
+// defaults, the pipeline overrides them, and runtime overrides (such as --var flags)
+// take precedence over both. The pipeline may be nil when composing a single component.
+func ResolveVariables(pipeline *models.Pipeline, settings *models.Settings) map[string]string {
+	values := make(map[string]string)
+
+	for name, value := range settings.Variables {
+		values[name] = value
+	}
+	if pipeline != nil {
+		for name, value := range pipeline.Variables {
+			values[name] = value
+		}
+	}
+	for name, value := range settings.VariableOverrides {
+		values[name] = value
+	}
+
+	return values
+}
+
+// UnresolvedVariables loads every component in a pipeline whose when: condition
+// holds and returns the sorted names of variables that have no value from
+// settings, the pipeline or overrides
+func UnresolvedVariables(pipeline *models.Pipeline, settings *models.Settings) ([]string, error) {
+	if pipeline == nil {
+		return nil, fmt.Errorf("cannot check variables: nil pipeline provided")
+	}
+
+	pipeline, err := files.ResolvePipelineIncludes(pipeline)
+	if err != nil {
+		return nil, err
+	}
+
+	values := ResolveVariables(pipeline, settings)
+	missing := make(map[string]bool)
+
+	// Components left out by their when: conditions don't need values
+	components, _ := FilterComponents(pipeline.Components, NewConditionContext(pipeline, settings))
+	for _, compRef := range components {
+		componentPath := filepath.Clean(filepath.Join(files.PipelinesDir, compRef.Path))
+		isArchived := strings.Contains(componentPath, "/archive/")
+
+		component, err := files.ReadArchivedOrActiveComponent(componentPath, isArchived)
+		if err != nil {
+			// Missing components are reported by composition itself
+			continue
+		}
+
+		for _, name := range ExtractVariables(component.Content) {
+			if _, ok := values[name]; !ok {
+				missing[name] = true
+			}
+		}
+	}
+
+	names := make([]string, 0, len(missing))
+	for name := range missing {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names, nil
+}
+
+// substituteComponentContent fills in variables and records any that remain unresolved
+func substituteComponentContent(content string, values map[string]string, unresolved map[string]bool) string {
+	result, missing := SubstituteVariables(content, values)
+	for _, name := range missing {
+		unresolved[name] = true
+	}
+	return result
+}
+
+// checkUnresolvedVariables returns an error in strict mode when variables are left unresolved
+func checkUnresolvedVariables(itemName string, unresolved map[string]bool, settings *models.Settings) error {
+	if !settings.Output.StrictVariables || len(unresolved) == 0 {
+		return nil
+	}
+
+	names := make([]string, 0, len(unresolved))
+	for name := range unresolved {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return fmt.Errorf("unresolved template variables in '%s': %s (set them with --var name=value)",
+		itemName, strings.Join(names, ", "))
+}
@@ -0,0 +1,109 @@
+package composer
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestExtractVariables(t *testing.T) {
+	content := "Project {{project_name}} ticket {{ ticket }} again {{project_name}} not {{ 1bad }}"
+	got := ExtractVariables(content)
+	want := []string{"project_name", "ticket"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ExtractVariables() = %v, want %v", got, want)
+	}
+}
+
+func TestSubstituteVariables(t *testing.T) {
+	content := "Working on {{ticket}} for {{project_name}} in {{env}}"
+	result, unresolved := SubstituteVariables(content, map[string]string{
+		"ticket":       "PLQ-42",
+		"project_name": "pluqqy",
+	})
+
+	if result != "Working on PLQ-42 for pluqqy in {{env}}" {
+		t.Errorf("SubstituteVariables() = %q", result)
+	}
+	if !reflect.DeepEqual(unresolved, []string{"env"}) {
+		t.Errorf("unresolved = %v, want [env]", unresolved)
+	}
+}
+
+func TestResolveVariablesPrecedence(t *testing.T) {
+	settings := models.DefaultSettings()
+	settings.Variables = map[string]string{"a": "settings", "b": "settings", "c": "settings"}
+	settings.VariableOverrides = map[string]string{"c": "override"}
+	pipeline := &models.Pipeline{Variables: map[string]string{"b": "pipeline", "c": "pipeline"}}
+
+	got := ResolveVariables(pipeline, settings)
+	want := map[string]string{"a": "settings", "b": "pipeline", "c": "override"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ResolveVariables() = %v, want %v", got, want)
+	}
+}
+
+func TestComposePipelineWithSettings_Variables(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatalf("Failed to initialize project structure: %v", err)
+	}
+
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.ContextsDir, "project.md"), "Project: {{project_name}}\nTicket: {{ticket}}")
+	files.WriteComponent(filepath.Join(files.ComponentsDir, files.ContextsDir, "deploy.md"), "Deploy to {{cluster}}")
+
+	// deploy.md is skipped by its condition, so cluster isn't asked for
+	pipeline := &models.Pipeline{
+		Name:      "vars",
+		Variables: map[string]string{"project_name": "pluqqy"},
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeContext, Path: "../components/contexts/project.md", Order: 1},
+			{Type: models.ComponentTypeContext, Path: "../components/contexts/deploy.md", Order: 2,
+				When: &models.Condition{Var: "target=backend"}},
+		},
+	}
+
+	settings := models.DefaultSettings()
+
+	missing, err := UnresolvedVariables(pipeline, settings)
+	if err != nil {
+		t.Fatalf("UnresolvedVariables failed: %v", err)
+	}
+	if !reflect.DeepEqual(missing, []string{"ticket"}) {
+		t.Errorf("UnresolvedVariables() = %v, want [ticket]", missing)
+	}
+
+	// Lenient mode leaves unresolved placeholders visible
+	output, err := ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ComposePipelineWithSettings failed: %v", err)
+	}
+	if !strings.Contains(output, "Project: pluqqy") || !strings.Contains(output, "Ticket: {{ticket}}") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+
+	// Strict mode fails on unresolved placeholders
+	settings.Output.StrictVariables = true
+	if _, err := ComposePipelineWithSettings(pipeline, settings); err == nil || !strings.Contains(err.Error(), "ticket") {
+		t.Errorf("expected strict mode error naming 'ticket', got %v", err)
+	}
+
+	// Overrides satisfy strict mode
+	settings.VariableOverrides = map[string]string{"ticket": "PLQ-7"}
+	output, err = ComposePipelineWithSettings(pipeline, settings)
+	if err != nil {
+		t.Fatalf("ComposePipelineWithSettings failed: %v", err)
+	}
+	if !strings.Contains(output, "Ticket: PLQ-7") {
+		t.Errorf("override not applied:\n%s", output)
+	}
+}
@@ -0,0 +1,467 @@
+// Package doctor checks a whole pluqqy library for problems that the rest of the
+// tool tolerates or silently skips, and repairs the ones that are safe to fix.
+package doctor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/composer"
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"github.com/pluqqy/pluqqy-terminal/pkg/tags"
+)
+
+// Problem severities
+const (
+	SeverityError   = "error"   // The item is broken or can't be composed as written
+	SeverityWarning = "warning" // The item works but is out of date or inconsistent
+)
+
+// Checks a problem can come from
+const (
+	CheckUnreadable        = "unreadable"         // A file or its YAML can't be read
+	CheckOversized         = "oversized"          // A file is too large to load or to inline
+	CheckFrontmatter       = "frontmatter"        // Component frontmatter is ignored because it's malformed
+	CheckInvalidPipeline   = "invalid-pipeline"   // Pipeline.Validate fails for a reason other than orders
+	CheckComponentOrder    = "component-order"    // Component orders are duplicated or not positive
+	CheckMissingComponent  = "missing-component"  // A pipeline includes a component that doesn't exist
+	CheckArchivedComponent = "archived-component" // A pipeline includes a component that was archived
+	CheckBrokenExtends     = "broken-extends"     // A pipeline extends one that is missing or forms a cycle
+	CheckFileReference     = "file-reference"     // An @file reference points at nothing
+	CheckUnregisteredTag   = "unregistered-tag"   // A tag is used but missing from tags.yaml
+	CheckOrphanedTag       = "orphaned-tag"       // A tag in tags.yaml isn't used anywhere
+	CheckStaleOutput       = "stale-output"       // An output file is older than what it was composed from
+)
+
+// Problem is a single finding. Path is relative to the .pluqqy directory, except
+// for output files, which are relative to the project root.
+type Problem struct {
+	Check    string `json:"check" yaml:"check"`
+	Severity string `json:"severity" yaml:"severity"`
+	Path     string `json:"path" yaml:"path"`
+	Message  string `json:"message" yaml:"message"`
+	Fixable  bool   `json:"fixable,omitempty" yaml:"fixable,omitempty"`
+	Fixed    bool   `json:"fixed,omitempty" yaml:"fixed,omitempty"`
+
+	fix func() error
+}
+
+// Report holds every problem found in the library
+type Report struct {
+	Problems   []Problem `json:"problems" yaml:"problems"`
+	Components int       `json:"components" yaml:"components"` // Components checked, archived included
+	Pipelines  int       `json:"pipelines" yaml:"pipelines"`   // Pipelines checked, archived included
+	Errors     int       `json:"errors" yaml:"errors"`         // Errors left unfixed
+	Warnings   int       `json:"warnings" yaml:"warnings"`     // Warnings left unfixed
+	Fixed      int       `json:"fixed" yaml:"fixed"`
+}
+
+// Fixable returns how many problems Fix would repair
+func (r *Report) Fixable() int {
+	count := 0
+	for _, problem := range r.Problems {
+		if problem.Fixable && !problem.Fixed {
+			count++
+		}
+	}
+	return count
+}
+
+// Fix applies every safe repair and updates the counts. Problems whose repair
+// fails are left unfixed and the first failure is returned.
+func (r *Report) Fix() error {
+	var firstErr error
+	for i := range r.Problems {
+		problem := &r.Problems[i]
+		if !problem.Fixable || problem.Fixed {
+			continue
+		}
+		if err := problem.fix(); err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("failed to fix %s: %w", problem.Path, err)
+			}
+			continue
+		}
+		problem.Fixed = true
+	}
+	r.count()
+	return firstErr
+}
+
+// count tallies the problems that are still open
+func (r *Report) count() {
+	r.Errors, r.Warnings, r.Fixed = 0, 0, 0
+	for _, problem := range r.Problems {
+		switch {
+		case problem.Fixed:
+			r.Fixed++
+		case problem.Severity == SeverityError:
+			r.Errors++
+		default:
+			r.Warnings++
+		}
+	}
+}
+
+// checker carries what the checks share while scanning the library
+type checker struct {
+	report   *Report
+	settings *models.Settings
+	usedTags map[string]bool // Normalized tags used by components and pipelines
+}
+
+// Run checks the library in the current project and returns what it found
+func Run() (*Report, error) {
+	if _, err := os.Stat(files.PluqqyDir); err != nil {
+		return nil, fmt.Errorf("no .pluqqy directory found. Run 'pluqqy init' first")
+	}
+
+	c := &checker{
+		report:   &Report{},
+		usedTags: make(map[string]bool),
+	}
+
+	settings, err := files.ReadSettings()
+	if err != nil {
+		c.add(CheckUnreadable, SeverityError, files.SettingsFile, err.Error())
+		settings = models.DefaultSettings()
+	}
+	c.settings = settings
+
+	for _, compType := range files.ComponentTypes() {
+		if components, err := files.ListComponents(compType.Name); err == nil {
+			for _, component := range components {
+				c.checkComponent(files.ComponentsDir+"/"+compType.DirName()+"/"+component, false)
+			}
+		}
+		if components, err := files.ListArchivedComponents(compType.Name); err == nil {
+			for _, component := range components {
+				c.checkComponent(files.ComponentsDir+"/"+compType.DirName()+"/"+component, true)
+			}
+		}
+	}
+
+	if pipelines, err := files.ListPipelines(); err == nil {
+		for _, pipeline := range pipelines {
+			c.checkPipeline(pipeline, false)
+		}
+	}
+	if pipelines, err := files.ListArchivedPipelines(); err == nil {
+		for _, pipeline := range pipelines {
+			c.checkPipeline(pipeline, true)
+		}
+	}
+
+	c.checkTags()
+
+	sort.SliceStable(c.report.Problems, func(i, j int) bool {
+		return c.report.Problems[i].Path < c.report.Problems[j].Path
+	})
+	c.report.count()
+	return c.report, nil
+}
+
+// add records a problem that can't be repaired automatically
+func (c *checker) add(check, severity, path, message string) {
+	c.report.Problems = append(c.report.Problems, Problem{
+		Check:    check,
+		Severity: severity,
+		Path:     path,
+		Message:  message,
+	})
+}
+
+// addFixable records a problem along with its safe repair
+func (c *checker) addFixable(check, severity, path, message string, fix func() error) {
+	c.report.Problems = append(c.report.Problems, Problem{
+		Check:    check,
+		Severity: severity,
+		Path:     path,
+		Message:  message,
+		Fixable:  true,
+		fix:      fix,
+	})
+}
+
+// readLibraryFile reads a component or pipeline, reporting it when it's too
+// large or can't be read. displayPath is relative to .pluqqy.
+func (c *checker) readLibraryFile(displayPath string) ([]byte, bool) {
+	absPath := filepath.Join(files.PluqqyDir, displayPath)
+	info, err := os.Stat(absPath)
+	if err != nil {
+		c.add(CheckUnreadable, SeverityError, displayPath, err.Error())
+		return nil, false
+	}
+	if info.Size() > files.MaxFileSize {
+		c.add(CheckOversized, SeverityError, displayPath, fmt.Sprintf("%d bytes is over the %d byte limit, so it can't be loaded",
+			info.Size(), files.MaxFileSize))
+		return nil, false
+	}
+
+	data, err := os.ReadFile(absPath)
+	if err != nil {
+		c.add(CheckUnreadable, SeverityError, displayPath, err.Error())
+		return nil, false
+	}
+	return data, true
+}
+
+// checkComponent checks a component's size, frontmatter and @file references
+// and records its tags. path is relative to .pluqqy, without the archive prefix.
+func (c *checker) checkComponent(path string, archived bool) {
+	c.report.Components++
+	displayPath := path
+	if archived {
+		displayPath = files.ArchiveDir + "/" + path
+	}
+
+	data, ok := c.readLibraryFile(displayPath)
+	if !ok {
+		return
+	}
+	if err := files.ValidateFrontmatter(data); err != nil {
+		c.add(CheckFrontmatter, SeverityError, displayPath, err.Error()+"; its name and tags are ignored")
+		return
+	}
+
+	component, err := files.ReadArchivedOrActiveComponent(path, archived)
+	if err != nil {
+		c.add(CheckUnreadable, SeverityError, displayPath, err.Error())
+		return
+	}
+	c.useTags(component.Tags)
+
+	// Archived components are never composed, so their references don't matter
+	if !archived {
+		c.checkFileReferences(displayPath, component.Content)
+	}
+}
+
+// checkFileReferences reports @file references that can't be expanded
+func (c *checker) checkFileReferences(displayPath, content string) {
+	rootDir := filepath.Dir(files.PluqqyDir)
+	maxSize := c.settings.Output.FileReferences.MaxFileSize
+	if maxSize <= 0 {
+		maxSize = models.DefaultMaxReferencedFileSize
+	}
+
+	for _, raw := range composer.ExtractFileReferences(content) {
+		ref, err := composer.ParseFileReference(raw)
+		if err != nil {
+			c.add(CheckFileReference, SeverityError, displayPath, fmt.Sprintf("%s: %v", raw, err))
+			continue
+		}
+		paths, err := composer.ResolveFileReference(ref, rootDir)
+		if err != nil {
+			c.add(CheckFileReference, SeverityError, displayPath, fmt.Sprintf("%s: %v", raw, err))
+			continue
+		}
+		for _, path := range paths {
+			if info, err := os.Stat(path); err == nil && info.Size() > maxSize {
+				rel, _ := filepath.Rel(rootDir, path)
+				c.add(CheckOversized, SeverityWarning, displayPath, fmt.Sprintf("%s: %s is %d bytes and will be cut to %d when expanded",
+					raw, filepath.ToSlash(rel), info.Size(), maxSize))
+			}
+		}
+	}
+}
+
+// checkPipeline checks a pipeline's YAML, component orders and references and
+// records its tags. path is as listed, e.g. pipelines/x.yaml or archive/pipelines/x.yaml.
+func (c *checker) checkPipeline(path string, archived bool) {
+	c.report.Pipelines++
+
+	if _, ok := c.readLibraryFile(path); !ok {
+		return
+	}
+	pipeline, err := files.ReadArchivedOrActivePipeline(path, archived)
+	if err != nil {
+		c.add(CheckUnreadable, SeverityError, path, err.Error())
+		return
+	}
+	c.useTags(pipeline.Tags)
+
+	// Archived pipelines aren't composed, so only their files and tags are checked
+	if archived {
+		return
+	}
+
+	renumbered := renumberComponents(pipeline)
+	if problems := orderProblems(pipeline.Components); len(problems) > 0 {
+		message := strings.Join(problems, "; ")
+		if renumbered.Validate() == nil {
+			c.addFixable(CheckComponentOrder, SeverityError, path, message+" (fix renumbers them in their current order)", func() error {
+				return files.WritePipeline(renumbered)
+			})
+		} else {
+			c.add(CheckComponentOrder, SeverityError, path, message)
+		}
+	}
+	if err := renumbered.Validate(); err != nil {
+		c.add(CheckInvalidPipeline, SeverityError, path, err.Error())
+	}
+
+	for i, ref := range pipeline.Components {
+		if ref.Path == "" {
+			continue
+		}
+		componentPath := files.ComponentRefPath(ref)
+		if _, err := os.Stat(filepath.Join(files.PluqqyDir, componentPath)); err == nil {
+			continue
+		}
+		if _, err := os.Stat(filepath.Join(files.PluqqyDir, files.ArchiveDir, componentPath)); err == nil {
+			c.add(CheckArchivedComponent, SeverityError, path, fmt.Sprintf("component %d (%s) is archived; restore it or remove it from the pipeline",
+				i+1, composer.SourceDisplayPath(ref.Path)))
+			continue
+		}
+		c.add(CheckMissingComponent, SeverityError, path, fmt.Sprintf("component %d (%s) doesn't exist",
+			i+1, composer.SourceDisplayPath(ref.Path)))
+	}
+
+	if _, err := files.ResolvePipelineIncludes(pipeline); err != nil {
+		c.add(CheckBrokenExtends, SeverityError, path, err.Error())
+	}
+
+	c.checkOutputs(path, pipeline)
+}
+
+// orderProblems describes component orders that Pipeline.Validate rejects
+func orderProblems(components []models.ComponentRef) []string {
+	var problems []string
+	seen := make(map[int]bool)
+	for i, comp := range components {
+		if comp.Order <= 0 {
+			problems = append(problems, fmt.Sprintf("component %d has order %d, which isn't positive", i+1, comp.Order))
+		} else if seen[comp.Order] {
+			problems = append(problems, fmt.Sprintf("component %d repeats order %d", i+1, comp.Order))
+		}
+		seen[comp.Order] = true
+	}
+	return problems
+}
+
+// renumberComponents returns a copy of the pipeline with its components
+// numbered 1, 2, 3... in the order they are composed
+func renumberComponents(pipeline *models.Pipeline) *models.Pipeline {
+	renumbered := *pipeline
+	renumbered.Components = append([]models.ComponentRef(nil), pipeline.Components...)
+	sort.SliceStable(renumbered.Components, func(i, j int) bool {
+		return renumbered.Components[i].Order < renumbered.Components[j].Order
+	})
+	for i := range renumbered.Components {
+		renumbered.Components[i].Order = i + 1
+	}
+	return &renumbered
+}
+
+// checkOutputs reports output files the pipeline writes itself that are older
+// than the pipeline or any component it includes. Files shared by every
+// pipeline, such as the default PLUQQY.md, can't be attributed and are skipped.
+func (c *checker) checkOutputs(path string, pipeline *models.Pipeline) {
+	var outputs []string
+	if pipeline.OutputPath != "" {
+		outputs = append(outputs, pipeline.OutputPath)
+	}
+	for _, target := range pipeline.Targets {
+		outputs = append(outputs, target.Path)
+	}
+	if len(outputs) == 0 {
+		return
+	}
+
+	sources := []string{filepath.Join(files.PluqqyDir, path)}
+	componentPaths, _ := files.PipelineComponentPaths(pipeline)
+	for _, componentPath := range componentPaths {
+		sources = append(sources, filepath.Join(files.PluqqyDir, componentPath))
+	}
+	var newest time.Time
+	var newestSource string
+	for _, source := range sources {
+		if info, err := os.Stat(source); err == nil && info.ModTime().After(newest) {
+			newest = info.ModTime()
+			newestSource = source
+		}
+	}
+
+	for _, output := range outputs {
+		info, err := os.Stat(composer.TargetPath(output))
+		if err != nil || !info.ModTime().Before(newest) {
+			continue
+		}
+		source, _ := filepath.Rel(files.PluqqyDir, newestSource)
+		c.add(CheckStaleOutput, SeverityWarning, filepath.ToSlash(filepath.Clean(output)),
+			fmt.Sprintf("older than %s; run 'pluqqy set %s' to regenerate it", filepath.ToSlash(source), pipelineName(path)))
+	}
+}
+
+// pipelineName returns the name set and other commands accept for a pipeline path
+func pipelineName(path string) string {
+	return strings.TrimSuffix(strings.TrimPrefix(path, files.PipelinesDir+"/"), ".yaml")
+}
+
+// useTags records tags used by a component or pipeline
+func (c *checker) useTags(itemTags []string) {
+	for _, tag := range itemTags {
+		c.usedTags[models.NormalizeTagName(tag)] = true
+	}
+}
+
+// checkTags compares the tags in use with the registry
+func (c *checker) checkTags() {
+	registry, err := tags.NewRegistry()
+	if err != nil {
+		c.add(CheckUnreadable, SeverityError, tags.TagsRegistryFile, err.Error())
+		return
+	}
+
+	used := make([]string, 0, len(c.usedTags))
+	for tag := range c.usedTags {
+		used = append(used, tag)
+	}
+	sort.Strings(used)
+	for _, tag := range used {
+		if tag == "" {
+			continue
+		}
+		if _, exists := registry.GetTag(tag); exists {
+			continue
+		}
+		c.addFixable(CheckUnregisteredTag, SeverityWarning, tags.TagsRegistryFile,
+			fmt.Sprintf("tag '%s' is used but isn't registered", tag),
+			func() error {
+				_, err := registry.GetOrCreateTag(tag)
+				return err
+			})
+	}
+
+	// A parent such as project counts as used when project/frontend is
+	ancestors := make(map[string]bool)
+	for tag := range c.usedTags {
+		for parent := models.GetTagParent(tag); parent != ""; parent = models.GetTagParent(parent) {
+			ancestors[parent] = true
+		}
+	}
+	for _, tag := range registry.ListTags() {
+		name := models.NormalizeTagName(tag.Name)
+		if c.usedTags[name] || ancestors[name] {
+			continue
+		}
+		message := fmt.Sprintf("tag '%s' isn't used by any component or pipeline", name)
+		// A description means someone curated the tag, so it's kept for them to decide
+		if tag.Description != "" {
+			c.add(CheckOrphanedTag, SeverityWarning, tags.TagsRegistryFile, message)
+			continue
+		}
+		c.addFixable(CheckOrphanedTag, SeverityWarning, tags.TagsRegistryFile, message, func() error {
+			if err := registry.RemoveTag(name); err != nil {
+				return err
+			}
+			return registry.Save()
+		})
+	}
+}
@@ -0,0 +1,174 @@
+package doctor
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/tags"
+)
+
+// setupDoctorProject creates a project with one of each kind of problem
+func setupDoctorProject(t *testing.T) {
+	t.Helper()
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatalf("Failed to init project structure: %v", err)
+	}
+	write := func(path, content string) {
+		t.Helper()
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	write("src/main.go", "package main\n")
+	write(".pluqqy/components/prompts/review.md", "---\ntags: [api, project/web]\n---\nReview @src/main.go and @docs/gone.md")
+	write(".pluqqy/components/rules/broken.md", "---\ntags: [api\n---\nBroken frontmatter")
+	write(".pluqqy/archive/components/contexts/old.md", "Archived context")
+	write(".pluqqy/pipelines/api.yaml", `name: API
+output_path: out/API.md
+components:
+  - type: prompts
+    path: ../components/prompts/review.md
+    order: 2
+  - type: contexts
+    path: ../components/contexts/old.md
+    order: 2
+  - type: rules
+    path: ../components/rules/gone.md
+    order: 0
+`)
+	write("out/API.md", "# API\n")
+	old := time.Now().Add(-time.Hour)
+	os.Chtimes("out/API.md", old, old)
+	write(".pluqqy/tags.yaml", "tags:\n  - name: project\n  - name: unused\n  - name: curated\n    description: Kept on purpose\n")
+}
+
+// problemsByCheck groups a report's problems by check
+func problemsByCheck(report *Report) map[string][]Problem {
+	byCheck := make(map[string][]Problem)
+	for _, problem := range report.Problems {
+		byCheck[problem.Check] = append(byCheck[problem.Check], problem)
+	}
+	return byCheck
+}
+
+func TestRun(t *testing.T) {
+	setupDoctorProject(t)
+
+	report, err := Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if report.Components != 3 || report.Pipelines != 1 {
+		t.Errorf("checked %d components and %d pipelines, want 3 and 1", report.Components, report.Pipelines)
+	}
+
+	byCheck := problemsByCheck(report)
+	expected := map[string]int{
+		CheckFileReference:     1,
+		CheckFrontmatter:       1,
+		CheckComponentOrder:    1,
+		CheckArchivedComponent: 1,
+		CheckMissingComponent:  1,
+		CheckStaleOutput:       1,
+		CheckUnregisteredTag:   2, // api and project/web; project is used through project/web
+		CheckOrphanedTag:       2,
+	}
+	for check, count := range expected {
+		if len(byCheck[check]) != count {
+			t.Errorf("%s: got %d problems %+v, want %d", check, len(byCheck[check]), byCheck[check], count)
+		}
+	}
+	if len(report.Problems) != 10 {
+		t.Errorf("got %d problems, want 10: %+v", len(report.Problems), report.Problems)
+	}
+	if report.Errors != 5 || report.Warnings != 5 {
+		t.Errorf("counted %d errors and %d warnings, want 5 and 5", report.Errors, report.Warnings)
+	}
+	if got := byCheck[CheckStaleOutput][0].Path; got != "out/API.md" {
+		t.Errorf("stale output path = %q, want out/API.md", got)
+	}
+	// Only the uncurated orphan can be removed automatically
+	if report.Fixable() != 4 {
+		t.Errorf("Fixable() = %d, want 4", report.Fixable())
+	}
+}
+
+func TestReport_Fix(t *testing.T) {
+	setupDoctorProject(t)
+
+	report, err := Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if err := report.Fix(); err != nil {
+		t.Fatalf("Fix() error = %v", err)
+	}
+	if report.Fixed != 4 || report.Errors != 4 || report.Warnings != 2 {
+		t.Errorf("after fixing: %d fixed, %d errors, %d warnings, want 4, 4 and 2", report.Fixed, report.Errors, report.Warnings)
+	}
+
+	// Orders are renumbered in the order the components were composed in
+	pipeline, err := files.ReadPipeline("api.yaml")
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantOrder := []string{"../components/rules/gone.md", "../components/prompts/review.md", "../components/contexts/old.md"}
+	for i, comp := range pipeline.Components {
+		if comp.Order != i+1 || comp.Path != wantOrder[i] {
+			t.Errorf("component %d = %s order %d, want %s order %d", i+1, comp.Path, comp.Order, wantOrder[i], i+1)
+		}
+	}
+
+	registry, err := tags.NewRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, name := range []string{"api", "project/web", "project", "curated"} {
+		if _, exists := registry.GetTag(name); !exists {
+			t.Errorf("tag %s should be registered", name)
+		}
+	}
+	if _, exists := registry.GetTag("unused"); exists {
+		t.Error("the unused tag should have been removed")
+	}
+
+	// A second run finds only what --fix leaves alone
+	again, err := Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if again.Fixable() != 0 || len(again.Problems) != 6 {
+		t.Errorf("second run found %d problems, %d fixable, want 6 and 0: %+v", len(again.Problems), again.Fixable(), again.Problems)
+	}
+}
+
+func TestRun_CleanProject(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	os.Chdir(tempDir)
+
+	if _, err := Run(); err == nil {
+		t.Error("Run() outside a project should fail")
+	}
+
+	files.InitProjectStructure()
+	files.WriteComponentWithNameAndTags("components/prompts/hello.md", "Hello", "", nil)
+	report, err := Run()
+	if err != nil {
+		t.Fatalf("Run() error = %v", err)
+	}
+	if len(report.Problems) != 0 {
+		t.Errorf("a clean project has problems: %+v", report.Problems)
+	}
+}
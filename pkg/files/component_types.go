@@ -0,0 +1,112 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// componentTypesCache remembers which settings file the custom type registry was
+// loaded from, so it is only re-read when the project or the file changes
+var componentTypesCache struct {
+	sync.Mutex
+	path    string
+	modTime time.Time
+}
+
+// loadComponentTypes registers the custom component types declared in the
+// current project's settings. Projects without settings only have the built-in types.
+func loadComponentTypes() {
+	settingsPath, err := filepath.Abs(filepath.Join(PluqqyDir, SettingsFile))
+	if err != nil {
+		return
+	}
+
+	componentTypesCache.Lock()
+	defer componentTypesCache.Unlock()
+
+	info, err := os.Stat(settingsPath)
+	if err != nil {
+		if componentTypesCache.path != "" {
+			models.SetCustomComponentTypes(nil)
+			componentTypesCache.path = ""
+			componentTypesCache.modTime = time.Time{}
+		}
+		return
+	}
+	if componentTypesCache.path == settingsPath && componentTypesCache.modTime.Equal(info.ModTime()) {
+		return
+	}
+
+	var types []models.ComponentTypeConfig
+	if data, err := os.ReadFile(settingsPath); err == nil {
+		var settings struct {
+			ComponentTypes []models.ComponentTypeConfig `yaml:"component_types"`
+		}
+		// Invalid settings are reported by ReadSettings; here they just mean no custom types
+		if yaml.Unmarshal(data, &settings) == nil && models.ValidateComponentTypeConfigs(settings.ComponentTypes) == nil {
+			types = settings.ComponentTypes
+		}
+	}
+
+	models.SetCustomComponentTypes(types)
+	componentTypesCache.path = settingsPath
+	componentTypesCache.modTime = info.ModTime()
+}
+
+// componentTypeDir returns the directory under components/ for a component type
+func componentTypeDir(componentType string) (string, error) {
+	loadComponentTypes()
+	t, ok := models.LookupComponentType(componentType)
+	if !ok {
+		return "", fmt.Errorf("invalid component type '%s': must be one of: %s",
+			componentType, strings.Join(models.ComponentTypeNames(), ", "))
+	}
+	return t.DirName(), nil
+}
+
+// ComponentTypeDirs returns the directory under components/ for every known component type
+func ComponentTypeDirs() []string {
+	loadComponentTypes()
+	var dirs []string
+	for _, t := range models.AllComponentTypes() {
+		dirs = append(dirs, t.DirName())
+	}
+	return dirs
+}
+
+// ComponentTypes returns every component type known in the current project,
+// built-in types first
+func ComponentTypes() []models.ComponentTypeConfig {
+	loadComponentTypes()
+	return models.AllComponentTypes()
+}
+
+// LookupComponentType finds a built-in or custom component type by its singular or plural name
+func LookupComponentType(name string) (models.ComponentTypeConfig, bool) {
+	loadComponentTypes()
+	return models.LookupComponentType(name)
+}
+
+// ComponentTypeForPath returns the type of the component at path, which may be
+// relative to .pluqqy or a pipeline (components/rules/x.md, ../components/rules/x.md)
+func ComponentTypeForPath(path string) (models.ComponentTypeConfig, bool) {
+	loadComponentTypes()
+	// The type is the directory that follows components/
+	parts := strings.Split(filepath.ToSlash(path), "/")
+	for i := 0; i+1 < len(parts); i++ {
+		if parts[i] == ComponentsDir {
+			if t, ok := models.ComponentTypeForDir(parts[i+1]); ok {
+				return t, true
+			}
+		}
+	}
+	return models.ComponentTypeConfig{}, false
+}
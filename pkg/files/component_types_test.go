@@ -0,0 +1,105 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupCustomTypeProject(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	os.Chdir(tempDir)
+
+	require.NoError(t, os.MkdirAll(PluqqyDir, 0755))
+	settings := models.DefaultSettings()
+	settings.ComponentTypes = []models.ComponentTypeConfig{
+		{Name: "examples", Heading: "## EXAMPLES", Order: 3},
+		{Name: "personas", Dir: "roles"},
+	}
+	require.NoError(t, WriteSettings(settings))
+	require.NoError(t, InitProjectStructure())
+}
+
+func TestCustomComponentTypes_InitCreatesDirectories(t *testing.T) {
+	setupCustomTypeProject(t)
+
+	for _, dir := range []string{"examples", "roles"} {
+		assert.DirExists(t, filepath.Join(PluqqyDir, ComponentsDir, dir))
+		assert.DirExists(t, filepath.Join(PluqqyDir, ArchiveDir, ComponentsDir, dir))
+	}
+}
+
+func TestCustomComponentTypes_ListAndRead(t *testing.T) {
+	setupCustomTypeProject(t)
+
+	require.NoError(t, WriteComponent("components/roles/reviewer.md", "You review code."))
+
+	components, err := ListComponents("personas")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"reviewer.md"}, components)
+
+	// Singular names are accepted as well
+	components, err = ListComponents("persona")
+	require.NoError(t, err)
+	assert.Len(t, components, 1)
+
+	comp, err := ReadComponent("components/roles/reviewer.md")
+	require.NoError(t, err)
+	assert.Equal(t, "personas", comp.Type)
+
+	_, err = ListComponents("widgets")
+	assert.ErrorContains(t, err, "must be one of: rules, contexts, prompts, examples, personas")
+}
+
+func TestCustomComponentTypes_Pipelines(t *testing.T) {
+	setupCustomTypeProject(t)
+
+	pipeline := &models.Pipeline{
+		Name: "review",
+		Path: "review.yaml",
+		Components: []models.ComponentRef{
+			{Type: "examples", Path: "../components/examples/good-pr.md", Order: 1},
+		},
+	}
+	require.NoError(t, WritePipeline(pipeline))
+
+	// Singular types in YAML are normalized on read
+	data := "name: review\ncomponents:\n  - type: persona\n    path: ../components/roles/reviewer.md\n    order: 1\n"
+	require.NoError(t, os.WriteFile(filepath.Join(PluqqyDir, PipelinesDir, "review.yaml"), []byte(data), 0644))
+	read, err := ReadPipeline("review.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "personas", read.Components[0].Type)
+}
+
+func TestCustomComponentTypes_InvalidSettings(t *testing.T) {
+	setupCustomTypeProject(t)
+
+	data := "component_types:\n  - name: rules\n"
+	require.NoError(t, os.WriteFile(filepath.Join(PluqqyDir, SettingsFile), []byte(data), 0644))
+
+	_, err := ReadSettings()
+	assert.ErrorContains(t, err, "'rules' is already defined")
+
+	// Without valid settings only the built-in types are known
+	_, err = ListComponents("examples")
+	assert.Error(t, err)
+}
+
+func TestCustomComponentTypes_OtherProject(t *testing.T) {
+	setupCustomTypeProject(t)
+	_, err := ListComponents("examples")
+	require.NoError(t, err)
+
+	// A project without settings only has the built-in types
+	setupIncludeTest(t)
+	_, err = ListComponents("examples")
+	assert.Error(t, err)
+	_, err = ListComponents(models.ComponentTypeRules)
+	assert.NoError(t, err)
+}
@@ -0,0 +1,164 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestComponentDates(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	if err := InitProjectStructure(); err != nil {
+		t.Fatalf("InitProjectStructure failed: %v", err)
+	}
+
+	componentPath := filepath.Join(ComponentsDir, PromptsDir, "review.md")
+	before := time.Now().Add(-time.Second)
+	if err := WriteComponentWithNameAndTags(componentPath, "Review the code", "Review", []string{"quality"}); err != nil {
+		t.Fatalf("WriteComponentWithNameAndTags failed: %v", err)
+	}
+
+	component, err := ReadComponent(componentPath)
+	if err != nil {
+		t.Fatalf("ReadComponent failed: %v", err)
+	}
+	created := component.Created
+	if created.Before(before) || created.After(time.Now()) {
+		t.Fatalf("Expected a creation date of now, got %v", created)
+	}
+	if component.Content != "Review the code" {
+		t.Errorf("Frontmatter leaked into content: %q", component.Content)
+	}
+
+	// Editors write the body without frontmatter dates
+	if err := WriteComponentWithNameAndTags(componentPath, "Review the code carefully", "Review", []string{"quality"}); err != nil {
+		t.Fatalf("WriteComponentWithNameAndTags failed: %v", err)
+	}
+	component, _ = ReadComponent(componentPath)
+	if !component.Created.Equal(created) {
+		t.Errorf("Expected creation date %v to be kept, got %v", created, component.Created)
+	}
+
+	// Archiving records the date, unarchiving clears it
+	if err := ArchiveComponent(componentPath); err != nil {
+		t.Fatalf("ArchiveComponent failed: %v", err)
+	}
+	archived, err := ReadArchivedComponent(componentPath)
+	if err != nil {
+		t.Fatalf("ReadArchivedComponent failed: %v", err)
+	}
+	if archived.Archived.IsZero() {
+		t.Error("Expected an archive date")
+	}
+	if !archived.Created.Equal(created) || archived.Content != "Review the code carefully" {
+		t.Errorf("Archiving changed the component: %+v", archived)
+	}
+
+	if err := UnarchiveComponent(componentPath); err != nil {
+		t.Fatalf("UnarchiveComponent failed: %v", err)
+	}
+	component, _ = ReadComponent(componentPath)
+	if !component.Archived.IsZero() {
+		t.Errorf("Expected the archive date to be cleared, got %v", component.Archived)
+	}
+
+	// Renaming keeps the creation date
+	if err := RenameComponent(componentPath, "Code Review"); err != nil {
+		t.Fatalf("RenameComponent failed: %v", err)
+	}
+	renamed, err := ReadComponent(filepath.Join(ComponentsDir, PromptsDir, "code-review.md"))
+	if err != nil {
+		t.Fatalf("ReadComponent failed: %v", err)
+	}
+	if !renamed.Created.Equal(created) {
+		t.Errorf("Expected creation date %v after rename, got %v", created, renamed.Created)
+	}
+}
+
+func TestComponentDates_MalformedFrontmatter(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	if err := InitProjectStructure(); err != nil {
+		t.Fatalf("InitProjectStructure failed: %v", err)
+	}
+
+	// A new component needs a creation date, but its frontmatter can't be rewritten
+	componentPath := filepath.Join(ComponentsDir, PromptsDir, "broken.md")
+	if err := WriteComponent(componentPath, "---\ntags: [api\n---\nReview the code"); err == nil {
+		t.Error("Expected an error for frontmatter that doesn't parse")
+	}
+	if _, err := os.Stat(filepath.Join(PluqqyDir, componentPath)); !os.IsNotExist(err) {
+		t.Errorf("Expected no file to be written, got err %v", err)
+	}
+}
+
+func TestPipelineDates(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	if err := InitProjectStructure(); err != nil {
+		t.Fatalf("InitProjectStructure failed: %v", err)
+	}
+
+	newPipeline := func() *models.Pipeline {
+		return &models.Pipeline{
+			Name: "release",
+			Path: "release.yaml",
+			Components: []models.ComponentRef{
+				{Type: models.ComponentTypePrompt, Path: "../components/prompts/test.md", Order: 1},
+			},
+		}
+	}
+
+	if err := WritePipeline(newPipeline()); err != nil {
+		t.Fatalf("WritePipeline failed: %v", err)
+	}
+	pipeline, err := ReadPipeline("release.yaml")
+	if err != nil {
+		t.Fatalf("ReadPipeline failed: %v", err)
+	}
+	created := pipeline.Created
+	if created.IsZero() {
+		t.Fatal("Expected a creation date")
+	}
+
+	// Saving a pipeline built from scratch keeps the original date
+	if err := WritePipeline(newPipeline()); err != nil {
+		t.Fatalf("WritePipeline failed: %v", err)
+	}
+	pipeline, _ = ReadPipeline("release.yaml")
+	if !pipeline.Created.Equal(created) {
+		t.Errorf("Expected creation date %v to be kept, got %v", created, pipeline.Created)
+	}
+
+	if err := ArchivePipeline("release.yaml"); err != nil {
+		t.Fatalf("ArchivePipeline failed: %v", err)
+	}
+	archived, err := ReadArchivedPipeline("release.yaml")
+	if err != nil {
+		t.Fatalf("ReadArchivedPipeline failed: %v", err)
+	}
+	if archived.Archived.IsZero() || !archived.Created.Equal(created) {
+		t.Errorf("Expected archive and creation dates, got %v and %v", archived.Archived, archived.Created)
+	}
+
+	if err := UnarchivePipeline("release.yaml"); err != nil {
+		t.Fatalf("UnarchivePipeline failed: %v", err)
+	}
+	pipeline, _ = ReadPipeline("release.yaml")
+	if !pipeline.Archived.IsZero() {
+		t.Errorf("Expected the archive date to be cleared, got %v", pipeline.Archived)
+	}
+}
@@ -23,6 +23,7 @@ const (
 	ArchiveDir        = "archive"
 	DefaultOutputFile = "PLUQQY.md"
 	SettingsFile      = "settings.yaml"
+	SearchIndexFile   = "search-index.gob" // Rebuilt from the files, so kept out of git
 	
 	// MaxFileSize is the maximum size for component and pipeline files (10MB)
 	MaxFileSize = 10 * 1024 * 1024
@@ -59,51 +60,54 @@ func InitProjectStructure() error {
 		PluqqyDir,
 		filepath.Join(PluqqyDir, PipelinesDir),
 		filepath.Join(PluqqyDir, ComponentsDir),
-		filepath.Join(PluqqyDir, ComponentsDir, PromptsDir),
-		filepath.Join(PluqqyDir, ComponentsDir, ContextsDir),
-		filepath.Join(PluqqyDir, ComponentsDir, RulesDir),
 		filepath.Join(PluqqyDir, ArchiveDir),
 		filepath.Join(PluqqyDir, ArchiveDir, PipelinesDir),
 		filepath.Join(PluqqyDir, ArchiveDir, ComponentsDir),
-		filepath.Join(PluqqyDir, ArchiveDir, ComponentsDir, PromptsDir),
-		filepath.Join(PluqqyDir, ArchiveDir, ComponentsDir, ContextsDir),
-		filepath.Join(PluqqyDir, ArchiveDir, ComponentsDir, RulesDir),
 		filepath.Join(PluqqyDir, strings.TrimSuffix(settings.Output.OutputPath, "/")), // tmp directory
 	}
 
+	// One directory per component type, including custom types from existing settings
+	for _, dir := range ComponentTypeDirs() {
+		dirs = append(dirs,
+			filepath.Join(PluqqyDir, ComponentsDir, dir),
+			filepath.Join(PluqqyDir, ArchiveDir, ComponentsDir, dir),
+		)
+	}
+
 	for _, dir := range dirs {
 		if err := os.MkdirAll(dir, 0755); err != nil {
 			return fmt.Errorf("failed to create directory %s: %w", dir, err)
 		}
 	}
 	
-	// Create or update .gitignore to ignore tmp directory
+	// Create or update .gitignore to ignore the tmp directory and the search index
+	return IgnoreFiles("/tmp/", "/"+SearchIndexFile)
+}
+
+// IgnoreFiles adds entries such as /tmp/ to .pluqqy/.gitignore, creating it if
+// needed. Entries already there are left alone.
+func IgnoreFiles(entries ...string) error {
 	gitignorePath := filepath.Join(PluqqyDir, ".gitignore")
-	gitignoreContent := "/tmp/\n"
+	gitignoreContent := ""
 	
 	// Check if .gitignore already exists
-	if _, err := os.Stat(gitignorePath); err == nil {
-		// Read existing content
-		existing, err := os.ReadFile(gitignorePath)
-		if err == nil {
-			// Check if tmp is already ignored
-			if !strings.Contains(string(existing), "/tmp/") {
-				// Append to existing content
-				gitignoreContent = string(existing)
-				if !strings.HasSuffix(gitignoreContent, "\n") {
-					gitignoreContent += "\n"
-				}
-				gitignoreContent += "/tmp/\n"
-			} else {
-				// Already contains /tmp/, don't modify
-				gitignoreContent = ""
+	if existing, err := os.ReadFile(gitignorePath); err == nil {
+		gitignoreContent = string(existing)
+	}
+	updated := gitignoreContent
+	for _, entry := range entries {
+		// Append entries that aren't ignored yet
+		if !strings.Contains(updated, entry) {
+			if updated != "" && !strings.HasSuffix(updated, "\n") {
+				updated += "\n"
 			}
+			updated += entry + "\n"
 		}
 	}
 	
 	// Write .gitignore if needed
-	if gitignoreContent != "" {
-		if err := os.WriteFile(gitignorePath, []byte(gitignoreContent), 0644); err != nil {
+	if updated != gitignoreContent {
+		if err := os.WriteFile(gitignorePath, []byte(updated), 0644); err != nil {
 			return fmt.Errorf("failed to create .gitignore: %w", err)
 		}
 	}
@@ -113,8 +117,10 @@ func InitProjectStructure() error {
 
 // componentFrontmatter represents the YAML frontmatter in component files
 type componentFrontmatter struct {
-	Name string   `yaml:"name,omitempty"`
-	Tags []string `yaml:"tags,omitempty"`
+	Name     string    `yaml:"name,omitempty"`
+	Tags     []string  `yaml:"tags,omitempty"`
+	Created  time.Time `yaml:"created,omitempty"`
+	Archived time.Time `yaml:"archived,omitempty"`
 }
 
 // extractFrontmatter extracts YAML frontmatter from markdown content
@@ -152,6 +158,26 @@ func extractFrontmatter(content []byte) (*componentFrontmatter, []byte, error) {
 	return &frontmatter, remainingContent, nil
 }
 
+// ValidateFrontmatter reports frontmatter that extractFrontmatter would silently
+// ignore: an opening --- that is never closed, or YAML that doesn't parse
+func ValidateFrontmatter(content []byte) error {
+	if !bytes.HasPrefix(content, []byte("---")) {
+		return nil
+	}
+
+	parts := bytes.SplitN(content, []byte("\n---\n"), 3)
+	if len(parts) < 2 {
+		return fmt.Errorf("frontmatter is not closed by a --- line")
+	}
+
+	var frontmatter componentFrontmatter
+	if err := yaml.Unmarshal(bytes.TrimPrefix(parts[0], []byte("---\n")), &frontmatter); err != nil {
+		return fmt.Errorf("invalid frontmatter: %w", err)
+	}
+
+	return nil
+}
+
 func ReadComponent(path string) (*models.Component, error) {
 	if err := validatePath(path); err != nil {
 		return nil, fmt.Errorf("invalid component path: %w", err)
@@ -196,6 +222,8 @@ func ReadComponent(path string) (*models.Component, error) {
 		Content:  string(contentWithoutFrontmatter), // Use content without frontmatter
 		Modified: info.ModTime(),
 		Tags:     frontmatter.Tags,
+		Created:  frontmatter.Created,
+		Archived: frontmatter.Archived,
 	}, nil
 }
 
@@ -217,6 +245,11 @@ func WriteComponent(path string, content string) error {
 		return fmt.Errorf("failed to create component directory '%s': %w", dir, err)
 	}
 
+	content, err := recordComponentDates(absPath, content)
+	if err != nil {
+		return fmt.Errorf("cannot write component file '%s': %w", path, err)
+	}
+
 	if err := writeFileAtomic(absPath, []byte(content), 0644); err != nil {
 		return fmt.Errorf("failed to write component file '%s': %w", path, err)
 	}
@@ -246,11 +279,15 @@ func formatComponentContentWithName(content string, name string, tags []string)
 		frontmatter.Tags = tags
 	}
 	
-	// Build new content with frontmatter
+	return renderComponentContent(frontmatter, contentWithoutFrontmatter)
+}
+
+// renderComponentContent joins frontmatter and body into a component file
+func renderComponentContent(frontmatter *componentFrontmatter, body []byte) string {
 	var buf bytes.Buffer
 	
-	// Always write frontmatter if we have name or tags
-	if frontmatter.Name != "" || len(frontmatter.Tags) > 0 {
+	// Always write frontmatter if we have any metadata
+	if frontmatter.Name != "" || len(frontmatter.Tags) > 0 || !frontmatter.Created.IsZero() || !frontmatter.Archived.IsZero() {
 		buf.WriteString("---\n")
 		frontmatterBytes, _ := yaml.Marshal(frontmatter)
 		buf.Write(frontmatterBytes)
@@ -258,11 +295,62 @@ func formatComponentContentWithName(content string, name string, tags []string)
 	}
 	
 	// Write the content
-	buf.Write(contentWithoutFrontmatter)
+	buf.Write(body)
 	
 	return buf.String()
 }
 
+// recordComponentDates fills in the created and archived dates of a component
+// about to be written to absPath. Editors write the body without those dates, so
+// they're carried over from the existing file; a new component is stamped with
+// the current time. Content whose frontmatter doesn't parse is an error rather
+// than getting a second frontmatter block in front of it.
+func recordComponentDates(absPath string, content string) (string, error) {
+	frontmatter, body, _ := extractFrontmatter([]byte(content))
+	
+	existing, err := os.ReadFile(absPath)
+	switch {
+	case os.IsNotExist(err):
+		if !frontmatter.Created.IsZero() {
+			return content, nil
+		}
+		frontmatter.Created = time.Now().Truncate(time.Second)
+	case err != nil:
+		return content, nil
+	default:
+		previous, _, _ := extractFrontmatter(existing)
+		if (previous.Created.IsZero() || !frontmatter.Created.IsZero()) &&
+			(previous.Archived.IsZero() || !frontmatter.Archived.IsZero()) {
+			return content, nil
+		}
+		if frontmatter.Created.IsZero() {
+			frontmatter.Created = previous.Created
+		}
+		if frontmatter.Archived.IsZero() {
+			frontmatter.Archived = previous.Archived
+		}
+	}
+	
+	if err := ValidateFrontmatter([]byte(content)); err != nil {
+		return "", err
+	}
+	return renderComponentContent(frontmatter, body), nil
+}
+
+// updateComponentFrontmatter rewrites the frontmatter of the component file at
+// absPath, leaving its body untouched
+func updateComponentFrontmatter(absPath string, update func(frontmatter *componentFrontmatter)) error {
+	content, err := os.ReadFile(absPath)
+	if err != nil {
+		return err
+	}
+	
+	frontmatter, body, _ := extractFrontmatter(content)
+	update(frontmatter)
+	
+	return writeFileAtomic(absPath, []byte(renderComponentContent(frontmatter, body)), 0644)
+}
+
 func ReadPipeline(path string) (*models.Pipeline, error) {
 	if err := validatePath(path); err != nil {
 		return nil, fmt.Errorf("invalid pipeline path: %w", err)
@@ -301,18 +389,9 @@ func ReadPipeline(path string) (*models.Pipeline, error) {
 	pipeline.Path = path
 	
 	// Normalize component types for backward compatibility (singular -> plural)
+	loadComponentTypes()
 	for i := range pipeline.Components {
-		switch pipeline.Components[i].Type {
-		case "context":
-			pipeline.Components[i].Type = models.ComponentTypeContext
-		case "prompt":
-			pipeline.Components[i].Type = models.ComponentTypePrompt
-		case "rule":
-			pipeline.Components[i].Type = models.ComponentTypeRules
-		// Already plural forms are correct - no change needed
-		case "contexts", "prompts", "rules":
-			// These are already in the correct format
-		}
+		pipeline.Components[i].Type = models.NormalizeComponentType(pipeline.Components[i].Type)
 	}
 	
 	return &pipeline, nil
@@ -320,6 +399,7 @@ func ReadPipeline(path string) (*models.Pipeline, error) {
 
 func WritePipeline(pipeline *models.Pipeline) error {
 	// Validate pipeline before writing
+	loadComponentTypes()
 	if err := pipeline.Validate(); err != nil {
 		return fmt.Errorf("invalid pipeline: %w", err)
 	}
@@ -339,6 +419,8 @@ func WritePipeline(pipeline *models.Pipeline) error {
 		return fmt.Errorf("failed to create pipeline directory '%s': %w", dir, err)
 	}
 
+	recordPipelineCreated(absPath, pipeline)
+
 	content, err := yaml.Marshal(pipeline)
 	if err != nil {
 		return fmt.Errorf("failed to serialize pipeline '%s' to YAML: %w", pipeline.Name, err)
@@ -351,59 +433,64 @@ func WritePipeline(pipeline *models.Pipeline) error {
 	return nil
 }
 
+// recordPipelineCreated sets the created date of a pipeline about to be written
+// to absPath, keeping the date of the existing file or stamping a new pipeline
+// with the current time
+func recordPipelineCreated(absPath string, pipeline *models.Pipeline) {
+	if !pipeline.Created.IsZero() {
+		return
+	}
+	
+	existing, err := os.ReadFile(absPath)
+	if os.IsNotExist(err) {
+		pipeline.Created = time.Now().Truncate(time.Second)
+		return
+	}
+	if err != nil {
+		return
+	}
+	
+	var previous struct {
+		Created time.Time `yaml:"created"`
+	}
+	if yaml.Unmarshal(existing, &previous) == nil {
+		pipeline.Created = previous.Created
+	}
+}
+
+// ListPipelines returns every pipeline, including those in nested folders
+// (e.g. pipelines/release/hotfix.yaml)
 func ListPipelines() ([]string, error) {
 	pipelinesPath := filepath.Join(PluqqyDir, PipelinesDir)
 	
-	entries, err := os.ReadDir(pipelinesPath)
+	files, err := listFilesRecursive(pipelinesPath, ".yaml")
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil
-		}
 		return nil, fmt.Errorf("failed to read pipelines directory '%s': %w", pipelinesPath, err)
 	}
 
 	var pipelines []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".yaml") {
-			// Use forward slash for cross-platform consistency
-			// This ensures Windows and Unix systems use the same path format
-			pipelines = append(pipelines, PipelinesDir + "/" + entry.Name())
-		}
+	for _, file := range files {
+		pipelines = append(pipelines, PipelinesDir+"/"+file)
 	}
 
 	return pipelines, nil
 }
 
+// ListComponents returns the components of a type as paths relative to the
+// type's directory, including nested folders (e.g. backend/db.md)
 func ListComponents(componentType string) ([]string, error) {
-	var subDir string
-	switch componentType {
-	case models.ComponentTypePrompt:
-		subDir = PromptsDir
-	case models.ComponentTypeContext:
-		subDir = ContextsDir
-	case models.ComponentTypeRules:
-		subDir = RulesDir
-	default:
-		return nil, fmt.Errorf("invalid component type '%s': must be one of: prompt, context, rules", componentType)
+	subDir, err := componentTypeDir(componentType)
+	if err != nil {
+		return nil, err
 	}
 
 	componentsPath := filepath.Join(PluqqyDir, ComponentsDir, subDir)
 	
-	entries, err := os.ReadDir(componentsPath)
+	components, err := listFilesRecursive(componentsPath, ".md")
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil
-		}
 		return nil, fmt.Errorf("failed to read components directory '%s': %w", componentsPath, err)
 	}
 
-	var components []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
-			components = append(components, entry.Name())
-		}
-	}
-
 	return components, nil
 }
 
@@ -411,21 +498,14 @@ func ListComponents(componentType string) ([]string, error) {
 func ListArchivedPipelines() ([]string, error) {
 	archivePipelinesPath := filepath.Join(PluqqyDir, ArchiveDir, PipelinesDir)
 	
-	entries, err := os.ReadDir(archivePipelinesPath)
+	files, err := listFilesRecursive(archivePipelinesPath, ".yaml")
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil
-		}
 		return nil, fmt.Errorf("failed to read archived pipelines directory '%s': %w", archivePipelinesPath, err)
 	}
 	
 	var pipelines []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".yaml") {
-			// Use forward slash for cross-platform consistency
-			// This ensures Windows and Unix systems use the same path format
-			pipelines = append(pipelines, ArchiveDir + "/" + PipelinesDir + "/" + entry.Name())
-		}
+	for _, file := range files {
+		pipelines = append(pipelines, ArchiveDir+"/"+PipelinesDir+"/"+file)
 	}
 	
 	return pipelines, nil
@@ -433,36 +513,57 @@ func ListArchivedPipelines() ([]string, error) {
 
 // ListArchivedComponents returns a list of archived component files for a given type
 func ListArchivedComponents(componentType string) ([]string, error) {
-	var subDir string
-	switch componentType {
-	case models.ComponentTypePrompt:
-		subDir = PromptsDir
-	case models.ComponentTypeContext:
-		subDir = ContextsDir
-	case models.ComponentTypeRules:
-		subDir = RulesDir
-	default:
-		return nil, fmt.Errorf("invalid component type '%s': must be one of: prompt, context, rules", componentType)
+	subDir, err := componentTypeDir(componentType)
+	if err != nil {
+		return nil, err
 	}
 
 	archiveComponentsPath := filepath.Join(PluqqyDir, ArchiveDir, ComponentsDir, subDir)
 	
-	entries, err := os.ReadDir(archiveComponentsPath)
+	components, err := listFilesRecursive(archiveComponentsPath, ".md")
 	if err != nil {
-		if os.IsNotExist(err) {
-			return []string{}, nil
-		}
 		return nil, fmt.Errorf("failed to read archived components directory '%s': %w", archiveComponentsPath, err)
 	}
 
-	var components []string
-	for _, entry := range entries {
-		if !entry.IsDir() && strings.HasSuffix(entry.Name(), ".md") {
-			components = append(components, entry.Name())
+	return components, nil
+}
+
+// listFilesRecursive returns the files under root with the given extension as
+// slash-separated paths relative to root, in lexical order. Hidden files and
+// folders are skipped, and a missing root yields an empty list.
+func listFilesRecursive(root, ext string) ([]string, error) {
+	found := []string{}
+	err := filepath.WalkDir(root, func(path string, entry os.DirEntry, err error) error {
+		if err != nil {
+			if path == root && os.IsNotExist(err) {
+				return filepath.SkipAll
+			}
+			return err
 		}
+		if path == root {
+			return nil
+		}
+		if strings.HasPrefix(entry.Name(), ".") {
+			if entry.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ext) {
+			return nil
+		}
+		rel, err := filepath.Rel(root, path)
+		if err != nil {
+			return err
+		}
+		// Use forward slash for cross-platform consistency
+		found = append(found, filepath.ToSlash(rel))
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
-
-	return components, nil
+	return found, nil
 }
 
 // ReadArchivedPipeline reads an archived pipeline file
@@ -552,12 +653,18 @@ func ReadArchivedComponent(path string) (*models.Component, error) {
 		Type:        getComponentType(path),
 		Modified:    fileInfo.ModTime(),
 		Tags:        frontmatter.Tags,
+		Created:     frontmatter.Created,
+		Archived:    frontmatter.Archived,
 	}
 	
 	return comp, nil
 }
 
 func getComponentType(path string) string {
+	if t, ok := ComponentTypeForPath(path); ok {
+		return t.Name
+	}
+	
 	if strings.Contains(path, PromptsDir) {
 		return models.ComponentTypePrompt
 	} else if strings.Contains(path, ContextsDir) {
@@ -585,48 +692,136 @@ func WriteFile(path string, content string) error {
 	return nil
 }
 
+// WriteFiles writes several output files so that either all of them are updated
+// or none is: every file is written to a temp file beside it, and only once all
+// of them are written are they renamed into place. If a rename fails, the files
+// already replaced get their old content back. paths and contents pair up.
+func WriteFiles(paths []string, contents []string) error {
+	if len(paths) != len(contents) {
+		return fmt.Errorf("got %d output files but %d contents", len(paths), len(contents))
+	}
+	
+	var staged []string
+	removeStaged := func() {
+		for _, tmpPath := range staged {
+			os.Remove(tmpPath)
+		}
+	}
+	
+	for i, path := range paths {
+		if err := validatePath(path); err != nil {
+			removeStaged()
+			return fmt.Errorf("invalid output file path: %w", err)
+		}
+		if len(contents[i]) > MaxFileSize {
+			removeStaged()
+			return fmt.Errorf("content size %d bytes of '%s' exceeds maximum allowed size of %d bytes", len(contents[i]), path, MaxFileSize)
+		}
+		tmpPath, err := stageFile(path, []byte(contents[i]), 0644)
+		if err != nil {
+			removeStaged()
+			return fmt.Errorf("failed to write output file '%s': %w", path, err)
+		}
+		staged = append(staged, tmpPath)
+	}
+	
+	// Keep what each target held so a failed rename can put the earlier ones back
+	previous := make(map[int][]byte)
+	for i, path := range paths {
+		if info, err := os.Stat(path); err == nil && info.Mode().IsRegular() {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				removeStaged()
+				return fmt.Errorf("failed to read output file '%s': %w", path, err)
+			}
+			previous[i] = data
+		}
+	}
+	
+	for i, tmpPath := range staged {
+		if err := os.Rename(tmpPath, paths[i]); err != nil {
+			for j := 0; j < i; j++ {
+				if data, ok := previous[j]; ok {
+					writeFileAtomic(paths[j], data, 0644)
+				} else {
+					os.Remove(paths[j])
+				}
+			}
+			staged = staged[i:]
+			removeStaged()
+			return fmt.Errorf("failed to write output file '%s': %w", paths[i], err)
+		}
+	}
+	return nil
+}
+
 // writeFileAtomic writes data to a file atomically by writing to a temp file first
 func writeFileAtomic(path string, data []byte, perm os.FileMode) error {
+	tmpPath, err := stageFile(path, data, perm)
+	if err != nil {
+		return err
+	}
+	
+	// Atomic rename
+	if err := os.Rename(tmpPath, path); err != nil {
+		os.Remove(tmpPath)
+		return fmt.Errorf("failed to rename temp file to target: %w", err)
+	}
+	
+	return nil
+}
+
+// stageFile writes data to a temp file in the same directory as path and
+// returns the temp file's path, ready to be renamed over path
+func stageFile(path string, data []byte, perm os.FileMode) (string, error) {
 	// Create temp file in the same directory as target
 	dir := filepath.Dir(path)
 	tmpFile, err := os.CreateTemp(dir, ".tmp-*")
 	if err != nil {
-		return fmt.Errorf("failed to create temp file: %w", err)
+		return "", fmt.Errorf("failed to create temp file: %w", err)
 	}
 	tmpPath := tmpFile.Name()
 	
-	// Ensure temp file is cleaned up
+	// Clean up the temp file unless it is complete
+	staged := false
 	defer func() {
 		tmpFile.Close()
-		os.Remove(tmpPath)
+		if !staged {
+			os.Remove(tmpPath)
+		}
 	}()
 	
 	// Write data to temp file
 	if _, err := tmpFile.Write(data); err != nil {
-		return fmt.Errorf("failed to write to temp file: %w", err)
+		return "", fmt.Errorf("failed to write to temp file: %w", err)
 	}
 	
 	// Sync to ensure data is written to disk
 	if err := tmpFile.Sync(); err != nil {
-		return fmt.Errorf("failed to sync temp file: %w", err)
+		return "", fmt.Errorf("failed to sync temp file: %w", err)
 	}
 	
 	// Close the temp file before renaming
 	if err := tmpFile.Close(); err != nil {
-		return fmt.Errorf("failed to close temp file: %w", err)
+		return "", fmt.Errorf("failed to close temp file: %w", err)
 	}
 	
 	// Set permissions
 	if err := os.Chmod(tmpPath, perm); err != nil {
-		return fmt.Errorf("failed to set temp file permissions: %w", err)
+		return "", fmt.Errorf("failed to set temp file permissions: %w", err)
 	}
 	
-	// Atomic rename
-	if err := os.Rename(tmpPath, path); err != nil {
-		return fmt.Errorf("failed to rename temp file to target: %w", err)
+	staged = true
+	return tmpPath, nil
+}
+
+// writePipelineFile serializes a pipeline to absPath as is, without validation
+func writePipelineFile(absPath string, pipeline *models.Pipeline) error {
+	data, err := yaml.Marshal(pipeline)
+	if err != nil {
+		return err
 	}
-	
-	return nil
+	return writeFileAtomic(absPath, data, 0644)
 }
 
 // DeletePipeline removes a pipeline file
@@ -706,6 +901,13 @@ func ArchivePipeline(path string) error {
 	if err := os.Rename(sourcePath, archivePath); err != nil {
 		return fmt.Errorf("failed to archive pipeline '%s': %w", path, err)
 	}
+	removeEmptyDirs(filepath.Dir(sourcePath), filepath.Join(PluqqyDir, PipelinesDir))
+	
+	// Record when the pipeline was archived
+	pipeline.Archived = time.Now().Truncate(time.Second)
+	if err := writePipelineFile(archivePath, pipeline); err != nil {
+		return fmt.Errorf("failed to record archive date for pipeline '%s': %w", path, err)
+	}
 	
 	// Update tag registry - remove tags if they're no longer used
 	if len(pipeline.Tags) > 0 {
@@ -748,6 +950,15 @@ func ArchiveComponent(path string) error {
 	if err := os.Rename(sourcePath, archivePath); err != nil {
 		return fmt.Errorf("failed to archive component '%s': %w", path, err)
 	}
+	removeEmptyDirs(filepath.Dir(sourcePath), componentTypeRoot(PluqqyDir, path))
+	
+	// Record when the component was archived
+	archivedAt := time.Now().Truncate(time.Second)
+	if err := updateComponentFrontmatter(archivePath, func(frontmatter *componentFrontmatter) {
+		frontmatter.Archived = archivedAt
+	}); err != nil {
+		return fmt.Errorf("failed to record archive date for component '%s': %w", path, err)
+	}
 	
 	// Update tag registry - remove tags if they're no longer used
 	if len(component.Tags) > 0 {
@@ -782,6 +993,15 @@ func ReadSettings() (*models.Settings, error) {
 		return nil, fmt.Errorf("failed to parse settings file: %w", err)
 	}
 	
+	if err := models.ValidateComponentTypeConfigs(settings.ComponentTypes); err != nil {
+		return nil, fmt.Errorf("invalid settings file: %w", err)
+	}
+	
+	if settings.Output.Mode != "" && !models.IsValidOutputMode(settings.Output.Mode) {
+		return nil, fmt.Errorf("invalid settings file: invalid output mode '%s', must be %s or %s",
+			settings.Output.Mode, models.OutputModeInline, models.OutputModeReference)
+	}
+	
 	// Merge with defaults to ensure all fields are populated
 	defaults := models.DefaultSettings()
 	mergeSettings(&settings, defaults)
@@ -816,11 +1036,40 @@ func mergeSettings(settings *models.Settings, defaults *models.Settings) {
 	if settings.Output.ExportPath == "" {
 		settings.Output.ExportPath = defaults.Output.ExportPath
 	}
+	if settings.Output.Format == "" {
+		settings.Output.Format = defaults.Output.Format
+	}
+	if settings.Output.Mode == "" {
+		settings.Output.Mode = defaults.Output.Mode
+	}
 	
 	// Merge sections configuration
 	if len(settings.Output.Formatting.Sections) == 0 {
 		settings.Output.Formatting.Sections = defaults.Output.Formatting.Sections
 	}
+	
+	// Merge file reference configuration
+	if settings.Output.FileReferences.MaxFileSize <= 0 {
+		settings.Output.FileReferences.MaxFileSize = defaults.Output.FileReferences.MaxFileSize
+	}
+	
+	// Merge token budget configuration
+	if settings.Output.Budget.Strategy == "" {
+		settings.Output.Budget.Strategy = defaults.Output.Budget.Strategy
+	}
+	
+	// Merge chat export configuration
+	if settings.Output.Chat.Shape == "" {
+		settings.Output.Chat.Shape = defaults.Output.Chat.Shape
+	}
+	
+	// Merge lint configuration
+	if len(settings.Lint.VaguePhrases) == 0 {
+		settings.Lint.VaguePhrases = defaults.Lint.VaguePhrases
+	}
+	if settings.Lint.MaxSectionTokens <= 0 {
+		settings.Lint.MaxSectionTokens = defaults.Lint.MaxSectionTokens
+	}
 }
 
 // CountComponentUsage returns a map of component paths to their usage count across all pipelines
@@ -951,6 +1200,15 @@ func UnarchivePipeline(path string) error {
 	if err := os.Rename(archivePath, activePath); err != nil {
 		return fmt.Errorf("failed to unarchive pipeline: %w", err)
 	}
+	removeEmptyDirs(filepath.Dir(archivePath), filepath.Join(PluqqyDir, ArchiveDir, PipelinesDir))
+	
+	// Clear the archive date now the pipeline is active again
+	if !pipeline.Archived.IsZero() {
+		pipeline.Archived = time.Time{}
+		if err := writePipelineFile(activePath, pipeline); err != nil {
+			return fmt.Errorf("failed to clear archive date for pipeline '%s': %w", path, err)
+		}
+	}
 	
 	// Update tag registry - add tags back
 	if len(pipeline.Tags) > 0 {
@@ -998,6 +1256,16 @@ func UnarchiveComponent(path string) error {
 	if err := os.Rename(archivePath, activePath); err != nil {
 		return fmt.Errorf("failed to unarchive component: %w", err)
 	}
+	removeEmptyDirs(filepath.Dir(archivePath), componentTypeRoot(filepath.Join(PluqqyDir, ArchiveDir), path))
+	
+	// Clear the archive date now the component is active again
+	if !component.Archived.IsZero() {
+		if err := updateComponentFrontmatter(activePath, func(frontmatter *componentFrontmatter) {
+			frontmatter.Archived = time.Time{}
+		}); err != nil {
+			return fmt.Errorf("failed to clear archive date for component '%s': %w", path, err)
+		}
+	}
 	
 	// Update tag registry - add tags back
 	if len(component.Tags) > 0 {
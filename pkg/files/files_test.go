@@ -3,6 +3,7 @@ package files
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 
 	"github.com/pluqqy/pluqqy-terminal/pkg/models"
@@ -34,6 +35,33 @@ func TestInitProjectStructure(t *testing.T) {
 			t.Errorf("Expected directory %s does not exist", dir)
 		}
 	}
+
+	gitignore, _ := os.ReadFile(filepath.Join(PluqqyDir, ".gitignore"))
+	if string(gitignore) != "/tmp/\n/"+SearchIndexFile+"\n" {
+		t.Errorf("Unexpected .gitignore:\n%s", gitignore)
+	}
+}
+
+func TestIgnoreFiles(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	os.MkdirAll(PluqqyDir, 0755)
+	os.WriteFile(filepath.Join(PluqqyDir, ".gitignore"), []byte("/tmp/"), 0644)
+
+	if err := IgnoreFiles("/tmp/", "/cache/"); err != nil {
+		t.Fatalf("IgnoreFiles failed: %v", err)
+	}
+	if err := IgnoreFiles("/cache/"); err != nil {
+		t.Fatalf("IgnoreFiles failed: %v", err)
+	}
+
+	gitignore, _ := os.ReadFile(filepath.Join(PluqqyDir, ".gitignore"))
+	if string(gitignore) != "/tmp/\n/cache/\n" {
+		t.Errorf("Expected each entry once, got:\n%s", gitignore)
+	}
 }
 
 func TestReadWriteComponent(t *testing.T) {
@@ -199,4 +227,88 @@ func TestErrorHandling(t *testing.T) {
 	if err == nil {
 		t.Error("Expected error for invalid component type")
 	}
-}
\ No newline at end of file
+}
+
+func TestValidateFrontmatter(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		wantErr bool
+	}{
+		{"no frontmatter", "Just content", false},
+		{"valid", "---\nname: API\ntags: [api]\n---\nContent", false},
+		{"not closed", "---\nname: API\nContent", true},
+		{"invalid yaml", "---\ntags: [api\n---\nContent", true},
+		{"wrong type", "---\ntags:\n  nested: map\n---\nContent", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateFrontmatter([]byte(tt.content))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateFrontmatter() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestReadSettings_InvalidOutputMode(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	if err := os.MkdirAll(PluqqyDir, 0755); err != nil {
+		t.Fatalf("Failed to create %s: %v", PluqqyDir, err)
+	}
+	data := "output:\n  mode: refrence\n"
+	if err := os.WriteFile(filepath.Join(PluqqyDir, SettingsFile), []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+
+	if _, err := ReadSettings(); err == nil || !strings.Contains(err.Error(), "invalid output mode 'refrence'") {
+		t.Errorf("Expected invalid output mode error, got %v", err)
+	}
+
+	data = "output:\n  mode: reference\n"
+	if err := os.WriteFile(filepath.Join(PluqqyDir, SettingsFile), []byte(data), 0644); err != nil {
+		t.Fatalf("Failed to write settings: %v", err)
+	}
+	settings, err := ReadSettings()
+	if err != nil {
+		t.Fatalf("Expected valid settings, got %v", err)
+	}
+	if settings.Output.Mode != models.OutputModeReference {
+		t.Errorf("Expected mode %q, got %q", models.OutputModeReference, settings.Output.Mode)
+	}
+}
+
+func TestWriteFiles_RestoresOnFailedRename(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	if err := os.WriteFile("a.md", []byte("old"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	// A non-empty directory can't be replaced by a file, so the last rename fails
+	if err := os.MkdirAll(filepath.Join("c.md", "keep"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	err := WriteFiles([]string{"a.md", "b.md", "c.md"}, []string{"new a", "new b", "new c"})
+	if err == nil {
+		t.Fatal("expected an error when a target can't be replaced")
+	}
+
+	if data, _ := os.ReadFile("a.md"); string(data) != "old" {
+		t.Errorf("a.md = %q, want its old content back", data)
+	}
+	if _, err := os.Stat("b.md"); !os.IsNotExist(err) {
+		t.Errorf("b.md should not exist after the failed write, got err %v", err)
+	}
+	if temps, _ := filepath.Glob(".tmp-*"); len(temps) > 0 {
+		t.Errorf("temp files left behind: %v", temps)
+	}
+}
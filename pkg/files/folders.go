@@ -0,0 +1,78 @@
+package files
+
+import (
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// FindNestedFiles returns the files under root that a reference names, searching
+// nested folders. A reference matches a file by its path relative to root
+// (backend/db) or by its base name alone (db); the extension is optional.
+// Returned paths are root joined with the file's relative path.
+func FindNestedFiles(root, ref, ext string) []string {
+	ref = strings.TrimSuffix(filepath.ToSlash(strings.TrimSpace(ref)), ext)
+	if ref == "" {
+		return nil
+	}
+
+	files, err := listFilesRecursive(root, ext)
+	if err != nil {
+		return nil
+	}
+
+	var exact, byName []string
+	for _, file := range files {
+		name := strings.TrimSuffix(file, ext)
+		switch {
+		case name == ref:
+			exact = append(exact, filepath.Join(root, filepath.FromSlash(file)))
+		case !strings.Contains(ref, "/") && path.Base(name) == ref:
+			byName = append(byName, filepath.Join(root, filepath.FromSlash(file)))
+		}
+	}
+
+	// A full relative path wins over files that merely share the base name
+	if len(exact) > 0 {
+		return exact
+	}
+	return byName
+}
+
+// ItemFolder returns the folder part of a path relative to a component type or
+// pipelines directory, e.g. "backend" for backend/db.md and "" for db.md
+func ItemFolder(relativePath string) string {
+	dir := path.Dir(filepath.ToSlash(relativePath))
+	if dir == "." {
+		return ""
+	}
+	return dir
+}
+
+// removeEmptyDirs removes dir and its parents while they are empty, stopping at stop.
+// Nested folders left empty by archiving or renaming are cleaned up this way.
+func removeEmptyDirs(dir, stop string) {
+	dir = filepath.Clean(dir)
+	stop = filepath.Clean(stop)
+	for dir != stop && strings.HasPrefix(dir, stop+string(os.PathSeparator)) {
+		entries, err := os.ReadDir(dir)
+		if err != nil || len(entries) > 0 {
+			return
+		}
+		if os.Remove(dir) != nil {
+			return
+		}
+		dir = filepath.Dir(dir)
+	}
+}
+
+// componentTypeRoot returns the type directory under base that holds a component
+// path such as components/contexts/backend/db.md
+func componentTypeRoot(base, componentPath string) string {
+	parts := strings.Split(filepath.ToSlash(componentPath), "/")
+	if len(parts) < 2 {
+		return base
+	}
+	return filepath.Join(base, parts[0], parts[1])
+}
@@ -0,0 +1,145 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNestedFolders_ListRecursively(t *testing.T) {
+	setupIncludeTest(t)
+
+	require.NoError(t, WriteComponent("components/contexts/api.md", "API"))
+	require.NoError(t, WriteComponent("components/contexts/backend/db.md", "DB"))
+	require.NoError(t, WriteComponent("components/contexts/backend/cache/redis.md", "Redis"))
+	require.NoError(t, os.MkdirAll(filepath.Join(PluqqyDir, ComponentsDir, "contexts", ".hidden"), 0755))
+	require.NoError(t, os.WriteFile(filepath.Join(PluqqyDir, ComponentsDir, "contexts", ".hidden", "x.md"), []byte("x"), 0644))
+
+	components, err := ListComponents(models.ComponentTypeContext)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"api.md", "backend/cache/redis.md", "backend/db.md"}, components)
+
+	comp, err := ReadComponent("components/contexts/backend/db.md")
+	require.NoError(t, err)
+	assert.Equal(t, models.ComponentTypeContext, comp.Type)
+
+	pipeline := &models.Pipeline{
+		Name: "Hotfix",
+		Path: "release/hotfix.yaml",
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeContext, Path: "../components/contexts/backend/db.md", Order: 1},
+		},
+	}
+	require.NoError(t, WritePipeline(pipeline))
+
+	pipelines, err := ListPipelines()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"pipelines/release/hotfix.yaml"}, pipelines)
+
+	read, err := ReadPipeline(pipelines[0])
+	require.NoError(t, err)
+	assert.Equal(t, "release/hotfix.yaml", read.Path)
+
+	loaded, err := LoadPipeline(filepath.Join(PluqqyDir, PipelinesDir, "release", "hotfix.yaml"))
+	require.NoError(t, err)
+	assert.Equal(t, "Hotfix", loaded.Name)
+}
+
+func TestFindNestedFiles(t *testing.T) {
+	setupIncludeTest(t)
+
+	require.NoError(t, WriteComponent("components/contexts/db.md", "top"))
+	require.NoError(t, WriteComponent("components/contexts/backend/db.md", "backend"))
+	require.NoError(t, WriteComponent("components/contexts/backend/cache.md", "cache"))
+	root := filepath.Join(PluqqyDir, ComponentsDir, "contexts")
+
+	tests := []struct {
+		name     string
+		ref      string
+		expected []string
+	}{
+		{"relative path wins over base name", "db", []string{"db.md"}},
+		{"folder path", "backend/db", []string{"backend/db.md"}},
+		{"extension is optional", "backend/db.md", []string{"backend/db.md"}},
+		{"base name in a folder", "cache", []string{"backend/cache.md"}},
+		{"missing", "nothing", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var expected []string
+			for _, rel := range tt.expected {
+				expected = append(expected, filepath.Join(root, filepath.FromSlash(rel)))
+			}
+			assert.Equal(t, expected, FindNestedFiles(root, tt.ref, ".md"))
+		})
+	}
+
+	// Two folders holding the same name are ambiguous
+	require.NoError(t, WriteComponent("components/contexts/frontend/cache.md", "cache"))
+	assert.Len(t, FindNestedFiles(root, "cache", ".md"), 2)
+}
+
+func TestNestedFolders_ArchiveAndUnarchive(t *testing.T) {
+	setupIncludeTest(t)
+
+	require.NoError(t, WriteComponent("components/contexts/backend/db.md", "DB"))
+	require.NoError(t, WriteComponent("components/rules/style.md", "Style"))
+	require.NoError(t, WritePipeline(&models.Pipeline{
+		Name: "Hotfix",
+		Path: "release/hotfix.yaml",
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeRules, Path: "../components/rules/style.md", Order: 1},
+		},
+	}))
+
+	require.NoError(t, ArchiveComponent("components/contexts/backend/db.md"))
+	assert.FileExists(t, filepath.Join(PluqqyDir, ArchiveDir, ComponentsDir, "contexts", "backend", "db.md"))
+	assert.NoDirExists(t, filepath.Join(PluqqyDir, ComponentsDir, "contexts", "backend"))
+	assert.DirExists(t, filepath.Join(PluqqyDir, ComponentsDir, "contexts"))
+
+	archived, err := ListArchivedComponents(models.ComponentTypeContext)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"backend/db.md"}, archived)
+
+	require.NoError(t, UnarchiveComponent("components/contexts/backend/db.md"))
+	assert.FileExists(t, filepath.Join(PluqqyDir, ComponentsDir, "contexts", "backend", "db.md"))
+	assert.NoDirExists(t, filepath.Join(PluqqyDir, ArchiveDir, ComponentsDir, "contexts", "backend"))
+
+	require.NoError(t, ArchivePipeline("release/hotfix.yaml"))
+	archivedPipelines, err := ListArchivedPipelines()
+	require.NoError(t, err)
+	assert.Equal(t, []string{"archive/pipelines/release/hotfix.yaml"}, archivedPipelines)
+	assert.NoDirExists(t, filepath.Join(PluqqyDir, PipelinesDir, "release"))
+
+	require.NoError(t, UnarchivePipeline("release/hotfix.yaml"))
+	assert.FileExists(t, filepath.Join(PluqqyDir, PipelinesDir, "release", "hotfix.yaml"))
+}
+
+func TestNestedFolders_RenameKeepsFolder(t *testing.T) {
+	setupIncludeTest(t)
+
+	require.NoError(t, WriteComponent("components/contexts/backend/db.md", "DB"))
+	require.NoError(t, WritePipeline(&models.Pipeline{
+		Name: "Hotfix",
+		Path: "release/hotfix.yaml",
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeContext, Path: "../components/contexts/backend/db.md", Order: 1},
+		},
+	}))
+
+	require.NoError(t, RenameComponent("components/contexts/backend/db.md", "Database"))
+	assert.FileExists(t, filepath.Join(PluqqyDir, ComponentsDir, "contexts", "backend", "database.md"))
+
+	pipeline, err := ReadPipeline("release/hotfix.yaml")
+	require.NoError(t, err)
+	assert.Equal(t, "../components/contexts/backend/database.md", pipeline.Components[0].Path)
+
+	require.NoError(t, RenamePipeline("release/hotfix.yaml", "Patch"))
+	assert.FileExists(t, filepath.Join(PluqqyDir, PipelinesDir, "release", "patch.yaml"))
+	assert.NoFileExists(t, filepath.Join(PluqqyDir, PipelinesDir, "release", "hotfix.yaml"))
+}
@@ -0,0 +1,151 @@
+package files
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// PipelineRefFilename normalizes a pipeline reference from an extends list
+// ("base-standards", "base-standards.yaml" or "pipelines/base-standards.yaml")
+// to the pipeline filename
+func PipelineRefFilename(ref string) string {
+	ref = strings.TrimPrefix(filepath.ToSlash(strings.TrimSpace(ref)), PipelinesDir+"/")
+	if !strings.HasSuffix(ref, ".yaml") {
+		ref += ".yaml"
+	}
+	return ref
+}
+
+// ResolvePipelineIncludes returns a copy of the pipeline with every pipeline it
+// extends flattened into its component list. Included components come first, in
+// the order the includes are listed, followed by the pipeline's own components.
+// Duplicate components keep their first position and orders are renumbered.
+// Variables from included pipelines act as defaults for the including pipeline.
+func ResolvePipelineIncludes(pipeline *models.Pipeline) (*models.Pipeline, error) {
+	if pipeline == nil {
+		return nil, fmt.Errorf("cannot resolve includes: nil pipeline provided")
+	}
+
+	if len(pipeline.Extends) == 0 {
+		return pipeline, nil
+	}
+
+	var chain []string
+	if pipeline.Path != "" {
+		chain = append(chain, PipelineRefFilename(pipeline.Path))
+	}
+
+	components, variables, err := flattenPipeline(pipeline, chain)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := *pipeline
+	resolved.Components = dedupeComponents(components)
+	resolved.Variables = variables
+	return &resolved, nil
+}
+
+// flattenPipeline collects the components and variables of a pipeline and its includes.
+// chain holds the filenames currently being resolved, for cycle detection.
+func flattenPipeline(pipeline *models.Pipeline, chain []string) ([]models.ComponentRef, map[string]string, error) {
+	var components []models.ComponentRef
+	variables := make(map[string]string)
+
+	for _, ref := range pipeline.Extends {
+		filename := PipelineRefFilename(ref)
+
+		for i, visited := range chain {
+			if visited == filename {
+				cycle := append(append([]string{}, chain[i:]...), filename)
+				return nil, nil, fmt.Errorf("pipeline include cycle detected: %s", formatIncludeChain(cycle))
+			}
+		}
+
+		included, err := ReadPipeline(filename)
+		if err != nil {
+			return nil, nil, fmt.Errorf("pipeline '%s' extends '%s': %w", pipeline.Name, ref, err)
+		}
+
+		includedComponents, includedVariables, err := flattenPipeline(included, append(chain, filename))
+		if err != nil {
+			return nil, nil, err
+		}
+
+		components = append(components, includedComponents...)
+		for name, value := range includedVariables {
+			variables[name] = value
+		}
+	}
+
+	own := make([]models.ComponentRef, len(pipeline.Components))
+	copy(own, pipeline.Components)
+	sort.SliceStable(own, func(i, j int) bool {
+		return own[i].Order < own[j].Order
+	})
+	components = append(components, own...)
+
+	for name, value := range pipeline.Variables {
+		variables[name] = value
+	}
+
+	return components, variables, nil
+}
+
+// dedupeComponents drops repeated component paths and renumbers orders from 1
+func dedupeComponents(components []models.ComponentRef) []models.ComponentRef {
+	seen := make(map[string]bool)
+	var result []models.ComponentRef
+
+	for _, comp := range components {
+		key := filepath.Clean(comp.Path)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		comp.Order = len(result) + 1
+		result = append(result, comp)
+	}
+
+	return result
+}
+
+// formatIncludeChain renders a chain of pipeline filenames as "a -> b -> a"
+func formatIncludeChain(chain []string) string {
+	names := make([]string, len(chain))
+	for i, filename := range chain {
+		names[i] = strings.TrimSuffix(filename, ".yaml")
+	}
+	return strings.Join(names, " -> ")
+}
+
+// PipelineExtends reports whether the pipeline directly extends any of the given pipeline filenames
+func PipelineExtends(pipeline *models.Pipeline, filenames map[string]bool) (string, bool) {
+	for _, ref := range pipeline.Extends {
+		if filenames[PipelineRefFilename(ref)] {
+			return PipelineRefFilename(ref), true
+		}
+	}
+	return "", false
+}
+
+// expandIncludingPipelines grows a set of affected pipeline filenames with every
+// active pipeline that extends one of them, directly or transitively
+func expandIncludingPipelines(affected map[string]bool, pipelines map[string]*models.Pipeline) {
+	for changed := true; changed; {
+		changed = false
+		for filename, pipeline := range pipelines {
+			if affected[filename] {
+				continue
+			}
+			if _, ok := PipelineExtends(pipeline, affected); ok {
+				affected[filename] = true
+				changed = true
+			}
+		}
+	}
+}
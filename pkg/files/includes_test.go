@@ -0,0 +1,201 @@
+package files
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupIncludeTest(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	os.Chdir(tempDir)
+
+	require.NoError(t, InitProjectStructure())
+}
+
+func TestPipelineRefFilename(t *testing.T) {
+	assert.Equal(t, "base.yaml", PipelineRefFilename("base"))
+	assert.Equal(t, "base.yaml", PipelineRefFilename("base.yaml"))
+	assert.Equal(t, "base.yaml", PipelineRefFilename("pipelines/base.yaml"))
+	assert.Equal(t, "base.yaml", PipelineRefFilename(" base "))
+}
+
+func TestResolvePipelineIncludes(t *testing.T) {
+	setupIncludeTest(t)
+
+	base := &models.Pipeline{
+		Name: "base",
+		Path: "base.yaml",
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeRules, Path: "../components/rules/style.md", Order: 1},
+			{Type: models.ComponentTypeContext, Path: "../components/contexts/repo.md", Order: 2},
+		},
+		Variables: map[string]string{"lang": "go", "team": "core"},
+	}
+	require.NoError(t, WritePipeline(base))
+
+	security := &models.Pipeline{
+		Name: "security",
+		Path: "security.yaml",
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeRules, Path: "../components/rules/secrets.md", Order: 1},
+			{Type: models.ComponentTypeRules, Path: "../components/rules/style.md", Order: 2},
+		},
+	}
+	require.NoError(t, WritePipeline(security))
+
+	t.Run("flattens includes before own components", func(t *testing.T) {
+		pipeline := &models.Pipeline{
+			Name:    "feature",
+			Path:    "feature.yaml",
+			Extends: []string{"base", "security"},
+			Components: []models.ComponentRef{
+				{Type: models.ComponentTypePrompt, Path: "../components/prompts/task.md", Order: 2},
+				{Type: models.ComponentTypeContext, Path: "../components/contexts/repo.md", Order: 1},
+			},
+			Variables: map[string]string{"lang": "rust"},
+		}
+
+		resolved, err := ResolvePipelineIncludes(pipeline)
+		require.NoError(t, err)
+
+		var paths []string
+		for i, comp := range resolved.Components {
+			paths = append(paths, comp.Path)
+			assert.Equal(t, i+1, comp.Order)
+		}
+		assert.Equal(t, []string{
+			"../components/rules/style.md",
+			"../components/contexts/repo.md",
+			"../components/rules/secrets.md",
+			"../components/prompts/task.md",
+		}, paths)
+
+		assert.Equal(t, "rust", resolved.Variables["lang"], "own variables override included ones")
+		assert.Equal(t, "core", resolved.Variables["team"], "included variables act as defaults")
+
+		// The original pipeline is left untouched
+		assert.Len(t, pipeline.Components, 2)
+	})
+
+	t.Run("pipeline without extends is returned as is", func(t *testing.T) {
+		resolved, err := ResolvePipelineIncludes(base)
+		require.NoError(t, err)
+		assert.Same(t, base, resolved)
+	})
+
+	t.Run("missing include is an error", func(t *testing.T) {
+		pipeline := &models.Pipeline{Name: "broken", Extends: []string{"nope"}}
+		_, err := ResolvePipelineIncludes(pipeline)
+		require.Error(t, err)
+		assert.Contains(t, err.Error(), "extends 'nope'")
+	})
+}
+
+func TestResolvePipelineIncludes_Cycle(t *testing.T) {
+	setupIncludeTest(t)
+
+	a := &models.Pipeline{Name: "a", Path: "a.yaml", Extends: []string{"b"}}
+	b := &models.Pipeline{Name: "b", Path: "b.yaml", Extends: []string{"c"}}
+	c := &models.Pipeline{Name: "c", Path: "c.yaml", Extends: []string{"a"}}
+	require.NoError(t, WritePipeline(a))
+	require.NoError(t, WritePipeline(b))
+	require.NoError(t, WritePipeline(c))
+
+	_, err := ResolvePipelineIncludes(a)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "a -> b -> c -> a")
+
+	self := &models.Pipeline{Name: "self", Path: "self.yaml", Extends: []string{"self"}}
+	require.NoError(t, WritePipeline(self))
+	_, err = ResolvePipelineIncludes(self)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "self -> self")
+}
+
+func TestFindAffectedPipelines_FollowsIncludes(t *testing.T) {
+	setupIncludeTest(t)
+
+	componentPath := filepath.Join(ComponentsDir, "rules", "style.md")
+	require.NoError(t, WriteComponent(componentPath, "# Style"))
+
+	require.NoError(t, WritePipeline(&models.Pipeline{
+		Name: "base",
+		Path: "base.yaml",
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeRules, Path: "../components/rules/style.md", Order: 1},
+		},
+	}))
+	require.NoError(t, WritePipeline(&models.Pipeline{
+		Name:    "middle",
+		Path:    "middle.yaml",
+		Extends: []string{"base"},
+	}))
+	require.NoError(t, WritePipeline(&models.Pipeline{
+		Name:    "top",
+		Path:    "top.yaml",
+		Extends: []string{"middle"},
+	}))
+	require.NoError(t, WritePipeline(&models.Pipeline{
+		Name: "unrelated",
+		Path: "unrelated.yaml",
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypePrompt, Path: "../components/prompts/other.md", Order: 1},
+		},
+	}))
+	require.NoError(t, WritePipelineToArchive(&models.Pipeline{
+		Name:    "old",
+		Extends: []string{"top"},
+	}))
+
+	active, archived, err := FindAffectedPipelines(componentPath)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"base", "middle", "top"}, active)
+	assert.Equal(t, []string{"old"}, archived)
+}
+
+func TestPipelineComponentPaths(t *testing.T) {
+	setupIncludeTest(t)
+
+	require.NoError(t, WriteComponent("components/rules/style.md", "Style"))
+	require.NoError(t, WriteComponent("components/contexts/repo.md", "Repo"))
+
+	base := &models.Pipeline{
+		Name: "base",
+		Path: "base.yaml",
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeRules, Path: "../components/rules/style.md", Order: 1},
+		},
+	}
+	require.NoError(t, WritePipeline(base))
+
+	pipeline := &models.Pipeline{
+		Name:    "feature",
+		Path:    "feature.yaml",
+		Extends: []string{"base"},
+		Components: []models.ComponentRef{
+			{Type: models.ComponentTypeContext, Path: "../components/contexts/repo.md", Order: 1},
+		},
+	}
+	paths, broken := PipelineComponentPaths(pipeline)
+	assert.Equal(t, []string{"components/rules/style.md", "components/contexts/repo.md"}, paths)
+	assert.False(t, broken)
+
+	pipeline.Components = append(pipeline.Components, models.ComponentRef{
+		Type: models.ComponentTypePrompt, Path: "../components/prompts/gone.md", Order: 2,
+	})
+	_, broken = PipelineComponentPaths(pipeline)
+	assert.True(t, broken, "a missing component breaks the pipeline")
+
+	pipeline.Components = pipeline.Components[:1]
+	pipeline.Extends = []string{"missing"}
+	paths, broken = PipelineComponentPaths(pipeline)
+	assert.True(t, broken, "a missing extended pipeline breaks the pipeline")
+	assert.Equal(t, []string{"components/contexts/repo.md"}, paths)
+}
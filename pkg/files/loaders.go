@@ -24,10 +24,9 @@ func LoadComponent(path string) (*models.Component, error) {
 // LoadPipeline is a convenience wrapper for ReadPipeline
 // It reads a pipeline from the specified path, handling both absolute and relative paths
 func LoadPipeline(path string) (*models.Pipeline, error) {
-	// If path already contains .pluqqy/pipelines, extract just the filename
-	if strings.Contains(path, filepath.Join(PluqqyDir, PipelinesDir)) {
-		// Get just the filename
-		path = filepath.Base(path)
+	// If path already contains .pluqqy/pipelines, keep the part below it so nested folders survive
+	if idx := strings.Index(path, filepath.Join(PluqqyDir, PipelinesDir)+string(filepath.Separator)); idx >= 0 {
+		path = path[idx+len(PluqqyDir)+len(PipelinesDir)+2:]
 	} else if strings.Contains(path, PluqqyDir) {
 		// If it just contains .pluqqy but not pipelines, remove .pluqqy/ prefix
 		idx := strings.Index(path, PluqqyDir)
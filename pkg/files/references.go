@@ -141,25 +141,31 @@ func FindAffectedPipelines(componentPath string) (activeNames []string, archived
 		return nil, nil, fmt.Errorf("failed to list active pipelines: %w", err)
 	}
 	
+	activeByFile := make(map[string]*models.Pipeline)
+	affectedFiles := make(map[string]bool)
+	var activeOrder []string
+	
 	for _, pipelineName := range activePipelines {
 		// Read the pipeline (expects just filename)
 		pipeline, err := ReadPipeline(pipelineName)
 		if err != nil {
 			continue // Skip pipelines that can't be read
 		}
+		activeByFile[pipeline.Path] = pipeline
+		activeOrder = append(activeOrder, pipeline.Path)
 		
 		// Check if this pipeline references the component
-		for _, comp := range pipeline.Components {
-			// Remove "../" prefix for comparison
-			compPath := strings.TrimPrefix(comp.Path, "../")
-			compPath = filepath.Clean(compPath)
-			
-			// Check if this component matches exactly
-			if compPath == componentPath {
-				// Use pipeline's display name
-				activeNames = append(activeNames, pipeline.Name)
-				break
-			}
+		if referencesComponent(pipeline, componentPath) {
+			affectedFiles[pipeline.Path] = true
+		}
+	}
+	
+	// Pipelines that extend an affected pipeline are affected too
+	expandIncludingPipelines(affectedFiles, activeByFile)
+	for _, filename := range activeOrder {
+		if affectedFiles[filename] {
+			// Use pipeline's display name
+			activeNames = append(activeNames, activeByFile[filename].Name)
 		}
 	}
 	
@@ -177,24 +183,61 @@ func FindAffectedPipelines(componentPath string) (activeNames []string, archived
 			continue // Skip pipelines that can't be read
 		}
 		
-		// Check if this pipeline references the component
-		for _, comp := range pipeline.Components {
-			// Remove "../" prefix for comparison
-			compPath := strings.TrimPrefix(comp.Path, "../")
-			compPath = filepath.Clean(compPath)
-			
-			// Check if this component matches exactly
-			if compPath == componentPath {
-				// Use pipeline's display name
-				archivedNames = append(archivedNames, pipeline.Name)
-				break
-			}
+		// Check if this pipeline references the component directly or through an include
+		_, extendsAffected := PipelineExtends(pipeline, affectedFiles)
+		if referencesComponent(pipeline, componentPath) || extendsAffected {
+			// Use pipeline's display name
+			archivedNames = append(archivedNames, pipeline.Name)
 		}
 	}
 	
 	return activeNames, archivedNames, nil
 }
 
+// referencesComponent checks whether a pipeline lists the component directly
+func referencesComponent(pipeline *models.Pipeline, componentPath string) bool {
+	for _, comp := range pipeline.Components {
+		// Remove "../" prefix for comparison
+		compPath := strings.TrimPrefix(comp.Path, "../")
+		compPath = filepath.Clean(compPath)
+		
+		// Check if this component matches exactly
+		if compPath == componentPath {
+			return true
+		}
+	}
+	return false
+}
+
+// ComponentRefPath returns the path of a pipeline's component reference relative
+// to the .pluqqy directory, e.g. components/contexts/api.md for ../components/contexts/api.md
+func ComponentRefPath(ref models.ComponentRef) string {
+	return filepath.ToSlash(filepath.Clean(filepath.Join(PipelinesDir, ref.Path)))
+}
+
+// PipelineComponentPaths returns the components a pipeline includes, following
+// extends, and whether any of them or any extended pipeline is missing
+func PipelineComponentPaths(pipeline *models.Pipeline) ([]string, bool) {
+	broken := false
+	resolved, err := ResolvePipelineIncludes(pipeline)
+	if err != nil {
+		// Fall back to the pipeline's own components
+		broken = true
+		resolved = pipeline
+	}
+	
+	var paths []string
+	for _, comp := range resolved.Components {
+		componentPath := ComponentRefPath(comp)
+		paths = append(paths, componentPath)
+		if _, err := os.Stat(filepath.Join(PluqqyDir, componentPath)); err != nil {
+			broken = true
+		}
+	}
+	
+	return paths, broken
+}
+
 // matchesPath checks if two paths refer to the same file
 // It handles relative paths and different path representations
 func matchesPath(path1, path2 string) bool {
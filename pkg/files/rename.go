@@ -159,8 +159,9 @@ func RenameComponent(oldPath, newDisplayName string) error {
 		return fmt.Errorf("failed to create backup: %w", err)
 	}
 	
-	// Write to new path with updated name in frontmatter
-	if err := WriteComponentWithNameAndTags(newPath, component.Content, newDisplayName, component.Tags); err != nil {
+	// Write to new path with updated name in frontmatter, keeping the recorded dates
+	frontmatter := &componentFrontmatter{Name: newDisplayName, Tags: component.Tags, Created: component.Created, Archived: component.Archived}
+	if err := WriteComponent(newPath, renderComponentContent(frontmatter, []byte(component.Content))); err != nil {
 		return fmt.Errorf("failed to write renamed component: %w", err)
 	}
 	
@@ -197,11 +198,14 @@ func RenamePipeline(oldPath, newDisplayName string) error {
 		return fmt.Errorf("new display name cannot be empty")
 	}
 	
-	// oldPath is just the filename, we need to handle it properly
+	// oldPath is relative to the pipelines directory; the pipeline stays in its folder
 	oldFilename := oldPath
 	newSlug := Slugify(newDisplayName)
 	ext := filepath.Ext(oldFilename)
 	newFilename := newSlug + ext
+	if folder := ItemFolder(oldFilename); folder != "" {
+		newFilename = folder + "/" + newFilename
+	}
 	
 	// Build absolute paths for file operations (add PipelinesDir)
 	absOldPath := filepath.Join(PluqqyDir, PipelinesDir, oldFilename)
@@ -313,11 +317,14 @@ func RenamePipelineInArchive(oldPath, newDisplayName string) error {
 		return fmt.Errorf("new display name cannot be empty")
 	}
 	
-	// oldPath is just the filename
+	// oldPath is relative to the archived pipelines directory; the pipeline stays in its folder
 	oldFilename := oldPath
 	newSlug := Slugify(newDisplayName)
 	ext := filepath.Ext(oldFilename)
 	newFilename := newSlug + ext
+	if folder := ItemFolder(oldFilename); folder != "" {
+		newFilename = folder + "/" + newFilename
+	}
 	
 	// Build absolute paths for archived pipelines
 	absOldPath := filepath.Join(PluqqyDir, ArchiveDir, PipelinesDir, oldFilename)
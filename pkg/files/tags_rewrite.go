@@ -0,0 +1,212 @@
+package files
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// TagRewriteResult records the files changed by RewriteTags. Paths are relative
+// to the .pluqqy directory, e.g. components/contexts/api.md or archive/pipelines/x.yaml.
+type TagRewriteResult struct {
+	Components []string `json:"components" yaml:"components"`
+	Pipelines  []string `json:"pipelines" yaml:"pipelines"`
+
+	// Original contents of every changed file, kept for Rollback
+	backups []tagRewrite
+}
+
+// tagRewrite is a pending change to a single file
+type tagRewrite struct {
+	path     string
+	original []byte
+	updated  []byte
+}
+
+// FilesChanged returns the number of components and pipelines that were rewritten
+func (r *TagRewriteResult) FilesChanged() int {
+	return len(r.Components) + len(r.Pipelines)
+}
+
+// Rollback restores every rewritten file to its original content. It is used
+// when a later step, such as saving the tag registry, fails.
+func (r *TagRewriteResult) Rollback() error {
+	var firstErr error
+	for _, backup := range r.backups {
+		if err := writeFileAtomic(backup.path, backup.original, 0644); err != nil && firstErr == nil {
+			firstErr = fmt.Errorf("failed to restore %s: %w", backup.path, err)
+		}
+	}
+	return firstErr
+}
+
+// RewriteTags replaces tags in every component and pipeline, active and archived.
+// Keys of replacements are the tags to replace and values their new names; several
+// keys may share a value to merge tags, and an empty value removes the tag. Items
+// that end up with the same tag twice keep a single copy. Either every file is
+// rewritten or, on failure, none is.
+func RewriteTags(replacements map[string]string) (*TagRewriteResult, error) {
+	normalized := make(map[string]string, len(replacements))
+	for from, to := range replacements {
+		normalized[models.NormalizeTagName(from)] = models.NormalizeTagName(to)
+	}
+
+	var paths []string
+	for _, compType := range ComponentTypes() {
+		dirs := []string{
+			filepath.Join(ComponentsDir, compType.DirName()),
+			filepath.Join(ArchiveDir, ComponentsDir, compType.DirName()),
+		}
+		for _, dir := range dirs {
+			found, err := listFilesRecursive(filepath.Join(PluqqyDir, dir), ".md")
+			if err != nil {
+				return nil, fmt.Errorf("failed to list components in %s: %w", dir, err)
+			}
+			for _, file := range found {
+				paths = append(paths, filepath.ToSlash(filepath.Join(dir, file)))
+			}
+		}
+	}
+
+	for _, dir := range []string{PipelinesDir, filepath.Join(ArchiveDir, PipelinesDir)} {
+		found, err := listFilesRecursive(filepath.Join(PluqqyDir, dir), ".yaml")
+		if err != nil {
+			return nil, fmt.Errorf("failed to list pipelines in %s: %w", dir, err)
+		}
+		for _, file := range found {
+			paths = append(paths, filepath.ToSlash(filepath.Join(dir, file)))
+		}
+	}
+
+	return EditItemTags(paths, func(tags []string) ([]string, bool) {
+		return replaceTags(tags, normalized)
+	})
+}
+
+// EditItemTags applies edit to the tags of the given components and pipelines.
+// Paths are relative to the .pluqqy directory, e.g. components/prompts/x.md or
+// archive/pipelines/y.yaml; .yaml files are treated as pipelines and everything
+// else as components. edit returns the new tags and whether they changed. Either
+// every changed file is rewritten or, on failure, none is.
+func EditItemTags(paths []string, edit func(tags []string) ([]string, bool)) (*TagRewriteResult, error) {
+	result := &TagRewriteResult{Components: []string{}, Pipelines: []string{}}
+
+	// Compute every change before touching the disk
+	var rewrites []tagRewrite
+	for _, relPath := range paths {
+		if err := validatePath(relPath); err != nil {
+			return nil, fmt.Errorf("invalid path %s: %w", relPath, err)
+		}
+		path := filepath.Join(PluqqyDir, relPath)
+
+		isPipeline := strings.HasSuffix(relPath, ".yaml")
+		var rewrite tagRewrite
+		var changed bool
+		var err error
+		if isPipeline {
+			rewrite, changed, err = rewritePipelineTags(path, edit)
+		} else {
+			rewrite, changed, err = rewriteComponentTags(path, edit)
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !changed {
+			continue
+		}
+
+		rewrites = append(rewrites, rewrite)
+		if isPipeline {
+			result.Pipelines = append(result.Pipelines, relPath)
+		} else {
+			result.Components = append(result.Components, relPath)
+		}
+	}
+
+	// Write all changes, restoring the files already written if one fails
+	for _, rewrite := range rewrites {
+		if err := writeFileAtomic(rewrite.path, rewrite.updated, 0644); err != nil {
+			result.Rollback()
+			return nil, fmt.Errorf("failed to update %s: %w", rewrite.path, err)
+		}
+		result.backups = append(result.backups, rewrite)
+	}
+
+	return result, nil
+}
+
+// rewriteComponentTags computes the new content of a component file
+func rewriteComponentTags(path string, edit func([]string) ([]string, bool)) (tagRewrite, bool, error) {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return tagRewrite{}, false, fmt.Errorf("failed to read component %s: %w", path, err)
+	}
+
+	frontmatter, _, _ := extractFrontmatter(original)
+	tags, changed := edit(frontmatter.Tags)
+	if !changed {
+		return tagRewrite{}, false, nil
+	}
+
+	updated := formatComponentContentWithName(string(original), "", tags)
+	return tagRewrite{path: path, original: original, updated: []byte(updated)}, true, nil
+}
+
+// rewritePipelineTags computes the new content of a pipeline file
+func rewritePipelineTags(path string, edit func([]string) ([]string, bool)) (tagRewrite, bool, error) {
+	original, err := os.ReadFile(path)
+	if err != nil {
+		return tagRewrite{}, false, fmt.Errorf("failed to read pipeline %s: %w", path, err)
+	}
+
+	var pipeline models.Pipeline
+	if err := yaml.Unmarshal(original, &pipeline); err != nil {
+		// Leave pipelines we can't parse alone, as other bulk updates do
+		return tagRewrite{}, false, nil
+	}
+
+	tags, changed := edit(pipeline.Tags)
+	if !changed {
+		return tagRewrite{}, false, nil
+	}
+	pipeline.Tags = tags
+
+	updated, err := yaml.Marshal(&pipeline)
+	if err != nil {
+		return tagRewrite{}, false, fmt.Errorf("failed to serialize pipeline %s: %w", path, err)
+	}
+	return tagRewrite{path: path, original: original, updated: updated}, true, nil
+}
+
+// replaceTags applies replacements to a tag list, dropping removed tags and
+// duplicates while keeping the original order. Lists without a replaced tag are
+// left untouched.
+func replaceTags(tags []string, replacements map[string]string) ([]string, bool) {
+	replaced := false
+	seen := make(map[string]bool, len(tags))
+	result := make([]string, 0, len(tags))
+
+	for _, tag := range tags {
+		name := tag
+		if replacement, ok := replacements[models.NormalizeTagName(tag)]; ok {
+			name = replacement
+			replaced = true
+		}
+		key := models.NormalizeTagName(name)
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		result = append(result, name)
+	}
+
+	if !replaced {
+		return tags, false
+	}
+	return result, true
+}
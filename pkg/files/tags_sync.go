@@ -75,11 +75,7 @@ func UpdateTagRegistryOnUnarchive(unarchivedTags []string) error {
 
 		// If tag doesn't exist, add it with auto-assigned color
 		if !exists {
-			newTag := models.Tag{
-				Name:  normalizedName,
-				Color: models.GetTagColor(normalizedName, ""),
-			}
-			registry.Tags = append(registry.Tags, newTag)
+			registry.Tags = append(registry.Tags, models.NewTag(normalizedName))
 		}
 	}
 
@@ -92,14 +88,14 @@ func getActiveItemTags() (map[string]bool, error) {
 	activeTags := make(map[string]bool)
 
 	// Get tags from active components
-	for _, compType := range []string{models.ComponentTypePrompt, models.ComponentTypeContext, models.ComponentTypeRules} {
-		components, err := ListComponents(compType)
+	for _, compType := range ComponentTypes() {
+		components, err := ListComponents(compType.Name)
 		if err != nil {
 			continue // Skip on error
 		}
 
 		for _, compFile := range components {
-			compPath := filepath.Join(ComponentsDir, compType, compFile)
+			compPath := filepath.Join(ComponentsDir, compType.DirName(), compFile)
 			comp, err := ReadComponent(compPath)
 			if err != nil {
 				continue // Skip components that can't be read
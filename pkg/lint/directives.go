@@ -0,0 +1,136 @@
+package lint
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// minDirectiveWords is the fewest words an instruction needs, once "always",
+// "never" and the like are stripped, to be compared with others. Shorter ones
+// such as "do it" are too generic to call contradictory or duplicated.
+const minDirectiveWords = 2
+
+// directive is a sentence read as an instruction: an action and whether it is
+// asked for or forbidden
+type directive struct {
+	doc     string
+	line    int
+	text    string // The sentence as written
+	action  string // Normalized words after the modal, e.g. "use tabs"
+	negated bool
+}
+
+// key identifies directives that say the same thing
+func (d directive) key() string {
+	if d.negated {
+		return "not " + d.action
+	}
+	return d.action
+}
+
+// modalWords lead into an instruction without changing what it asks for
+var modalWords = map[string]bool{
+	"you": true, "we": true, "please": true, "always": true, "must": true, "should": true,
+	"shall": true, "do": true, "need": true, "needs": true, "to": true, "make": true, "sure": true,
+}
+
+// negationWords turn an instruction into a prohibition
+var negationWords = map[string]bool{
+	"never": true, "not": true, "don't": true, "dont": true, "mustn't": true,
+	"shouldn't": true, "no": true, "cannot": true, "can't": true,
+}
+
+var (
+	// listMarker matches quote markers, bullets, numbers and checkboxes at the start of a line
+	listMarker = regexp.MustCompile(`^\s*(?:>\s*)*(?:(?:[-*+]|\d+[.)])\s+)?(?:\[[ xX]\]\s+)?`)
+	// placeholder matches {{name}} template variables
+	placeholder = regexp.MustCompile(`\{\{[^}]*\}\}`)
+	// sentenceEnd splits a line into sentences
+	sentenceEnd = regexp.MustCompile(`[.!?;]+(?:\s+|$)`)
+	// wordPattern matches the words compared between directives
+	wordPattern = regexp.MustCompile(`[\p{L}\p{N}']+`)
+)
+
+// extractDirectives reads every sentence outside code blocks, headings and
+// tables as a directive
+func extractDirectives(doc Document, lines []line) []directive {
+	var directives []directive
+	for _, l := range lines {
+		if l.fence || l.inFence || headingPattern.MatchString(l.text) || strings.HasPrefix(strings.TrimSpace(l.text), "|") {
+			continue
+		}
+		text := listMarker.ReplaceAllString(l.text, "")
+		for _, sentence := range sentenceEnd.Split(text, -1) {
+			sentence = strings.TrimSpace(sentence)
+			// A label such as "Key components:" introduces instructions rather than
+			// giving one, and a sentence with a {{placeholder}} isn't written yet
+			if strings.HasSuffix(sentence, ":") || placeholder.MatchString(sentence) {
+				continue
+			}
+			if d, ok := parseDirective(sentence); ok {
+				d.doc = doc.Path
+				d.line = l.number + doc.LineOffset
+				directives = append(directives, d)
+			}
+		}
+	}
+	return directives
+}
+
+// parseDirective strips the modal words leading into a sentence, noting any negation
+func parseDirective(sentence string) (directive, bool) {
+	normalized := strings.ToLower(strings.ReplaceAll(sentence, "’", "'"))
+	words := wordPattern.FindAllString(normalized, -1)
+
+	d := directive{text: sentence}
+	i := 0
+	for ; i < len(words); i++ {
+		if negationWords[words[i]] {
+			d.negated = true
+		} else if !modalWords[words[i]] {
+			break
+		}
+	}
+	if len(words)-i < minDirectiveWords {
+		return directive{}, false
+	}
+	d.action = strings.Join(words[i:], " ")
+	return d, true
+}
+
+// checkDirectives reports instructions that are repeated and ones that contradict
+// an earlier instruction. Each is reported where it occurs the second time.
+func checkDirectives(directives []directive, settings models.LintSettings) []Finding {
+	var findings []Finding
+	first := make(map[string]directive)
+
+	for _, d := range directives {
+		if previous, ok := first[d.key()]; ok {
+			if settings.Enabled(RuleDuplicate) {
+				findings = append(findings, Finding{
+					Rule:      RuleDuplicate,
+					Component: d.doc,
+					Line:      d.line,
+					Message:   fmt.Sprintf("%q repeats %q from %s:%d", d.text, previous.text, previous.doc, previous.line),
+				})
+			}
+			continue
+		}
+
+		opposite := directive{action: d.action, negated: !d.negated}
+		if previous, ok := first[opposite.key()]; ok && settings.Enabled(RuleContradiction) {
+			findings = append(findings, Finding{
+				Rule:      RuleContradiction,
+				Component: d.doc,
+				Line:      d.line,
+				Message:   fmt.Sprintf("%q contradicts %q from %s:%d", d.text, previous.text, previous.doc, previous.line),
+			})
+		}
+		first[d.key()] = d
+	}
+
+	return findings
+}
@@ -0,0 +1,282 @@
+// Package lint checks the text of a pipeline's components for instructions that
+// work against each other or leave a model guessing. The rules and their limits
+// are configured in the lint section of settings.
+package lint
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/composer"
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// Rules the linter applies
+const (
+	RuleContradiction = "contradiction" // One directive says to do what another says not to
+	RuleDuplicate     = "duplicate"     // The same instruction is given more than once
+	RuleVague         = "vague"         // A phrase from the vague_phrases deny-list
+	RuleLongSection   = "long-section"  // A section is over max_section_tokens
+	RuleCodeFence     = "code-fence"    // A code fence is never closed
+)
+
+// Rules lists every rule, in the order they are described
+var Rules = []string{RuleContradiction, RuleDuplicate, RuleVague, RuleLongSection, RuleCodeFence}
+
+// Finding is a single lint warning. Line is the line in the component file.
+type Finding struct {
+	Rule      string `json:"rule" yaml:"rule"`
+	Component string `json:"component" yaml:"component"` // e.g. rules/style.md
+	Line      int    `json:"line" yaml:"line"`
+	Message   string `json:"message" yaml:"message"`
+}
+
+// String formats the finding as component:line: message
+func (f Finding) String() string {
+	return fmt.Sprintf("%s:%d: %s", f.Component, f.Line, f.Message)
+}
+
+// Document is a component's text as the linter sees it
+type Document struct {
+	Path       string // Shown in findings, e.g. rules/style.md
+	Content    string // Content without frontmatter
+	LineOffset int    // Lines of frontmatter before Content in the file
+}
+
+// LintPipeline lints the components a pipeline includes, following extends, in
+// the order they are composed. Components that can't be read are skipped.
+func LintPipeline(pipeline *models.Pipeline, settings models.LintSettings) ([]Finding, int, error) {
+	resolved, err := files.ResolvePipelineIncludes(pipeline)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	refs := append([]models.ComponentRef(nil), resolved.Components...)
+	sort.SliceStable(refs, func(i, j int) bool {
+		return refs[i].Order < refs[j].Order
+	})
+
+	var docs []Document
+	for _, ref := range refs {
+		componentPath := files.ComponentRefPath(ref)
+		component, err := files.ReadComponent(componentPath)
+		if err != nil {
+			continue
+		}
+		docs = append(docs, Document{
+			Path:       composer.SourceDisplayPath(ref.Path),
+			Content:    component.Content,
+			LineOffset: frontmatterLines(componentPath, component.Content),
+		})
+	}
+
+	return Lint(docs, settings), len(docs), nil
+}
+
+// frontmatterLines counts the lines in a component file before its content
+func frontmatterLines(componentPath, content string) int {
+	raw, err := os.ReadFile(filepath.Join(files.PluqqyDir, componentPath))
+	if err != nil || !strings.HasSuffix(string(raw), content) {
+		return 0
+	}
+	return strings.Count(string(raw[:len(raw)-len(content)]), "\n")
+}
+
+// Lint applies every enabled rule to the documents. Contradictions and
+// duplicates are looked for across all of them.
+func Lint(docs []Document, settings models.LintSettings) []Finding {
+	var findings []Finding
+	var directives []directive
+
+	for _, doc := range docs {
+		lines := scanLines(doc.Content)
+
+		if settings.Enabled(RuleCodeFence) {
+			findings = append(findings, checkCodeFences(doc, lines)...)
+		}
+		if settings.Enabled(RuleVague) {
+			findings = append(findings, checkVaguePhrases(doc, lines, settings.VaguePhrases)...)
+		}
+		if settings.Enabled(RuleLongSection) {
+			findings = append(findings, checkSectionLength(doc, lines, settings.MaxSectionTokens)...)
+		}
+		directives = append(directives, extractDirectives(doc, lines)...)
+	}
+
+	findings = append(findings, checkDirectives(directives, settings)...)
+
+	sort.SliceStable(findings, func(i, j int) bool {
+		return docIndex(docs, findings[i].Component) < docIndex(docs, findings[j].Component) ||
+			(findings[i].Component == findings[j].Component && findings[i].Line < findings[j].Line)
+	})
+	return findings
+}
+
+// docIndex returns the position of the document with the given path
+func docIndex(docs []Document, path string) int {
+	for i, doc := range docs {
+		if doc.Path == path {
+			return i
+		}
+	}
+	return len(docs)
+}
+
+// line is a line of component text along with where it sits relative to code fences
+type line struct {
+	number  int // Line in the content, 1-based
+	text    string
+	fence   bool // The line opens or closes a code fence
+	inFence bool // The line is inside a code block
+}
+
+// fenceOpener matches the start of a fenced code block: three or more backticks or tildes
+var fenceOpener = regexp.MustCompile("^\\s{0,3}(`{3,}|~{3,})")
+
+// scanLines splits content into lines and marks fences. A fence is closed by a
+// run of the same character at least as long as the one that opened it, with
+// nothing after it.
+func scanLines(content string) []line {
+	var lines []line
+	opener := ""
+	for i, text := range strings.Split(content, "\n") {
+		l := line{number: i + 1, text: text}
+		match := fenceOpener.FindStringSubmatch(text)
+		switch {
+		case opener == "" && match != nil:
+			opener = match[1]
+			l.fence = true
+		case opener != "" && match != nil && match[1][0] == opener[0] && len(match[1]) >= len(opener) &&
+			strings.TrimSpace(strings.TrimLeft(strings.TrimSpace(text), match[1][:1])) == "":
+			opener = ""
+			l.fence = true
+		case opener != "":
+			l.inFence = true
+		}
+		lines = append(lines, l)
+	}
+	return lines
+}
+
+// checkCodeFences reports a code fence left open at the end of a component,
+// which swallows everything composed after it into the code block
+func checkCodeFences(doc Document, lines []line) []Finding {
+	open := 0
+	for _, l := range lines {
+		if l.fence {
+			if open == 0 {
+				open = l.number
+			} else {
+				open = 0
+			}
+		}
+	}
+	if open == 0 {
+		return nil
+	}
+	return []Finding{{
+		Rule:      RuleCodeFence,
+		Component: doc.Path,
+		Line:      open + doc.LineOffset,
+		Message:   "code fence is never closed, so everything composed after it becomes code",
+	}}
+}
+
+// checkVaguePhrases reports each use of a phrase from the deny-list outside code
+// blocks and headings
+func checkVaguePhrases(doc Document, lines []line, phrases []string) []Finding {
+	var patterns []*regexp.Regexp
+	for _, phrase := range phrases {
+		if phrase = strings.TrimSpace(phrase); phrase != "" {
+			patterns = append(patterns, phrasePattern(phrase))
+		}
+	}
+
+	var findings []Finding
+	for _, l := range lines {
+		if l.fence || l.inFence || headingPattern.MatchString(l.text) {
+			continue
+		}
+		for _, pattern := range patterns {
+			for _, match := range pattern.FindAllString(l.text, -1) {
+				findings = append(findings, Finding{
+					Rule:      RuleVague,
+					Component: doc.Path,
+					Line:      l.number + doc.LineOffset,
+					Message:   fmt.Sprintf("%q is vague; say exactly what to do", match),
+				})
+			}
+		}
+	}
+	return findings
+}
+
+// phrasePattern matches a phrase case-insensitively as whole words. Word
+// boundaries are only required where the phrase starts or ends with a word
+// character, so that entries such as "etc." still match.
+func phrasePattern(phrase string) *regexp.Regexp {
+	pattern := regexp.QuoteMeta(phrase)
+	if isWordByte(phrase[0]) {
+		pattern = `\b` + pattern
+	}
+	if isWordByte(phrase[len(phrase)-1]) {
+		pattern += `\b`
+	}
+	return regexp.MustCompile(`(?i)` + pattern)
+}
+
+// isWordByte reports whether b is an ASCII letter, digit or underscore
+func isWordByte(b byte) bool {
+	return b == '_' || b >= '0' && b <= '9' || b >= 'a' && b <= 'z' || b >= 'A' && b <= 'Z'
+}
+
+// headingPattern matches a Markdown heading
+var headingPattern = regexp.MustCompile(`^\s{0,3}#{1,6}\s`)
+
+// checkSectionLength reports sections over maxTokens. Headings outside code
+// blocks start a new section; a component without headings is one section.
+func checkSectionLength(doc Document, lines []line, maxTokens int) []Finding {
+	if maxTokens <= 0 {
+		maxTokens = models.DefaultMaxSectionTokens
+	}
+
+	var findings []Finding
+	start := 0
+	flush := func(end int) {
+		section := lines[start:end]
+		var text strings.Builder
+		for _, l := range section {
+			text.WriteString(l.text)
+			text.WriteString("\n")
+		}
+		tokens := composer.EstimateTokens(text.String())
+		if tokens <= maxTokens || len(section) == 0 {
+			return
+		}
+
+		name := "the component"
+		if headingPattern.MatchString(section[0].text) && !section[0].inFence {
+			name = fmt.Sprintf("section %q", strings.TrimSpace(section[0].text))
+		}
+		findings = append(findings, Finding{
+			Rule:      RuleLongSection,
+			Component: doc.Path,
+			Line:      section[0].number + doc.LineOffset,
+			Message:   fmt.Sprintf("%s is about %d tokens, over the %d token limit; split or trim it", name, tokens, maxTokens),
+		})
+	}
+
+	for i, l := range lines {
+		if i > start && !l.inFence && !l.fence && headingPattern.MatchString(l.text) {
+			flush(i)
+			start = i
+		}
+	}
+	flush(len(lines))
+	return findings
+}
@@ -0,0 +1,152 @@
+package lint
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// defaultLintSettings returns the lint settings a new project starts with
+func defaultLintSettings() models.LintSettings {
+	return models.DefaultSettings().Lint
+}
+
+// findingsFor returns the findings of one rule
+func findingsFor(findings []Finding, rule string) []Finding {
+	var matched []Finding
+	for _, finding := range findings {
+		if finding.Rule == rule {
+			matched = append(matched, finding)
+		}
+	}
+	return matched
+}
+
+func TestLint_Directives(t *testing.T) {
+	docs := []Document{
+		{Path: "rules/style.md", Content: "# Style\n\n- Always use tabs.\n- Keep functions small. Write tests first."},
+		{Path: "rules/legacy.md", Content: "Never use tabs!\nYou should keep functions small.\n```\nNever write tests first.\n```"},
+		{Path: "prompts/review.md", Content: "Don’t write tests first; do it now."},
+	}
+
+	findings := Lint(docs, defaultLintSettings())
+
+	contradictions := findingsFor(findings, RuleContradiction)
+	if len(contradictions) != 2 {
+		t.Fatalf("got contradictions %v, want 2", contradictions)
+	}
+	if got := contradictions[0].String(); got != `rules/legacy.md:1: "Never use tabs" contradicts "Always use tabs" from rules/style.md:3` {
+		t.Errorf("first contradiction = %s", got)
+	}
+	// The sentence inside the code block isn't an instruction
+	if contradictions[1].Component != "prompts/review.md" {
+		t.Errorf("second contradiction = %s, want it in prompts/review.md", contradictions[1])
+	}
+
+	duplicates := findingsFor(findings, RuleDuplicate)
+	if len(duplicates) != 1 || duplicates[0].Component != "rules/legacy.md" || duplicates[0].Line != 2 {
+		t.Errorf("got duplicates %v, want one at rules/legacy.md:2", duplicates)
+	}
+}
+
+func TestLint_VagueLongAndFences(t *testing.T) {
+	long := "# Intro\nShort intro.\n## Details\n" + strings.Repeat("word ", 400) + "\n"
+	docs := []Document{
+		{Path: "contexts/api.md", Content: "Handle errors as appropriate, etc.\n```go\n// Try to keep this as needed\n", LineOffset: 3},
+		{Path: "contexts/long.md", Content: long},
+	}
+	settings := defaultLintSettings()
+	settings.MaxSectionTokens = 100
+
+	findings := Lint(docs, settings)
+
+	vague := findingsFor(findings, RuleVague)
+	if len(vague) != 2 || vague[0].Line != 4 {
+		t.Errorf("got vague phrases %v, want 2 on line 4 and none in the code block", vague)
+	}
+	fences := findingsFor(findings, RuleCodeFence)
+	if len(fences) != 1 || fences[0].Line != 5 {
+		t.Errorf("got fence findings %v, want one at line 5", fences)
+	}
+	sections := findingsFor(findings, RuleLongSection)
+	if len(sections) != 1 || sections[0].Line != 3 || !strings.Contains(sections[0].Message, `"## Details"`) {
+		t.Errorf("got long sections %v, want ## Details at line 3", sections)
+	}
+
+	// Disabled rules and a custom deny-list
+	settings.Disabled = []string{RuleCodeFence, "Long-Section"}
+	settings.VaguePhrases = []string{"handle errors", "etc."}
+	findings = Lint(docs, settings)
+	if len(findings) != 2 || findings[0].Rule != RuleVague || !strings.Contains(findings[0].Message, `"Handle errors"`) ||
+		!strings.Contains(findings[1].Message, `"etc."`) {
+		t.Errorf("with rules disabled got %v", findings)
+	}
+}
+
+func TestScanLines_ClosingFences(t *testing.T) {
+	lines := scanLines("````\n```\ninside\n````\n~~~\n```\n~~~~\nafter")
+	var inFence []int
+	for _, l := range lines {
+		if l.inFence {
+			inFence = append(inFence, l.number)
+		}
+	}
+	// A shorter run or a different character doesn't close a fence
+	if want := []int{2, 3, 6}; !equalInts(inFence, want) {
+		t.Errorf("lines inside fences = %v, want %v", inFence, want)
+	}
+	if checkCodeFences(Document{}, lines) != nil {
+		t.Error("balanced fences were reported")
+	}
+}
+
+func equalInts(a, b []int) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+func TestLintPipeline(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatal(err)
+	}
+	files.WriteComponentWithNameAndTags("components/rules/tabs.md", "Always use tabs.", "", []string{"style"})
+	files.WriteComponentWithNameAndTags("components/rules/spaces.md", "Never use tabs.", "", nil)
+	pipeline := &models.Pipeline{
+		Name: "Style",
+		Components: []models.ComponentRef{
+			{Type: "rules", Path: "../components/rules/spaces.md", Order: 2},
+			{Type: "rules", Path: "../components/rules/tabs.md", Order: 1},
+			{Type: "rules", Path: "../components/rules/missing.md", Order: 3},
+		},
+	}
+
+	findings, linted, err := LintPipeline(pipeline, defaultLintSettings())
+	if err != nil {
+		t.Fatalf("LintPipeline() error = %v", err)
+	}
+	if linted != 2 {
+		t.Errorf("linted %d components, want 2", linted)
+	}
+	// Findings follow composition order and count the frontmatter lines
+	raw, _ := os.ReadFile(filepath.Join(files.PluqqyDir, "components/rules/spaces.md"))
+	wantLine := strings.Count(string(raw), "\n") + 1
+	if len(findings) != 1 || findings[0].Component != "rules/spaces.md" || findings[0].Line != wantLine {
+		t.Errorf("got %v, want a contradiction at rules/spaces.md:%d", findings, wantLine)
+	}
+}
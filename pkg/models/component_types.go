@@ -0,0 +1,209 @@
+package models
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// ComponentTypeConfig declares a component type. The built-in types are
+// contexts, prompts and rules; projects can add their own in settings.
+type ComponentTypeConfig struct {
+	Name    string `yaml:"name"`              // Plural type name used in pipelines and queries, e.g. examples
+	Dir     string `yaml:"dir,omitempty"`     // Directory under components/; defaults to the name
+	Heading string `yaml:"heading,omitempty"` // Section heading; defaults to "## NAME"
+	Order   int    `yaml:"order,omitempty"`   // 1-based position among the output sections; 0 appends it last
+}
+
+// DirName returns the directory under components/ that holds the type's files
+func (c ComponentTypeConfig) DirName() string {
+	if c.Dir != "" {
+		return c.Dir
+	}
+	return c.Name
+}
+
+// SectionHeading returns the heading the type is written under
+func (c ComponentTypeConfig) SectionHeading() string {
+	if c.Heading != "" {
+		return c.Heading
+	}
+	return "## " + strings.ToUpper(c.Name)
+}
+
+// builtinComponentTypes are always available, in their default section order
+var builtinComponentTypes = []ComponentTypeConfig{
+	{Name: ComponentTypeRules, Heading: "## RULES", Order: 1},
+	{Name: ComponentTypeContext, Heading: "## CONTEXTS", Order: 2},
+	{Name: ComponentTypePrompt, Heading: "## PROMPTS", Order: 3},
+}
+
+var (
+	customTypesMu sync.RWMutex
+	customTypes   []ComponentTypeConfig
+)
+
+var componentTypeNamePattern = regexp.MustCompile(`^[a-z][a-z0-9_-]*$`)
+
+// SetCustomComponentTypes registers the project's custom component types. The
+// files package registers them again whenever the settings file changes, so the
+// registry reflects the current project.
+func SetCustomComponentTypes(types []ComponentTypeConfig) {
+	customTypesMu.Lock()
+	defer customTypesMu.Unlock()
+	customTypes = append([]ComponentTypeConfig(nil), types...)
+}
+
+// CustomComponentTypes returns the registered custom component types
+func CustomComponentTypes() []ComponentTypeConfig {
+	customTypesMu.RLock()
+	defer customTypesMu.RUnlock()
+	return append([]ComponentTypeConfig(nil), customTypes...)
+}
+
+// AllComponentTypes returns the built-in types followed by the custom types
+func AllComponentTypes() []ComponentTypeConfig {
+	all := append([]ComponentTypeConfig(nil), builtinComponentTypes...)
+	return append(all, CustomComponentTypes()...)
+}
+
+// ComponentTypeNames returns the names of every known component type
+func ComponentTypeNames() []string {
+	var names []string
+	for _, t := range AllComponentTypes() {
+		names = append(names, t.Name)
+	}
+	return names
+}
+
+// LookupComponentType finds a component type by name, accepting the singular
+// form as well (e.g. "example" for "examples")
+func LookupComponentType(name string) (ComponentTypeConfig, bool) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if name == "" {
+		return ComponentTypeConfig{}, false
+	}
+	for _, t := range AllComponentTypes() {
+		if t.Name == name || strings.TrimSuffix(t.Name, "s") == name {
+			return t, true
+		}
+	}
+	return ComponentTypeConfig{}, false
+}
+
+// NormalizeComponentType returns the canonical name for a type, or the input
+// unchanged when the type is unknown
+func NormalizeComponentType(name string) string {
+	if t, ok := LookupComponentType(name); ok {
+		return t.Name
+	}
+	return name
+}
+
+// IsValidComponentType reports whether name is a canonical component type name
+func IsValidComponentType(name string) bool {
+	t, ok := LookupComponentType(name)
+	return ok && t.Name == name
+}
+
+// IsBuiltinComponentType reports whether name is contexts, prompts or rules
+func IsBuiltinComponentType(name string) bool {
+	for _, t := range builtinComponentTypes {
+		if t.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// ComponentTypeForDir returns the type whose files live in components/<dir>
+func ComponentTypeForDir(dir string) (ComponentTypeConfig, bool) {
+	for _, t := range AllComponentTypes() {
+		if t.DirName() == dir {
+			return t, true
+		}
+	}
+	return ComponentTypeConfig{}, false
+}
+
+// ValidateComponentTypeConfigs checks custom types for valid, unique names and directories
+func ValidateComponentTypeConfigs(types []ComponentTypeConfig) error {
+	names := make(map[string]bool)
+	dirs := make(map[string]bool)
+	// Words the CLI already uses next to type names, e.g. pluqqy list components
+	for _, reserved := range []string{"all", "archive", "component", "components", "pipeline", "pipelines"} {
+		names[reserved] = true
+		dirs[reserved] = true
+	}
+	for _, t := range builtinComponentTypes {
+		names[t.Name] = true
+		names[strings.TrimSuffix(t.Name, "s")] = true
+		dirs[t.DirName()] = true
+	}
+
+	for i, t := range types {
+		if !componentTypeNamePattern.MatchString(t.Name) {
+			return fmt.Errorf("component type %d: invalid name '%s', use lowercase letters, digits, - and _", i+1, t.Name)
+		}
+		if names[t.Name] || names[strings.TrimSuffix(t.Name, "s")] {
+			return fmt.Errorf("component type %d: '%s' is already defined or reserved", i+1, t.Name)
+		}
+		dir := t.DirName()
+		if !componentTypeNamePattern.MatchString(dir) {
+			return fmt.Errorf("component type %d: invalid dir '%s', must be a single directory name", i+1, dir)
+		}
+		if dirs[dir] {
+			return fmt.Errorf("component type %d: directory '%s' is already used by another type", i+1, dir)
+		}
+		if t.Order < 0 {
+			return fmt.Errorf("component type %d: order cannot be negative", i+1)
+		}
+		names[t.Name] = true
+		names[strings.TrimSuffix(t.Name, "s")] = true
+		dirs[dir] = true
+	}
+	return nil
+}
+
+// OutputSections returns the configured sections plus a section for every
+// custom component type the configuration doesn't mention, placed by its order
+func (s *Settings) OutputSections() []Section {
+	sections := append([]Section(nil), s.Output.Formatting.Sections...)
+
+	present := make(map[string]bool)
+	for _, section := range sections {
+		present[section.Type] = true
+	}
+
+	var missing []ComponentTypeConfig
+	for _, t := range s.ComponentTypes {
+		if !present[t.Name] {
+			missing = append(missing, t)
+		}
+	}
+	sort.SliceStable(missing, func(i, j int) bool {
+		return positionKey(missing[i].Order) < positionKey(missing[j].Order)
+	})
+
+	for _, t := range missing {
+		section := Section{Type: t.Name, Heading: t.SectionHeading()}
+		pos := len(sections)
+		if t.Order > 0 && t.Order-1 < pos {
+			pos = t.Order - 1
+		}
+		sections = append(sections, Section{})
+		copy(sections[pos+1:], sections[pos:])
+		sections[pos] = section
+	}
+	return sections
+}
+
+// positionKey sorts unordered types (0) after ordered ones
+func positionKey(order int) int {
+	if order == 0 {
+		return int(^uint(0) >> 1)
+	}
+	return order
+}
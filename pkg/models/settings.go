@@ -1,16 +1,89 @@
 package models
 
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
 // Settings represents the application configuration
 type Settings struct {
-	Output OutputSettings `yaml:"output"`
+	Output    OutputSettings    `yaml:"output"`
+	Variables map[string]string `yaml:"variables,omitempty"` // Project-wide values for {{name}} placeholders
+
+	// ComponentTypes declares component types beyond contexts, prompts and rules
+	ComponentTypes []ComponentTypeConfig `yaml:"component_types,omitempty"`
+
+	// Lint configures the prompt linter used by lint and the builder preview
+	Lint LintSettings `yaml:"lint"`
+
+	// VariableOverrides holds values supplied at runtime (e.g. --var flags).
+	// They take precedence over pipeline and project values and are never saved.
+	VariableOverrides map[string]string `yaml:"-"`
+
+	// FormatOverride holds an output format supplied at runtime (e.g. --format).
+	// It takes precedence over the pipeline and project format and is never saved.
+	FormatOverride string `yaml:"-"`
 }
 
 // OutputSettings controls pipeline output behavior
 type OutputSettings struct {
-	DefaultFilename string             `yaml:"default_filename"`
-	ExportPath      string             `yaml:"export_path"`
-	OutputPath      string             `yaml:"output_path"`      // Directory for pipeline-generated output files
-	Formatting      FormattingSettings `yaml:"formatting"`
+	DefaultFilename string                `yaml:"default_filename"`
+	ExportPath      string                `yaml:"export_path"`
+	OutputPath      string                `yaml:"output_path"` // Directory for pipeline-generated output files
+	Format          string                `yaml:"format"`      // Renderer for composed output: markdown, xml, plain or json
+	Mode            string                `yaml:"mode"`        // inline writes component content, reference writes @imports
+	Formatting      FormattingSettings    `yaml:"formatting"`
+	FileReferences  FileReferenceSettings `yaml:"file_references"`
+	StrictVariables bool                  `yaml:"strict_variables"` // Fail composition when {{name}} placeholders have no value
+	Budget          TokenBudgetSettings   `yaml:"budget"`
+	SourceMarkers   bool                  `yaml:"source_markers"`    // Precede each component with a <!-- source: path --> comment
+	Targets         []OutputTarget        `yaml:"targets,omitempty"` // Files written by set instead of the default filename
+	Chat            ChatSettings          `yaml:"chat"`
+}
+
+// OutputTarget is a file written by set, with its own renderer and section layout
+type OutputTarget struct {
+	Path         string    `yaml:"path"`                    // Relative to the project root, e.g. CLAUDE.md or .github/copilot-instructions.md
+	Format       string    `yaml:"format,omitempty"`        // Renderer; empty uses the pipeline or project format
+	Sections     []Section `yaml:"sections,omitempty"`      // Section order and headings; empty uses the project sections
+	ShowHeadings *bool     `yaml:"show_headings,omitempty"` // Nil uses the project setting
+}
+
+// ValidateOutputTargets checks that every target has a path and no two targets share one
+func ValidateOutputTargets(targets []OutputTarget) error {
+	seen := make(map[string]bool)
+	for i, target := range targets {
+		path := strings.TrimSpace(target.Path)
+		if path == "" {
+			return fmt.Errorf("target %d: path cannot be empty", i+1)
+		}
+		key := filepath.Clean(path)
+		if seen[key] {
+			return fmt.Errorf("target %d: duplicate path '%s'", i+1, target.Path)
+		}
+		seen[key] = true
+	}
+	return nil
+}
+
+// Output formats for composed pipelines
+const (
+	OutputFormatMarkdown = "markdown" // Markdown with the configured section headings
+	OutputFormatXML      = "xml"      // Sections wrapped in XML tags such as <rules>...</rules>
+	OutputFormatPlain    = "plain"    // Plain text without markup
+	OutputFormatJSON     = "json"     // Structured JSON listing sections and components
+)
+
+// Output modes for composed pipelines
+const (
+	OutputModeInline    = "inline"    // Write each component's content
+	OutputModeReference = "reference" // Write an @path import for each component
+)
+
+// IsValidOutputMode reports whether mode is a known output mode
+func IsValidOutputMode(mode string) bool {
+	return mode == OutputModeInline || mode == OutputModeReference
 }
 
 // FormattingSettings controls output formatting
@@ -25,7 +98,97 @@ type Section struct {
 	Heading string `yaml:"heading"`
 }
 
+// FileReferenceSettings controls how @file references in components are composed
+type FileReferenceSettings struct {
+	Expand      bool  `yaml:"expand"`        // Inline referenced files instead of copying @path literally
+	MaxFileSize int64 `yaml:"max_file_size"` // Maximum bytes inlined per referenced file
+}
+
+// TokenBudgetSettings sets the default token budget for composed pipelines
+type TokenBudgetSettings struct {
+	MaxTokens int    `yaml:"max_tokens"` // 0 means no budget
+	Strategy  string `yaml:"strategy"`   // What to do when over budget: fail, drop or truncate
+}
+
+// Token budget strategies
+const (
+	BudgetStrategyFail     = "fail"     // Refuse to compose
+	BudgetStrategyDrop     = "drop"     // Drop the lowest-priority components until the output fits
+	BudgetStrategyTruncate = "truncate" // Shorten the lowest-priority components until the output fits
+)
+
+// IsValidBudgetStrategy reports whether strategy is a known budget strategy
+func IsValidBudgetStrategy(strategy string) bool {
+	switch strategy {
+	case BudgetStrategyFail, BudgetStrategyDrop, BudgetStrategyTruncate:
+		return true
+	}
+	return false
+}
+
+// ChatSettings controls export --as chat-json
+type ChatSettings struct {
+	Shape string            `yaml:"shape"`           // Payload shape: anthropic or openai
+	Roles map[string]string `yaml:"roles,omitempty"` // Message role per component type: system, user or assistant
+}
+
+// Chat payload shapes
+const (
+	ChatShapeAnthropic = "anthropic" // Top-level system string plus a messages array
+	ChatShapeOpenAI    = "openai"    // A single messages array that starts with the system message
+)
+
+// Chat message roles
+const (
+	ChatRoleSystem    = "system"
+	ChatRoleUser      = "user"
+	ChatRoleAssistant = "assistant"
+)
+
+// DefaultChatRoles maps component types to message roles when settings don't say otherwise
+var DefaultChatRoles = map[string]string{
+	ComponentTypeRules:   ChatRoleSystem,
+	ComponentTypeContext: ChatRoleSystem,
+	ComponentTypePrompt:  ChatRoleUser,
+}
+
+// LintSettings configures the rules the prompt linter applies to component text
+type LintSettings struct {
+	Disabled         []string `yaml:"disabled,omitempty"` // Rules to skip, e.g. vague or long-section
+	VaguePhrases     []string `yaml:"vague_phrases"`      // Phrases the vague rule flags; empty uses DefaultVaguePhrases
+	MaxSectionTokens int      `yaml:"max_section_tokens"` // Longest section the long-section rule allows
+}
+
+// Enabled reports whether a lint rule is on
+func (l LintSettings) Enabled(rule string) bool {
+	for _, disabled := range l.Disabled {
+		if strings.EqualFold(strings.TrimSpace(disabled), rule) {
+			return false
+		}
+	}
+	return true
+}
+
+// DefaultVaguePhrases are hedges that leave a model to guess what was meant
+var DefaultVaguePhrases = []string{
+	"as appropriate",
+	"as needed",
+	"if necessary",
+	"if possible",
+	"when appropriate",
+	"try to",
+	"be careful",
+	"and so on",
+	"etc",
+	"best practices",
+	"somehow",
+}
+
+// DefaultMaxSectionTokens is the default longest section the linter allows
+const DefaultMaxSectionTokens = 1500
 
+// DefaultMaxReferencedFileSize is the default per-file cap for expanded @file references (100KB)
+const DefaultMaxReferencedFileSize = 100 * 1024
 
 // DefaultSettings returns the default configuration
 func DefaultSettings() *Settings {
@@ -34,6 +197,8 @@ func DefaultSettings() *Settings {
 			DefaultFilename: "PLUQQY.md",
 			ExportPath:      "./",
 			OutputPath:      "tmp/",
+			Format:          OutputFormatMarkdown,
+			Mode:            OutputModeInline,
 			Formatting: FormattingSettings{
 				ShowHeadings: true,
 				Sections: []Section{
@@ -42,6 +207,21 @@ func DefaultSettings() *Settings {
 					{Type: "prompts", Heading: "## PROMPTS"},
 				},
 			},
+			FileReferences: FileReferenceSettings{
+				Expand:      false,
+				MaxFileSize: DefaultMaxReferencedFileSize,
+			},
+			Budget: TokenBudgetSettings{
+				MaxTokens: 0,
+				Strategy:  BudgetStrategyFail,
+			},
+			Chat: ChatSettings{
+				Shape: ChatShapeAnthropic,
+			},
+		},
+		Lint: LintSettings{
+			VaguePhrases:     append([]string(nil), DefaultVaguePhrases...),
+			MaxSectionTokens: DefaultMaxSectionTokens,
 		},
 	}
-}
\ No newline at end of file
+}
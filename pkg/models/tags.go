@@ -11,6 +11,7 @@ var (
 	ErrEmptyTagName        = errors.New("tag name cannot be empty")
 	ErrTagNameTooLong      = errors.New("tag name cannot exceed 50 characters")
 	ErrInvalidTagCharacter = errors.New("tag name contains invalid characters")
+	ErrInvalidTagColor     = errors.New("tag color must be a hex color like #3498db")
 )
 
 // Tag represents a tag with metadata
@@ -61,6 +62,26 @@ func GetTagColor(tagName string, registryColor string) string {
 	return DefaultColorPalette[int(hash)%len(DefaultColorPalette)]
 }
 
+// NewTag creates registry metadata for a tag. Top-level tags get a color derived
+// from their name; hierarchical tags such as project/frontend get their parent
+// filled in and no color of their own, so they are shown in the parent's color.
+func NewTag(name string) Tag {
+	normalized := NormalizeTagName(name)
+	tag := Tag{
+		Name:   normalized,
+		Parent: GetTagParent(normalized),
+	}
+	if tag.Parent == "" {
+		tag.Color = GetTagColor(normalized, "")
+	}
+	return tag
+}
+
+// GetTagRoot returns the top-level part of a hierarchical tag
+func GetTagRoot(tagName string) string {
+	return strings.Split(tagName, "/")[0]
+}
+
 // NormalizeTagName normalizes a tag name for consistency
 func NormalizeTagName(name string) string {
 	// Convert to lowercase and trim spaces
@@ -101,6 +122,21 @@ func ValidateTagName(name string) error {
 	return nil
 }
 
+// ValidateTagColor checks that a color is a #rgb or #rrggbb hex color
+func ValidateTagColor(color string) error {
+	if !strings.HasPrefix(color, "#") || (len(color) != 4 && len(color) != 7) {
+		return ErrInvalidTagColor
+	}
+	
+	for _, r := range color[1:] {
+		if !((r >= '0' && r <= '9') || (r >= 'a' && r <= 'f') || (r >= 'A' && r <= 'F')) {
+			return ErrInvalidTagColor
+		}
+	}
+	
+	return nil
+}
+
 // IsHierarchicalTag checks if a tag has a parent (contains /)
 func IsHierarchicalTag(tagName string) bool {
 	return strings.Contains(tagName, "/")
@@ -119,4 +155,15 @@ func GetTagParent(tagName string) string {
 func GetTagLeaf(tagName string) string {
 	parts := strings.Split(tagName, "/")
 	return parts[len(parts)-1]
-}
\ No newline at end of file
+}
+
+// TagMatchesHierarchy reports whether a tag is ancestor itself or one of its
+// descendants, so project matches project, project/frontend and project/frontend/ui
+func TagMatchesHierarchy(tagName, ancestor string) bool {
+	tagName = NormalizeTagName(tagName)
+	ancestor = strings.Trim(NormalizeTagName(ancestor), "/")
+	if ancestor == "" {
+		return false
+	}
+	return tagName == ancestor || strings.HasPrefix(tagName, ancestor+"/")
+}
@@ -61,6 +61,32 @@ func TestValidateTagName(t *testing.T) {
 	}
 }
 
+func TestValidateTagColor(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		wantErr bool
+	}{
+		{"long hex", "#3498db", false},
+		{"short hex", "#fff", false},
+		{"upper case", "#E74C3C", false},
+		{"missing hash", "3498db", true},
+		{"wrong length", "#3498d", true},
+		{"not hex", "#zzzzzz", true},
+		{"color name", "red", true},
+		{"empty", "", true},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := ValidateTagColor(tt.input)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ValidateTagColor(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			}
+		})
+	}
+}
+
 func TestGetTagColor(t *testing.T) {
 	tests := []struct {
 		name         string
@@ -169,4 +195,30 @@ func TestGetTagLeaf(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+
+func TestTagMatchesHierarchy(t *testing.T) {
+	tests := []struct {
+		tag      string
+		ancestor string
+		want     bool
+	}{
+		{"project", "project", true},
+		{"project/frontend", "project", true},
+		{"project/frontend/ui", "project", true},
+		{"project/frontend/ui", "project/frontend", true},
+		{"Project/Frontend", "project", true},
+		{"projects", "project", false},
+		{"project", "project/frontend", false},
+		{"other/project", "project", false},
+		{"project", "", false},
+	}
+	
+	for _, tt := range tests {
+		t.Run(tt.tag+"_"+tt.ancestor, func(t *testing.T) {
+			if got := TagMatchesHierarchy(tt.tag, tt.ancestor); got != tt.want {
+				t.Errorf("TagMatchesHierarchy(%q, %q) = %v, want %v", tt.tag, tt.ancestor, got, tt.want)
+			}
+		})
+	}
+}
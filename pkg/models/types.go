@@ -2,6 +2,7 @@ package models
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
@@ -19,20 +20,47 @@ type Component struct {
 	Content  string
 	Modified time.Time
 	Tags     []string `yaml:"tags,omitempty"`
+	Created  time.Time // When the component was created; zero if never recorded
+	Archived time.Time // When the component was archived; zero if active or never recorded
 }
 
 type ComponentRef struct {
-	Type  string `yaml:"type"`
-	Path  string `yaml:"path"`
-	Order int    `yaml:"order"`
+	Type     string     `yaml:"type"`
+	Path     string     `yaml:"path"`
+	Order    int        `yaml:"order"`
+	Priority int        `yaml:"priority,omitempty"` // Higher priority components are cut last when over the token budget
+	When     *Condition `yaml:"when,omitempty"`
+}
+
+// Condition decides whether a component is included when a pipeline is composed.
+// Every field that is set must match. A value starting with ! negates the check.
+type Condition struct {
+	Env    string `yaml:"env,omitempty"`    // NAME (set and non-empty) or NAME=pattern
+	Branch string `yaml:"branch,omitempty"` // Glob pattern for the current git branch, e.g. feature/*
+	Exists string `yaml:"exists,omitempty"` // Path relative to the project root
+	Var    string `yaml:"var,omitempty"`    // NAME (has a value) or NAME=pattern
+}
+
+// IsEmpty reports whether the condition has no checks
+func (c *Condition) IsEmpty() bool {
+	return c == nil || (c.Env == "" && c.Branch == "" && c.Exists == "" && c.Var == "")
 }
 
 type Pipeline struct {
-	Name       string         `yaml:"name"`
-	Path       string         `yaml:"-"`
-	Components []ComponentRef `yaml:"components"`
-	OutputPath string         `yaml:"output_path,omitempty"`
-	Tags       []string       `yaml:"tags,omitempty"`
+	Name           string            `yaml:"name"`
+	Path           string            `yaml:"-"`
+	Extends        []string          `yaml:"extends,omitempty"` // Pipelines whose components are included first
+	Components     []ComponentRef    `yaml:"components"`
+	OutputPath     string            `yaml:"output_path,omitempty"`
+	Tags           []string          `yaml:"tags,omitempty"`
+	Variables      map[string]string `yaml:"variables,omitempty"`       // Values for {{name}} placeholders in components
+	MaxTokens      int               `yaml:"max_tokens,omitempty"`      // Token budget; 0 uses the settings default, -1 disables it
+	BudgetStrategy string            `yaml:"budget_strategy,omitempty"` // fail, drop or truncate; empty uses the settings default
+	Format         string            `yaml:"format,omitempty"`          // Output renderer; empty uses the settings default
+	Mode           string            `yaml:"mode,omitempty"`            // inline or reference; empty uses the settings default
+	Targets        []OutputTarget    `yaml:"targets,omitempty"`         // Files written by set; replaces the settings targets
+	Created        time.Time         `yaml:"created,omitempty"`         // Set when the pipeline is first written
+	Archived       time.Time         `yaml:"archived,omitempty"`        // Set when the pipeline is archived, cleared on unarchive
 }
 
 // Validate checks if the pipeline is valid
@@ -41,8 +69,28 @@ func (p *Pipeline) Validate() error {
 		return fmt.Errorf("pipeline name cannot be empty")
 	}
 	
-	if len(p.Components) == 0 {
-		return fmt.Errorf("pipeline must have at least one component")
+	if len(p.Components) == 0 && len(p.Extends) == 0 {
+		return fmt.Errorf("pipeline must have at least one component or extend another pipeline")
+	}
+	
+	if p.BudgetStrategy != "" && !IsValidBudgetStrategy(p.BudgetStrategy) {
+		return fmt.Errorf("invalid budget strategy '%s', must be one of: %s, %s, %s",
+			p.BudgetStrategy, BudgetStrategyFail, BudgetStrategyDrop, BudgetStrategyTruncate)
+	}
+	
+	if p.Mode != "" && !IsValidOutputMode(p.Mode) {
+		return fmt.Errorf("invalid output mode '%s', must be %s or %s", p.Mode, OutputModeInline, OutputModeReference)
+	}
+	
+	if err := ValidateOutputTargets(p.Targets); err != nil {
+		return err
+	}
+	
+	// Validate included pipelines
+	for i, ref := range p.Extends {
+		if strings.TrimSpace(ref) == "" {
+			return fmt.Errorf("extends %d: pipeline reference cannot be empty", i+1)
+		}
 	}
 	
 	// Validate each component reference
@@ -52,12 +100,9 @@ func (p *Pipeline) Validate() error {
 		}
 		
 		// Validate component type
-		switch comp.Type {
-		case ComponentTypeContext, ComponentTypePrompt, ComponentTypeRules:
-			// Valid type
-		default:
-			return fmt.Errorf("component %d: invalid type '%s', must be one of: %s, %s, %s", 
-				i+1, comp.Type, ComponentTypeContext, ComponentTypePrompt, ComponentTypeRules)
+		if !IsValidComponentType(comp.Type) {
+			return fmt.Errorf("component %d: invalid type '%s', must be one of: %s",
+				i+1, comp.Type, strings.Join(ComponentTypeNames(), ", "))
 		}
 		
 		if comp.Path == "" {
@@ -67,6 +112,10 @@ func (p *Pipeline) Validate() error {
 		if comp.Order <= 0 {
 			return fmt.Errorf("component %d: order must be greater than 0", i+1)
 		}
+		
+		if comp.When != nil && comp.When.IsEmpty() {
+			return fmt.Errorf("component %d: when condition must set env, branch, exists or var", i+1)
+		}
 	}
 	
 	// Check for duplicate order values
@@ -186,6 +186,20 @@ func TestGetSearchSuggestions(t *testing.T) {
 	if !found && len(suggestions) > 0 {
 		t.Error("Expected to find 'test' in suggestions")
 	}
+	
+	// Bare words complete to type and status filters
+	for partial, expected := range map[string]string{"pro": "type:prompt", "arch": "status:archived"} {
+		found = false
+		for _, s := range manager.GetSearchSuggestions(partial) {
+			if s == expected {
+				found = true
+				break
+			}
+		}
+		if !found {
+			t.Errorf("Expected %q in suggestions for %q", expected, partial)
+		}
+	}
 }
 
 func TestSearchEngine_ContentSearch(t *testing.T) {
@@ -14,14 +14,16 @@ type ComponentItem struct {
 	TokenCount   int
 	Tags         []string
 	IsArchived   bool
+	NameMatches  []MatchRange // Spans of Name matched by the search that returned the item
 }
 
 // PipelineItem represents a pipeline for searching
 type PipelineItem struct {
-	Name       string
-	Path       string
-	Tags       []string
-	TokenCount int
-	IsArchived bool
-	Modified   time.Time
+	Name        string
+	Path        string
+	Tags        []string
+	TokenCount  int
+	IsArchived  bool
+	Modified    time.Time
+	NameMatches []MatchRange // Spans of Name matched by the search that returned the item
 }
\ No newline at end of file
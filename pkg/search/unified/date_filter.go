@@ -0,0 +1,182 @@
+package unified
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dateFilterTypes are the filters whose values are dates
+var dateFilterTypes = map[string]bool{
+	"modified": true,
+	"created":  true,
+	"archived": true,
+}
+
+// dateRange is the span of time matched by a date filter. From is inclusive and
+// To exclusive; a zero bound leaves that side open.
+type dateRange struct {
+	From time.Time
+	To   time.Time
+}
+
+// contains reports whether t falls within the range. Unrecorded (zero) dates
+// never match.
+func (r dateRange) contains(t time.Time) bool {
+	if t.IsZero() {
+		return false
+	}
+	if !r.From.IsZero() && t.Before(r.From) {
+		return false
+	}
+	if !r.To.IsZero() && !t.Before(r.To) {
+		return false
+	}
+	return true
+}
+
+// ageRegex matches relative ages such as 7d: hours, days, weeks, months or years
+var ageRegex = regexp.MustCompile(`^(\d+)([hdwmy])$`)
+
+// dateOperand is a single date in a filter value. An absolute date covers a span
+// (a whole day, month or year); an age is a point in time.
+type dateOperand struct {
+	start time.Time
+	end   time.Time
+	isAge bool
+}
+
+// parseDateFilter parses the value of a modified:, created: or archived: filter.
+//
+//	today, yesterday        that day
+//	2026-01-15, 2026-01     that day or month, 2026 for the whole year
+//	>2026-01-01, <=2026-03  after or before a date
+//	7d, <7d                 within the last 7 days (h, d, w, m and y are supported)
+//	>90d                    more than 90 days ago
+//	2026-01..2026-03        a range; either end may be left open, as in 2026-01..
+func parseDateFilter(value string, now time.Time) (dateRange, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+
+	if from, to, isRange := strings.Cut(value, ".."); isRange {
+		if from == "" && to == "" {
+			return dateRange{}, dateFilterError(value)
+		}
+		var r dateRange
+		var fromOperand, toOperand dateOperand
+		var err error
+		if from != "" {
+			if fromOperand, err = parseDateOperand(from, now); err != nil {
+				return dateRange{}, err
+			}
+			r.From = fromOperand.start
+		}
+		if to != "" {
+			if toOperand, err = parseDateOperand(to, now); err != nil {
+				return dateRange{}, err
+			}
+			r.To = toOperand.end
+		}
+		// Ages count backwards, so 7d..30d is the same range as 30d..7d
+		if from != "" && to != "" && r.From.After(r.To) {
+			r = dateRange{From: toOperand.start, To: fromOperand.end}
+		}
+		return r, nil
+	}
+
+	op := ""
+	for _, candidate := range []string{">=", "<=", ">", "<"} {
+		if strings.HasPrefix(value, candidate) {
+			op = candidate
+			value = value[len(candidate):]
+			break
+		}
+	}
+
+	operand, err := parseDateOperand(value, now)
+	if err != nil {
+		return dateRange{}, err
+	}
+
+	if operand.isAge {
+		// Comparisons on ages compare how long ago: >90d is older than 90 days
+		switch op {
+		case ">", ">=":
+			return dateRange{To: operand.start}, nil
+		default:
+			return dateRange{From: operand.start}, nil
+		}
+	}
+
+	switch op {
+	case ">":
+		return dateRange{From: operand.end}, nil
+	case ">=":
+		return dateRange{From: operand.start}, nil
+	case "<":
+		return dateRange{To: operand.start}, nil
+	case "<=":
+		return dateRange{To: operand.end}, nil
+	default:
+		return dateRange{From: operand.start, To: operand.end}, nil
+	}
+}
+
+// parseDateOperand parses a keyword, absolute date or age
+func parseDateOperand(value string, now time.Time) (dateOperand, error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+
+	switch value {
+	case "today":
+		return dateOperand{start: today, end: today.AddDate(0, 0, 1)}, nil
+	case "yesterday":
+		return dateOperand{start: today.AddDate(0, 0, -1), end: today}, nil
+	}
+
+	if match := ageRegex.FindStringSubmatch(value); match != nil {
+		n, err := strconv.Atoi(match[1])
+		if err != nil {
+			return dateOperand{}, dateFilterError(value)
+		}
+		var point time.Time
+		switch match[2] {
+		case "h":
+			point = now.Add(-time.Duration(n) * time.Hour)
+		case "d":
+			point = now.AddDate(0, 0, -n)
+		case "w":
+			point = now.AddDate(0, 0, -7*n)
+		case "m":
+			point = now.AddDate(0, -n, 0)
+		case "y":
+			point = now.AddDate(-n, 0, 0)
+		}
+		return dateOperand{start: point, end: point, isAge: true}, nil
+	}
+
+	layouts := []struct {
+		layout string
+		years  int
+		months int
+		days   int
+	}{
+		{"2006-01-02", 0, 0, 1},
+		{"2006-01", 0, 1, 0},
+		{"2006", 1, 0, 0},
+	}
+	for _, l := range layouts {
+		if len(value) != len(l.layout) {
+			continue
+		}
+		if start, err := time.ParseInLocation(l.layout, value, now.Location()); err == nil {
+			return dateOperand{start: start, end: start.AddDate(l.years, l.months, l.days)}, nil
+		}
+	}
+
+	return dateOperand{}, dateFilterError(value)
+}
+
+func dateFilterError(value string) error {
+	return fmt.Errorf("invalid date %q; use YYYY-MM-DD, today, yesterday or an age such as 7d", value)
+}
@@ -0,0 +1,107 @@
+package unified
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseDateFilter(t *testing.T) {
+	now := time.Date(2026, 3, 15, 14, 30, 0, 0, time.UTC)
+	day := func(y int, m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+
+	tests := []struct {
+		value    string
+		expected dateRange
+	}{
+		{"today", dateRange{From: day(2026, 3, 15), To: day(2026, 3, 16)}},
+		{"yesterday", dateRange{From: day(2026, 3, 14), To: day(2026, 3, 15)}},
+		{"2026-01-10", dateRange{From: day(2026, 1, 10), To: day(2026, 1, 11)}},
+		{"2026-01", dateRange{From: day(2026, 1, 1), To: day(2026, 2, 1)}},
+		{"2025", dateRange{From: day(2025, 1, 1), To: day(2026, 1, 1)}},
+		{">2026-01-01", dateRange{From: day(2026, 1, 2)}},
+		{">=2026-01-01", dateRange{From: day(2026, 1, 1)}},
+		{"<2026-01-01", dateRange{To: day(2026, 1, 1)}},
+		{"<=2026-01", dateRange{To: day(2026, 2, 1)}},
+		{"7d", dateRange{From: now.AddDate(0, 0, -7)}},
+		{"<7d", dateRange{From: now.AddDate(0, 0, -7)}},
+		{">90d", dateRange{To: now.AddDate(0, 0, -90)}},
+		{">2w", dateRange{To: now.AddDate(0, 0, -14)}},
+		{"<3m", dateRange{From: now.AddDate(0, -3, 0)}},
+		{">1y", dateRange{To: now.AddDate(-1, 0, 0)}},
+		{"12h", dateRange{From: now.Add(-12 * time.Hour)}},
+		{"2026-01..2026-02", dateRange{From: day(2026, 1, 1), To: day(2026, 3, 1)}},
+		{"2026-02-01..", dateRange{From: day(2026, 2, 1)}},
+		{"..2025", dateRange{To: day(2026, 1, 1)}},
+		{"30d..7d", dateRange{From: now.AddDate(0, 0, -30), To: now.AddDate(0, 0, -7)}},
+		{"7d..30d", dateRange{From: now.AddDate(0, 0, -30), To: now.AddDate(0, 0, -7)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseDateFilter(tt.value, now)
+			if err != nil {
+				t.Fatalf("parseDateFilter(%q) error = %v", tt.value, err)
+			}
+			if !got.From.Equal(tt.expected.From) || !got.To.Equal(tt.expected.To) {
+				t.Errorf("parseDateFilter(%q) = %v..%v, want %v..%v", tt.value, got.From, got.To, tt.expected.From, tt.expected.To)
+			}
+		})
+	}
+
+	for _, value := range []string{"soon", "2026-13-01", "7x", "..", ">", "2026-1-5"} {
+		if _, err := parseDateFilter(value, now); err == nil {
+			t.Errorf("parseDateFilter(%q) expected an error", value)
+		}
+	}
+}
+
+func TestDateRangeContains(t *testing.T) {
+	r := dateRange{
+		From: time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+		To:   time.Date(2026, 2, 1, 0, 0, 0, 0, time.UTC),
+	}
+
+	if !r.contains(r.From) {
+		t.Error("range should include its start")
+	}
+	if r.contains(r.To) {
+		t.Error("range should exclude its end")
+	}
+	if r.contains(time.Time{}) {
+		t.Error("an unrecorded date should never match")
+	}
+	if !(dateRange{}).contains(r.From) {
+		t.Error("an open range should match any recorded date")
+	}
+}
+
+func TestDateFilters_Pipelines(t *testing.T) {
+	setupIndexProject(t)
+
+	// Pipeline paths come as listed, with the pipelines/ folder
+	item := ConvertTUIPipelineItemToShared("Security Review", "pipelines/security-review.yaml", nil, 0, false)
+	if item.Modified.IsZero() {
+		t.Fatal("the pipeline's modification time wasn't read")
+	}
+
+	tests := []struct {
+		query string
+		want  int
+	}{
+		{"modified:today", 1},
+		{"modified:>2020-01-01", 1},
+		{"modified:<2020-01-01", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			helper := NewSearchHelper()
+			_, _, _, pipelines, err := helper.UnifiedFilterAll(tt.query, nil, nil, nil, []PipelineItem{item})
+			if err != nil {
+				t.Fatalf("UnifiedFilterAll(%q) error = %v", tt.query, err)
+			}
+			if len(pipelines) != tt.want {
+				t.Errorf("UnifiedFilterAll(%q) = %d pipelines, want %d", tt.query, len(pipelines), tt.want)
+			}
+		})
+	}
+}
@@ -3,7 +3,9 @@ package unified
 import (
 	"sort"
 	"strings"
+	"sync"
 	"time"
+	"unicode/utf8"
 
 	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 )
@@ -27,9 +29,13 @@ type Searchable interface {
 	GetTags() []string
 	GetContent() string     // Searchable content
 	GetModified() time.Time
+	GetCreated() time.Time    // Zero when no creation date is recorded
+	GetArchivedAt() time.Time // Zero for active items or when no archive date is recorded
 	IsArchived() bool
 	GetTokenCount() int
 	GetUsageCount() int // For components, returns usage count
+	GetComponentPaths() []string // For pipelines, the components they include, e.g. components/contexts/api.md
+	HasBrokenReferences() bool   // For pipelines, whether a component or extended pipeline is missing
 }
 
 // SearchResult represents a ranked search result
@@ -46,6 +52,23 @@ type SearchRelevance struct {
 	TagMatch     bool
 	ExactMatch   bool // Exact name or tag match
 	Highlights   map[string][]string // Field -> highlighted excerpts
+	Ranges       map[string][]MatchRange // Field -> matched spans of the name or content, for underlining
+	Excerpts     []Excerpt // Content lines holding matches, in order
+}
+
+// addRanges records matched spans of a field, keeping them sorted and merged
+func (r *SearchRelevance) addRanges(field string, ranges []MatchRange) {
+	if len(ranges) == 0 {
+		return
+	}
+	if r.Ranges == nil {
+		r.Ranges = make(map[string][]MatchRange)
+	}
+	merged := mergeRanges(append(r.Ranges[field], ranges...))
+	if len(merged) > maxFieldRanges {
+		merged = merged[:maxFieldRanges]
+	}
+	r.Ranges[field] = merged
 }
 
 // SearchOptions configures search behavior
@@ -53,7 +76,7 @@ type SearchOptions struct {
 	Mode           SearchMode
 	MaxResults     int
 	IncludeArchived bool
-	SortBy         string // "relevance", "name", "modified", "usage"
+	SortBy         string // "relevance", "name", "modified", "created", "usage"; a leading "-" reverses the order
 }
 
 // SearchEngine provides a unified search interface for both components and pipelines
@@ -63,6 +86,53 @@ type SearchEngine[T Searchable] struct {
 	
 	// Search options
 	options SearchOptions
+	
+	// Collection statistics for BM25 content ranking, built on first use
+	corpus   *corpusStats
+	corpusMu sync.Mutex
+	
+	// Search index the items' content came from, if any
+	index *SearchIndex
+}
+
+// indexedItem is implemented by items whose content may come from the search index
+type indexedItem interface {
+	indexedDocument() *IndexedDocument // nil when the content was read from the file
+	searchHeader() string              // What GetContent puts before the indexed content
+}
+
+// bodiedItem is implemented by items whose GetContent puts their name and tags
+// before the content itself
+type bodiedItem interface {
+	searchBody() string // The content without the name and tags
+}
+
+// contentBody returns an item's content without what GetContent puts before it,
+// so content: filters and excerpts only see the text of the file
+func contentBody[T Searchable](item T) string {
+	if bi, ok := any(item).(bodiedItem); ok {
+		return bi.searchBody()
+	}
+	return item.GetContent()
+}
+
+// bodyRanges moves spans matched in content, which ends with body, onto body,
+// dropping those that fall in the name and tags before it
+func bodyRanges(content, body string, ranges []MatchRange) []MatchRange {
+	offset := len(content) - len(body)
+	var moved []MatchRange
+	for _, r := range ranges {
+		if r.Start >= offset {
+			moved = append(moved, MatchRange{Start: r.Start - offset, End: r.End - offset})
+		}
+	}
+	return moved
+}
+
+// corpusStats holds the collection statistics BM25 needs
+type corpusStats struct {
+	avgDocLen float64
+	docFreq   map[string]int // Term -> number of items whose content contains it
 }
 
 // NewSearchEngine creates a new search engine for a specific type
@@ -79,7 +149,19 @@ func NewSearchEngine[T Searchable]() *SearchEngine[T] {
 
 // SetItems updates the items to search through
 func (se *SearchEngine[T]) SetItems(items []T) {
+	se.corpusMu.Lock()
+	defer se.corpusMu.Unlock()
 	se.items = items
+	se.corpus = nil
+}
+
+// SetIndex sets the search index the items' content came from, whose postings
+// then stand in for scanning every item's content
+func (se *SearchEngine[T]) SetIndex(index *SearchIndex) {
+	se.corpusMu.Lock()
+	defer se.corpusMu.Unlock()
+	se.index = index
+	se.corpus = nil
 }
 
 // SetOptions updates search configuration
@@ -87,20 +169,33 @@ func (se *SearchEngine[T]) SetOptions(options SearchOptions) {
 	se.options = options
 }
 
-// Search performs a search with the given query
+// Search performs a search with the given query. Queries use the boolean syntax
+// described at ParseQueryExpression; a malformed query returns a *QuerySyntaxError.
 func (se *SearchEngine[T]) Search(query string) ([]SearchResult[T], error) {
-	if query == "" {
+	expr, err := ParseQueryExpression(query)
+	if err != nil {
+		return nil, err
+	}
+	
+	// A sort: term orders the results of this search only
+	expr, sortBy := splitQuerySort(expr)
+	if sortBy != "" {
+		originalSort := se.options.SortBy
+		se.options.SortBy = sortBy
+		defer func() { se.options.SortBy = originalSort }()
+	}
+	
+	if expr == nil {
 		// No query, return all items (filtered by archived status)
 		return se.getAllItems(), nil
 	}
 	
-	// Try to use the search engine first for structured queries
-	if se.isStructuredQuery(query) {
-		return se.searchWithEngine(query)
+	// Plain words keep the simple text search ranking
+	if text, ok := expr.(*TextNode); ok && !text.Phrase {
+		return se.simpleTextSearch(strings.Join(text.Terms, " ")), nil
 	}
 	
-	// Fall back to simple text search
-	return se.simpleTextSearch(query), nil
+	return se.searchWithExpression(expr), nil
 }
 
 // SearchByMode performs a search in a specific mode
@@ -168,10 +263,8 @@ func (se *SearchEngine[T]) GetFilteredItems(itemTypes []string, includeArchived
 // isStructuredQuery checks if the query contains structured search syntax
 func (se *SearchEngine[T]) isStructuredQuery(query string) bool {
 	// Check for field-based searches (tag:, type:, status:, etc.)
-	structuredPatterns := []string{"tag:", "type:", "status:", "name:", "content:", "modified:"}
-	
 	lowerQuery := strings.ToLower(query)
-	for _, pattern := range structuredPatterns {
+	for _, pattern := range filterPrefixes {
 		if strings.Contains(lowerQuery, pattern) {
 			return true
 		}
@@ -180,248 +273,6 @@ func (se *SearchEngine[T]) isStructuredQuery(query string) bool {
 	return false
 }
 
-// searchWithEngine uses the search engine for structured queries
-func (se *SearchEngine[T]) searchWithEngine(query string) ([]SearchResult[T], error) {
-	// Parse the query to extract all filters
-	parsedQuery := ParseQuery(query)
-	
-	// If no filters and no free text, return all items
-	if len(parsedQuery.Filters) == 0 && parsedQuery.FreeText == "" {
-		return se.getAllItems(), nil
-	}
-	
-	// If only free text (no filters), do simple text search
-	if len(parsedQuery.Filters) == 0 && parsedQuery.FreeText != "" {
-		return se.simpleTextSearch(parsedQuery.FreeText), nil
-	}
-	
-	// Use the new multi-filter search for queries with filters
-	if len(parsedQuery.Filters) > 0 {
-		return se.searchWithMultipleFilters(parsedQuery), nil
-	}
-	
-	// Fallback to handle legacy single-filter queries
-	// This section can be removed once we're confident the new parser handles everything
-	lowerQuery := strings.ToLower(query)
-	var results []SearchResult[T]
-	
-	// Handle tag: queries
-	if strings.HasPrefix(lowerQuery, "tag:") {
-		tagQuery := strings.TrimPrefix(lowerQuery, "tag:")
-		tagQuery = strings.TrimSpace(tagQuery)
-		
-		for _, item := range se.items {
-			// Skip archived items if not included
-			if !se.options.IncludeArchived && item.IsArchived() {
-				continue
-			}
-			
-			// Check if item has the requested tag
-			tags := item.GetTags()
-			hasTag := false
-			for _, tag := range tags {
-				if strings.EqualFold(tag, tagQuery) {
-					hasTag = true
-					break
-				}
-			}
-			
-			if hasTag {
-				results = append(results, SearchResult[T]{
-					Item:  item,
-					Score: 10.0, // High score for exact tag match
-					Relevance: SearchRelevance{
-						TagMatch:   true,
-						ExactMatch: true,
-						Highlights: map[string][]string{
-							"tags": {tagQuery},
-						},
-					},
-				})
-			}
-		}
-		
-		// Sort by score and name
-		se.sortResults(results)
-		return results, nil
-	}
-	
-	// Handle status: queries
-	if strings.HasPrefix(lowerQuery, "status:") {
-		statusQuery := strings.TrimPrefix(lowerQuery, "status:")
-		statusQuery = strings.TrimSpace(statusQuery)
-		
-		for _, item := range se.items {
-			isArchived := item.IsArchived()
-			
-			// Match based on status
-			if (statusQuery == "archived" && isArchived) ||
-			   (statusQuery == "active" && !isArchived) {
-				results = append(results, SearchResult[T]{
-					Item:  item,
-					Score: 10.0,
-					Relevance: SearchRelevance{
-						ExactMatch: true,
-						Highlights: make(map[string][]string),
-					},
-				})
-			}
-		}
-		
-		// Sort by score and name
-		se.sortResults(results)
-		return results, nil
-	}
-	
-	// Handle type: queries
-	if strings.HasPrefix(lowerQuery, "type:") {
-		typeQuery := strings.TrimPrefix(lowerQuery, "type:")
-		typeQuery = strings.TrimSpace(typeQuery)
-		
-		for _, item := range se.items {
-			// Skip archived items if not included
-			if !se.options.IncludeArchived && item.IsArchived() {
-				continue
-			}
-			
-			itemType := strings.ToLower(item.GetType())
-			itemSubType := strings.ToLower(item.GetSubType())
-			
-			// Match exact type or handle plural/singular forms
-			// Check exact match
-			if itemType == typeQuery || itemSubType == typeQuery {
-				results = append(results, SearchResult[T]{
-					Item:  item,
-					Score: 10.0,
-					Relevance: SearchRelevance{
-						ExactMatch: true,
-						Highlights: make(map[string][]string),
-					},
-				})
-				continue
-			}
-			
-			// Check plural/singular variations
-			// If query is plural, check if item is singular
-			if itemType+"s" == typeQuery || itemSubType+"s" == typeQuery {
-				results = append(results, SearchResult[T]{
-					Item:  item,
-					Score: 10.0,
-					Relevance: SearchRelevance{
-						ExactMatch: true,
-						Highlights: make(map[string][]string),
-					},
-				})
-				continue
-			}
-			
-			// If query is singular, check if item is plural
-			if strings.TrimSuffix(itemType, "s") == typeQuery || strings.TrimSuffix(itemSubType, "s") == typeQuery {
-				results = append(results, SearchResult[T]{
-					Item:  item,
-					Score: 10.0,
-					Relevance: SearchRelevance{
-						ExactMatch: true,
-						Highlights: make(map[string][]string),
-					},
-				})
-			}
-		}
-		
-		// Sort by score and name
-		se.sortResults(results)
-		return results, nil
-	}
-	
-	// Handle content: queries
-	if strings.HasPrefix(lowerQuery, "content:") {
-		contentQuery := strings.TrimPrefix(lowerQuery, "content:")
-		contentQuery = strings.TrimSpace(contentQuery)
-		// Remove quotes if present
-		contentQuery = strings.Trim(contentQuery, `"'`)
-		
-		for _, item := range se.items {
-			// Skip archived items if not included
-			if !se.options.IncludeArchived && item.IsArchived() {
-				continue
-			}
-			
-			// Check if item content contains the search term
-			content := strings.ToLower(item.GetContent())
-			if strings.Contains(content, strings.ToLower(contentQuery)) {
-				// Calculate score based on number of matches
-				score := float64(strings.Count(content, strings.ToLower(contentQuery)))
-				
-				// Extract excerpts around matches
-				excerpts := extractExcerpts(item.GetContent(), contentQuery, 3, 100)
-				
-				results = append(results, SearchResult[T]{
-					Item:  item,
-					Score: score,
-					Relevance: SearchRelevance{
-						ContentMatch: true,
-						ExactMatch:   false,
-						Highlights: map[string][]string{
-							"content": excerpts,
-						},
-					},
-				})
-			}
-		}
-		
-		// Sort by score and name
-		se.sortResults(results)
-		return results, nil
-	}
-	
-	// Handle name: queries
-	if strings.HasPrefix(lowerQuery, "name:") {
-		nameQuery := strings.TrimPrefix(lowerQuery, "name:")
-		nameQuery = strings.TrimSpace(nameQuery)
-		// Remove quotes if present
-		nameQuery = strings.Trim(nameQuery, `"'`)
-		
-		for _, item := range se.items {
-			// Skip archived items if not included
-			if !se.options.IncludeArchived && item.IsArchived() {
-				continue
-			}
-			
-			// Check if item name contains the search term
-			name := strings.ToLower(item.GetName())
-			if strings.Contains(name, strings.ToLower(nameQuery)) {
-				// Higher score for exact match
-				score := 5.0
-				exactMatch := false
-				if name == strings.ToLower(nameQuery) {
-					score = 10.0
-					exactMatch = true
-				}
-				
-				results = append(results, SearchResult[T]{
-					Item:  item,
-					Score: score,
-					Relevance: SearchRelevance{
-						NameMatch:  true,
-						ExactMatch: exactMatch,
-						Highlights: map[string][]string{
-							"name": {item.GetName()},
-						},
-					},
-				})
-			}
-		}
-		
-		// Sort by score and name
-		se.sortResults(results)
-		return results, nil
-	}
-	
-	// Fallback to simple search for unrecognized structured queries
-	return se.simpleTextSearch(query), nil
-}
-
-
 // simpleTextSearch performs a simple text-based search
 func (se *SearchEngine[T]) simpleTextSearch(query string) []SearchResult[T] {
 	query = strings.ToLower(strings.TrimSpace(query))
@@ -438,7 +289,7 @@ func (se *SearchEngine[T]) simpleTextSearch(query string) []SearchResult[T] {
 			continue
 		}
 		
-		score, relevance := se.calculateSimpleScore(item, queryTerms)
+		score, relevance := se.calculateSimpleScore(item, queryTerms, false)
 		if score > 0 {
 			results = append(results, SearchResult[T]{
 				Item:      item,
@@ -456,13 +307,16 @@ func (se *SearchEngine[T]) simpleTextSearch(query string) []SearchResult[T] {
 		results = results[:se.options.MaxResults]
 	}
 	
+	addExcerpts(results)
 	return results
 }
 
-// calculateSimpleScore calculates relevance score for simple text search
-func (se *SearchEngine[T]) calculateSimpleScore(item T, queryTerms []string) (float64, SearchRelevance) {
+// calculateSimpleScore calculates relevance score for simple text search. Names
+// and tags match exactly, by prefix or substring, by subsequence (apidc finds
+// api-docs) or within a couple of typos; content is ranked with BM25. A phrase
+// is a single term that must match as written, on word boundaries.
+func (se *SearchEngine[T]) calculateSimpleScore(item T, queryTerms []string, phrase bool) (float64, SearchRelevance) {
 	name := strings.ToLower(item.GetName())
-	content := strings.ToLower(item.GetContent())
 	tags := make([]string, len(item.GetTags()))
 	for i, tag := range item.GetTags() {
 		tags[i] = strings.ToLower(models.NormalizeTagName(tag))
@@ -472,6 +326,11 @@ func (se *SearchEngine[T]) calculateSimpleScore(item T, queryTerms []string) (fl
 	relevance := SearchRelevance{
 		Highlights: make(map[string][]string),
 	}
+	docLen := -1
+	find := findAll
+	if phrase {
+		find = findPhrase
+	}
 	
 	for _, term := range queryTerms {
 		term = strings.TrimSpace(term)
@@ -479,22 +338,16 @@ func (se *SearchEngine[T]) calculateSimpleScore(item T, queryTerms []string) (fl
 			continue
 		}
 		term = strings.ToLower(term) // Ensure term is lowercase for comparison
+		fuzzy := !phrase && !strings.ContainsAny(term, " \t")
 		
 		// Check name matches
 		if se.shouldSearchField("name") {
-			if name == term {
-				score += 10.0 // Exact name match
-				relevance.ExactMatch = true
-				relevance.NameMatch = true
-				relevance.Highlights["name"] = []string{item.GetName()}
-			} else if strings.HasPrefix(name, term) {
-				score += 5.0 // Name prefix match
-				relevance.NameMatch = true
-				relevance.Highlights["name"] = []string{item.GetName()}
-			} else if strings.Contains(name, term) {
-				score += 3.0 // Name contains match
+			if nameScore, ranges := scoreName(item.GetName(), name, term, find, fuzzy); nameScore > 0 {
+				score += nameScore
 				relevance.NameMatch = true
+				relevance.ExactMatch = relevance.ExactMatch || name == term
 				relevance.Highlights["name"] = []string{item.GetName()}
+				relevance.addRanges("name", ranges)
 			}
 		}
 		
@@ -505,23 +358,45 @@ func (se *SearchEngine[T]) calculateSimpleScore(item T, queryTerms []string) (fl
 					score += 8.0 // Exact tag match
 					relevance.ExactMatch = true
 					relevance.TagMatch = true
-				} else if strings.HasPrefix(tag, term) {
+				} else if occurrences := find(tag, term); len(occurrences) > 0 && occurrences[0].Start == 0 {
 					score += 4.0 // Tag prefix match
 					relevance.TagMatch = true
-				} else if strings.Contains(tag, term) {
+				} else if len(occurrences) > 0 {
 					score += 2.0 // Tag contains match
 					relevance.TagMatch = true
+				} else if _, _, ok := typoMatch(tag, term); fuzzy && ok {
+					score += 1.0 // Tag within a typo or two
+					relevance.TagMatch = true
 				}
 			}
 		}
 		
 		// Check content matches
-		if se.shouldSearchField("content") && strings.Contains(content, term) {
-			score += 1.0 // Content match
-			relevance.ContentMatch = true
-			// Extract excerpt around the match
-			if excerpts := extractExcerpts(item.GetContent(), term, 1, 50); len(excerpts) > 0 {
-				relevance.Highlights["content"] = excerpts
+		if se.shouldSearchField("content") {
+			content := item.GetContent()
+			if occurrences := find(content, term); len(occurrences) > 0 {
+				if docLen < 0 {
+					docLen = len(wordSpans(content))
+				}
+				// Occurrences at the start of a word count fully, those inside
+				// a word (log in catalog) count half
+				tf := 0.0
+				for _, occurrence := range occurrences {
+					if isWordStart(content, occurrence.Start) {
+						tf += 1.0
+					} else {
+						tf += 0.5
+					}
+				}
+				score += se.contentScore(term, tf, docLen)
+				relevance.ContentMatch = true
+				relevance.addRanges("content", bodyRanges(content, contentBody(item), occurrences))
+			} else if fuzzy && se.mayHaveTypo(item, term) {
+				if _, ranges, ok := typoMatch(content, term); ok {
+					score += 0.5 // Content within a typo or two
+					relevance.ContentMatch = true
+					relevance.addRanges("content", bodyRanges(content, contentBody(item), ranges))
+				}
 			}
 		}
 	}
@@ -545,6 +420,121 @@ func (se *SearchEngine[T]) calculateSimpleScore(item T, queryTerms []string) (fl
 	return score, relevance
 }
 
+// scoreName scores a lowercased term against a name, returning the matched ranges.
+// Exact, prefix and substring matches, found with find, rank above fuzzy ones.
+func scoreName(original, name, term string, find func(text, term string) []MatchRange, fuzzy bool) (float64, []MatchRange) {
+	if name == term {
+		return 10.0, []MatchRange{{Start: 0, End: len(original)}} // Exact name match
+	}
+	if occurrences := find(original, term); len(occurrences) > 0 {
+		if occurrences[0].Start == 0 {
+			return 5.0, occurrences // Name prefix match
+		}
+		for _, occurrence := range occurrences {
+			if isWordStart(original, occurrence.Start) {
+				return 4.0, occurrences // Name contains match at a word start, e.g. docs in api-docs
+			}
+		}
+		return 3.0, occurrences // Name contains match
+	}
+	if !fuzzy {
+		return 0, nil
+	}
+	
+	best := 0.0
+	var bestRanges []MatchRange
+	if len(term) >= 3 {
+		if quality, ranges, ok := subsequenceMatch(original, term); ok {
+			best, bestRanges = 2.5*quality, ranges // Characters in order, like fzf
+		}
+	}
+	if distance, ranges, ok := typoMatch(original, term); ok {
+		if typoScore := 2.0 - 0.5*float64(distance); typoScore > best {
+			best, bestRanges = typoScore, ranges // A word in the name within a typo or two
+		}
+	}
+	return best, bestRanges
+}
+
+// contentScore ranks a content match with BM25, so rare terms and terms repeated
+// in short documents score higher. The result is squashed into 1 to 3 so
+// content alone never outranks a name match.
+func (se *SearchEngine[T]) contentScore(term string, tf float64, docLen int) float64 {
+	docs, df, avgDocLen := se.termStats(term)
+	weight := bm25(tf, df, docs, docLen, avgDocLen)
+	return 1.0 + 2.0*weight/(weight+1.0)
+}
+
+// termStats returns the number of items, how many of them contain term and the
+// average content length in words, building the statistics on first use
+func (se *SearchEngine[T]) termStats(term string) (int, int, float64) {
+	se.corpusMu.Lock()
+	defer se.corpusMu.Unlock()
+	
+	if se.corpus == nil {
+		total := 0
+		for _, item := range se.items {
+			total += se.wordCount(item)
+		}
+		se.corpus = &corpusStats{docFreq: make(map[string]int)}
+		if len(se.items) > 0 {
+			se.corpus.avgDocLen = float64(total) / float64(len(se.items))
+		}
+	}
+	
+	df, ok := se.corpus.docFreq[term]
+	if !ok {
+		for _, item := range se.items {
+			if se.containsTerm(item, term) {
+				df++
+			}
+		}
+		se.corpus.docFreq[term] = df
+	}
+	return len(se.items), df, se.corpus.avgDocLen
+}
+
+// indexed returns an item's document in the search index and the part of its
+// content that isn't in the document, or nil when the item wasn't indexed
+func (se *SearchEngine[T]) indexed(item T) (*IndexedDocument, string) {
+	if ii, ok := any(item).(indexedItem); ok && se.index != nil {
+		if doc := ii.indexedDocument(); doc != nil {
+			return doc, ii.searchHeader()
+		}
+	}
+	return nil, ""
+}
+
+// wordCount returns how many words an item's content has
+func (se *SearchEngine[T]) wordCount(item T) int {
+	if doc, header := se.indexed(item); doc != nil {
+		return len(wordSpans(header)) + doc.Words
+	}
+	return len(wordSpans(item.GetContent()))
+}
+
+// containsTerm reports whether an item's content contains a lowercased term. For
+// indexed items a term of letters and digits is looked up in the index's words.
+func (se *SearchEngine[T]) containsTerm(item T, term string) bool {
+	if doc, header := se.indexed(item); doc != nil && isIndexTerm(term) {
+		return strings.Contains(foldCase(header), term) || se.index.documentsContaining(term)[doc.ID]
+	}
+	return strings.Contains(foldCase(item.GetContent()), term)
+}
+
+// mayHaveTypo reports whether an item's content may have a word within a typo or
+// two of a lowercased term, so items the index rules out aren't scanned word by word
+func (se *SearchEngine[T]) mayHaveTypo(item T, term string) bool {
+	if doc, header := se.indexed(item); doc != nil && isIndexTerm(term) {
+		if se.index.documentsWithTypo(term)[doc.ID] {
+			return true
+		}
+		_, _, ok := typoMatch(header, term)
+		return ok
+	}
+	return true
+}
+
 // shouldSearchField checks if a field should be searched based on current mode
 func (se *SearchEngine[T]) shouldSearchField(field string) bool {
 	switch se.options.Mode {
@@ -585,7 +575,16 @@ func (se *SearchEngine[T]) getAllItems() []SearchResult[T] {
 
 // sortResults sorts search results based on current sort option
 func (se *SearchEngine[T]) sortResults(results []SearchResult[T]) {
-	switch se.options.SortBy {
+	sortBy, reverse := strings.CutPrefix(se.options.SortBy, "-")
+	if reverse {
+		defer func() {
+			for i, j := 0, len(results)-1; i < j; i, j = i+1, j-1 {
+				results[i], results[j] = results[j], results[i]
+			}
+		}()
+	}
+	
+	switch sortBy {
 	case "name":
 		sort.Slice(results, func(i, j int) bool {
 			return results[i].Item.GetName() < results[j].Item.GetName()
@@ -594,6 +593,10 @@ func (se *SearchEngine[T]) sortResults(results []SearchResult[T]) {
 		sort.Slice(results, func(i, j int) bool {
 			return results[i].Item.GetModified().After(results[j].Item.GetModified())
 		})
+	case "created":
+		sort.Slice(results, func(i, j int) bool {
+			return results[i].Item.GetCreated().After(results[j].Item.GetCreated())
+		})
 	case "usage":
 		sort.Slice(results, func(i, j int) bool {
 			return results[i].Item.GetUsageCount() > results[j].Item.GetUsageCount()
@@ -624,40 +627,95 @@ func (se *SearchEngine[T]) hasTagMatch(highlights map[string][]string) bool {
 	return exists
 }
 
-// extractExcerpts extracts highlighted excerpts from content
-func extractExcerpts(content, searchTerm string, maxExcerpts, contextChars int) []string {
-	var excerpts []string
-	lowerContent := strings.ToLower(content)
-	lowerTerm := strings.ToLower(searchTerm)
-	
-	index := 0
-	for i := 0; i < maxExcerpts; i++ {
-		pos := strings.Index(lowerContent[index:], lowerTerm)
-		if pos == -1 {
-			break
+// Excerpt is a line of content with one or more matches on it
+type Excerpt struct {
+	Line   int          // 1-based line number within the content
+	Text   string       // The line, shortened around the matches when it's long
+	Ranges []MatchRange // Matched spans within Text
+}
+
+// excerptContextChars is how much of a line is kept either side of its matches
+const excerptContextChars = 60
+
+// addExcerpts fills in the content excerpts of search results from their matched
+// content ranges
+func addExcerpts[T Searchable](results []SearchResult[T]) {
+	for i := range results {
+		ranges := results[i].Relevance.Ranges["content"]
+		if len(ranges) == 0 {
+			continue
 		}
-		
-		pos += index
-		start := pos - contextChars
-		if start < 0 {
-			start = 0
+		excerpts := extractExcerpts(contentBody(results[i].Item), ranges, excerptContextChars)
+		results[i].Relevance.Excerpts = excerpts
+		texts := make([]string, len(excerpts))
+		for j, excerpt := range excerpts {
+			texts[j] = excerpt.Text
+		}
+		results[i].Relevance.Highlights["content"] = texts
+	}
+}
+
+// extractExcerpts returns an excerpt for every line of content holding one of the
+// matches, in order. Lines longer than the matches plus contextChars either side
+// are cut down, with "..." marking what was left out.
+func extractExcerpts(content string, matches []MatchRange, contextChars int) []Excerpt {
+	var excerpts []Excerpt
+	matches = mergeRanges(matches)
+	
+	line, lineStart := 1, 0
+	for i := 0; i < len(matches); {
+		// Move to the line holding the next match
+		for {
+			next := strings.IndexByte(content[lineStart:], '\n')
+			if next < 0 || lineStart+next >= matches[i].Start {
+				break
+			}
+			lineStart += next + 1
+			line++
 		}
+		newline := len(content)
+		if next := strings.IndexByte(content[lineStart:], '\n'); next >= 0 {
+			newline = lineStart + next
+		}
+		lineEnd := lineStart + len(strings.TrimRight(content[lineStart:newline], "\r"))
 		
-		end := pos + len(searchTerm) + contextChars
-		if end > len(content) {
-			end = len(content)
+		// Gather the matches on this line; one running onto the next line is cut
+		// at the end of this one
+		var onLine []MatchRange
+		for ; i < len(matches) && matches[i].Start <= newline; i++ {
+			if end := min(matches[i].End, lineEnd); end > matches[i].Start {
+				onLine = append(onLine, MatchRange{Start: matches[i].Start, End: end})
+			}
+		}
+		if len(onLine) == 0 {
+			continue
 		}
 		
-		excerpt := content[start:end]
-		if start > 0 {
-			excerpt = "..." + excerpt
+		start := max(lineStart, onLine[0].Start-contextChars)
+		end := min(lineEnd, onLine[len(onLine)-1].End+contextChars)
+		for start > lineStart && !utf8.RuneStart(content[start]) {
+			start--
 		}
-		if end < len(content) {
-			excerpt = excerpt + "..."
+		for end < lineEnd && !utf8.RuneStart(content[end]) {
+			end++
 		}
 		
-		excerpts = append(excerpts, excerpt)
-		index = pos + len(searchTerm)
+		prefix, suffix := "", ""
+		if start > lineStart {
+			prefix = "..."
+		}
+		if end < lineEnd {
+			suffix = "..."
+		}
+		ranges := make([]MatchRange, len(onLine))
+		for j, r := range onLine {
+			ranges[j] = MatchRange{Start: r.Start - start + len(prefix), End: r.End - start + len(prefix)}
+		}
+		excerpts = append(excerpts, Excerpt{
+			Line:   line,
+			Text:   prefix + content[start:end] + suffix,
+			Ranges: ranges,
+		})
 	}
 	
 	return excerpts
@@ -1,37 +1,34 @@
 package unified
 
 import (
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
 	"strings"
+	"time"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 )
 
-// searchWithMultipleFilters handles queries with multiple filters using AND logic
-func (se *SearchEngine[T]) searchWithMultipleFilters(parsedQuery *ParsedQuery) []SearchResult[T] {
+// searchWithExpression returns the items matching a boolean query expression
+func (se *SearchEngine[T]) searchWithExpression(expr QueryNode) []SearchResult[T] {
 	var results []SearchResult[T]
 	
-	// Process each item and check if it matches ALL filters
+	// Archived items are only searched when asked for, either through the options
+	// or by a status:archived or archived: date filter that isn't negated
+	includeArchived := se.options.IncludeArchived || queriesArchived(expr)
+	
 	for _, item := range se.items {
-		// Skip archived items if not included (unless status:archived is specified)
-		if !se.shouldIncludeItem(item, parsedQuery) {
+		if !includeArchived && item.IsArchived() {
 			continue
 		}
 		
-		// Check if item matches ALL filters
-		matches, score, relevance := se.checkAllFilters(item, parsedQuery)
+		matches, score, relevance := se.evaluateNode(item, expr)
 		if !matches {
 			continue
 		}
 		
-		// If there's free text, also check that
-		if parsedQuery.FreeText != "" {
-			textScore, textRelevance := se.calculateSimpleScore(item, strings.Fields(parsedQuery.FreeText))
-			if textScore == 0 {
-				continue // Doesn't match free text
-			}
-			// Combine scores and relevance
-			score += textScore
-			relevance = se.combineRelevance(relevance, textRelevance)
-		}
-		
 		results = append(results, SearchResult[T]{
 			Item:      item,
 			Score:     score,
@@ -47,47 +44,77 @@ func (se *SearchEngine[T]) searchWithMultipleFilters(parsedQuery *ParsedQuery) [
 		results = results[:se.options.MaxResults]
 	}
 	
+	addExcerpts(results)
 	return results
 }
 
-// shouldIncludeItem checks if an item should be included based on archived status
-func (se *SearchEngine[T]) shouldIncludeItem(item T, parsedQuery *ParsedQuery) bool {
-	// Check if there's a status filter
-	if statusFilter, hasStatus := parsedQuery.GetFilter("status"); hasStatus {
-		isArchived := item.IsArchived()
-		if statusFilter.Value == "archived" {
-			return isArchived
-		} else if statusFilter.Value == "active" {
-			return !isArchived
+// queriesArchived reports whether an expression asks for archived items
+func queriesArchived(expr QueryNode) bool {
+	found := false
+	walkQuery(expr, false, func(node QueryNode, negated bool) {
+		filter, ok := node.(*FilterNode)
+		if !ok || negated {
+			return
 		}
-	}
-	
-	// Default behavior - exclude archived unless explicitly included
-	if !se.options.IncludeArchived && item.IsArchived() {
-		return false
-	}
-	
-	return true
+		if (filter.Filter.Type == "status" && strings.EqualFold(filter.Filter.Value, "archived")) ||
+			filter.Filter.Type == "archived" {
+			found = true
+		}
+	})
+	return found
 }
 
-// checkAllFilters checks if an item matches ALL filters in the query
-func (se *SearchEngine[T]) checkAllFilters(item T, parsedQuery *ParsedQuery) (bool, float64, SearchRelevance) {
-	totalScore := 0.0
+// evaluateNode checks an item against an expression node, returning whether it
+// matches along with the score and relevance of the matching terms
+func (se *SearchEngine[T]) evaluateNode(item T, node QueryNode) (bool, float64, SearchRelevance) {
 	relevance := SearchRelevance{
 		Highlights: make(map[string][]string),
 	}
 	
-	for _, filter := range parsedQuery.Filters {
-		matches, filterScore, filterRelevance := se.checkSingleFilter(item, filter)
-		if !matches {
-			return false, 0, SearchRelevance{} // Item doesn't match this filter
+	switch n := node.(type) {
+	case *AndNode:
+		totalScore := 0.0
+		for _, child := range n.Children {
+			matches, score, childRelevance := se.evaluateNode(item, child)
+			if !matches {
+				return false, 0, SearchRelevance{}
+			}
+			totalScore += score
+			relevance = se.combineRelevance(relevance, childRelevance)
+		}
+		return true, totalScore, relevance
+		
+	case *OrNode:
+		matched := false
+		totalScore := 0.0
+		for _, child := range n.Children {
+			matches, score, childRelevance := se.evaluateNode(item, child)
+			if !matches {
+				continue
+			}
+			matched = true
+			totalScore += score
+			relevance = se.combineRelevance(relevance, childRelevance)
+		}
+		return matched, totalScore, relevance
+		
+	case *NotNode:
+		// Negated terms filter but don't contribute to ranking
+		matches, _, _ := se.evaluateNode(item, n.Child)
+		return !matches, 0, relevance
+		
+	case *FilterNode:
+		if n.Pattern != nil {
+			return se.checkPatternFilter(item, n.Filter.Type, n.Pattern)
 		}
+		return se.checkSingleFilter(item, n.Filter)
 		
-		totalScore += filterScore
-		relevance = se.combineRelevance(relevance, filterRelevance)
+	case *TextNode:
+		score, textRelevance := se.calculateSimpleScore(item, n.Terms, n.Phrase)
+		return score > 0, score, textRelevance
 	}
 	
-	return true, totalScore, relevance
+	return false, 0, relevance
 }
 
 // checkSingleFilter checks if an item matches a single filter
@@ -98,14 +125,27 @@ func (se *SearchEngine[T]) checkSingleFilter(item T, filter QueryFilter) (bool,
 	
 	switch filter.Type {
 	case "tag":
-		// Check if item has the requested tag
+		// tag:=name matches only that exact tag; tag:name also matches its
+		// descendants, so tag:project finds project/frontend
+		exact := strings.HasPrefix(filter.Value, "=")
+		tagQuery := strings.Trim(strings.TrimPrefix(filter.Value, "="), `"'`)
+
+		matched := false
 		for _, tag := range item.GetTags() {
-			if strings.EqualFold(tag, filter.Value) {
+			if strings.EqualFold(tag, tagQuery) {
 				relevance.TagMatch = true
 				relevance.ExactMatch = true
-				relevance.Highlights["tags"] = []string{filter.Value}
+				relevance.Highlights["tags"] = []string{tag}
 				return true, 10.0, relevance
 			}
+			if !exact && models.TagMatchesHierarchy(tag, tagQuery) {
+				relevance.TagMatch = true
+				relevance.Highlights["tags"] = append(relevance.Highlights["tags"], tag)
+				matched = true
+			}
+		}
+		if matched {
+			return true, 8.0, relevance
 		}
 		return false, 0, relevance
 		
@@ -147,14 +187,17 @@ func (se *SearchEngine[T]) checkSingleFilter(item T, filter QueryFilter) (bool,
 		return false, 0, relevance
 		
 	case "name":
-		// Check if name contains the search term
-		name := strings.ToLower(item.GetName())
-		nameQuery := strings.ToLower(filter.Value)
+		// Check if name contains the search term; a quoted value must match
+		// whole words
+		find := findAll
+		if filter.Quoted {
+			find = findPhrase
+		}
 		
-		if strings.Contains(name, nameQuery) {
+		if occurrences := find(item.GetName(), filter.Value); len(occurrences) > 0 {
 			score := 5.0
 			exactMatch := false
-			if name == nameQuery {
+			if strings.EqualFold(item.GetName(), filter.Value) {
 				score = 10.0
 				exactMatch = true
 			}
@@ -162,34 +205,145 @@ func (se *SearchEngine[T]) checkSingleFilter(item T, filter QueryFilter) (bool,
 			relevance.NameMatch = true
 			relevance.ExactMatch = exactMatch
 			relevance.Highlights["name"] = []string{item.GetName()}
+			relevance.addRanges("name", occurrences)
 			return true, score, relevance
 		}
 		return false, 0, relevance
 		
 	case "content":
-		// Check if content contains the search term
-		content := strings.ToLower(item.GetContent())
-		contentQuery := strings.ToLower(filter.Value)
-		
-		if strings.Contains(content, contentQuery) {
-			// Count occurrences
-			count := strings.Count(content, contentQuery)
-			score := float64(count) * 2.0
-			
+		// Check if content contains the search term; a quoted value must match
+		// whole words
+		find := findAll
+		if filter.Quoted {
+			find = findPhrase
+		}
+		
+		if occurrences := find(contentBody(item), filter.Value); len(occurrences) > 0 {
+			score := float64(len(occurrences)) * 2.0
 			relevance.ContentMatch = true
-			if excerpts := extractExcerpts(item.GetContent(), filter.Value, 2, 50); len(excerpts) > 0 {
-				relevance.Highlights["content"] = excerpts
-			}
+			relevance.addRanges("content", occurrences)
 			return true, score, relevance
 		}
 		return false, 0, relevance
 		
+	case "modified", "created", "archived":
+		// Check the date against the range, e.g. modified:>30d or created:2026-01
+		dates, err := parseDateFilter(filter.Value, time.Now())
+		if err != nil {
+			return false, 0, relevance
+		}
+		
+		var date time.Time
+		switch filter.Type {
+		case "modified":
+			date = item.GetModified()
+		case "created":
+			date = item.GetCreated()
+		default:
+			date = item.GetArchivedAt()
+		}
+		
+		if dates.contains(date) {
+			relevance.Highlights[filter.Type] = []string{date.Format("2006-01-02")}
+			return true, 5.0, relevance
+		}
+		return false, 0, relevance
+		
+	case "tokens", "usage", "components":
+		// Compare a count, e.g. tokens:>2000 or usage:0
+		counts, err := parseNumericFilter(filter.Value)
+		if err != nil {
+			return false, 0, relevance
+		}
+		
+		var count int
+		switch filter.Type {
+		case "tokens":
+			count = item.GetTokenCount()
+		case "usage":
+			// Only components are used by pipelines
+			if item.GetType() == "pipeline" {
+				return false, 0, relevance
+			}
+			count = item.GetUsageCount()
+		default:
+			// Only pipelines include components
+			if item.GetType() != "pipeline" {
+				return false, 0, relevance
+			}
+			count = len(item.GetComponentPaths())
+		}
+		
+		if counts.contains(count) {
+			relevance.Highlights[filter.Type] = []string{strconv.Itoa(count)}
+			return true, 5.0, relevance
+		}
+		return false, 0, relevance
+		
+	case "uses":
+		// Check the components a pipeline includes, e.g. uses:contexts/api-docs
+		var used []string
+		for _, componentPath := range item.GetComponentPaths() {
+			if matchComponentRef(componentPath, filter.Value) {
+				used = append(used, componentPath)
+			}
+		}
+		if len(used) > 0 {
+			relevance.ExactMatch = true
+			relevance.Highlights["uses"] = used
+			return true, 10.0, relevance
+		}
+		return false, 0, relevance
+		
+	case "broken":
+		// Check for pipelines with missing components or extended pipelines
+		want, _ := strconv.ParseBool(filter.Value)
+		if item.GetType() == "pipeline" && item.HasBrokenReferences() == want {
+			relevance.Highlights["broken"] = []string{filter.Value}
+			return true, 10.0, relevance
+		}
+		return false, 0, relevance
+		
+	case "path":
+		// Check the folder path inside the component type or pipelines directory
+		if matchItemPath(item.GetPath(), filter.Value) {
+			relevance.ExactMatch = true
+			relevance.Highlights["path"] = []string{item.GetPath()}
+			return true, 10.0, relevance
+		}
+		return false, 0, relevance
+		
 	default:
 		// Unknown filter type - ignore for now
 		return true, 0, relevance
 	}
 }
 
+// checkPatternFilter checks a content: or name: filter written as a regular
+// expression, such as content:/TODO|FIXME/ or name:/^api-/
+func (se *SearchEngine[T]) checkPatternFilter(item T, field string, pattern *regexp.Regexp) (bool, float64, SearchRelevance) {
+	relevance := SearchRelevance{
+		Highlights: make(map[string][]string),
+	}
+	
+	switch field {
+	case "name":
+		if occurrences := findRegex(item.GetName(), pattern); len(occurrences) > 0 {
+			relevance.NameMatch = true
+			relevance.Highlights["name"] = []string{item.GetName()}
+			relevance.addRanges("name", occurrences)
+			return true, 5.0, relevance
+		}
+	case "content":
+		if occurrences := findRegex(contentBody(item), pattern); len(occurrences) > 0 {
+			relevance.ContentMatch = true
+			relevance.addRanges("content", occurrences)
+			return true, float64(len(occurrences)) * 2.0, relevance
+		}
+	}
+	return false, 0, relevance
+}
+
 // combineRelevance combines two SearchRelevance objects
 func (se *SearchEngine[T]) combineRelevance(r1, r2 SearchRelevance) SearchRelevance {
 	combined := SearchRelevance{
@@ -224,5 +378,90 @@ func (se *SearchEngine[T]) combineRelevance(r1, r2 SearchRelevance) SearchReleva
 		}
 	}
 	
+	for key, ranges := range r1.Ranges {
+		combined.addRanges(key, ranges)
+	}
+	for key, ranges := range r2.Ranges {
+		combined.addRanges(key, ranges)
+	}
+	
 	return combined
-}
\ No newline at end of file
+}
+
+// itemFolderPath returns an item's path relative to its component type or pipelines
+// directory without the extension, e.g. backend/db for components/contexts/backend/db.md
+func itemFolderPath(itemPath string) string {
+	p := filepath.ToSlash(itemPath)
+	if idx := strings.Index(p, ".pluqqy/"); idx >= 0 {
+		p = p[idx+len(".pluqqy/"):]
+	}
+	p = strings.TrimPrefix(p, "archive/")
+	switch {
+	case strings.HasPrefix(p, "components/"):
+		// Drop components/<type>/
+		parts := strings.SplitN(p, "/", 3)
+		if len(parts) == 3 {
+			p = parts[2]
+		}
+	case strings.HasPrefix(p, "pipelines/"):
+		p = strings.TrimPrefix(p, "pipelines/")
+	}
+	return strings.TrimSuffix(p, path.Ext(p))
+}
+
+// matchItemPath reports whether an item's folder path matches a path query.
+// Queries with wildcards are glob patterns (backend/*) that also match every item
+// below a matching folder; plain queries match the item itself or a folder prefix.
+func matchItemPath(itemPath, query string) bool {
+	rel := strings.ToLower(itemFolderPath(itemPath))
+	query = strings.ToLower(strings.Trim(filepath.ToSlash(strings.TrimSpace(query)), "/"))
+	if query == "" {
+		return false
+	}
+	query = strings.TrimSuffix(query, path.Ext(query))
+	
+	if !strings.ContainsAny(query, "*?[") {
+		return rel == query || strings.HasPrefix(rel, query+"/")
+	}
+	
+	// Try the item and each of its parent folders against the pattern
+	for candidate := rel; candidate != "." && candidate != ""; candidate = path.Dir(candidate) {
+		if matched, err := path.Match(query, candidate); err == nil && matched {
+			return true
+		}
+	}
+	return false
+}
+
+// componentRef returns a component path relative to the components directory
+// without the extension, e.g. contexts/api-docs for components/contexts/api-docs.md
+func componentRef(componentPath string) string {
+	p := strings.ToLower(filepath.ToSlash(strings.TrimSpace(componentPath)))
+	p = strings.TrimPrefix(p, "../")
+	if idx := strings.Index(p, ".pluqqy/"); idx >= 0 {
+		p = p[idx+len(".pluqqy/"):]
+	}
+	p = strings.TrimPrefix(p, "archive/")
+	p = strings.TrimPrefix(p, "components/")
+	return strings.TrimSuffix(p, path.Ext(p))
+}
+
+// matchComponentRef reports whether an included component matches a uses: query.
+// The query is a component such as contexts/api-docs, a bare name such as
+// api-docs, or a glob pattern such as contexts/backend/*.
+func matchComponentRef(componentPath, query string) bool {
+	ref := componentRef(componentPath)
+	query = componentRef(query)
+	if query == "" {
+		return false
+	}
+	
+	if strings.ContainsAny(query, "*?[") {
+		matched, err := path.Match(query, ref)
+		return err == nil && matched
+	}
+	if !strings.Contains(query, "/") {
+		return path.Base(ref) == query
+	}
+	return ref == query
+}
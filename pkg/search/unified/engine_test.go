@@ -284,24 +284,42 @@ func TestSearchEngine_Deduplication(t *testing.T) {
 func TestSearchEngine_ExtractExcerpts(t *testing.T) {
 	content := "This is a test document with multiple occurrences of the word test in various test positions."
 	
-	excerpts := extractExcerpts(content, "test", 2, 30)
+	excerpts := extractExcerpts(content, findAll(content, "test"), 30)
 	
-	if len(excerpts) == 0 {
-		t.Error("Expected excerpts to be extracted")
+	// Matches on the same line share an excerpt
+	if len(excerpts) != 1 {
+		t.Fatalf("Expected 1 excerpt, got %d", len(excerpts))
 	}
 	
 	// Check that excerpts contain the search term
 	for _, excerpt := range excerpts {
-		if !strings.Contains(strings.ToLower(excerpt), "test") {
-			t.Errorf("Excerpt doesn't contain search term: %s", excerpt)
+		if !strings.Contains(strings.ToLower(excerpt.Text), "test") {
+			t.Errorf("Excerpt doesn't contain search term: %s", excerpt.Text)
+		}
+		if len(excerpt.Ranges) != 3 {
+			t.Errorf("Expected 3 ranges, got %v", excerpt.Ranges)
+		}
+		for _, r := range excerpt.Ranges {
+			if got := excerpt.Text[r.Start:r.End]; got != "test" {
+				t.Errorf("Range %v covers %q, want test", r, got)
+			}
 		}
 	}
 	
-	// Check excerpt length constraint
-	for _, excerpt := range excerpts {
-		if len(excerpt) > 100 { // Some buffer for "..." additions
-			t.Errorf("Excerpt too long: %d characters", len(excerpt))
-		}
+	// Every matching line is returned with its line number, long lines cut down
+	content = "first line\nsecond test line\nthird\r\n" + strings.Repeat("x", 100) + " test " + strings.Repeat("y", 100)
+	excerpts = extractExcerpts(content, findAll(content, "test"), 10)
+	if len(excerpts) != 2 {
+		t.Fatalf("Expected 2 excerpts, got %d", len(excerpts))
+	}
+	if excerpts[0].Line != 2 || excerpts[0].Text != "second test line" {
+		t.Errorf("First excerpt = line %d %q", excerpts[0].Line, excerpts[0].Text)
+	}
+	if want := "..." + strings.Repeat("x", 9) + " test " + strings.Repeat("y", 9) + "..."; excerpts[1].Line != 4 || excerpts[1].Text != want {
+		t.Errorf("Second excerpt = line %d %q, want line 4 %q", excerpts[1].Line, excerpts[1].Text, want)
+	}
+	if r := excerpts[1].Ranges[0]; excerpts[1].Text[r.Start:r.End] != "test" {
+		t.Errorf("Second excerpt range %v doesn't cover the match", r)
 	}
 }
 
@@ -361,9 +379,13 @@ type TestSearchableItem struct {
 	tags       []string
 	content    string
 	modified   time.Time
+	created    time.Time
+	archivedAt time.Time
 	archived   bool
 	tokenCount int
 	usageCount int
+	components []string
+	broken     bool
 }
 
 func (t *TestSearchableItem) GetName() string        { return t.name }
@@ -373,6 +395,10 @@ func (t *TestSearchableItem) GetSubType() string     { return t.subType }
 func (t *TestSearchableItem) GetTags() []string      { return t.tags }
 func (t *TestSearchableItem) GetContent() string     { return t.content }
 func (t *TestSearchableItem) GetModified() time.Time { return t.modified }
+func (t *TestSearchableItem) GetCreated() time.Time  { return t.created }
+func (t *TestSearchableItem) GetArchivedAt() time.Time { return t.archivedAt }
 func (t *TestSearchableItem) IsArchived() bool       { return t.archived }
 func (t *TestSearchableItem) GetTokenCount() int     { return t.tokenCount }
-func (t *TestSearchableItem) GetUsageCount() int     { return t.usageCount }
\ No newline at end of file
+func (t *TestSearchableItem) GetUsageCount() int     { return t.usageCount }
+func (t *TestSearchableItem) GetComponentPaths() []string { return t.components }
+func (t *TestSearchableItem) HasBrokenReferences() bool   { return t.broken }
\ No newline at end of file
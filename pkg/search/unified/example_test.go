@@ -23,9 +23,13 @@ func (e *ExampleItem) GetSubType() string     { return "" }
 func (e *ExampleItem) GetTags() []string      { return e.Tags }
 func (e *ExampleItem) GetContent() string     { return e.Content }
 func (e *ExampleItem) GetModified() time.Time { return e.Modified }
+func (e *ExampleItem) GetCreated() time.Time  { return time.Time{} }
+func (e *ExampleItem) GetArchivedAt() time.Time { return time.Time{} }
 func (e *ExampleItem) IsArchived() bool       { return false }
 func (e *ExampleItem) GetTokenCount() int     { return 0 }
 func (e *ExampleItem) GetUsageCount() int     { return 0 }
+func (e *ExampleItem) GetComponentPaths() []string { return nil }
+func (e *ExampleItem) HasBrokenReferences() bool   { return false }
 
 // Example demonstrates using the unified search engine with multiple filters
 func Example_multiFilterSearch() {
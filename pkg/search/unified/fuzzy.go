@@ -0,0 +1,310 @@
+package unified
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MatchRange is a matched span of a field's text as byte offsets; End is exclusive
+type MatchRange struct {
+	Start int
+	End   int
+}
+
+// maxFieldRanges caps how many ranges are kept per field, so a common word in a
+// long document doesn't produce tens of thousands of them
+const maxFieldRanges = 1000
+
+// minSubsequenceQuality is the lowest subsequence quality (0 to 1) that counts as
+// a match; below it the characters are too scattered to be what was meant
+const minSubsequenceQuality = 0.5
+
+// BM25 parameters: k1 controls term frequency saturation and b how strongly long
+// documents are penalised
+const (
+	bm25K1 = 1.2
+	bm25B  = 0.75
+)
+
+// foldCase lowercases text for matching. Byte offsets into the result must line
+// up with the original, so when full Unicode lowercasing changes the length only
+// ASCII letters are folded.
+func foldCase(text string) string {
+	lower := strings.ToLower(text)
+	if len(lower) == len(text) {
+		return lower
+	}
+	b := []byte(text)
+	for i, c := range b {
+		if c >= 'A' && c <= 'Z' {
+			b[i] = c + ('a' - 'A')
+		}
+	}
+	return string(b)
+}
+
+// isWordChar reports whether r is part of a word
+func isWordChar(r rune) bool {
+	return unicode.IsLetter(r) || unicode.IsDigit(r)
+}
+
+// isWordStart reports whether a word starts at byte offset i of text: at the
+// beginning, after a separator such as - or _, or at a camelCase hump
+func isWordStart(text string, i int) bool {
+	if i == 0 {
+		return true
+	}
+	prev, _ := utf8.DecodeLastRuneInString(text[:i])
+	cur, _ := utf8.DecodeRuneInString(text[i:])
+	if !isWordChar(prev) {
+		return isWordChar(cur)
+	}
+	return unicode.IsLower(prev) && unicode.IsUpper(cur)
+}
+
+// wordSpans returns the byte ranges of the words in text
+func wordSpans(text string) []MatchRange {
+	var spans []MatchRange
+	start := -1
+	for i, r := range text {
+		if isWordChar(r) {
+			if start < 0 {
+				start = i
+			}
+			continue
+		}
+		if start >= 0 {
+			spans = append(spans, MatchRange{Start: start, End: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		spans = append(spans, MatchRange{Start: start, End: len(text)})
+	}
+	return spans
+}
+
+// findAll returns every case-insensitive occurrence of term in text
+func findAll(text, term string) []MatchRange {
+	if term == "" {
+		return nil
+	}
+	lower := foldCase(text)
+	term = strings.ToLower(term)
+
+	var ranges []MatchRange
+	for offset := 0; ; {
+		pos := strings.Index(lower[offset:], term)
+		if pos < 0 {
+			break
+		}
+		start := offset + pos
+		ranges = append(ranges, MatchRange{Start: start, End: start + len(term)})
+		offset = start + len(term)
+	}
+	return ranges
+}
+
+// findPhrase returns the occurrences of phrase in text that start and end on word
+// boundaries, so "error handling" doesn't match terror handlings
+func findPhrase(text, phrase string) []MatchRange {
+	var ranges []MatchRange
+	for _, occurrence := range findAll(text, phrase) {
+		if atWordBoundaries(text, occurrence) {
+			ranges = append(ranges, occurrence)
+		}
+	}
+	return ranges
+}
+
+// atWordBoundaries reports whether a match neither starts nor ends inside a word
+func atWordBoundaries(text string, r MatchRange) bool {
+	first, _ := utf8.DecodeRuneInString(text[r.Start:r.End])
+	last, _ := utf8.DecodeLastRuneInString(text[r.Start:r.End])
+	if r.Start > 0 && isWordChar(first) {
+		if prev, _ := utf8.DecodeLastRuneInString(text[:r.Start]); isWordChar(prev) {
+			return false
+		}
+	}
+	if r.End < len(text) && isWordChar(last) {
+		if next, _ := utf8.DecodeRuneInString(text[r.End:]); isWordChar(next) {
+			return false
+		}
+	}
+	return true
+}
+
+// subsequenceMatch matches the characters of term in order anywhere in text, as
+// fzf does, so apidc matches api-docs. The quality, from 0 to 1, rewards
+// characters at word starts and runs of consecutive characters and penalises
+// gaps. The returned ranges cover the matched characters.
+func subsequenceMatch(text, term string) (float64, []MatchRange, bool) {
+	lower := foldCase(text)
+	term = strings.ToLower(term)
+	if term == "" || len(term) > len(lower) {
+		return 0, nil, false
+	}
+
+	// Find where the first complete match ends, then walk back from there to
+	// find the shortest window that still contains the whole term
+	ti, end := 0, -1
+	for i := 0; i < len(lower); i++ {
+		if lower[i] == term[ti] {
+			ti++
+			if ti == len(term) {
+				end = i
+				break
+			}
+		}
+	}
+	if end < 0 {
+		return 0, nil, false
+	}
+
+	positions := make([]int, len(term))
+	ti = len(term) - 1
+	for i := end; i >= 0 && ti >= 0; i-- {
+		if lower[i] == term[ti] {
+			positions[ti] = i
+			ti--
+		}
+	}
+
+	const (
+		charScore        = 1.0
+		wordStartBonus   = 2.0
+		consecutiveBonus = 1.5
+		gapPenalty       = 0.2
+	)
+	score := 0.0
+	var ranges []MatchRange
+	for i, pos := range positions {
+		score += charScore
+		if isWordStart(text, pos) {
+			score += wordStartBonus
+		}
+		if i > 0 {
+			if gap := pos - positions[i-1] - 1; gap == 0 {
+				score += consecutiveBonus
+			} else {
+				score -= gapPenalty * float64(gap)
+			}
+		}
+		if n := len(ranges); n > 0 && ranges[n-1].End == pos {
+			ranges[n-1].End = pos + 1
+		} else {
+			ranges = append(ranges, MatchRange{Start: pos, End: pos + 1})
+		}
+	}
+
+	best := charScore + wordStartBonus + float64(len(term)-1)*(charScore+consecutiveBonus)
+	quality := math.Max(0, score/best)
+	return quality, ranges, quality >= minSubsequenceQuality
+}
+
+// maxTypos is how many edits a term of this length may be off by and still match.
+// Short terms must be exact, otherwise nearly every word would match them.
+func maxTypos(term string) int {
+	switch n := utf8.RuneCountInString(term); {
+	case n < 4:
+		return 0
+	case n < 8:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// editDistance is the optimal string alignment distance between a and b: the
+// number of insertions, deletions, substitutions and adjacent transpositions
+// needed to turn one into the other
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	rows := make([][]int, len(ra)+1)
+	for i := range rows {
+		rows[i] = make([]int, len(rb)+1)
+		rows[i][0] = i
+	}
+	for j := range rows[0] {
+		rows[0][j] = j
+	}
+
+	for i := 1; i <= len(ra); i++ {
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			d := min(rows[i-1][j]+1, rows[i][j-1]+1, rows[i-1][j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				d = min(d, rows[i-2][j-2]+1)
+			}
+			rows[i][j] = d
+		}
+	}
+	return rows[len(ra)][len(rb)]
+}
+
+// typoMatch finds the words in text within maxTypos edits of term. It returns the
+// smallest distance found and the ranges of every word within the limit.
+func typoMatch(text, term string) (int, []MatchRange, bool) {
+	term = strings.ToLower(term)
+	limit := maxTypos(term)
+	if limit == 0 {
+		return 0, nil, false
+	}
+	termLen := utf8.RuneCountInString(term)
+
+	best := limit + 1
+	var ranges []MatchRange
+	for _, span := range wordSpans(text) {
+		word := strings.ToLower(text[span.Start:span.End])
+		if diff := utf8.RuneCountInString(word) - termLen; diff > limit || -diff > limit {
+			continue
+		}
+		if d := editDistance(word, term); d <= limit {
+			best = min(best, d)
+			ranges = append(ranges, span)
+		}
+	}
+	return best, ranges, len(ranges) > 0
+}
+
+// mergeRanges sorts ranges and joins any that overlap or touch
+func mergeRanges(ranges []MatchRange) []MatchRange {
+	if len(ranges) < 2 {
+		return ranges
+	}
+	sorted := append([]MatchRange(nil), ranges...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].Start < sorted[j].Start
+	})
+
+	merged := sorted[:1]
+	for _, r := range sorted[1:] {
+		last := &merged[len(merged)-1]
+		if r.Start <= last.End {
+			last.End = max(last.End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+// bm25 scores a term occurring tf times in a document of docLen words, where df
+// of the docs documents contain it and documents average avgDocLen words
+func bm25(tf float64, df, docs, docLen int, avgDocLen float64) float64 {
+	if tf <= 0 || docs == 0 {
+		return 0
+	}
+	idf := math.Log(1 + (float64(docs)-float64(df)+0.5)/(float64(df)+0.5))
+	norm := 1.0
+	if avgDocLen > 0 {
+		norm = 1 - bm25B + bm25B*float64(docLen)/avgDocLen
+	}
+	return idf * tf * (bm25K1 + 1) / (tf + bm25K1*norm)
+}
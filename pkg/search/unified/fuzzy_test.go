@@ -0,0 +1,160 @@
+package unified
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestSubsequenceMatch(t *testing.T) {
+	tests := []struct {
+		text     string
+		term     string
+		matches  bool
+		expected []MatchRange
+	}{
+		{"api-docs", "apidc", true, []MatchRange{{0, 3}, {4, 5}, {6, 7}}},
+		{"ErrorHandler", "eh", true, []MatchRange{{0, 1}, {5, 6}}},
+		{"api-docs", "apx", false, nil},
+		{"database-migration", "aeiou", false, nil}, // too scattered
+		{"api", "apis", false, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.text+"/"+tt.term, func(t *testing.T) {
+			_, ranges, ok := subsequenceMatch(tt.text, tt.term)
+			if ok != tt.matches {
+				t.Fatalf("subsequenceMatch(%q, %q) ok = %v, want %v", tt.text, tt.term, ok, tt.matches)
+			}
+			if ok && !reflect.DeepEqual(ranges, tt.expected) {
+				t.Errorf("ranges = %v, want %v", ranges, tt.expected)
+			}
+		})
+	}
+
+	// Matches at word starts rank above scattered ones
+	boundary, _, _ := subsequenceMatch("api-docs", "ad")
+	scattered, _, _ := subsequenceMatch("abcdefgh", "ad")
+	if boundary <= scattered {
+		t.Errorf("word start quality %.2f should beat scattered %.2f", boundary, scattered)
+	}
+}
+
+func TestEditDistance(t *testing.T) {
+	tests := []struct {
+		a, b     string
+		expected int
+	}{
+		{"", "", 0},
+		{"auth", "auth", 0},
+		{"authentcation", "authentication", 1},
+		{"hadnler", "handler", 1}, // transposition
+		{"kitten", "sitting", 3},
+		{"", "abc", 3},
+	}
+
+	for _, tt := range tests {
+		if got := editDistance(tt.a, tt.b); got != tt.expected {
+			t.Errorf("editDistance(%q, %q) = %d, want %d", tt.a, tt.b, got, tt.expected)
+		}
+	}
+}
+
+func TestTypoMatch(t *testing.T) {
+	distance, ranges, ok := typoMatch("Handles authentcation errors", "authentication")
+	if !ok || distance != 1 {
+		t.Fatalf("typoMatch ok = %v, distance = %d; want a match 1 edit away", ok, distance)
+	}
+	if want := []MatchRange{{8, 21}}; !reflect.DeepEqual(ranges, want) {
+		t.Errorf("ranges = %v, want %v", ranges, want)
+	}
+
+	// Short terms must match exactly
+	if _, _, ok := typoMatch("the api", "apu"); ok {
+		t.Error("a three letter term should not tolerate typos")
+	}
+	if _, _, ok := typoMatch("logging setup", "authentication"); ok {
+		t.Error("unrelated words should not match")
+	}
+}
+
+func TestMergeRanges(t *testing.T) {
+	got := mergeRanges([]MatchRange{{10, 12}, {0, 3}, {2, 5}, {5, 6}, {20, 22}})
+	want := []MatchRange{{0, 6}, {10, 12}, {20, 22}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("mergeRanges = %v, want %v", got, want)
+	}
+}
+
+func TestBM25(t *testing.T) {
+	// Rarer terms and more occurrences score higher; longer documents lower
+	rare := bm25(1, 1, 100, 50, 50)
+	common := bm25(1, 50, 100, 50, 50)
+	if rare <= common {
+		t.Errorf("rare term %.3f should outscore common term %.3f", rare, common)
+	}
+	if more := bm25(3, 1, 100, 50, 50); more <= rare {
+		t.Errorf("three occurrences %.3f should outscore one %.3f", more, rare)
+	}
+	if long := bm25(1, 1, 100, 500, 50); long >= rare {
+		t.Errorf("long document %.3f should score below average length %.3f", long, rare)
+	}
+	if got := bm25(0, 1, 100, 50, 50); got != 0 {
+		t.Errorf("no occurrences scored %.3f, want 0", got)
+	}
+}
+
+func TestSearchEngine_FuzzySearch(t *testing.T) {
+	engine := NewSearchEngine[*TestSearchableItem]()
+	engine.SetItems([]*TestSearchableItem{
+		{name: "api-docs", content: "Reference for the public REST endpoints.", modified: time.Now().Add(-30 * 24 * time.Hour)},
+		{name: "authentication", content: "Sign in with OAuth tokens.", modified: time.Now().Add(-30 * 24 * time.Hour)},
+		{name: "database-migration", content: "Schema changes and rollbacks.", modified: time.Now().Add(-30 * 24 * time.Hour)},
+		{name: "logging", content: "Structured logs for the catalog service. Logs rotate daily.", modified: time.Now().Add(-30 * 24 * time.Hour)},
+		{name: "catalog", content: "The product catalog lists every item in the catalog.", modified: time.Now().Add(-30 * 24 * time.Hour)},
+	})
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"apidc", []string{"api-docs"}},
+		{"authentcation", []string{"authentication"}},
+		{"databse", []string{"database-migration"}},
+		{"rollbakcs", []string{"database-migration"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := engine.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search(%q) error = %v", tt.query, err)
+			}
+			var names []string
+			for _, r := range results {
+				names = append(names, r.Item.GetName())
+			}
+			if !reflect.DeepEqual(names, tt.expected) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, names, tt.expected)
+			}
+		})
+	}
+
+	results, _ := engine.Search("apidc")
+	if got, want := results[0].Relevance.Ranges["name"], []MatchRange{{0, 3}, {4, 5}, {6, 7}}; !reflect.DeepEqual(got, want) {
+		t.Errorf("name ranges = %v, want %v", got, want)
+	}
+
+	// The name prefix match leads; catalog only contains log inside words
+	results, _ = engine.Search("log")
+	var names []string
+	for _, r := range results {
+		names = append(names, r.Item.GetName())
+	}
+	if want := []string{"logging", "catalog"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("Search(log) = %v, want %v", names, want)
+	}
+	if got := results[0].Relevance.Ranges["content"]; len(got) != 3 {
+		t.Errorf("content ranges = %v, want the three occurrences of log", got)
+	}
+}
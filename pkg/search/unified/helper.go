@@ -1,6 +1,7 @@
 package unified
 
-// SearchHelper provides compatibility functions for the existing TUI search interface
+// SearchHelper provides compatibility functions for the existing TUI search interface.
+// Queries go to its UnifiedSearchManager unchanged, so the same expansion rule applies.
 type SearchHelper struct {
 	unifiedManager *UnifiedSearchManager
 }
@@ -12,8 +13,15 @@ func NewSearchHelper() *SearchHelper {
 	}
 }
 
+// NewSearchHelperForManager creates a search helper around an existing manager, so
+// the items loaded for a search stay available to it, e.g. for suggestions
+func NewSearchHelperForManager(manager *UnifiedSearchManager) *SearchHelper {
+	return &SearchHelper{
+		unifiedManager: manager,
+	}
+}
 
-// UnifiedFilterComponents uses the new unified search to filter components
+// UnifiedFilterComponents uses the new unified search to filter components.
 func (sh *SearchHelper) UnifiedFilterComponents(query string, prompts, contexts, rules []ComponentItem) ([]ComponentItem, []ComponentItem, []ComponentItem, error) {
 	// Load components into the unified manager
 	sh.unifiedManager.LoadComponentItems(prompts, contexts, rules)
@@ -38,7 +46,7 @@ func (sh *SearchHelper) UnifiedFilterComponents(query string, prompts, contexts,
 	return filteredPrompts, filteredContexts, filteredRules, nil
 }
 
-// UnifiedFilterPipelines uses the new unified search to filter pipelines
+// UnifiedFilterPipelines uses the new unified search to filter pipelines.
 func (sh *SearchHelper) UnifiedFilterPipelines(query string, pipelines []PipelineItem) ([]PipelineItem, error) {
 	// Load pipelines into the unified manager
 	sh.unifiedManager.LoadPipelineItems(pipelines)
@@ -62,7 +70,7 @@ func (sh *SearchHelper) UnifiedFilterPipelines(query string, pipelines []Pipelin
 	return ConvertPipelineResults(results), nil
 }
 
-// UnifiedFilterAll performs a unified search across both components and pipelines
+// UnifiedFilterAll performs a unified search across both components and pipelines.
 func (sh *SearchHelper) UnifiedFilterAll(query string, prompts, contexts, rules []ComponentItem, pipelines []PipelineItem) ([]ComponentItem, []ComponentItem, []ComponentItem, []PipelineItem, error) {
 	// Load data into the unified manager
 	sh.unifiedManager.LoadComponentItems(prompts, contexts, rules)
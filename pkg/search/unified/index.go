@@ -0,0 +1,545 @@
+package unified
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/composer"
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"github.com/pluqqy/pluqqy-terminal/pkg/utils"
+)
+
+// searchIndexVersion changes whenever the index layout or the way content is split
+// into terms does, so an index written by another version is rebuilt
+const searchIndexVersion = 2
+
+// IndexedDocument is a component or pipeline file as recorded in the search index
+type IndexedDocument struct {
+	ID       int // Numbers the document in the postings
+	ModTime  time.Time
+	Size     int64
+	Hash     string // SHA-256 of the file
+	Name     string
+	Tags     []string
+	Content  string // A component's body without frontmatter, or a pipeline's search content
+	Words    int    // Words in Content
+	Tokens   int    // Estimated tokens of a component's content or a pipeline's composed output
+	Created  time.Time
+	Archived time.Time
+
+	// For pipelines
+	Refs       []string // Component paths as written in the pipeline, e.g. ../components/contexts/api.md
+	Components []string // Components included, through extends too, e.g. components/contexts/api.md
+	Broken     bool     // Whether an included component or extended pipeline is missing
+}
+
+// SearchIndex is an inverted index of the project's components and pipelines kept
+// in .pluqqy/search-index.gob, so searches don't read and split every file
+// again. Documents are checked against their file's modification time and size
+// and, when those change, its hash before being used. The index is a cache
+// rebuilt from the files, so it is stored as gob, which loads several times
+// faster than JSON for libraries of thousands of components.
+type SearchIndex struct {
+	Version   int
+	Documents map[string]*IndexedDocument // By path within .pluqqy, e.g. components/prompts/x.md or archive/pipelines/y.yaml
+	Postings  map[string][]int            // Lowercased term -> IDs of the documents containing it, ascending
+	Usage     map[string]int              // Component path as written in pipelines -> active pipelines including it
+	NextID    int
+
+	mu         sync.Mutex
+	dirty      bool
+	containing map[string]map[int]bool // Term -> documents with a word containing it
+	typos      map[string]map[int]bool // Term -> documents with a word within a typo or two of it
+}
+
+// NewSearchIndex creates an empty search index
+func NewSearchIndex() *SearchIndex {
+	return &SearchIndex{
+		Version:   searchIndexVersion,
+		Documents: make(map[string]*IndexedDocument),
+		Postings:  make(map[string][]int),
+		Usage:     make(map[string]int),
+	}
+}
+
+// LoadSearchIndex reads the project's search index. A missing index, or one that
+// can't be read or was written by another version, starts out empty.
+func LoadSearchIndex() (*SearchIndex, error) {
+	data, err := os.ReadFile(filepath.Join(files.PluqqyDir, files.SearchIndexFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return NewSearchIndex(), nil
+		}
+		return nil, fmt.Errorf("failed to read search index: %w", err)
+	}
+
+	index := NewSearchIndex()
+	if err := gob.NewDecoder(bytes.NewReader(data)).Decode(index); err != nil || index.Version != searchIndexVersion || index.Documents == nil {
+		// Rebuilt from the files
+		index = NewSearchIndex()
+		index.dirty = true
+		return index, nil
+	}
+	if index.Postings == nil {
+		index.Postings = make(map[string][]int)
+	}
+	if index.Usage == nil {
+		index.Usage = make(map[string]int)
+	}
+	return index, nil
+}
+
+// OpenSearchIndex reads the project's search index, brings it up to date with the
+// component and pipeline files and saves it if anything changed
+func OpenSearchIndex() (*SearchIndex, error) {
+	index, err := LoadSearchIndex()
+	if err != nil {
+		return nil, err
+	}
+	if err := index.Refresh(); err != nil {
+		return nil, err
+	}
+	if err := index.Save(); err != nil {
+		return nil, err
+	}
+	return index, nil
+}
+
+// Save writes the index to .pluqqy/search-index.gob if it changed since it was
+// read. Nothing is written outside a project.
+func (idx *SearchIndex) Save() error {
+	if idx == nil {
+		return nil
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if !idx.dirty {
+		return nil
+	}
+	if info, err := os.Stat(files.PluqqyDir); err != nil || !info.IsDir() {
+		return nil
+	}
+
+	var data bytes.Buffer
+	if err := gob.NewEncoder(&data).Encode(idx); err != nil {
+		return fmt.Errorf("failed to encode search index: %w", err)
+	}
+
+	// Write atomically, so the TUI and CLI never read half an index
+	path := filepath.Join(files.PluqqyDir, files.SearchIndexFile)
+	if _, err := os.Stat(path); os.IsNotExist(err) {
+		// Projects set up before the index existed don't ignore it yet
+		if err := files.IgnoreFiles("/" + files.SearchIndexFile); err != nil {
+			return err
+		}
+	}
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data.Bytes(), 0644); err != nil {
+		return fmt.Errorf("failed to write search index: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		os.Remove(tmpFile) // Clean up temp file
+		return fmt.Errorf("failed to save search index: %w", err)
+	}
+	idx.dirty = false
+	return nil
+}
+
+// Refresh brings the index up to date with the component and pipeline files:
+// new and changed files are indexed and deleted ones dropped. When anything
+// changed, the components pipelines include are resolved again, since a missing
+// component or extended pipeline breaks pipelines that didn't change themselves.
+func (idx *SearchIndex) Refresh() error {
+	keys, err := indexableFiles()
+	if err != nil {
+		return err
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	changed := false
+	seen := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		seen[key] = true
+		info, err := os.Stat(filepath.Join(files.PluqqyDir, key))
+		if err != nil {
+			continue
+		}
+		if doc := idx.Documents[key]; doc != nil && doc.ModTime.Equal(info.ModTime()) && doc.Size == info.Size() {
+			continue
+		}
+		if idx.update(key, info) {
+			changed = true
+		}
+	}
+	for key := range idx.Documents {
+		if !seen[key] {
+			idx.remove(key)
+			changed = true
+		}
+	}
+
+	if changed {
+		for key, doc := range idx.Documents {
+			if isPipelineKey(key) {
+				if pipeline := readIndexedPipeline(key); pipeline != nil {
+					doc.Components, doc.Broken = files.PipelineComponentPaths(pipeline)
+					doc.Tokens = pipelineTokens(pipeline)
+				}
+			}
+		}
+		idx.updateUsage()
+	}
+	return nil
+}
+
+// Component returns the indexed component at path, a path such as
+// components/prompts/x.md, re-indexing it first if the file changed. It returns
+// nil when the file can't be read or there is no index.
+func (idx *SearchIndex) Component(path string, archived bool) *IndexedDocument {
+	if idx == nil || path == "" {
+		return nil
+	}
+	return idx.lookup(componentIndexKey(path, archived))
+}
+
+// Pipeline returns the indexed pipeline at path, a path such as pipelines/x.yaml
+// or just x.yaml, re-indexing it first if the file changed. It returns nil when
+// the file can't be read or there is no index.
+func (idx *SearchIndex) Pipeline(path string, archived bool) *IndexedDocument {
+	if idx == nil || path == "" {
+		return nil
+	}
+	return idx.lookup(pipelineIndexKey(path, archived))
+}
+
+// ComponentUsage returns how many active pipelines include the component at path,
+// a path such as components/prompts/x.md
+func (idx *SearchIndex) ComponentUsage(path string) int {
+	if idx == nil {
+		return 0
+	}
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	return idx.Usage["../"+filepath.ToSlash(filepath.Clean(path))]
+}
+
+// lookup returns the document for a file, re-indexing it if its modification time
+// or size changed
+func (idx *SearchIndex) lookup(key string) *IndexedDocument {
+	info, err := os.Stat(filepath.Join(files.PluqqyDir, key))
+	if err != nil || info.IsDir() {
+		return nil
+	}
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if doc := idx.Documents[key]; doc != nil && doc.ModTime.Equal(info.ModTime()) && doc.Size == info.Size() {
+		return doc
+	}
+	if !idx.update(key, info) && idx.Documents[key] == nil {
+		return nil
+	}
+	if isPipelineKey(key) {
+		idx.updateUsage()
+	}
+	return idx.Documents[key]
+}
+
+// update indexes the file at key, returning whether its document changed. A file
+// whose hash hasn't changed only has its modification time and size updated.
+func (idx *SearchIndex) update(key string, info os.FileInfo) bool {
+	if info.Size() > files.MaxFileSize {
+		return false
+	}
+	data, err := os.ReadFile(filepath.Join(files.PluqqyDir, key))
+	if err != nil {
+		return false
+	}
+	sum := sha256.Sum256(data)
+	hash := hex.EncodeToString(sum[:])
+
+	if doc := idx.Documents[key]; doc != nil && doc.Hash == hash {
+		doc.ModTime, doc.Size = info.ModTime(), info.Size()
+		idx.dirty = true
+		return false
+	}
+
+	var doc *IndexedDocument
+	if isPipelineKey(key) {
+		doc = indexPipeline(key)
+	} else {
+		doc = indexComponent(key)
+	}
+	if doc == nil {
+		return false
+	}
+	doc.ModTime, doc.Size, doc.Hash = info.ModTime(), info.Size(), hash
+	doc.Words = len(wordSpans(doc.Content))
+
+	// New IDs are the highest yet, so appending keeps the postings ascending
+	idx.remove(key)
+	doc.ID = idx.NextID
+	idx.NextID++
+	idx.Documents[key] = doc
+	for term := range indexTerms(doc.Content) {
+		idx.Postings[term] = append(idx.Postings[term], doc.ID)
+	}
+	// A new document isn't in the cached lookups, as a changed one isn't
+	// after remove, so they're worked out again
+	idx.dirty = true
+	idx.containing, idx.typos = nil, nil
+	return true
+}
+
+// remove drops a document and its postings
+func (idx *SearchIndex) remove(key string) {
+	doc := idx.Documents[key]
+	if doc == nil {
+		return
+	}
+	for term := range indexTerms(doc.Content) {
+		ids := idx.Postings[term]
+		if i, found := slices.BinarySearch(ids, doc.ID); found {
+			ids = slices.Delete(ids, i, i+1)
+		}
+		if len(ids) == 0 {
+			delete(idx.Postings, term)
+		} else {
+			idx.Postings[term] = ids
+		}
+	}
+	delete(idx.Documents, key)
+	idx.dirty = true
+	idx.containing, idx.typos = nil, nil
+}
+
+// updateUsage counts how many active pipelines include each component, as
+// files.CountComponentUsage does
+func (idx *SearchIndex) updateUsage() {
+	usage := make(map[string]int)
+	for key, doc := range idx.Documents {
+		if strings.HasPrefix(key, files.PipelinesDir+"/") {
+			for _, ref := range doc.Refs {
+				usage[ref]++
+			}
+		}
+	}
+	idx.Usage = usage
+	idx.dirty = true
+}
+
+// documentsContaining returns the IDs of the documents with a word containing
+// term, a lowercased run of letters and digits
+func (idx *SearchIndex) documentsContaining(term string) map[int]bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if docs, ok := idx.containing[term]; ok {
+		return docs
+	}
+	docs := make(map[int]bool)
+	for word, ids := range idx.Postings {
+		if strings.Contains(word, term) {
+			for _, id := range ids {
+				docs[id] = true
+			}
+		}
+	}
+	if idx.containing == nil {
+		idx.containing = make(map[string]map[int]bool)
+	}
+	idx.containing[term] = docs
+	return docs
+}
+
+// documentsWithTypo returns the IDs of the documents with a word within maxTypos
+// edits of term, the words typoMatch would find
+func (idx *SearchIndex) documentsWithTypo(term string) map[int]bool {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	if docs, ok := idx.typos[term]; ok {
+		return docs
+	}
+	docs := make(map[int]bool)
+	limit := maxTypos(term)
+	termLen := utf8.RuneCountInString(term)
+	for word, ids := range idx.Postings {
+		if diff := utf8.RuneCountInString(word) - termLen; diff > limit || -diff > limit {
+			continue
+		}
+		if editDistance(word, term) <= limit {
+			for _, id := range ids {
+				docs[id] = true
+			}
+		}
+	}
+	if idx.typos == nil {
+		idx.typos = make(map[string]map[int]bool)
+	}
+	idx.typos[term] = docs
+	return docs
+}
+
+// indexTerms returns the distinct lowercased words of content
+func indexTerms(content string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, span := range wordSpans(content) {
+		terms[strings.ToLower(content[span.Start:span.End])] = true
+	}
+	return terms
+}
+
+// isIndexTerm reports whether term is made of letters and digits only, so the
+// index's words can tell which documents contain it
+func isIndexTerm(term string) bool {
+	if term == "" {
+		return false
+	}
+	for _, r := range term {
+		if !isWordChar(r) {
+			return false
+		}
+	}
+	return true
+}
+
+// indexComponent reads the component at key for the index
+func indexComponent(key string) *IndexedDocument {
+	readComponent := files.ReadComponent
+	if rest, archived := strings.CutPrefix(key, files.ArchiveDir+"/"); archived {
+		key, readComponent = rest, files.ReadArchivedComponent
+	}
+	component, err := readComponent(key)
+	if err != nil {
+		return nil
+	}
+	return &IndexedDocument{
+		Name:     component.Name,
+		Tags:     component.Tags,
+		Content:  component.Content,
+		Tokens:   utils.EstimateTokens(component.Content),
+		Created:  component.Created,
+		Archived: component.Archived,
+	}
+}
+
+// indexPipeline reads the pipeline at key for the index
+func indexPipeline(key string) *IndexedDocument {
+	pipeline := readIndexedPipeline(key)
+	if pipeline == nil {
+		return nil
+	}
+	doc := &IndexedDocument{
+		Name:     pipeline.Name,
+		Tags:     pipeline.Tags,
+		Content:  pipelineSearchContent(pipeline),
+		Created:  pipeline.Created,
+		Archived: pipeline.Archived,
+	}
+	for _, comp := range pipeline.Components {
+		doc.Refs = append(doc.Refs, filepath.Clean(comp.Path))
+	}
+	doc.Components, doc.Broken = files.PipelineComponentPaths(pipeline)
+	doc.Tokens = pipelineTokens(pipeline)
+	return doc
+}
+
+// pipelineTokens estimates the tokens of a composed pipeline, 0 if it can't be composed
+func pipelineTokens(pipeline *models.Pipeline) int {
+	output, err := composer.ComposePipeline(pipeline)
+	if err != nil {
+		return 0
+	}
+	return utils.EstimateTokens(output)
+}
+
+// readIndexedPipeline reads the active or archived pipeline at key
+func readIndexedPipeline(key string) *models.Pipeline {
+	readPipeline := files.ReadPipeline
+	if strings.HasPrefix(key, files.ArchiveDir+"/") {
+		readPipeline = files.ReadArchivedPipeline
+	}
+	pipeline, err := readPipeline(key)
+	if err != nil {
+		return nil
+	}
+	return pipeline
+}
+
+// indexableFiles lists the component and pipeline files the index covers, as
+// paths within .pluqqy
+func indexableFiles() ([]string, error) {
+	var keys []string
+	for _, ct := range files.ComponentTypes() {
+		active, err := files.ListComponents(ct.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range active {
+			keys = append(keys, path.Join(files.ComponentsDir, ct.DirName(), name))
+		}
+		archived, err := files.ListArchivedComponents(ct.Name)
+		if err != nil {
+			return nil, err
+		}
+		for _, name := range archived {
+			keys = append(keys, path.Join(files.ArchiveDir, files.ComponentsDir, ct.DirName(), name))
+		}
+	}
+
+	pipelines, err := files.ListPipelines()
+	if err != nil {
+		return nil, err
+	}
+	archived, err := files.ListArchivedPipelines()
+	if err != nil {
+		return nil, err
+	}
+	return append(append(keys, pipelines...), archived...), nil
+}
+
+// componentIndexKey is where a component's document is kept in the index
+func componentIndexKey(componentPath string, archived bool) string {
+	key := strings.TrimPrefix(filepath.ToSlash(filepath.Clean(componentPath)), files.ArchiveDir+"/")
+	if archived {
+		return files.ArchiveDir + "/" + key
+	}
+	return key
+}
+
+// pipelineIndexKey is where a pipeline's document is kept in the index
+func pipelineIndexKey(pipelinePath string, archived bool) string {
+	name := filepath.ToSlash(filepath.Clean(pipelinePath))
+	if rest, ok := strings.CutPrefix(name, files.ArchiveDir+"/"+files.PipelinesDir+"/"); ok {
+		name = rest
+	} else {
+		name = strings.TrimPrefix(name, files.PipelinesDir+"/")
+	}
+	if archived {
+		return files.ArchiveDir + "/" + files.PipelinesDir + "/" + name
+	}
+	return files.PipelinesDir + "/" + name
+}
+
+// isPipelineKey reports whether an index key is a pipeline file
+func isPipelineKey(key string) bool {
+	return strings.HasPrefix(key, files.PipelinesDir+"/") ||
+		strings.HasPrefix(key, files.ArchiveDir+"/"+files.PipelinesDir+"/")
+}
@@ -0,0 +1,211 @@
+package unified
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// setupIndexProject creates a project with a few components and a pipeline using two of them
+func setupIndexProject(t *testing.T) {
+	t.Helper()
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatalf("Failed to init project structure: %v", err)
+	}
+	components := []struct {
+		path, content string
+		tags          []string
+	}{
+		{"components/contexts/auth.md", "Authentication uses OAuth tokens.\nRefresh tokens expire after a day.", []string{"api", "security"}},
+		{"components/prompts/review.md", "Review the authentication flow for errors.", []string{"review"}},
+		{"components/rules/style.md", "Prefer small functions. Handle every error.", nil},
+	}
+	for _, c := range components {
+		if err := files.WriteComponentWithNameAndTags(c.path, c.content, "", c.tags); err != nil {
+			t.Fatalf("Failed to write %s: %v", c.path, err)
+		}
+	}
+	pipeline := &models.Pipeline{
+		Name: "Security Review",
+		Path: "security-review.yaml",
+		Components: []models.ComponentRef{
+			{Type: "contexts", Path: "../components/contexts/auth.md", Order: 1},
+			{Type: "prompts", Path: "../components/prompts/review.md", Order: 2},
+		},
+	}
+	if err := files.WritePipeline(pipeline); err != nil {
+		t.Fatalf("Failed to write pipeline: %v", err)
+	}
+}
+
+func TestSearchIndex(t *testing.T) {
+	setupIndexProject(t)
+
+	index, err := OpenSearchIndex()
+	if err != nil {
+		t.Fatalf("OpenSearchIndex() error = %v", err)
+	}
+	if len(index.Documents) != 4 {
+		t.Fatalf("indexed %d documents, want 4", len(index.Documents))
+	}
+	doc := index.Component("components/contexts/auth.md", false)
+	if doc == nil {
+		t.Fatal("auth.md wasn't indexed")
+	}
+	if doc.Tokens == 0 || doc.Words != 10 || !reflect.DeepEqual(doc.Tags, []string{"api", "security"}) {
+		t.Errorf("auth.md indexed as %+v", doc)
+	}
+	if got := index.Postings["tokens"]; !reflect.DeepEqual(got, []int{doc.ID}) {
+		t.Errorf("postings for tokens = %v, want auth.md's ID %d", got, doc.ID)
+	}
+	if got := index.ComponentUsage("components/prompts/review.md"); got != 1 {
+		t.Errorf("ComponentUsage(review.md) = %d, want 1", got)
+	}
+	pipelineTokens := 0
+	if pipeline := index.Pipeline("pipelines/security-review.yaml", false); pipeline == nil || pipeline.Broken || len(pipeline.Components) != 2 || pipeline.Tokens == 0 {
+		t.Errorf("pipeline indexed as %+v", pipeline)
+	} else {
+		pipelineTokens = pipeline.Tokens
+	}
+
+	// The index is saved and read back as it was
+	if _, err := os.Stat(filepath.Join(files.PluqqyDir, files.SearchIndexFile)); err != nil {
+		t.Fatalf("search index wasn't saved: %v", err)
+	}
+	loaded, err := LoadSearchIndex()
+	if err != nil {
+		t.Fatalf("LoadSearchIndex() error = %v", err)
+	}
+	if !reflect.DeepEqual(loaded.Postings, index.Postings) || len(loaded.Documents) != 4 {
+		t.Error("the loaded index differs from the saved one")
+	}
+
+	// A file touched without changing keeps its document; an edited one is indexed again
+	stylePath := filepath.Join(files.PluqqyDir, "components/rules/style.md")
+	later := time.Now().Add(time.Hour)
+	os.Chtimes(stylePath, later, later)
+	if doc := loaded.Component("components/rules/style.md", false); doc == nil || !doc.ModTime.Equal(later) {
+		t.Errorf("touched style.md = %+v, want its new modification time", doc)
+	}
+	if err := files.WriteComponentWithNameAndTags("components/rules/style.md", "Prefer pure functions.", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if doc := loaded.Component("components/rules/style.md", false); doc == nil || doc.Content != "Prefer pure functions." {
+		t.Errorf("edited style.md = %+v", doc)
+	}
+	if _, ok := loaded.Postings["pure"]; !ok {
+		t.Error("the edited content's terms weren't indexed")
+	}
+	if _, ok := loaded.Postings["handle"]; ok {
+		t.Error("the old content's terms are still indexed")
+	}
+
+	// Deleting a component drops it and breaks the pipeline that includes it
+	os.Remove(filepath.Join(files.PluqqyDir, "components/prompts/review.md"))
+	if err := loaded.Refresh(); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	if _, ok := loaded.Documents["components/prompts/review.md"]; ok {
+		t.Error("the deleted component is still indexed")
+	}
+	pipeline := loaded.Pipeline("pipelines/security-review.yaml", false)
+	if pipeline == nil || !pipeline.Broken {
+		t.Fatalf("pipeline after deleting a component = %+v, want broken", pipeline)
+	}
+	if pipeline.Tokens == pipelineTokens {
+		t.Errorf("pipeline tokens = %d after deleting a component, want them counted again", pipeline.Tokens)
+	}
+}
+
+func TestSearchIndex_NewDocumentsClearCachedLookups(t *testing.T) {
+	setupIndexProject(t)
+
+	index, err := OpenSearchIndex()
+	if err != nil {
+		t.Fatalf("OpenSearchIndex() error = %v", err)
+	}
+	if docs := index.documentsContaining("deploy"); len(docs) != 0 {
+		t.Fatalf("documentsContaining(deploy) = %v before adding a file", docs)
+	}
+	if docs := index.documentsWithTypo("deplyo"); len(docs) != 0 {
+		t.Fatalf("documentsWithTypo(deplyo) = %v before adding a file", docs)
+	}
+
+	if err := files.WriteComponentWithNameAndTags("components/contexts/deploy.md", "Deploy on Fridays.", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := index.Refresh(); err != nil {
+		t.Fatalf("Refresh() error = %v", err)
+	}
+	doc := index.Component("components/contexts/deploy.md", false)
+	if doc == nil {
+		t.Fatal("deploy.md wasn't indexed")
+	}
+	if docs := index.documentsContaining("deploy"); !docs[doc.ID] {
+		t.Errorf("documentsContaining(deploy) = %v, want deploy.md's ID %d", docs, doc.ID)
+	}
+	if docs := index.documentsWithTypo("deplyo"); !docs[doc.ID] {
+		t.Errorf("documentsWithTypo(deplyo) = %v, want deploy.md's ID %d", docs, doc.ID)
+	}
+}
+
+func TestSearchIndex_RebuildsUnreadableIndex(t *testing.T) {
+	setupIndexProject(t)
+
+	os.WriteFile(filepath.Join(files.PluqqyDir, files.SearchIndexFile), []byte("{not json"), 0644)
+	index, err := OpenSearchIndex()
+	if err != nil {
+		t.Fatalf("OpenSearchIndex() error = %v", err)
+	}
+	if len(index.Documents) != 4 {
+		t.Errorf("rebuilt index has %d documents, want 4", len(index.Documents))
+	}
+}
+
+func TestSearchIndex_SameResultsAsReadingFiles(t *testing.T) {
+	setupIndexProject(t)
+
+	index, err := OpenSearchIndex()
+	if err != nil {
+		t.Fatalf("OpenSearchIndex() error = %v", err)
+	}
+	components := []ComponentItem{
+		{Name: "auth", Path: "components/contexts/auth.md", CompType: "contexts", Tags: []string{"api", "security"}},
+		{Name: "review", Path: "components/prompts/review.md", CompType: "prompts", Tags: []string{"review"}},
+		{Name: "style", Path: "components/rules/style.md", CompType: "rules"},
+	}
+
+	withoutIndex := NewUnifiedSearchManager()
+	withoutIndex.LoadComponentItems(nil, components, nil)
+	withIndex := NewUnifiedSearchManager()
+	withIndex.SetIndex(index)
+	withIndex.LoadComponentItems(nil, components, nil)
+
+	for _, query := range []string{"tokens", "error", "authentcation", "oauth expire", `"refresh tokens"`, "tag:api OR functions"} {
+		expected, err := withoutIndex.SearchComponents(query, nil)
+		if err != nil {
+			t.Fatalf("SearchComponents(%q) error = %v", query, err)
+		}
+		got, _ := withIndex.SearchComponents(query, nil)
+		if len(got) != len(expected) {
+			t.Errorf("%q: %d results with the index, %d without", query, len(got), len(expected))
+			continue
+		}
+		for i := range got {
+			if got[i].Item.GetPath() != expected[i].Item.GetPath() || got[i].Score != expected[i].Score {
+				t.Errorf("%q result %d: %s (%.3f) with the index, %s (%.3f) without", query, i,
+					got[i].Item.GetPath(), got[i].Score, expected[i].Item.GetPath(), expected[i].Score)
+			}
+		}
+	}
+}
@@ -8,6 +8,7 @@ import (
 )
 
 // UnifiedSearchManager manages search operations across different TUI views
+// Queries reach it with their @saved searches already expanded by ExpandSavedSearches.
 type UnifiedSearchManager struct {
 	// Component search engine
 	componentEngine *SearchEngine[*ComponentItemWrapper]
@@ -21,6 +22,9 @@ type UnifiedSearchManager struct {
 	// Search configuration
 	includeArchived bool
 	maxResults      int
+	
+	// Search index content is taken from instead of reading each file, if any
+	index *SearchIndex
 }
 
 // NewUnifiedSearchManager creates a new unified search manager
@@ -62,60 +66,50 @@ func (usm *UnifiedSearchManager) SetMaxResults(max int) {
 	usm.pipelineEngine.SetOptions(pipelineOptions)
 }
 
+// SetIndex makes the manager take content from a search index, such as the one
+// OpenSearchIndex returns, instead of reading every component and pipeline
+func (usm *UnifiedSearchManager) SetIndex(index *SearchIndex) {
+	usm.index = index
+	usm.componentEngine.SetIndex(index)
+	usm.pipelineEngine.SetIndex(index)
+}
+
+// RefreshIndex brings the manager's search index up to date with the files, e.g.
+// after items were created, renamed or deleted, and saves it
+func (usm *UnifiedSearchManager) RefreshIndex() error {
+	if usm.index == nil {
+		return nil
+	}
+	if err := usm.index.Refresh(); err != nil {
+		return err
+	}
+	return usm.index.Save()
+}
+
 // LoadComponentItems loads and wraps component items for searching
 func (usm *UnifiedSearchManager) LoadComponentItems(prompts, contexts, rules []ComponentItem) {
 	var componentItems []*ComponentItemWrapper
 	
 	// Convert prompts
 	for _, item := range prompts {
-		wrapper := NewComponentItemWrapper(
-			item.Name,
-			item.Path,
-			item.CompType,
-			item.LastModified,
-			item.UsageCount,
-			item.TokenCount,
-			item.Tags,
-			item.IsArchived,
-			usm.loadComponentContent(item.Path), // Load actual content for searching
-		)
-		componentItems = append(componentItems, wrapper)
+		componentItems = append(componentItems, usm.wrapComponentItem(item))
 	}
 	
 	// Convert contexts
 	for _, item := range contexts {
-		wrapper := NewComponentItemWrapper(
-			item.Name,
-			item.Path,
-			item.CompType,
-			item.LastModified,
-			item.UsageCount,
-			item.TokenCount,
-			item.Tags,
-			item.IsArchived,
-			usm.loadComponentContent(item.Path),
-		)
-		componentItems = append(componentItems, wrapper)
+		componentItems = append(componentItems, usm.wrapComponentItem(item))
 	}
 	
 	// Convert rules
 	for _, item := range rules {
-		wrapper := NewComponentItemWrapper(
-			item.Name,
-			item.Path,
-			item.CompType,
-			item.LastModified,
-			item.UsageCount,
-			item.TokenCount,
-			item.Tags,
-			item.IsArchived,
-			usm.loadComponentContent(item.Path),
-		)
-		componentItems = append(componentItems, wrapper)
+		componentItems = append(componentItems, usm.wrapComponentItem(item))
 	}
 	
 	// Set items in the component engine
 	usm.componentEngine.SetItems(componentItems)
+	
+	// Keep any files that were indexed again; failing to only costs time next search
+	usm.index.Save()
 }
 
 // LoadPipelineItems loads and wraps pipeline items for searching
@@ -130,13 +124,58 @@ func (usm *UnifiedSearchManager) LoadPipelineItems(pipelines []PipelineItem) {
 			item.TokenCount,
 			item.IsArchived,
 			item.Modified,
-			usm.loadPipelineContent(item.Path), // Load pipeline content for searching
+			"",
 		)
+		// Load pipeline content, recorded dates and components for searching,
+		// from the search index when it has them
+		if doc := usm.index.Pipeline(item.Path, item.IsArchived); doc != nil {
+			wrapper.content = doc.Content
+			wrapper.created = doc.Created
+			wrapper.archivedAt = doc.Archived
+			wrapper.componentPaths, wrapper.broken = doc.Components, doc.Broken
+			wrapper.doc = doc
+		} else if pipeline := usm.loadPipeline(item.Path); pipeline != nil {
+			wrapper.content = pipelineSearchContent(pipeline)
+			wrapper.created = pipeline.Created
+			wrapper.archivedAt = pipeline.Archived
+			wrapper.componentPaths, wrapper.broken = files.PipelineComponentPaths(pipeline)
+		}
 		pipelineItems = append(pipelineItems, wrapper)
 	}
 	
 	// Set items in the pipeline engine
 	usm.pipelineEngine.SetItems(pipelineItems)
+	
+	// Keep any files that were indexed again; failing to only costs time next search
+	usm.index.Save()
+}
+
+// wrapComponentItem wraps a component item for searching
+func (usm *UnifiedSearchManager) wrapComponentItem(item ComponentItem) *ComponentItemWrapper {
+	wrapper := NewComponentItemWrapper(
+		item.Name,
+		item.Path,
+		item.CompType,
+		item.LastModified,
+		item.UsageCount,
+		item.TokenCount,
+		item.Tags,
+		item.IsArchived,
+		"",
+	)
+	// Load actual content and recorded dates for searching, from the search index
+	// when it has them
+	if doc := usm.index.Component(item.Path, item.IsArchived); doc != nil {
+		wrapper.content = doc.Content
+		wrapper.created = doc.Created
+		wrapper.archivedAt = doc.Archived
+		wrapper.doc = doc
+	} else if component := usm.loadComponent(item.Path); component != nil {
+		wrapper.content = component.Content
+		wrapper.created = component.Created
+		wrapper.archivedAt = component.Archived
+	}
+	return wrapper
 }
 
 // SearchComponents performs a search across components only
@@ -157,6 +196,7 @@ func (usm *UnifiedSearchManager) SearchComponents(query string, componentTypes [
 		}
 		tempEngine.SetItems(filteredItems)
 		tempEngine.SetOptions(usm.componentEngine.options)
+		tempEngine.SetIndex(usm.index)
 		
 		return tempEngine.Search(query)
 	}
@@ -185,7 +225,7 @@ func (usm *UnifiedSearchManager) SearchAll(query string) ([]SearchResult[*Compon
 	return componentResults, pipelineResults, nil
 }
 
-// FilterComponentsByQuery filters components using the existing search query parsing
+// FilterComponentsByQuery filters components using the existing search query parsing.
 func (usm *UnifiedSearchManager) FilterComponentsByQuery(query string, allPrompts, allContexts, allRules []ComponentItem) ([]ComponentItem, []ComponentItem, []ComponentItem, error) {
 	// Load the components into the search engine
 	usm.LoadComponentItems(allPrompts, allContexts, allRules)
@@ -206,7 +246,7 @@ func (usm *UnifiedSearchManager) FilterComponentsByQuery(query string, allPrompt
 	return usm.convertToComponentItems(results, allPrompts, allContexts, allRules)
 }
 
-// FilterPipelinesByQuery filters pipelines using the existing search query parsing
+// FilterPipelinesByQuery filters pipelines using the existing search query parsing.
 func (usm *UnifiedSearchManager) FilterPipelinesByQuery(query string, allPipelines []PipelineItem) ([]PipelineItem, error) {
 	// Load the pipelines into the search engine
 	usm.LoadPipelineItems(allPipelines)
@@ -229,48 +269,46 @@ func (usm *UnifiedSearchManager) FilterPipelinesByQuery(query string, allPipelin
 
 // Helper methods
 
-// loadComponentContent loads the actual content of a component for searching
-func (usm *UnifiedSearchManager) loadComponentContent(path string) string {
+// loadComponent loads a component for searching, returning nil if it can't be read
+func (usm *UnifiedSearchManager) loadComponent(path string) *models.Component {
 	if path == "" {
-		return ""
+		return nil
 	}
 	
-	var component *models.Component
-	var err error
-	
 	// Try to load as regular component first
-	component, err = files.ReadComponent(path)
+	component, err := files.ReadComponent(path)
 	if err != nil {
 		// Try as archived component
 		component, err = files.ReadArchivedComponent(path)
 		if err != nil {
-			return ""
+			return nil
 		}
 	}
 	
-	return component.Content
+	return component
 }
 
-// loadPipelineContent loads pipeline information for searching
-func (usm *UnifiedSearchManager) loadPipelineContent(path string) string {
+// loadPipeline loads a pipeline for searching, returning nil if it can't be read
+func (usm *UnifiedSearchManager) loadPipeline(path string) *models.Pipeline {
 	if path == "" {
-		return ""
+		return nil
 	}
 	
-	var pipeline *models.Pipeline
-	var err error
-	
 	// Try to load as regular pipeline first
-	pipeline, err = files.ReadPipeline(path)
+	pipeline, err := files.ReadPipeline(path)
 	if err != nil {
 		// Try as archived pipeline
 		pipeline, err = files.ReadArchivedPipeline(path)
 		if err != nil {
-			return ""
+			return nil
 		}
 	}
 	
-	// Create searchable content from pipeline metadata
+	return pipeline
+}
+
+// pipelineSearchContent creates searchable content from pipeline metadata
+func pipelineSearchContent(pipeline *models.Pipeline) string {
 	content := pipeline.Name
 	if len(pipeline.Tags) > 0 {
 		content += " " + strings.Join(pipeline.Tags, " ")
@@ -316,16 +354,20 @@ func (usm *UnifiedSearchManager) convertToComponentItems(results []SearchResult[
 		switch compType {
 		case models.ComponentTypePrompt:
 			if item, exists := promptMap[path]; exists {
+				item.NameMatches = result.Relevance.Ranges["name"]
 				filteredPrompts = append(filteredPrompts, item)
 			}
-		case models.ComponentTypeContext:
-			if item, exists := contextMap[path]; exists {
-				filteredContexts = append(filteredContexts, item)
-			}
 		case models.ComponentTypeRules:
 			if item, exists := rulesMap[path]; exists {
+				item.NameMatches = result.Relevance.Ranges["name"]
 				filteredRules = append(filteredRules, item)
 			}
+		default:
+			// Contexts, plus custom component types which travel with them
+			if item, exists := contextMap[path]; exists {
+				item.NameMatches = result.Relevance.Ranges["name"]
+				filteredContexts = append(filteredContexts, item)
+			}
 		}
 	}
 	
@@ -347,6 +389,7 @@ func (usm *UnifiedSearchManager) convertToPipelineItems(results []SearchResult[*
 		path := wrapper.GetPath()
 		
 		if item, exists := pipelineMap[path]; exists {
+			item.NameMatches = result.Relevance.Ranges["name"]
 			filteredPipelines = append(filteredPipelines, item)
 		}
 	}
@@ -354,7 +397,7 @@ func (usm *UnifiedSearchManager) convertToPipelineItems(results []SearchResult[*
 	return filteredPipelines
 }
 
-// IsStructuredQuery checks if a query uses structured search syntax
+// IsStructuredQuery checks if an expanded query uses structured search syntax
 func (usm *UnifiedSearchManager) IsStructuredQuery(query string) bool {
 	return usm.componentEngine.isStructuredQuery(query)
 }
@@ -1,6 +1,8 @@
 package unified
 
 import (
+	"sort"
+	"strings"
 	"testing"
 	"time"
 )
@@ -237,4 +239,426 @@ func TestSearchEngine_ComplexMultiFilter(t *testing.T) {
 			t.Error("Expected content highlights")
 		}
 	}
-}
\ No newline at end of file
+}
+
+func TestSearchEngine_PathFilter(t *testing.T) {
+	items := []*TestSearchableItem{
+		{name: "API", path: "components/contexts/api.md", itemType: "component", subType: "contexts"},
+		{name: "DB", path: "components/contexts/backend/db.md", itemType: "component", subType: "contexts"},
+		{name: "Redis", path: "components/contexts/backend/cache/redis.md", itemType: "component", subType: "contexts"},
+		{name: "Backend Notes", path: "components/prompts/backend-notes.md", itemType: "component", subType: "prompts"},
+		{name: "Hotfix", path: "pipelines/release/hotfix.yaml", itemType: "pipeline"},
+	}
+
+	engine := NewSearchEngine[*TestSearchableItem]()
+	engine.SetItems(items)
+
+	tests := []struct {
+		query         string
+		expectedNames []string
+	}{
+		{"path:backend/*", []string{"DB", "Redis"}},
+		{"path:backend", []string{"DB", "Redis"}},
+		{"path:backend/cache", []string{"Redis"}},
+		{"path:backend/db.md", []string{"DB"}},
+		{"path:release/*", []string{"Hotfix"}},
+		{"path:backend/*/redis", []string{"Redis"}},
+		{"path:backend/* type:contexts", []string{"DB", "Redis"}},
+		{"path:frontend/*", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := engine.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+
+			names := make(map[string]bool)
+			for _, result := range results {
+				names[result.Item.GetName()] = true
+			}
+			if len(results) != len(tt.expectedNames) {
+				t.Errorf("Expected %d results, got %d: %v", len(tt.expectedNames), len(results), names)
+			}
+			for _, name := range tt.expectedNames {
+				if !names[name] {
+					t.Errorf("Expected result %q not found", name)
+				}
+			}
+		})
+	}
+}
+
+func TestSearchEngine_HierarchicalTagFilter(t *testing.T) {
+	items := []*TestSearchableItem{
+		{name: "Project Root", itemType: "component", subType: "contexts", tags: []string{"project"}},
+		{name: "Frontend Rules", itemType: "component", subType: "rules", tags: []string{"project/frontend"}},
+		{name: "Backend API", itemType: "component", subType: "prompts", tags: []string{"Project/Backend/API"}},
+		{name: "Projector", itemType: "component", subType: "prompts", tags: []string{"projector"}},
+	}
+
+	engine := NewSearchEngine[*TestSearchableItem]()
+	engine.SetItems(items)
+
+	tests := []struct {
+		query         string
+		expectedNames []string
+	}{
+		{"tag:project", []string{"Project Root", "Frontend Rules", "Backend API"}},
+		{"tag:project/backend", []string{"Backend API"}},
+		{"tag:=project", []string{"Project Root"}},
+		{`tag:="project/frontend"`, []string{"Frontend Rules"}},
+		{"tag:=project/backend", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := engine.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			if len(results) != len(tt.expectedNames) {
+				t.Fatalf("Expected %d results, got %d", len(tt.expectedNames), len(results))
+			}
+			found := make(map[string]bool)
+			for _, result := range results {
+				found[result.Item.GetName()] = true
+			}
+			for _, name := range tt.expectedNames {
+				if !found[name] {
+					t.Errorf("Expected item '%s' not found in results", name)
+				}
+			}
+			// Exact tag matches rank above descendant matches
+			if len(results) > 1 && results[0].Item.GetName() != tt.expectedNames[0] {
+				t.Errorf("First result = %s, want %s", results[0].Item.GetName(), tt.expectedNames[0])
+			}
+		})
+	}
+}
+
+func TestSearchEngine_BooleanQueries(t *testing.T) {
+	items := []*TestSearchableItem{
+		{name: "API Prompt", itemType: "component", subType: "prompts", tags: []string{"api"}, content: "Handle error handling in the API"},
+		{name: "REST Context", itemType: "component", subType: "contexts", tags: []string{"rest"}, content: "REST conventions"},
+		{name: "Draft Rules", itemType: "component", subType: "rules", tags: []string{"api", "draft"}, content: "Errors are handled later"},
+		{name: "Old API", itemType: "component", subType: "prompts", tags: []string{"api"}, content: "Legacy", archived: true},
+	}
+
+	engine := NewSearchEngine[*TestSearchableItem]()
+	engine.SetItems(items)
+
+	tests := []struct {
+		query         string
+		expectedNames []string
+	}{
+		{"tag:api AND type:prompt", []string{"API Prompt"}},
+		{"tag:api OR tag:rest", []string{"API Prompt", "REST Context", "Draft Rules"}},
+		{"(tag:api OR tag:rest) -tag:draft", []string{"API Prompt", "REST Context"}},
+		{"tag:api NOT type:rules", []string{"API Prompt"}},
+		{"NOT tag:api", []string{"REST Context"}},
+		{`"error handling"`, []string{"API Prompt"}},
+		{`content:"error handling" OR name:rest`, []string{"API Prompt", "REST Context"}},
+		{"tag:api status:archived", []string{"Old API"}},
+		{"tag:api -status:archived", []string{"API Prompt", "Draft Rules"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := engine.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			found := make(map[string]bool)
+			for _, result := range results {
+				found[result.Item.GetName()] = true
+			}
+			if len(results) != len(tt.expectedNames) {
+				t.Errorf("Expected %d results, got %d: %v", len(tt.expectedNames), len(results), found)
+			}
+			for _, name := range tt.expectedNames {
+				if !found[name] {
+					t.Errorf("Expected item '%s' not found in results", name)
+				}
+			}
+		})
+	}
+
+	if _, err := engine.Search("tag:api AND"); err == nil {
+		t.Error("expected a syntax error for a dangling AND")
+	}
+}
+
+func TestSearchEngine_DateFilters(t *testing.T) {
+	now := time.Now()
+	items := []*TestSearchableItem{
+		{name: "Fresh", itemType: "component", subType: "prompts", modified: now.Add(-time.Hour), created: now.AddDate(0, 0, -2)},
+		{name: "Stale", itemType: "component", subType: "prompts", modified: now.AddDate(0, 0, -120), created: now.AddDate(-1, 0, 0)},
+		{name: "Untracked", itemType: "component", subType: "contexts", modified: now.AddDate(0, 0, -40)},
+		{name: "Shelved", itemType: "component", subType: "rules", modified: now.AddDate(0, 0, -200), archived: true, archivedAt: now.AddDate(0, 0, -10)},
+	}
+
+	engine := NewSearchEngine[*TestSearchableItem]()
+	engine.SetItems(items)
+
+	tests := []struct {
+		query         string
+		expectedNames []string
+	}{
+		{"modified:today", []string{"Fresh"}},
+		{"modified:<7d", []string{"Fresh"}},
+		{"modified:>30d", []string{"Stale", "Untracked"}},
+		{"modified:>90d type:prompt", []string{"Stale"}},
+		{"modified:>" + now.AddDate(0, 0, -60).Format("2006-01-02"), []string{"Fresh", "Untracked"}},
+		{"created:7d", []string{"Fresh"}},
+		{"created:<" + now.Format("2006"), []string{"Stale"}},
+		{"archived:30d", []string{"Shelved"}},
+		{"archived:>30d", nil},
+		{"-created:7d -status:archived", []string{"Stale", "Untracked"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := engine.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			found := make(map[string]bool)
+			for _, result := range results {
+				found[result.Item.GetName()] = true
+			}
+			if len(results) != len(tt.expectedNames) {
+				t.Errorf("Expected %d results, got %d: %v", len(tt.expectedNames), len(results), found)
+			}
+			for _, name := range tt.expectedNames {
+				if !found[name] {
+					t.Errorf("Expected item '%s' not found in results", name)
+				}
+			}
+		})
+	}
+
+	if _, err := engine.Search("modified:someday"); err == nil {
+		t.Error("expected a syntax error for an invalid date")
+	}
+}
+
+func TestSearchEngine_SortFilter(t *testing.T) {
+	now := time.Now()
+	items := []*TestSearchableItem{
+		{name: "Beta", itemType: "component", subType: "prompts", tags: []string{"api"}, modified: now.AddDate(0, 0, -5), created: now.AddDate(0, 0, -1)},
+		{name: "Alpha", itemType: "component", subType: "prompts", tags: []string{"api"}, modified: now.AddDate(0, 0, -1), created: now.AddDate(0, 0, -9)},
+		{name: "Gamma", itemType: "component", subType: "prompts", tags: []string{"api"}, modified: now.AddDate(0, 0, -30), created: now.AddDate(0, 0, -3)},
+	}
+
+	engine := NewSearchEngine[*TestSearchableItem]()
+	engine.SetItems(items)
+
+	tests := []struct {
+		query    string
+		expected []string
+	}{
+		{"sort:name", []string{"Alpha", "Beta", "Gamma"}},
+		{"tag:api sort:-name", []string{"Gamma", "Beta", "Alpha"}},
+		{"sort:modified tag:api", []string{"Alpha", "Beta", "Gamma"}},
+		{"tag:api sort:-modified", []string{"Gamma", "Beta", "Alpha"}},
+		{"sort:created", []string{"Beta", "Gamma", "Alpha"}},
+		{"api sort:name", []string{"Alpha", "Beta", "Gamma"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := engine.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			var names []string
+			for _, result := range results {
+				names = append(names, result.Item.GetName())
+			}
+			if strings.Join(names, ",") != strings.Join(tt.expected, ",") {
+				t.Errorf("Search(%q) order = %v, want %v", tt.query, names, tt.expected)
+			}
+		})
+	}
+
+	if engine.options.SortBy != "relevance" {
+		t.Errorf("sort: should only apply to its own search, SortBy = %q", engine.options.SortBy)
+	}
+	if _, err := engine.Search("sort:size"); err == nil {
+		t.Error("expected a syntax error for an unknown sort")
+	}
+}
+
+func TestSearchEngine_NumericAndReferenceFilters(t *testing.T) {
+	items := []*TestSearchableItem{
+		{name: "API Docs", itemType: "component", subType: "contexts", path: "components/contexts/api-docs.md", tokenCount: 3200, usageCount: 2},
+		{name: "Style Guide", itemType: "component", subType: "rules", path: "components/rules/style.md", tokenCount: 400, usageCount: 0},
+		{name: "Review", itemType: "component", subType: "prompts", path: "components/prompts/review.md", tokenCount: 150, usageCount: 5},
+		{name: "Release", itemType: "pipeline", path: "release.yaml", tokenCount: 5000, components: []string{
+			"components/contexts/api-docs.md", "components/rules/style.md", "components/prompts/review.md",
+		}},
+		{name: "Hotfix", itemType: "pipeline", path: "hotfix.yaml", tokenCount: 900, broken: true, components: []string{
+			"components/contexts/backend/db.md",
+		}},
+	}
+
+	engine := NewSearchEngine[*TestSearchableItem]()
+	engine.SetItems(items)
+
+	tests := []struct {
+		query         string
+		expectedNames []string
+	}{
+		{"tokens:>2000", []string{"API Docs", "Release"}},
+		{"tokens:>2k type:context", []string{"API Docs"}},
+		{"tokens:<=400", []string{"Style Guide", "Review"}},
+		{"usage:0", []string{"Style Guide"}},
+		{"usage:>=2", []string{"API Docs", "Review"}},
+		{"components:>2", []string{"Release"}},
+		{"components:1", []string{"Hotfix"}},
+		{"uses:contexts/api-docs", []string{"Release"}},
+		{"uses:components/contexts/api-docs.md", []string{"Release"}},
+		{"uses:review", []string{"Release"}},
+		{"uses:contexts/backend/*", []string{"Hotfix"}},
+		{"broken:true", []string{"Hotfix"}},
+		{"broken:false", []string{"Release"}},
+		{"usage:0 OR tokens:>4000", []string{"Style Guide", "Release"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := engine.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			found := make(map[string]bool)
+			for _, result := range results {
+				found[result.Item.GetName()] = true
+			}
+			if len(results) != len(tt.expectedNames) {
+				t.Errorf("Expected %d results, got %d: %v", len(tt.expectedNames), len(results), found)
+			}
+			for _, name := range tt.expectedNames {
+				if !found[name] {
+					t.Errorf("Expected item '%s' not found in results", name)
+				}
+			}
+		})
+	}
+
+	for _, query := range []string{"tokens:lots", "broken:maybe"} {
+		if _, err := engine.Search(query); err == nil {
+			t.Errorf("expected a syntax error for %q", query)
+		}
+	}
+}
+
+func TestSearchEngine_RegexAndPhraseFilters(t *testing.T) {
+	items := []*TestSearchableItem{
+		{name: "api-docs", content: "# API\nTODO: document auth\nUses error handling middleware."},
+		{name: "api-client", content: "Retry 3 times, then FIXME."},
+		{name: "rest-api", content: "No terror handlings here.\nJust error-handling."},
+		{name: "style", content: "Write clear code.\nRun /usr/bin/env go."},
+	}
+
+	engine := NewSearchEngine[*TestSearchableItem]()
+	engine.SetItems(items)
+
+	tests := []struct {
+		query         string
+		expectedNames []string
+	}{
+		{"name:/^api-/", []string{"api-docs", "api-client"}},
+		{"content:/TODO|FIXME/", []string{"api-docs", "api-client"}},
+		{"content:/todo|fixme/", nil},
+		{"content:/todo|fixme/i", []string{"api-docs", "api-client"}},
+		{`content:/retry \d+ times/i`, []string{"api-client"}},
+		{`content:/^Just/m`, []string{"rest-api"}},
+		{"-content:/TODO|FIXME/ name:/api/", []string{"rest-api"}},
+		{"content:/usr/bin", []string{"style"}},
+		{`content:"error handling"`, []string{"api-docs"}},
+		{"content:error handling", []string{"api-docs", "rest-api"}},
+		{`"error handling"`, []string{"api-docs"}},
+		{`"error-handling"`, []string{"rest-api"}},
+		{`name:"api"`, []string{"api-docs", "api-client", "rest-api"}},
+		{`content:"rror"`, nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			results, err := engine.Search(tt.query)
+			if err != nil {
+				t.Fatalf("Search failed: %v", err)
+			}
+			var names []string
+			for _, result := range results {
+				names = append(names, result.Item.GetName())
+			}
+			sort.Strings(names)
+			expected := append([]string(nil), tt.expectedNames...)
+			sort.Strings(expected)
+			if strings.Join(names, ",") != strings.Join(expected, ",") {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, names, expected)
+			}
+		})
+	}
+
+	// Every match comes back as an excerpt with its line number
+	results, err := engine.Search("content:/TODO|error/")
+	if err != nil {
+		t.Fatalf("Search failed: %v", err)
+	}
+	for _, result := range results {
+		if result.Item.GetName() != "api-docs" {
+			continue
+		}
+		excerpts := result.Relevance.Excerpts
+		if len(excerpts) != 2 || excerpts[0].Line != 2 || excerpts[1].Line != 3 {
+			t.Fatalf("Excerpts = %+v, want lines 2 and 3", excerpts)
+		}
+		if r := excerpts[1].Ranges[0]; excerpts[1].Text[r.Start:r.End] != "error" {
+			t.Errorf("Excerpt range %v covers %q", r, excerpts[1].Text[r.Start:r.End])
+		}
+		if got := result.Relevance.Highlights["content"]; len(got) != 2 {
+			t.Errorf("Highlights = %v, want both lines", got)
+		}
+	}
+}
+
+func TestSearchEngine_ContentFiltersSkipNameAndTags(t *testing.T) {
+	engine := NewSearchEngine[*ComponentItemWrapper]()
+	engine.SetItems([]*ComponentItemWrapper{
+		NewComponentItemWrapper("style", "components/rules/style.md", "rules", time.Now(), 0, 0,
+			[]string{"api"}, false, "Follow the style guide.\nBe concise."),
+	})
+
+	for query, want := range map[string]int{
+		"content:api":       0, // Only a tag
+		"content:/^Follow/": 1, // The first line of the content
+		"content:concise":   1,
+	} {
+		results, err := engine.Search(query)
+		if err != nil {
+			t.Fatalf("Search(%q) failed: %v", query, err)
+		}
+		if len(results) != want {
+			t.Errorf("Search(%q) = %d results, want %d", query, len(results), want)
+		}
+	}
+
+	// Excerpts are lines of the content, not of the name and tags before it
+	results, err := engine.Search("concise")
+	if err != nil || len(results) != 1 {
+		t.Fatalf("Search(concise) = %v, %v", results, err)
+	}
+	excerpts := results[0].Relevance.Excerpts
+	if len(excerpts) != 1 || excerpts[0].Line != 2 || excerpts[0].Text != "Be concise." {
+		t.Errorf("Excerpts = %+v, want line 2, Be concise.", excerpts)
+	}
+	results, _ = engine.Search("style")
+	if excerpts := results[0].Relevance.Excerpts; len(excerpts) != 1 || excerpts[0].Text != "Follow the style guide." {
+		t.Errorf("Excerpts = %+v, want only the content line", excerpts)
+	}
+}
@@ -0,0 +1,110 @@
+package unified
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// numericFilterTypes are the filters whose values are counts
+var numericFilterTypes = map[string]bool{
+	"tokens":     true,
+	"usage":      true,
+	"components": true,
+}
+
+// numberRange is the inclusive range of counts matched by a numeric filter
+type numberRange struct {
+	Min int
+	Max int
+}
+
+func (r numberRange) contains(n int) bool {
+	return n >= r.Min && n <= r.Max
+}
+
+// parseNumericFilter parses the value of a tokens:, usage: or components: filter.
+//
+//	0, =3          exactly that many
+//	>2000, >=3     more than, at least
+//	<10, <=10      fewer than, at most
+//	100..500       a range; either end may be left open, as in 100..
+//
+// Numbers take an optional k suffix, so tokens:>2k is tokens:>2000.
+func parseNumericFilter(value string) (numberRange, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	open := numberRange{Min: math.MinInt, Max: math.MaxInt}
+
+	if from, to, isRange := strings.Cut(value, ".."); isRange {
+		if from == "" && to == "" {
+			return numberRange{}, numericFilterError(value)
+		}
+		r := open
+		var err error
+		if from != "" {
+			if r.Min, err = parseCount(from); err != nil {
+				return numberRange{}, err
+			}
+		}
+		if to != "" {
+			if r.Max, err = parseCount(to); err != nil {
+				return numberRange{}, err
+			}
+		}
+		if r.Min > r.Max {
+			r.Min, r.Max = r.Max, r.Min
+		}
+		return r, nil
+	}
+
+	for _, op := range []string{">=", "<=", ">", "<", "="} {
+		if !strings.HasPrefix(value, op) {
+			continue
+		}
+		n, err := parseCount(value[len(op):])
+		if err != nil {
+			return numberRange{}, err
+		}
+		r := open
+		switch op {
+		case ">=":
+			r.Min = n
+		case "<=":
+			r.Max = n
+		case ">":
+			r.Min = n + 1
+		case "<":
+			r.Max = n - 1
+		default:
+			r.Min, r.Max = n, n
+		}
+		return r, nil
+	}
+
+	n, err := parseCount(value)
+	if err != nil {
+		return numberRange{}, err
+	}
+	return numberRange{Min: n, Max: n}, nil
+}
+
+// parseCount parses a non-negative count such as 300 or 2k
+func parseCount(value string) (int, error) {
+	multiplier := 1
+	digits := value
+	if strings.HasSuffix(digits, "k") {
+		multiplier = 1000
+		digits = strings.TrimSuffix(digits, "k")
+	}
+
+	n, err := strconv.Atoi(digits)
+	if err != nil || n < 0 {
+		return 0, numericFilterError(value)
+	}
+	return n * multiplier, nil
+}
+
+func numericFilterError(value string) error {
+	return fmt.Errorf("invalid number %q; use a count such as 10, >2000, <=3 or 100..500", value)
+}
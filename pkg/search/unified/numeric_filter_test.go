@@ -0,0 +1,43 @@
+package unified
+
+import (
+	"math"
+	"testing"
+)
+
+func TestParseNumericFilter(t *testing.T) {
+	tests := []struct {
+		value    string
+		expected numberRange
+	}{
+		{"0", numberRange{Min: 0, Max: 0}},
+		{"=3", numberRange{Min: 3, Max: 3}},
+		{">2000", numberRange{Min: 2001, Max: math.MaxInt}},
+		{">=3", numberRange{Min: 3, Max: math.MaxInt}},
+		{"<10", numberRange{Min: math.MinInt, Max: 9}},
+		{"<=10", numberRange{Min: math.MinInt, Max: 10}},
+		{">2k", numberRange{Min: 2001, Max: math.MaxInt}},
+		{"100..500", numberRange{Min: 100, Max: 500}},
+		{"500..100", numberRange{Min: 100, Max: 500}},
+		{"100..", numberRange{Min: 100, Max: math.MaxInt}},
+		{"..5", numberRange{Min: math.MinInt, Max: 5}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, err := parseNumericFilter(tt.value)
+			if err != nil {
+				t.Fatalf("parseNumericFilter(%q) error = %v", tt.value, err)
+			}
+			if got != tt.expected {
+				t.Errorf("parseNumericFilter(%q) = %+v, want %+v", tt.value, got, tt.expected)
+			}
+		})
+	}
+
+	for _, value := range []string{"many", ">", "-3", "..", "1.5", "2m"} {
+		if _, err := parseNumericFilter(value); err == nil {
+			t.Errorf("parseNumericFilter(%q) expected an error", value)
+		}
+	}
+}
@@ -0,0 +1,558 @@
+package unified
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// QueryNode is a node of a parsed boolean search expression
+type QueryNode interface {
+	String() string
+}
+
+// AndNode matches items that match all of its children
+type AndNode struct {
+	Children []QueryNode
+}
+
+// OrNode matches items that match any of its children
+type OrNode struct {
+	Children []QueryNode
+}
+
+// NotNode matches items that don't match its child
+type NotNode struct {
+	Child QueryNode
+}
+
+// FilterNode matches a single field filter such as tag:api
+type FilterNode struct {
+	Filter  QueryFilter
+	Pos     int            // Byte offset of the filter in the query
+	Pattern *regexp.Regexp // Compiled value of a content:/pattern/ or name:/pattern/ filter
+}
+
+// TextNode matches free text. Adjacent words without an operator between them
+// are kept together and ranked like a plain text search, matching any of them;
+// a quoted phrase is a single term that must appear as written.
+type TextNode struct {
+	Terms  []string
+	Phrase bool
+	Pos    int
+}
+
+func (n *AndNode) String() string { return joinNodes(n.Children, " AND ") }
+func (n *OrNode) String() string  { return joinNodes(n.Children, " OR ") }
+func (n *NotNode) String() string { return "NOT " + joinNodes([]QueryNode{n.Child}, "") }
+
+func (n *FilterNode) String() string {
+	if n.Pattern != nil {
+		return n.Filter.Type + ":" + n.Filter.Value
+	}
+	if n.Filter.Quoted {
+		return n.Filter.Type + ":" + `"` + n.Filter.Value + `"`
+	}
+	return n.Filter.Type + ":" + quoteIfNeeded(n.Filter.Value)
+}
+
+func (n *TextNode) String() string {
+	if n.Phrase {
+		return `"` + n.Terms[0] + `"`
+	}
+	return strings.Join(n.Terms, " ")
+}
+
+func joinNodes(nodes []QueryNode, sep string) string {
+	parts := make([]string, len(nodes))
+	for i, node := range nodes {
+		parts[i] = node.String()
+		switch node.(type) {
+		case *AndNode, *OrNode:
+			parts[i] = "(" + parts[i] + ")"
+		}
+	}
+	return strings.Join(parts, sep)
+}
+
+func quoteIfNeeded(value string) string {
+	if strings.ContainsAny(value, " \t()") {
+		return `"` + value + `"`
+	}
+	return value
+}
+
+// QuerySyntaxError describes a malformed search query and where it went wrong
+type QuerySyntaxError struct {
+	Query   string
+	Pos     int    // Byte offset of the offending token
+	Token   string // The offending token, empty at the end of the query
+	Message string
+}
+
+func (e *QuerySyntaxError) Error() string {
+	if e.Token == "" {
+		return fmt.Sprintf("%s at end of query", e.Message)
+	}
+	return fmt.Sprintf("%s at column %d", e.Message, e.Column())
+}
+
+// Column returns the 1-based column of the offending token
+func (e *QuerySyntaxError) Column() int {
+	return len([]rune(e.Query[:e.Pos])) + 1
+}
+
+// Pointer returns the query with a caret line underneath marking the offending token
+func (e *QuerySyntaxError) Pointer() string {
+	width := len([]rune(e.Token))
+	if width == 0 {
+		width = 1
+	}
+	return e.Query + "\n" + strings.Repeat(" ", e.Column()-1) + strings.Repeat("^", width)
+}
+
+// queryTokenKind identifies the kind of a query token
+type queryTokenKind int
+
+const (
+	tokenWord queryTokenKind = iota
+	tokenPhrase
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenMinus
+	tokenLParen
+	tokenRParen
+	tokenEOF
+)
+
+// queryToken is a lexical token of a search query
+type queryToken struct {
+	kind queryTokenKind
+	text string // Raw text as written in the query
+	pos  int
+}
+
+// tokenizeQuery splits a query into words, quoted phrases, operators and parentheses
+func tokenizeQuery(query string) ([]queryToken, error) {
+	var tokens []queryToken
+	runes := []rune(query)
+	offsets := make([]int, len(runes)+1)
+	for i, pos := 0, 0; i < len(runes); i++ {
+		offsets[i] = pos
+		pos += len(string(runes[i]))
+		offsets[i+1] = pos
+	}
+
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		start := i
+		switch {
+		case unicode.IsSpace(r):
+			i++
+			continue
+		case r == '(':
+			tokens = append(tokens, queryToken{kind: tokenLParen, text: "(", pos: offsets[i]})
+			i++
+			continue
+		case r == ')':
+			tokens = append(tokens, queryToken{kind: tokenRParen, text: ")", pos: offsets[i]})
+			i++
+			continue
+		case r == '-' && i+1 < len(runes) && !unicode.IsSpace(runes[i+1]) && runes[i+1] != ')':
+			tokens = append(tokens, queryToken{kind: tokenMinus, text: "-", pos: offsets[i]})
+			i++
+			continue
+		case r == '"' || r == '\'':
+			end := indexRune(runes, r, i+1)
+			if end < 0 {
+				return nil, &QuerySyntaxError{Query: query, Pos: offsets[i], Token: string(r), Message: "unterminated quote"}
+			}
+			tokens = append(tokens, queryToken{kind: tokenPhrase, text: string(runes[i : end+1]), pos: offsets[i]})
+			i = end + 1
+			continue
+		}
+
+		// A word runs until whitespace or a parenthesis; quotes inside it, as in
+		// content:"error handling", extend it to the closing quote, as does the
+		// closing slash of a regular expression such as content:/a (b|c)/i
+		for i < len(runes) && !unicode.IsSpace(runes[i]) && runes[i] != '(' && runes[i] != ')' {
+			if (runes[i] == '"' || runes[i] == '\'') && i > start && runes[i-1] == ':' {
+				end := indexRune(runes, runes[i], i+1)
+				if end < 0 {
+					return nil, &QuerySyntaxError{Query: query, Pos: offsets[i], Token: string(runes[i]), Message: "unterminated quote"}
+				}
+				i = end + 1
+				continue
+			}
+			if runes[i] == '/' && i > start && runes[i-1] == ':' && regexFilterTypes[strings.ToLower(string(runes[start:i-1]))] {
+				if end := regexEnd(runes, i); end > 0 {
+					i = end + 1
+					continue
+				}
+			}
+			i++
+		}
+
+		text := string(runes[start:i])
+		kind := tokenWord
+		switch text {
+		case "AND":
+			kind = tokenAnd
+		case "OR":
+			kind = tokenOr
+		case "NOT":
+			kind = tokenNot
+		}
+		tokens = append(tokens, queryToken{kind: kind, text: text, pos: offsets[start]})
+	}
+
+	tokens = append(tokens, queryToken{kind: tokenEOF, pos: len(query)})
+	return tokens, nil
+}
+
+func indexRune(runes []rune, r rune, from int) int {
+	for i := from; i < len(runes); i++ {
+		if runes[i] == r {
+			return i
+		}
+	}
+	return -1
+}
+
+// ParseQueryExpression parses a search query into a boolean expression tree.
+//
+// Terms are field filters (tag:api), words and quoted phrases. They combine with
+// AND, OR and NOT (or a leading -), and parentheses group them. Terms next to each
+// other are ANDed together, except that plain words typed next to each other form
+// one free text term. AND binds tighter than OR:
+//
+//	tag:api type:context
+//	tag:api AND (type:prompt OR type:rules) -status:archived
+//	"error handling" NOT tag:deprecated
+//
+// An empty query parses to a nil node. Malformed queries return a *QuerySyntaxError.
+func ParseQueryExpression(query string) (QueryNode, error) {
+	tokens, err := tokenizeQuery(query)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &queryExprParser{query: query, tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, nil
+	}
+
+	node, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if tok := p.peek(); tok.kind != tokenEOF {
+		return nil, p.errorAt(tok, fmt.Sprintf("unexpected %q", tok.text))
+	}
+	return node, nil
+}
+
+// queryExprParser is a recursive descent parser over query tokens
+type queryExprParser struct {
+	query  string
+	tokens []queryToken
+	pos    int
+}
+
+func (p *queryExprParser) peek() queryToken {
+	return p.tokens[p.pos]
+}
+
+func (p *queryExprParser) next() queryToken {
+	tok := p.tokens[p.pos]
+	if tok.kind != tokenEOF {
+		p.pos++
+	}
+	return tok
+}
+
+func (p *queryExprParser) errorAt(tok queryToken, message string) error {
+	return &QuerySyntaxError{Query: p.query, Pos: tok.pos, Token: tok.text, Message: message}
+}
+
+// parseOr parses: and ("OR" and)*
+func (p *queryExprParser) parseOr() (QueryNode, error) {
+	first, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+
+	children := []QueryNode{first}
+	for p.peek().kind == tokenOr {
+		op := p.next()
+		if err := p.expectOperand(op); err != nil {
+			return nil, err
+		}
+		child, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		children = append(children, child)
+	}
+
+	if len(children) == 1 {
+		return first, nil
+	}
+	return &OrNode{Children: children}, nil
+}
+
+// parseAnd parses: unary (["AND"] unary)*
+func (p *queryExprParser) parseAnd() (QueryNode, error) {
+	if err := p.expectOperand(queryToken{}); err != nil {
+		return nil, err
+	}
+
+	var children []QueryNode
+	explicit := false
+	for {
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+
+		// Words typed next to each other stay one free text term
+		if text, ok := child.(*TextNode); ok && !text.Phrase && !explicit && len(children) > 0 {
+			if prev, ok := children[len(children)-1].(*TextNode); ok && !prev.Phrase {
+				prev.Terms = append(prev.Terms, text.Terms...)
+				child = nil
+			}
+		}
+		if child != nil {
+			children = append(children, child)
+		}
+
+		explicit = false
+		switch p.peek().kind {
+		case tokenAnd:
+			op := p.next()
+			if err := p.expectOperand(op); err != nil {
+				return nil, err
+			}
+			explicit = true
+			continue
+		case tokenOr, tokenRParen, tokenEOF:
+		default:
+			continue
+		}
+		break
+	}
+
+	if len(children) == 1 {
+		return children[0], nil
+	}
+	return &AndNode{Children: children}, nil
+}
+
+// expectOperand reports an error when the next token can't start a term. op is
+// the operator that needs the operand, if any.
+func (p *queryExprParser) expectOperand(op queryToken) error {
+	tok := p.peek()
+	switch tok.kind {
+	case tokenAnd, tokenOr, tokenRParen:
+		if op.text != "" {
+			return p.errorAt(tok, fmt.Sprintf("unexpected %q after %q", tok.text, op.text))
+		}
+		if tok.kind == tokenRParen {
+			return p.errorAt(tok, `unexpected ")"`)
+		}
+		return p.errorAt(tok, fmt.Sprintf("expected a search term before %q", tok.text))
+	case tokenEOF:
+		if op.text != "" {
+			return p.errorAt(op, fmt.Sprintf("expected a search term after %q", op.text))
+		}
+	}
+	return nil
+}
+
+// parseUnary parses: ("NOT" | "-") unary | primary
+func (p *queryExprParser) parseUnary() (QueryNode, error) {
+	if kind := p.peek().kind; kind == tokenNot || kind == tokenMinus {
+		op := p.next()
+		if err := p.expectOperand(op); err != nil {
+			return nil, err
+		}
+		child, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return &NotNode{Child: child}, nil
+	}
+	return p.parsePrimary()
+}
+
+// parsePrimary parses: "(" or-expression ")" | phrase | word
+func (p *queryExprParser) parsePrimary() (QueryNode, error) {
+	tok := p.next()
+	switch tok.kind {
+	case tokenLParen:
+		if p.peek().kind == tokenRParen {
+			return nil, p.errorAt(p.peek(), "empty parentheses")
+		}
+		node, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if p.peek().kind != tokenRParen {
+			return nil, p.errorAt(tok, `unclosed "("`)
+		}
+		p.next()
+		return node, nil
+
+	case tokenPhrase:
+		phrase := tok.text[1 : len(tok.text)-1]
+		if strings.TrimSpace(phrase) == "" {
+			return nil, p.errorAt(tok, "empty phrase")
+		}
+		return &TextNode{Terms: []string{phrase}, Phrase: true, Pos: tok.pos}, nil
+
+	case tokenWord:
+		if filter, ok := parseFilterTerm(tok.text); ok {
+			if filter.Value == "" {
+				return nil, p.errorAt(tok, fmt.Sprintf("missing value for %q", tok.text))
+			}
+			if err := validateFilter(filter); err != nil {
+				return nil, p.errorAt(tok, err.Error())
+			}
+			node := &FilterNode{Filter: filter, Pos: tok.pos}
+			if regexFilterTypes[filter.Type] && !filter.Quoted && isRegexValue(filter.Value) {
+				node.Pattern, _ = parseRegexFilter(filter.Value) // Already validated
+			}
+			return node, nil
+		}
+		return &TextNode{Terms: []string{tok.text}, Pos: tok.pos}, nil
+	}
+
+	return nil, p.errorAt(tok, fmt.Sprintf("unexpected %q", tok.text))
+}
+
+// parseFilterTerm splits a field filter such as tag:api or content:"error handling"
+// into its type and value. Words with an unknown prefix aren't filters.
+func parseFilterTerm(word string) (QueryFilter, bool) {
+	lower := strings.ToLower(word)
+	for _, prefix := range filterPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			value := strings.TrimSpace(word[len(prefix):])
+			quoted := false
+			if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') && value[len(value)-1] == value[0] {
+				value = value[1 : len(value)-1]
+				quoted = true
+			}
+			return QueryFilter{Type: strings.TrimSuffix(prefix, ":"), Value: value, Quoted: quoted}, true
+		}
+	}
+	return QueryFilter{}, false
+}
+
+// sortFields are the values accepted by sort:, each of which may be prefixed
+// with "-" to reverse the order
+var sortFields = []string{"relevance", "name", "modified", "created", "usage"}
+
+// validateFilter checks filter values that have a fixed syntax
+func validateFilter(filter QueryFilter) error {
+	if dateFilterTypes[filter.Type] {
+		_, err := parseDateFilter(filter.Value, time.Now())
+		return err
+	}
+	if numericFilterTypes[filter.Type] {
+		_, err := parseNumericFilter(filter.Value)
+		return err
+	}
+	if regexFilterTypes[filter.Type] && !filter.Quoted && isRegexValue(filter.Value) {
+		_, err := parseRegexFilter(filter.Value)
+		return err
+	}
+	if filter.Type == "broken" {
+		if _, err := strconv.ParseBool(filter.Value); err != nil {
+			return fmt.Errorf("invalid value %q for broken:; use true or false", filter.Value)
+		}
+	}
+	if filter.Type == "sort" {
+		field := strings.TrimPrefix(strings.ToLower(filter.Value), "-")
+		for _, valid := range sortFields {
+			if field == valid {
+				return nil
+			}
+		}
+		return fmt.Errorf("unknown sort %q; use %s", filter.Value, strings.Join(sortFields, ", "))
+	}
+	return nil
+}
+
+// splitQuerySort removes sort: terms from an expression, returning what's left
+// (nil if nothing) and the last sort order asked for
+func splitQuerySort(node QueryNode) (QueryNode, string) {
+	switch n := node.(type) {
+	case *FilterNode:
+		if n.Filter.Type == "sort" {
+			return nil, strings.ToLower(n.Filter.Value)
+		}
+	case *AndNode:
+		children, sortBy := splitSortChildren(n.Children)
+		return collapseNodes(children, func(c []QueryNode) QueryNode { return &AndNode{Children: c} }), sortBy
+	case *OrNode:
+		children, sortBy := splitSortChildren(n.Children)
+		return collapseNodes(children, func(c []QueryNode) QueryNode { return &OrNode{Children: c} }), sortBy
+	case *NotNode:
+		child, sortBy := splitQuerySort(n.Child)
+		if child == nil {
+			return nil, sortBy
+		}
+		return &NotNode{Child: child}, sortBy
+	}
+	return node, ""
+}
+
+func splitSortChildren(nodes []QueryNode) ([]QueryNode, string) {
+	var children []QueryNode
+	sortBy := ""
+	for _, child := range nodes {
+		remaining, childSort := splitQuerySort(child)
+		if childSort != "" {
+			sortBy = childSort
+		}
+		if remaining != nil {
+			children = append(children, remaining)
+		}
+	}
+	return children, sortBy
+}
+
+func collapseNodes(children []QueryNode, combine func([]QueryNode) QueryNode) QueryNode {
+	switch len(children) {
+	case 0:
+		return nil
+	case 1:
+		return children[0]
+	}
+	return combine(children)
+}
+
+// walkQuery calls fn for every node of the expression; negated is true for nodes
+// under an odd number of NOTs
+func walkQuery(node QueryNode, negated bool, fn func(node QueryNode, negated bool)) {
+	if node == nil {
+		return
+	}
+	fn(node, negated)
+	switch n := node.(type) {
+	case *AndNode:
+		for _, child := range n.Children {
+			walkQuery(child, negated, fn)
+		}
+	case *OrNode:
+		for _, child := range n.Children {
+			walkQuery(child, negated, fn)
+		}
+	case *NotNode:
+		walkQuery(n.Child, !negated, fn)
+	}
+}
@@ -0,0 +1,104 @@
+package unified
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseQueryExpression(t *testing.T) {
+	tests := []struct {
+		query    string
+		expected string // String() of the parsed tree
+	}{
+		{"tag:api", "tag:api"},
+		{"tag:api type:context", "tag:api AND type:context"},
+		{"tag:api AND type:context", "tag:api AND type:context"},
+		{"tag:api OR tag:rest type:prompt", "tag:api OR (tag:rest AND type:prompt)"},
+		{"(tag:api OR tag:rest) type:prompt", "(tag:api OR tag:rest) AND type:prompt"},
+		{"NOT tag:draft", "NOT tag:draft"},
+		{"-tag:draft type:rules", "NOT tag:draft AND type:rules"},
+		{"-(tag:a OR tag:b)", "NOT (tag:a OR tag:b)"},
+		{`content:"error handling" tag:api`, `content:"error handling" AND tag:api`},
+		{`"error handling" -deprecated`, `"error handling" AND NOT deprecated`},
+		{"error handling tag:api", "error handling AND tag:api"},
+		{"error AND handling", "error AND handling"},
+		{"api-docs", "api-docs"},
+		{"and or not", "and or not"},
+		{"foo:bar", "foo:bar"},
+		{"content:/a (b|c)/i tag:api", "content:/a (b|c)/i AND tag:api"},
+		{`name:/^api-/ OR content:"log"`, `name:/^api-/ OR content:"log"`},
+		{"content:/etc type:rules", "content:/etc AND type:rules"},
+		{"content:/usr/bin type:rules", "content:/usr/bin AND type:rules"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			node, err := ParseQueryExpression(tt.query)
+			if err != nil {
+				t.Fatalf("ParseQueryExpression(%q) error = %v", tt.query, err)
+			}
+			if got := node.String(); got != tt.expected {
+				t.Errorf("ParseQueryExpression(%q) = %s, want %s", tt.query, got, tt.expected)
+			}
+		})
+	}
+
+	node, err := ParseQueryExpression("   ")
+	if node != nil || err != nil {
+		t.Errorf("blank query = %v, %v; want nil, nil", node, err)
+	}
+}
+
+func TestParseQueryExpressionErrors(t *testing.T) {
+	tests := []struct {
+		query   string
+		message string
+		column  int
+		token   string
+	}{
+		{"tag:api AND", `expected a search term after "AND"`, 9, "AND"},
+		{"OR tag:api", `expected a search term before "OR"`, 1, "OR"},
+		{"tag:api AND OR type:prompt", `unexpected "OR" after "AND"`, 13, "OR"},
+		{"(tag:api OR tag:rest", `unclosed "("`, 1, "("},
+		{"tag:api )", `unexpected ")"`, 9, ")"},
+		{"tag:api ()", "empty parentheses", 10, ")"},
+		{`content:"error handling`, "unterminated quote", 9, `"`},
+		{"tag: type:prompt", `missing value for "tag:"`, 1, "tag:"},
+		{"NOT", `expected a search term after "NOT"`, 1, "NOT"},
+		{"tag:api modified:soon", `invalid date "soon"; use YYYY-MM-DD, today, yesterday or an age such as 7d`, 9, "modified:soon"},
+		{"sort:size", `unknown sort "size"; use relevance, name, modified, created, usage`, 1, "sort:size"},
+		{"tag:api content:/a(b/", "invalid regular expression /a(b/: missing closing )", 9, "content:/a(b/"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			_, err := ParseQueryExpression(tt.query)
+			var syntaxErr *QuerySyntaxError
+			if !errors.As(err, &syntaxErr) {
+				t.Fatalf("ParseQueryExpression(%q) error = %v, want a QuerySyntaxError", tt.query, err)
+			}
+			if syntaxErr.Message != tt.message {
+				t.Errorf("Message = %q, want %q", syntaxErr.Message, tt.message)
+			}
+			if syntaxErr.Column() != tt.column || syntaxErr.Token != tt.token {
+				t.Errorf("error at column %d on %q, want column %d on %q", syntaxErr.Column(), syntaxErr.Token, tt.column, tt.token)
+			}
+		})
+	}
+}
+
+func TestQuerySyntaxErrorPointer(t *testing.T) {
+	_, err := ParseQueryExpression("tag:api AND ) type:prompt")
+	var syntaxErr *QuerySyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Fatalf("expected a QuerySyntaxError, got %v", err)
+	}
+
+	expected := "tag:api AND ) type:prompt\n            ^"
+	if got := syntaxErr.Pointer(); got != expected {
+		t.Errorf("Pointer() =\n%s\nwant\n%s", got, expected)
+	}
+	if got := syntaxErr.Error(); got != `unexpected ")" after "AND" at column 13` {
+		t.Errorf("Error() = %q", got)
+	}
+}
@@ -6,8 +6,9 @@ import (
 
 // QueryFilter represents a single filter in a search query
 type QueryFilter struct {
-	Type  string // "tag", "type", "status", "name", "content"
-	Value string
+	Type   string // "tag", "type", "status", "name", "content", "path"
+	Value  string
+	Quoted bool // The value was quoted, so name: and content: match it as a phrase
 }
 
 // ParsedQuery represents a parsed search query with multiple filters
@@ -16,6 +17,9 @@ type ParsedQuery struct {
 	FreeText string // Any remaining text that isn't part of a filter
 }
 
+// filterPrefixes are the field filters supported in search queries
+var filterPrefixes = []string{"tag:", "type:", "status:", "name:", "content:", "modified:", "created:", "archived:", "path:", "tokens:", "usage:", "components:", "uses:", "broken:", "sort:"}
+
 // ParseQuery parses a search query into structured filters
 // Example: "tag:tui content:coding" returns two filters
 func ParseQuery(query string) *ParsedQuery {
@@ -26,9 +30,6 @@ func ParseQuery(query string) *ParsedQuery {
 	// Track what parts of the query have been processed
 	processedParts := make(map[string]bool)
 	
-	// Define the filter prefixes we support
-	filterPrefixes := []string{"tag:", "type:", "status:", "name:", "content:", "modified:"}
-	
 	// Split query into parts but preserve quoted strings
 	parts := splitQueryPreservingQuotes(query)
 	
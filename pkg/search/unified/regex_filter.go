@@ -0,0 +1,90 @@
+package unified
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"regexp/syntax"
+	"strings"
+)
+
+// regexFilterTypes are the filters whose value may be a /pattern/flags regular expression
+var regexFilterTypes = map[string]bool{
+	"content": true,
+	"name":    true,
+}
+
+// regexFlags are the flags allowed after the closing slash; they map onto Go's
+// (?flags) syntax
+const regexFlags = "imsU"
+
+// isRegexValue reports whether a filter value is written as /pattern/flags. A
+// value without a closing slash, such as /etc, or whose last slash isn't
+// followed by flags, such as /usr/bin, is a plain value.
+func isRegexValue(value string) bool {
+	if len(value) < 2 || value[0] != '/' {
+		return false
+	}
+	end := strings.LastIndex(value, "/")
+	if end == 0 {
+		return false
+	}
+	for _, r := range value[end+1:] {
+		if !strings.ContainsRune(regexFlags, r) {
+			return false
+		}
+	}
+	return true
+}
+
+// regexEnd returns the index of the slash closing a regular expression that
+// opens at runes[open], skipping escaped slashes, or -1 if there isn't one
+func regexEnd(runes []rune, open int) int {
+	for i := open + 1; i < len(runes); i++ {
+		switch runes[i] {
+		case '\\':
+			i++
+		case '/':
+			return i
+		}
+	}
+	return -1
+}
+
+// parseRegexFilter compiles a /pattern/flags value as a Go regular expression.
+//
+//	content:/TODO|FIXME/       lines mentioning either
+//	content:/error handling/i  i ignores case, m makes ^ and $ match at lines,
+//	                           s lets . match newlines and U makes repeats lazy
+//	name:/^api-/               names starting with api-
+func parseRegexFilter(value string) (*regexp.Regexp, error) {
+	end := strings.LastIndex(value, "/")
+	pattern, flags := value[1:end], value[end+1:]
+	if pattern == "" {
+		return nil, fmt.Errorf("empty regular expression")
+	}
+
+	if flags != "" {
+		pattern = "(?" + flags + ")" + pattern
+	}
+	re, err := regexp.Compile(pattern)
+	if err != nil {
+		var syntaxErr *syntax.Error
+		if errors.As(err, &syntaxErr) {
+			return nil, fmt.Errorf("invalid regular expression %s: %s", value, syntaxErr.Code)
+		}
+		return nil, fmt.Errorf("invalid regular expression %s: %w", value, err)
+	}
+	return re, nil
+}
+
+// findRegex returns the spans of text matched by re, skipping empty matches
+func findRegex(text string, re *regexp.Regexp) []MatchRange {
+	var ranges []MatchRange
+	for _, loc := range re.FindAllStringIndex(text, -1) {
+		if loc[1] > loc[0] {
+			ranges = append(ranges, MatchRange{Start: loc[0], End: loc[1]})
+		}
+	}
+	return ranges
+}
@@ -0,0 +1,224 @@
+package unified
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+)
+
+const (
+	SavedSearchesFile = "searches.yaml"
+)
+
+// SavedSearch is a named query shared by everyone working in the project.
+// Queries refer to it as @name.
+type SavedSearch struct {
+	Name        string `yaml:"name" json:"name"`
+	Query       string `yaml:"query" json:"query"`
+	Description string `yaml:"description,omitempty" json:"description,omitempty"`
+}
+
+// savedSearchesFile is the layout of .pluqqy/searches.yaml
+type savedSearchesFile struct {
+	Searches []SavedSearch `yaml:"searches"`
+}
+
+// savedSearchNameRegex matches valid saved search names, such as api-contexts
+var savedSearchNameRegex = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// savedSearchRefRegex matches @name references at the start of a word. A
+// reference must also end the word, which isSavedSearchRefEnd checks, so that
+// @docs/api.md is left as a file reference.
+var savedSearchRefRegex = regexp.MustCompile(`(^|[\s(-])@([A-Za-z0-9][A-Za-z0-9_-]*)`)
+
+// isSavedSearchRefEnd reports whether a reference ending at end in query ends the word
+func isSavedSearchRefEnd(query string, end int) bool {
+	return end == len(query) || strings.ContainsRune(" \t\n)", rune(query[end]))
+}
+
+// ValidateSavedSearchName checks that a name can be used as @name in a query
+func ValidateSavedSearchName(name string) error {
+	if !savedSearchNameRegex.MatchString(name) {
+		return fmt.Errorf("invalid saved search name %q; use lowercase letters, numbers, - and _", name)
+	}
+	return nil
+}
+
+// LoadSavedSearches reads the project's saved searches, sorted by name. A missing
+// file means there are none.
+func LoadSavedSearches() ([]SavedSearch, error) {
+	data, err := os.ReadFile(filepath.Join(files.PluqqyDir, SavedSearchesFile))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read saved searches: %w", err)
+	}
+
+	var saved savedSearchesFile
+	if err := yaml.Unmarshal(data, &saved); err != nil {
+		return nil, fmt.Errorf("failed to parse saved searches: %w", err)
+	}
+
+	sort.Slice(saved.Searches, func(i, j int) bool {
+		return saved.Searches[i].Name < saved.Searches[j].Name
+	})
+	return saved.Searches, nil
+}
+
+// writeSavedSearches replaces the project's saved searches
+func writeSavedSearches(searches []SavedSearch) error {
+	sort.Slice(searches, func(i, j int) bool {
+		return searches[i].Name < searches[j].Name
+	})
+
+	data, err := yaml.Marshal(savedSearchesFile{Searches: searches})
+	if err != nil {
+		return fmt.Errorf("failed to marshal saved searches: %w", err)
+	}
+
+	// Write atomically
+	path := filepath.Join(files.PluqqyDir, SavedSearchesFile)
+	tmpFile := path + ".tmp"
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write saved searches: %w", err)
+	}
+	if err := os.Rename(tmpFile, path); err != nil {
+		os.Remove(tmpFile) // Clean up temp file
+		return fmt.Errorf("failed to save saved searches: %w", err)
+	}
+	return nil
+}
+
+// SaveSearch adds a saved search or replaces the one with the same name. The
+// query must parse, including any saved searches it refers to.
+func SaveSearch(search SavedSearch) error {
+	search.Name = strings.ToLower(strings.TrimPrefix(search.Name, "@"))
+	search.Query = strings.TrimSpace(search.Query)
+	if err := ValidateSavedSearchName(search.Name); err != nil {
+		return err
+	}
+	if search.Query == "" {
+		return fmt.Errorf("saved search %q needs a query", search.Name)
+	}
+
+	searches, err := LoadSavedSearches()
+	if err != nil {
+		return err
+	}
+
+	replaced := false
+	for i, existing := range searches {
+		if existing.Name == search.Name {
+			searches[i] = search
+			replaced = true
+			break
+		}
+	}
+	if !replaced {
+		searches = append(searches, search)
+	}
+
+	// Check the query against the updated set, so it can't refer to itself
+	expanded, err := expandSavedSearches(search.Query, searches, nil)
+	if err != nil {
+		return err
+	}
+	if _, err := ParseQueryExpression(expanded); err != nil {
+		return fmt.Errorf("invalid query for saved search %q: %w", search.Name, err)
+	}
+
+	return writeSavedSearches(searches)
+}
+
+// DeleteSavedSearch removes a saved search by name
+func DeleteSavedSearch(name string) error {
+	name = strings.ToLower(strings.TrimPrefix(name, "@"))
+
+	searches, err := LoadSavedSearches()
+	if err != nil {
+		return err
+	}
+
+	for i, existing := range searches {
+		if existing.Name == name {
+			return writeSavedSearches(append(searches[:i], searches[i+1:]...))
+		}
+	}
+	return fmt.Errorf("saved search %q not found", name)
+}
+
+// FindSavedSearch looks up a saved search by name, with or without its @
+func FindSavedSearch(searches []SavedSearch, name string) (SavedSearch, bool) {
+	name = strings.ToLower(strings.TrimPrefix(name, "@"))
+	for _, search := range searches {
+		if search.Name == name {
+			return search, true
+		}
+	}
+	return SavedSearch{}, false
+}
+
+// ExpandSavedSearches replaces each @name in a query with the saved search's
+// query in parentheses, so @api-contexts -tag:draft narrows a saved search.
+// An @word that isn't a saved search, such as @param, is left as text to search
+// for. Queries without an @name are returned unchanged without reading the
+// project. Expand a query once, where it's entered, and search with the result.
+func ExpandSavedSearches(query string) (string, error) {
+	if !savedSearchRefRegex.MatchString(query) {
+		return query, nil
+	}
+
+	searches, err := LoadSavedSearches()
+	if err != nil {
+		return query, err
+	}
+	return expandSavedSearches(query, searches, nil)
+}
+
+// ExpandSavedSearchesWith is ExpandSavedSearches with the project's saved
+// searches already loaded, for callers that keep them between queries
+func ExpandSavedSearchesWith(query string, searches []SavedSearch) (string, error) {
+	return expandSavedSearches(query, searches, nil)
+}
+
+// expandSavedSearches expands @name references against searches. expanding holds
+// the saved searches being expanded, to catch ones that refer to themselves.
+func expandSavedSearches(query string, searches []SavedSearch, expanding []string) (string, error) {
+	var expanded strings.Builder
+	last := 0
+	for _, match := range savedSearchRefRegex.FindAllStringSubmatchIndex(query, -1) {
+		// match holds the whole reference, then the prefix and the name
+		nameStart, end := match[4], match[5]
+		if !isSavedSearchRefEnd(query, end) {
+			continue
+		}
+		name := strings.ToLower(query[nameStart:end])
+		search, ok := FindSavedSearch(searches, name)
+		if !ok {
+			continue
+		}
+		for _, seen := range expanding {
+			if seen == name {
+				return query, fmt.Errorf("saved search @%s refers to itself", name)
+			}
+		}
+
+		inner, err := expandSavedSearches(search.Query, searches, append(expanding, name))
+		if err != nil {
+			return query, err
+		}
+		expanded.WriteString(query[last : nameStart-1])
+		expanded.WriteString("(" + inner + ")")
+		last = end
+	}
+	expanded.WriteString(query[last:])
+	return expanded.String(), nil
+}
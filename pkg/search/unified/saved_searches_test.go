@@ -0,0 +1,187 @@
+package unified
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestSavedSearches(t *testing.T) {
+	// Create a temporary directory and change to it
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatalf("Failed to init project structure: %v", err)
+	}
+
+	searches, err := LoadSavedSearches()
+	if err != nil || len(searches) != 0 {
+		t.Fatalf("LoadSavedSearches() = %v, %v; want none", searches, err)
+	}
+
+	if err := SaveSearch(SavedSearch{Name: "api-contexts", Query: "tag:api type:context -status:archived"}); err != nil {
+		t.Fatalf("SaveSearch() error = %v", err)
+	}
+	if err := SaveSearch(SavedSearch{Name: "@Drafts", Query: "tag:draft", Description: "Work in progress"}); err != nil {
+		t.Fatalf("SaveSearch() error = %v", err)
+	}
+
+	searches, _ = LoadSavedSearches()
+	var names []string
+	for _, s := range searches {
+		names = append(names, s.Name)
+	}
+	if want := []string{"api-contexts", "drafts"}; !reflect.DeepEqual(names, want) {
+		t.Errorf("saved searches = %v, want %v", names, want)
+	}
+
+	// Saving again replaces the query
+	if err := SaveSearch(SavedSearch{Name: "drafts", Query: "tag:draft OR tag:wip"}); err != nil {
+		t.Fatalf("SaveSearch() error = %v", err)
+	}
+	searches, _ = LoadSavedSearches()
+	if drafts, ok := FindSavedSearch(searches, "@DRAFTS"); !ok || drafts.Query != "tag:draft OR tag:wip" {
+		t.Errorf("FindSavedSearch(@DRAFTS) = %+v, %v", drafts, ok)
+	}
+
+	t.Run("Expand", func(t *testing.T) {
+		tests := []struct {
+			query    string
+			expected string
+		}{
+			{"@api-contexts", "(tag:api type:context -status:archived)"},
+			{"@api-contexts -@drafts auth", "(tag:api type:context -status:archived) -(tag:draft OR tag:wip) auth"},
+			{"(@drafts OR tag:todo)", "((tag:draft OR tag:wip) OR tag:todo)"},
+			{"email user@example.com", "email user@example.com"},
+			// Unknown names and file references stay as text to search for
+			{"@param", "@param"},
+			{"@missing tag:api", "@missing tag:api"},
+			{"@drafts/notes.md", "@drafts/notes.md"},
+			{"@drafts @drafts.md", "(tag:draft OR tag:wip) @drafts.md"},
+		}
+		for _, tt := range tests {
+			got, err := ExpandSavedSearches(tt.query)
+			if err != nil || got != tt.expected {
+				t.Errorf("ExpandSavedSearches(%q) = %q, %v; want %q", tt.query, got, err, tt.expected)
+			}
+		}
+	})
+
+	t.Run("NestedAndCycles", func(t *testing.T) {
+		if err := SaveSearch(SavedSearch{Name: "api-drafts", Query: "@api-contexts @drafts"}); err != nil {
+			t.Fatalf("SaveSearch() error = %v", err)
+		}
+		got, _ := ExpandSavedSearches("@api-drafts")
+		if want := "((tag:api type:context -status:archived) (tag:draft OR tag:wip))"; got != want {
+			t.Errorf("nested expansion = %q, want %q", got, want)
+		}
+
+		err := SaveSearch(SavedSearch{Name: "drafts", Query: "@api-drafts"})
+		if err == nil || !strings.Contains(err.Error(), "refers to itself") {
+			t.Errorf("cyclic saved search error = %v", err)
+		}
+	})
+
+	t.Run("Invalid", func(t *testing.T) {
+		if err := SaveSearch(SavedSearch{Name: "bad name", Query: "tag:api"}); err == nil {
+			t.Error("expected an error for a name with a space")
+		}
+		if err := SaveSearch(SavedSearch{Name: "broken", Query: "tag:api AND"}); err == nil {
+			t.Error("expected an error for a query that doesn't parse")
+		}
+		if _, ok := FindSavedSearch(mustLoadSavedSearches(t), "broken"); ok {
+			t.Error("an invalid saved search should not be stored")
+		}
+	})
+
+	t.Run("ShouldIncludeArchived", func(t *testing.T) {
+		if err := SaveSearch(SavedSearch{Name: "old", Query: "status:archived tag:api"}); err != nil {
+			t.Fatalf("SaveSearch() error = %v", err)
+		}
+		expanded, _ := ExpandSavedSearches("@old")
+		if !ShouldIncludeArchived(expanded) {
+			t.Error("a saved search for archived items should include them")
+		}
+	})
+
+	t.Run("Delete", func(t *testing.T) {
+		if err := DeleteSavedSearch("@old"); err != nil {
+			t.Fatalf("DeleteSavedSearch() error = %v", err)
+		}
+		if _, ok := FindSavedSearch(mustLoadSavedSearches(t), "old"); ok {
+			t.Error("deleted saved search is still there")
+		}
+		if err := DeleteSavedSearch("old"); err == nil {
+			t.Error("expected an error deleting a missing saved search")
+		}
+	})
+}
+
+func TestSearchHelper_SavedSearches(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	defer os.Chdir(oldWd)
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatalf("Failed to init project structure: %v", err)
+	}
+	if err := SaveSearch(SavedSearch{Name: "api-contexts", Query: "tag:api type:context -status:archived"}); err != nil {
+		t.Fatalf("SaveSearch() error = %v", err)
+	}
+
+	contexts := []ComponentItem{
+		{Name: "api-context", Path: "components/contexts/api-context.md", CompType: "context", Tags: []string{"api"}},
+		{Name: "old-api", Path: "components/contexts/old-api.md", CompType: "context", Tags: []string{"api"}, IsArchived: true},
+		{Name: "db-context", Path: "components/contexts/db-context.md", CompType: "context", Tags: []string{"db"}},
+	}
+	prompts := []ComponentItem{
+		{Name: "api-prompt", Path: "components/prompts/api-prompt.md", CompType: "prompt", Tags: []string{"api"}},
+	}
+
+	query, err := ExpandSavedSearches("@api-contexts")
+	if err != nil {
+		t.Fatalf("ExpandSavedSearches() error = %v", err)
+	}
+	helper := NewSearchHelper()
+	_, filtered, _, err := helper.UnifiedFilterComponents(query, prompts, contexts, nil)
+	if err != nil {
+		t.Fatalf("UnifiedFilterComponents() error = %v", err)
+	}
+	if len(filtered) != 1 || filtered[0].Name != "api-context" {
+		t.Errorf("filtered contexts = %v, want only api-context", filtered)
+	}
+
+	// An @file reference isn't a saved search, so it searches content
+	if err := files.WriteComponentWithNameAndTags("components/prompts/docs.md", "Follow @docs/api.md for endpoints", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	prompts = append(prompts, ComponentItem{Name: "docs", Path: "components/prompts/docs.md", CompType: models.ComponentTypePrompt})
+	query, err = ExpandSavedSearches("@docs/api.md")
+	if err != nil || query != "@docs/api.md" {
+		t.Fatalf("ExpandSavedSearches(@docs/api.md) = %q, %v", query, err)
+	}
+	filteredPrompts, _, _, err := helper.UnifiedFilterComponents(query, prompts, contexts, nil)
+	if err != nil {
+		t.Fatalf("UnifiedFilterComponents() error = %v", err)
+	}
+	if len(filteredPrompts) != 1 || filteredPrompts[0].Name != "docs" {
+		t.Errorf("filtered prompts = %v, want only docs", filteredPrompts)
+	}
+}
+
+func mustLoadSavedSearches(t *testing.T) []SavedSearch {
+	t.Helper()
+	searches, err := LoadSavedSearches()
+	if err != nil {
+		t.Fatalf("LoadSavedSearches() error = %v", err)
+	}
+	return searches
+}
@@ -0,0 +1,176 @@
+package unified
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// Suggestion is a completion for the query being typed
+type Suggestion struct {
+	Query string // The whole query once the suggestion is accepted
+	Text  string // What is shown, e.g. tag:api
+	Kind  string // filter, tag, type, status, sort, name, saved or recent
+}
+
+// SuggestionSources are completions that don't come from the loaded items
+type SuggestionSources struct {
+	Tags          []string      // Tags from the registry, including unused ones
+	SavedSearches []SavedSearch // Completed as @name
+	Recent        []string      // Recent queries, newest first
+}
+
+// filterValues are the fixed values offered after a filter key
+var filterValues = map[string][]string{
+	"status": {"active", "archived"},
+	"broken": {"true", "false"},
+	"sort":   {"modified", "-modified", "created", "name", "usage", "relevance"},
+}
+
+// Suggest completes the word at the end of a query: filter keys such as tag:,
+// values for tag:, type:, status:, sort: and name:, tags, item names and @saved
+// searches. Recent queries that start with what has been typed come first. At
+// most limit suggestions are returned; 0 means no limit.
+func (usm *UnifiedSearchManager) Suggest(query string, sources SuggestionSources, limit int) []Suggestion {
+	var suggestions []Suggestion
+	seen := make(map[string]bool)
+	add := func(s Suggestion) {
+		if s.Query == query || seen[s.Query] {
+			return
+		}
+		seen[s.Query] = true
+		suggestions = append(suggestions, s)
+	}
+
+	// Recent queries complete the whole query
+	lowerQuery := strings.ToLower(query)
+	for _, recent := range sources.Recent {
+		if strings.HasPrefix(strings.ToLower(recent), lowerQuery) {
+			add(Suggestion{Query: recent, Text: recent, Kind: "recent"})
+		}
+	}
+
+	// Complete the word being typed, keeping any - or ( in front of it. Nothing
+	// is completed inside a quoted phrase or after a space.
+	start := strings.LastIndexAny(query, " \t") + 1
+	word := query[start:]
+	if strings.Count(query, `"`)%2 == 1 {
+		word = ""
+	}
+	lead := len(word) - len(strings.TrimLeft(word, "-("))
+	before, word := query[:start+lead], word[lead:]
+	complete := func(text, kind string) {
+		add(Suggestion{Query: before + text, Text: text, Kind: kind})
+	}
+
+	if word != "" {
+		lowerWord := strings.ToLower(word)
+		switch key, value, isFilter := strings.Cut(lowerWord, ":"); {
+		case strings.HasPrefix(lowerWord, "@"):
+			for _, search := range sources.SavedSearches {
+				if strings.HasPrefix("@"+search.Name, lowerWord) {
+					complete("@"+search.Name, "saved")
+				}
+			}
+		case isFilter:
+			for _, v := range usm.filterValueSuggestions(key, sources) {
+				if strings.HasPrefix(strings.ToLower(v), value) {
+					complete(key+":"+quoteIfNeeded(v), suggestionKind(key))
+				}
+			}
+		default:
+			for _, prefix := range filterPrefixes {
+				if strings.HasPrefix(prefix, lowerWord) {
+					complete(prefix, "filter")
+				}
+			}
+			for _, tag := range usm.suggestionTags(sources) {
+				if strings.HasPrefix(tag, lowerWord) {
+					complete("tag:"+tag, "tag")
+				}
+			}
+			for _, name := range usm.suggestionNames() {
+				if strings.HasPrefix(strings.ToLower(name), lowerWord) {
+					complete(quoteIfNeeded(name), "name")
+				}
+			}
+		}
+	}
+
+	if limit > 0 && len(suggestions) > limit {
+		suggestions = suggestions[:limit]
+	}
+	return suggestions
+}
+
+// filterValueSuggestions returns the values offered after key:
+func (usm *UnifiedSearchManager) filterValueSuggestions(key string, sources SuggestionSources) []string {
+	switch key {
+	case "tag":
+		return usm.suggestionTags(sources)
+	case "type":
+		types := []string{"pipelines"}
+		for _, ct := range files.ComponentTypes() {
+			types = append(types, ct.Name)
+		}
+		return types
+	case "name":
+		return usm.suggestionNames()
+	}
+	return filterValues[key]
+}
+
+// suggestionTags returns the registry's tags and those used by the loaded items, sorted
+func (usm *UnifiedSearchManager) suggestionTags(sources SuggestionSources) []string {
+	seen := make(map[string]bool)
+	var tags []string
+	addTags := func(names []string) {
+		for _, tag := range names {
+			normalized := models.NormalizeTagName(tag)
+			if normalized != "" && !seen[normalized] {
+				seen[normalized] = true
+				tags = append(tags, normalized)
+			}
+		}
+	}
+
+	addTags(sources.Tags)
+	for _, item := range usm.componentEngine.items {
+		addTags(item.GetTags())
+	}
+	for _, item := range usm.pipelineEngine.items {
+		addTags(item.GetTags())
+	}
+	sort.Strings(tags)
+	return tags
+}
+
+// suggestionNames returns the names of the loaded items, pipelines first
+func (usm *UnifiedSearchManager) suggestionNames() []string {
+	seen := make(map[string]bool)
+	var names []string
+	for _, item := range usm.pipelineEngine.items {
+		if name := item.GetName(); !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+	for _, item := range usm.componentEngine.items {
+		if name := item.GetName(); !seen[strings.ToLower(name)] {
+			seen[strings.ToLower(name)] = true
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// suggestionKind is the kind of suggestion offered for a filter's values
+func suggestionKind(key string) string {
+	switch key {
+	case "tag", "type", "status", "sort", "name":
+		return key
+	}
+	return "filter"
+}
@@ -0,0 +1,64 @@
+package unified
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestUnifiedSearchManager_Suggest(t *testing.T) {
+	manager := NewUnifiedSearchManager()
+	manager.LoadComponentItems(
+		[]ComponentItem{{Name: "api-prompt", CompType: "prompts", Tags: []string{"api"}}},
+		[]ComponentItem{{Name: "Auth Context", CompType: "contexts", Tags: []string{"auth", "api"}}},
+		nil,
+	)
+	manager.LoadPipelineItems([]PipelineItem{{Name: "api-review", Tags: []string{"review"}}})
+
+	sources := SuggestionSources{
+		Tags:          []string{"architecture"},
+		SavedSearches: []SavedSearch{{Name: "api-contexts", Query: "tag:api type:contexts"}},
+		Recent:        []string{"tag:api sort:name", "auth"},
+	}
+
+	queries := func(suggestions []Suggestion) []string {
+		var result []string
+		for _, s := range suggestions {
+			result = append(result, s.Query)
+		}
+		return result
+	}
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"recent queries for an empty query", "", []string{"tag:api sort:name", "auth"}},
+		{"filter keys", "st", []string{"status:"}},
+		{"tags and names", "a", []string{"auth", "archived:", "tag:api", "tag:architecture", "tag:auth", "api-review", "api-prompt", `"Auth Context"`}},
+		{"tag values", "tag:a", []string{"tag:api sort:name", "tag:api", "tag:architecture", "tag:auth"}},
+		{"status values", "tag:api status:", []string{"tag:api status:active", "tag:api status:archived"}},
+		{"keeps a leading minus", "auth -status:ar", []string{"auth -status:archived"}},
+		{"keeps an open parenthesis", "(ty", []string{"(type:"}},
+		{"saved searches", "@api", []string{"@api-contexts"}},
+		{"nothing after a space", "auth ", nil},
+		{"nothing inside a phrase", `"auth con`, nil},
+		{"no suggestion for a complete word", "status:active", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := queries(manager.Suggest(tt.query, sources, 0))
+			if !reflect.DeepEqual(got, tt.expected) {
+				t.Errorf("Suggest(%q) = %q, want %q", tt.query, got, tt.expected)
+			}
+		})
+	}
+
+	if got := manager.Suggest("a", sources, 3); len(got) != 3 {
+		t.Errorf("Suggest with a limit of 3 returned %d suggestions", len(got))
+	}
+	if got := manager.Suggest("tag:ap", sources, 0); len(got) != 2 || got[1].Text != "tag:api" || got[1].Kind != "tag" {
+		t.Errorf("Suggest(tag:ap) = %+v, want a recent query then the api tag", got)
+	}
+}
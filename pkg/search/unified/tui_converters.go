@@ -25,18 +25,11 @@ func ConvertTUIComponentItemToShared(name, path, compType string, lastModified t
 
 // ConvertTUIPipelineItemToShared converts a TUI pipelineItem to unified PipelineItem
 func ConvertTUIPipelineItemToShared(name, path string, tags []string, tokenCount int, isArchived bool) PipelineItem {
-	// Get modified time from file system
+	// Get modified time from file system; path may be pipelines/x.yaml,
+	// archive/pipelines/x.yaml or just x.yaml
 	var modTime time.Time
-	if isArchived {
-		archivedPath := filepath.Join(files.PluqqyDir, files.ArchiveDir, files.PipelinesDir, path)
-		if stat, err := os.Stat(archivedPath); err == nil {
-			modTime = stat.ModTime()
-		}
-	} else {
-		pipelinePath := filepath.Join(files.PluqqyDir, files.PipelinesDir, path)
-		if stat, err := os.Stat(pipelinePath); err == nil {
-			modTime = stat.ModTime()
-		}
+	if stat, err := os.Stat(filepath.Join(files.PluqqyDir, pipelineIndexKey(path, isArchived))); err == nil {
+		modTime = stat.ModTime()
 	}
 	
 	return PipelineItem{
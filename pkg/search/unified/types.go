@@ -14,7 +14,10 @@ type ComponentItemWrapper struct {
 	tokenCount   int
 	tags         []string
 	isArchived   bool
+	created      time.Time
+	archivedAt   time.Time
 	content      string // For search purposes
+	doc          *IndexedDocument // The search index's document the content came from, if any
 }
 
 // NewComponentItemWrapper creates a new wrapper for componentItem
@@ -55,22 +58,44 @@ func (c *ComponentItemWrapper) GetTags() []string {
 
 func (c *ComponentItemWrapper) GetContent() string {
 	// Return searchable content (name + tags + actual content)
-	searchableContent := c.name
-	if len(c.tags) > 0 {
-		for _, tag := range c.tags {
-			searchableContent += " " + tag
-		}
-	}
+	searchableContent := c.searchHeader()
 	if c.content != "" {
 		searchableContent += " " + c.content
 	}
 	return searchableContent
 }
 
+// searchHeader is the name and tags that GetContent puts before the content
+func (c *ComponentItemWrapper) searchHeader() string {
+	header := c.name
+	for _, tag := range c.tags {
+		header += " " + tag
+	}
+	return header
+}
+
+// searchBody is the component's content without the name and tags
+func (c *ComponentItemWrapper) searchBody() string {
+	return c.content
+}
+
+// indexedDocument returns the search index's document holding the content, if any
+func (c *ComponentItemWrapper) indexedDocument() *IndexedDocument {
+	return c.doc
+}
+
 func (c *ComponentItemWrapper) GetModified() time.Time {
 	return c.lastModified
 }
 
+func (c *ComponentItemWrapper) GetCreated() time.Time {
+	return c.created
+}
+
+func (c *ComponentItemWrapper) GetArchivedAt() time.Time {
+	return c.archivedAt
+}
+
 func (c *ComponentItemWrapper) IsArchived() bool {
 	return c.isArchived
 }
@@ -83,6 +108,14 @@ func (c *ComponentItemWrapper) GetUsageCount() int {
 	return c.usageCount
 }
 
+func (c *ComponentItemWrapper) GetComponentPaths() []string {
+	return nil // Components don't include other components
+}
+
+func (c *ComponentItemWrapper) HasBrokenReferences() bool {
+	return false
+}
+
 // PipelineItemWrapper wraps the TUI pipelineItem to implement Searchable
 type PipelineItemWrapper struct {
 	name       string
@@ -91,7 +124,13 @@ type PipelineItemWrapper struct {
 	tokenCount int
 	isArchived bool
 	modified   time.Time
+	created    time.Time
+	archivedAt time.Time
 	content    string // For search purposes
+	doc        *IndexedDocument // The search index's document the content came from, if any
+	
+	componentPaths []string // Components included by the pipeline, through extends too
+	broken         bool     // Whether any included component or extended pipeline is missing
 }
 
 // NewPipelineItemWrapper creates a new wrapper for pipelineItem
@@ -130,22 +169,44 @@ func (p *PipelineItemWrapper) GetTags() []string {
 
 func (p *PipelineItemWrapper) GetContent() string {
 	// Return searchable content (name + tags + content)
-	searchableContent := p.name
-	if len(p.tags) > 0 {
-		for _, tag := range p.tags {
-			searchableContent += " " + tag
-		}
-	}
+	searchableContent := p.searchHeader()
 	if p.content != "" {
 		searchableContent += " " + p.content
 	}
 	return searchableContent
 }
 
+// searchHeader is the name and tags that GetContent puts before the content
+func (p *PipelineItemWrapper) searchHeader() string {
+	header := p.name
+	for _, tag := range p.tags {
+		header += " " + tag
+	}
+	return header
+}
+
+// searchBody is the pipeline's content without the name and tags
+func (p *PipelineItemWrapper) searchBody() string {
+	return p.content
+}
+
+// indexedDocument returns the search index's document holding the content, if any
+func (p *PipelineItemWrapper) indexedDocument() *IndexedDocument {
+	return p.doc
+}
+
 func (p *PipelineItemWrapper) GetModified() time.Time {
 	return p.modified
 }
 
+func (p *PipelineItemWrapper) GetCreated() time.Time {
+	return p.created
+}
+
+func (p *PipelineItemWrapper) GetArchivedAt() time.Time {
+	return p.archivedAt
+}
+
 func (p *PipelineItemWrapper) IsArchived() bool {
 	return p.isArchived
 }
@@ -156,4 +217,12 @@ func (p *PipelineItemWrapper) GetTokenCount() int {
 
 func (p *PipelineItemWrapper) GetUsageCount() int {
 	return 0 // Pipelines don't have usage counts in the same way
+}
+
+func (p *PipelineItemWrapper) GetComponentPaths() []string {
+	return p.componentPaths
+}
+
+func (p *PipelineItemWrapper) HasBrokenReferences() bool {
+	return p.broken
 }
\ No newline at end of file
@@ -6,7 +6,8 @@ import (
 	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 )
 
-// ShouldIncludeArchived checks if the search query includes archived items
+// ShouldIncludeArchived checks if the search query includes archived items. An
+// @saved search that asks for them only counts once it has been expanded.
 func ShouldIncludeArchived(searchQuery string) bool {
 	if searchQuery == "" {
 		return false
@@ -18,18 +19,23 @@ func ShouldIncludeArchived(searchQuery string) bool {
 		return true
 	}
 
-	// Parse the search query to check for status:archived using unified parser
+	// Parse the search query to check for status:archived or an archive date
+	// filter using unified parser
 	parsedQuery := ParseQuery(searchQuery)
 	for _, filter := range parsedQuery.Filters {
 		if filter.Type == "status" && strings.ToLower(filter.Value) == "archived" {
 			return true
 		}
+		if filter.Type == "archived" {
+			return true
+		}
 	}
 
 	return false
 }
 
-// FilterSearchResultsByType separates component results by type
+// FilterSearchResultsByType separates component results by type.
+// Custom component types travel with contexts and keep their own CompType.
 func FilterSearchResultsByType(results []SearchResult[*ComponentItemWrapper]) ([]ComponentItem, []ComponentItem, []ComponentItem) {
 	var prompts, contexts, rules []ComponentItem
 
@@ -43,15 +49,16 @@ func FilterSearchResultsByType(results []SearchResult[*ComponentItemWrapper]) ([
 			TokenCount:   result.Item.tokenCount,
 			Tags:         result.Item.tags,
 			IsArchived:   result.Item.isArchived,
+			NameMatches:  result.Relevance.Ranges["name"],
 		}
 
 		switch result.Item.compType {
 		case models.ComponentTypePrompt:
 			prompts = append(prompts, item)
-		case models.ComponentTypeContext:
-			contexts = append(contexts, item)
 		case models.ComponentTypeRules:
 			rules = append(rules, item)
+		default:
+			contexts = append(contexts, item)
 		}
 	}
 
@@ -64,12 +71,13 @@ func ConvertPipelineResults(results []SearchResult[*PipelineItemWrapper]) []Pipe
 
 	for _, result := range results {
 		pipelines = append(pipelines, PipelineItem{
-			Name:       result.Item.name,
-			Path:       result.Item.path,
-			Tags:       result.Item.tags,
-			TokenCount: result.Item.tokenCount,
-			IsArchived: result.Item.isArchived,
-			Modified:   result.Item.modified,
+			Name:        result.Item.name,
+			Path:        result.Item.path,
+			Tags:        result.Item.tags,
+			TokenCount:  result.Item.tokenCount,
+			IsArchived:  result.Item.isArchived,
+			Modified:    result.Item.modified,
+			NameMatches: result.Relevance.Ranges["name"],
 		})
 	}
 
@@ -175,7 +175,22 @@ func (r *Registry) ListTags() []models.Tag {
 	return tags
 }
 
-// GetOrCreateTag gets an existing tag or creates a new one with default color
+// TagColor returns the color a tag is displayed with: its own registry color, else
+// the color of its nearest ancestor with one, else a color derived from the name
+// of its top-level ancestor so that a whole hierarchy shares one color
+func (r *Registry) TagColor(name string) string {
+	normalized := models.NormalizeTagName(name)
+	for current := normalized; current != ""; current = models.GetTagParent(current) {
+		if tag, exists := r.GetTag(current); exists && tag.Color != "" {
+			return tag.Color
+		}
+	}
+	return models.GetTagColor(models.GetTagRoot(normalized), "")
+}
+
+// GetOrCreateTag gets an existing tag or creates a new one with default color.
+// New hierarchical tags such as project/frontend get their parent filled in and
+// are left without a color of their own so they inherit the parent's.
 func (r *Registry) GetOrCreateTag(name string) (*models.Tag, error) {
 	// Check if tag exists
 	if tag, exists := r.GetTag(name); exists {
@@ -183,11 +198,7 @@ func (r *Registry) GetOrCreateTag(name string) (*models.Tag, error) {
 	}
 	
 	// Create new tag with auto-assigned color
-	normalizedName := models.NormalizeTagName(name)
-	newTag := models.Tag{
-		Name:  normalizedName,
-		Color: models.GetTagColor(normalizedName, ""),
-	}
+	newTag := models.NewTag(name)
 	
 	// Add to registry
 	if err := r.AddTag(newTag); err != nil {
@@ -202,7 +213,8 @@ func (r *Registry) GetOrCreateTag(name string) (*models.Tag, error) {
 	return &newTag, nil
 }
 
-// RenameTag renames a tag throughout the system
+// RenameTag renames a tag in the registry only. Use RenameTagEverywhere to also
+// update the components and pipelines that use it.
 func (r *Registry) RenameTag(oldName, newName string) error {
 	r.mu.Lock()
 	defer r.mu.Unlock()
@@ -215,39 +227,87 @@ func (r *Registry) RenameTag(oldName, newName string) error {
 	oldNormalized := models.NormalizeTagName(oldName)
 	newNormalized := models.NormalizeTagName(newName)
 	
-	// Check if old tag exists
-	found := false
-	for i, tag := range r.registry.Tags {
-		if models.NormalizeTagName(tag.Name) == oldNormalized {
-			r.registry.Tags[i].Name = newNormalized
-			found = true
-			break
-		}
+	// Renaming onto another existing tag would leave two entries with one name
+	if oldNormalized != newNormalized && r.indexOf(newNormalized) >= 0 {
+		return fmt.Errorf("tag '%s' already exists; merge the tags instead", newNormalized)
 	}
 	
-	if !found {
+	// Check if old tag exists
+	i := r.indexOf(oldNormalized)
+	if i < 0 {
 		return fmt.Errorf("tag '%s' not found", oldName)
 	}
+	r.registry.Tags[i].Name = newNormalized
 	
-	// TODO: Update all components and pipelines that use this tag
-	// This would require scanning all files and updating their tags
+	return nil
+}
+
+// MergeTags replaces the source tags with target in the registry only. The target
+// keeps its metadata; when it isn't registered yet it takes over the metadata of
+// the first registered source. Use MergeTagsEverywhere to also update the
+// components and pipelines that use the tags.
+func (r *Registry) MergeTags(sources []string, target string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	
+	if err := models.ValidateTagName(target); err != nil {
+		return fmt.Errorf("invalid target tag name: %w", err)
+	}
+	targetNormalized := models.NormalizeTagName(target)
+	
+	targetFound := r.indexOf(targetNormalized) >= 0
+	newTags := make([]models.Tag, 0, len(r.registry.Tags))
+	for _, tag := range r.registry.Tags {
+		name := models.NormalizeTagName(tag.Name)
+		if name != targetNormalized && containsTag(sources, name) {
+			if targetFound {
+				continue
+			}
+			// The first source becomes the target
+			tag.Name = targetNormalized
+			targetFound = true
+		}
+		newTags = append(newTags, tag)
+	}
+	
+	r.registry.Tags = newTags
 	return nil
 }
 
+// indexOf returns the position of a normalized tag name in the registry or -1.
+// The caller must hold the lock.
+func (r *Registry) indexOf(normalizedName string) int {
+	for i, tag := range r.registry.Tags {
+		if models.NormalizeTagName(tag.Name) == normalizedName {
+			return i
+		}
+	}
+	return -1
+}
+
+// containsTag reports whether names contains the normalized tag name
+func containsTag(names []string, normalizedName string) bool {
+	for _, name := range names {
+		if models.NormalizeTagName(name) == normalizedName {
+			return true
+		}
+	}
+	return false
+}
+
 // GetTagStats returns usage statistics for all tags
 func (r *Registry) GetTagStats() (map[string]int, error) {
 	stats := make(map[string]int)
 	
 	// Count component usage
-	for _, compType := range []string{models.ComponentTypePrompt, models.ComponentTypeContext, models.ComponentTypeRules} {
-		components, err := files.ListComponents(compType)
+	for _, compType := range files.ComponentTypes() {
+		components, err := files.ListComponents(compType.Name)
 		if err != nil {
-			return nil, fmt.Errorf("failed to list %s components: %w", compType, err)
+			return nil, fmt.Errorf("failed to list %s components: %w", compType.Name, err)
 		}
 		
 		for _, compFile := range components {
-			compPath := filepath.Join(files.ComponentsDir, compType, compFile)
+			compPath := filepath.Join(files.ComponentsDir, compType.DirName(), compFile)
 			comp, err := files.ReadComponent(compPath)
 			if err != nil {
 				continue // Skip components that can't be read
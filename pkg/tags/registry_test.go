@@ -208,6 +208,44 @@ func TestRegistry(t *testing.T) {
 		}
 	})
 	
+	t.Run("HierarchicalTags", func(t *testing.T) {
+		registry, _ := NewRegistry()
+		
+		parent, _ := registry.GetOrCreateTag("project")
+		child, err := registry.GetOrCreateTag("Project/Frontend")
+		if err != nil {
+			t.Fatalf("GetOrCreateTag() error = %v", err)
+		}
+		
+		// Slash tags get their parent filled in and inherit its color
+		if child.Parent != "project" {
+			t.Errorf("Parent = %q, want project", child.Parent)
+		}
+		if child.Color != "" {
+			t.Errorf("child tag has its own color %q", child.Color)
+		}
+		if got := registry.TagColor("project/frontend"); got != parent.Color {
+			t.Errorf("TagColor(project/frontend) = %q, want parent color %q", got, parent.Color)
+		}
+		
+		// Unregistered descendants resolve through the nearest ancestor
+		registry.AddTag(models.Tag{Name: "project", Color: "#123456"})
+		if got := registry.TagColor("project/frontend/css"); got != "#123456" {
+			t.Errorf("TagColor(project/frontend/css) = %q, want #123456", got)
+		}
+		
+		// A color of its own wins over the parent's
+		registry.AddTag(models.Tag{Name: "project/frontend", Parent: "project", Color: "#abcdef"})
+		if got := registry.TagColor("project/frontend/css"); got != "#abcdef" {
+			t.Errorf("TagColor(project/frontend/css) = %q, want #abcdef", got)
+		}
+		
+		// Without any registered ancestor, siblings share their root's color
+		if registry.TagColor("team/a") != registry.TagColor("team/b") {
+			t.Error("sibling tags without registry colors should share a color")
+		}
+	})
+	
 	t.Run("ValidateTagName", func(t *testing.T) {
 		registry, _ := NewRegistry()
 		
@@ -0,0 +1,190 @@
+package tags
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// RenameTagEverywhere renames a tag in the registry and in every component and
+// pipeline that uses it, archived items included. Nothing is changed if any
+// file or the registry can't be written.
+func (r *Registry) RenameTagEverywhere(oldName, newName string) (*files.TagRewriteResult, error) {
+	if err := models.ValidateTagName(newName); err != nil {
+		return nil, fmt.Errorf("invalid new tag name: %w", err)
+	}
+	oldNormalized := models.NormalizeTagName(oldName)
+	newNormalized := models.NormalizeTagName(newName)
+	if oldNormalized == newNormalized {
+		return nil, fmt.Errorf("tag '%s' already has that name", oldName)
+	}
+	if _, exists := r.GetTag(newNormalized); exists {
+		return nil, fmt.Errorf("tag '%s' already exists; use merge to combine the tags", newNormalized)
+	}
+
+	snapshot := r.ListTags()
+	_, registered := r.GetTag(oldNormalized)
+	if registered {
+		if err := r.RenameTag(oldNormalized, newNormalized); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.rewriteFiles(map[string]string{oldNormalized: newNormalized}, registered, snapshot,
+		fmt.Sprintf("tag '%s' not found", oldName))
+}
+
+// MergeTagsEverywhere replaces the source tags with target in the registry and in
+// every component and pipeline, archived items included. Items that carried more
+// than one of the tags keep a single copy of target. Nothing is changed if any
+// file or the registry can't be written.
+func (r *Registry) MergeTagsEverywhere(sources []string, target string) (*files.TagRewriteResult, error) {
+	if err := models.ValidateTagName(target); err != nil {
+		return nil, fmt.Errorf("invalid target tag name: %w", err)
+	}
+	targetNormalized := models.NormalizeTagName(target)
+
+	replacements := make(map[string]string)
+	var names []string
+	registered := false
+	for _, source := range sources {
+		normalized := models.NormalizeTagName(source)
+		if normalized == targetNormalized || replacements[normalized] != "" {
+			continue
+		}
+		replacements[normalized] = targetNormalized
+		names = append(names, normalized)
+		if _, exists := r.GetTag(normalized); exists {
+			registered = true
+		}
+	}
+	if len(replacements) == 0 {
+		return nil, fmt.Errorf("no tags to merge into '%s'", targetNormalized)
+	}
+
+	snapshot := r.ListTags()
+	if err := r.MergeTags(names, targetNormalized); err != nil {
+		return nil, err
+	}
+
+	return r.rewriteFiles(replacements, registered, snapshot,
+		fmt.Sprintf("tags %s not found", strings.Join(names, ", ")))
+}
+
+// rewriteFiles applies replacements to every file and saves the registry, restoring
+// the files and the in-memory registry when either step fails. A tag that is neither
+// registered nor used by any file is reported with notFound.
+func (r *Registry) rewriteFiles(replacements map[string]string, registered bool, snapshot []models.Tag, notFound string) (*files.TagRewriteResult, error) {
+	result, err := files.RewriteTags(replacements)
+	if err != nil {
+		r.restore(snapshot)
+		return nil, err
+	}
+	if !registered && result.FilesChanged() == 0 {
+		r.restore(snapshot)
+		return nil, errors.New(notFound)
+	}
+
+	// Tags used by files but missing from the registry get registered as the target
+	for _, target := range replacements {
+		if target == "" {
+			continue
+		}
+		if _, exists := r.GetTag(target); !exists {
+			r.AddTag(models.NewTag(target))
+		}
+	}
+
+	if err := r.Save(); err != nil {
+		r.restore(snapshot)
+		if rollbackErr := result.Rollback(); rollbackErr != nil {
+			return nil, fmt.Errorf("failed to save tag registry: %w (rollback failed: %v)", err, rollbackErr)
+		}
+		return nil, fmt.Errorf("failed to save tag registry: %w", err)
+	}
+
+	return result, nil
+}
+
+// restore replaces the in-memory registry tags with a snapshot
+func (r *Registry) restore(snapshot []models.Tag) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.registry.Tags = snapshot
+}
+
+// DeleteTagEverywhere removes a tag from the registry and from every component and
+// pipeline, archived items included. Nothing is changed if any file or the
+// registry can't be written.
+func (r *Registry) DeleteTagEverywhere(name string) (*files.TagRewriteResult, error) {
+	normalized := models.NormalizeTagName(name)
+
+	snapshot := r.ListTags()
+	_, registered := r.GetTag(normalized)
+	if registered {
+		if err := r.RemoveTag(normalized); err != nil {
+			return nil, err
+		}
+	}
+
+	return r.rewriteFiles(map[string]string{normalized: ""}, registered, snapshot,
+		fmt.Sprintf("tag '%s' not found", name))
+}
+
+// AddTagToItems adds a tag to the given components and pipelines and registers
+// it. Paths are relative to the .pluqqy directory. Items that already have the tag
+// are left alone. Nothing is changed if any file or the registry can't be written.
+func (r *Registry) AddTagToItems(name string, paths []string) (*files.TagRewriteResult, error) {
+	if err := models.ValidateTagName(name); err != nil {
+		return nil, fmt.Errorf("invalid tag name: %w", err)
+	}
+	normalized := models.NormalizeTagName(name)
+
+	result, err := files.EditItemTags(paths, func(tags []string) ([]string, bool) {
+		for _, tag := range tags {
+			if models.NormalizeTagName(tag) == normalized {
+				return tags, false
+			}
+		}
+		return append(append([]string{}, tags...), normalized), true
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if _, exists := r.GetTag(normalized); exists {
+		return result, nil
+	}
+
+	snapshot := r.ListTags()
+	r.AddTag(models.NewTag(normalized))
+	if err := r.Save(); err != nil {
+		r.restore(snapshot)
+		if rollbackErr := result.Rollback(); rollbackErr != nil {
+			return nil, fmt.Errorf("failed to save tag registry: %w (rollback failed: %v)", err, rollbackErr)
+		}
+		return nil, fmt.Errorf("failed to save tag registry: %w", err)
+	}
+
+	return result, nil
+}
+
+// RemoveTagFromItems removes a tag from the given components and pipelines. Paths
+// are relative to the .pluqqy directory. The tag stays in the registry; use
+// CleanupOrphanedTags to drop it once nothing uses it.
+func RemoveTagFromItems(name string, paths []string) (*files.TagRewriteResult, error) {
+	normalized := models.NormalizeTagName(name)
+
+	return files.EditItemTags(paths, func(tags []string) ([]string, bool) {
+		remaining := make([]string, 0, len(tags))
+		for _, tag := range tags {
+			if models.NormalizeTagName(tag) != normalized {
+				remaining = append(remaining, tag)
+			}
+		}
+		return remaining, len(remaining) != len(tags)
+	})
+}
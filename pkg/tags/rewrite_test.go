@@ -0,0 +1,301 @@
+package tags
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"gopkg.in/yaml.v3"
+)
+
+// setupRewriteProject creates a project with tagged components and pipelines in
+// both the active and archive trees
+func setupRewriteProject(t *testing.T) {
+	t.Helper()
+
+	tmpDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	os.Chdir(tmpDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatalf("Failed to init project structure: %v", err)
+	}
+
+	writeFile := func(relPath, content string) {
+		path := filepath.Join(files.PluqqyDir, relPath)
+		os.MkdirAll(filepath.Dir(path), 0755)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", relPath, err)
+		}
+	}
+
+	writeFile("components/prompts/api.md", "---\nname: API\ntags: [api, backend]\n---\nAPI content")
+	writeFile("components/rules/nested/style.md", "---\ntags: [Backend, style]\n---\nStyle content")
+	writeFile("archive/components/contexts/old.md", "---\ntags: [backend]\n---\nOld content")
+	writeFile("components/contexts/untagged.md", "No frontmatter")
+
+	writePipeline := func(relPath string, pipeline models.Pipeline) {
+		data, _ := yaml.Marshal(&pipeline)
+		writeFile(relPath, string(data))
+	}
+	writePipeline("pipelines/main.yaml", models.Pipeline{Name: "main", Tags: []string{"backend", "server"}})
+	writePipeline("archive/pipelines/legacy.yaml", models.Pipeline{Name: "legacy", Tags: []string{"server"}})
+
+	registry := &models.TagRegistry{
+		Tags: []models.Tag{
+			{Name: "api", Color: "#111111"},
+			{Name: "backend", Color: "#222222", Description: "Backend work"},
+			{Name: "server", Color: "#333333"},
+			{Name: "style", Color: "#444444"},
+		},
+	}
+	data, _ := yaml.Marshal(registry)
+	writeFile(TagsRegistryFile, string(data))
+}
+
+func readComponentTags(t *testing.T, relPath string) []string {
+	t.Helper()
+	comp, err := files.ReadComponent(relPath)
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", relPath, err)
+	}
+	return comp.Tags
+}
+
+func readPipelineTags(t *testing.T, relPath string) []string {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join(files.PluqqyDir, relPath))
+	if err != nil {
+		t.Fatalf("Failed to read %s: %v", relPath, err)
+	}
+	var pipeline models.Pipeline
+	if err := yaml.Unmarshal(data, &pipeline); err != nil {
+		t.Fatalf("Failed to parse %s: %v", relPath, err)
+	}
+	return pipeline.Tags
+}
+
+func TestRenameTagEverywhere(t *testing.T) {
+	setupRewriteProject(t)
+
+	registry, _ := NewRegistry()
+	result, err := registry.RenameTagEverywhere("backend", "server-side")
+	if err != nil {
+		t.Fatalf("RenameTagEverywhere() error = %v", err)
+	}
+
+	if len(result.Components) != 3 || len(result.Pipelines) != 1 {
+		t.Errorf("changed %d components and %d pipelines, want 3 and 1", len(result.Components), len(result.Pipelines))
+	}
+	if result.FilesChanged() != 4 {
+		t.Errorf("FilesChanged() = %d, want 4", result.FilesChanged())
+	}
+
+	if got := strings.Join(readComponentTags(t, "components/prompts/api.md"), ","); got != "api,server-side" {
+		t.Errorf("api.md tags = %s, want api,server-side", got)
+	}
+	if got := strings.Join(readComponentTags(t, "components/rules/nested/style.md"), ","); got != "server-side,style" {
+		t.Errorf("style.md tags = %s, want server-side,style", got)
+	}
+	if got := strings.Join(readComponentTags(t, "archive/components/contexts/old.md"), ","); got != "server-side" {
+		t.Errorf("archived old.md tags = %s, want server-side", got)
+	}
+	if got := strings.Join(readPipelineTags(t, "pipelines/main.yaml"), ","); got != "server-side,server" {
+		t.Errorf("main.yaml tags = %s, want server-side,server", got)
+	}
+
+	// Content and name must survive the frontmatter rewrite
+	comp, _ := files.ReadComponent("components/prompts/api.md")
+	if comp.Name != "API" || strings.TrimSpace(comp.Content) != "API content" {
+		t.Errorf("api.md lost name or content: name=%q content=%q", comp.Name, comp.Content)
+	}
+
+	// The registry entry keeps its metadata under the new name
+	reloaded, _ := NewRegistry()
+	if _, exists := reloaded.GetTag("backend"); exists {
+		t.Error("old tag still in registry")
+	}
+	tag, exists := reloaded.GetTag("server-side")
+	if !exists || tag.Color != "#222222" || tag.Description != "Backend work" {
+		t.Errorf("renamed tag = %+v, exists=%v", tag, exists)
+	}
+}
+
+func TestRenameTagEverywhereErrors(t *testing.T) {
+	setupRewriteProject(t)
+	registry, _ := NewRegistry()
+
+	if _, err := registry.RenameTagEverywhere("backend", "server"); err == nil {
+		t.Error("expected error when renaming onto an existing tag")
+	}
+	if _, err := registry.RenameTagEverywhere("missing", "other"); err == nil {
+		t.Error("expected error for unknown tag")
+	}
+	if _, err := registry.RenameTagEverywhere("backend", "bad@name"); err == nil {
+		t.Error("expected error for invalid name")
+	}
+
+	// Nothing may have changed
+	if got := strings.Join(readPipelineTags(t, "pipelines/main.yaml"), ","); got != "backend,server" {
+		t.Errorf("main.yaml tags = %s, want backend,server", got)
+	}
+}
+
+func TestMergeTagsEverywhere(t *testing.T) {
+	setupRewriteProject(t)
+
+	registry, _ := NewRegistry()
+	result, err := registry.MergeTagsEverywhere([]string{"backend", "server"}, "infra")
+	if err != nil {
+		t.Fatalf("MergeTagsEverywhere() error = %v", err)
+	}
+	if result.FilesChanged() != 5 {
+		t.Errorf("FilesChanged() = %d, want 5", result.FilesChanged())
+	}
+
+	// Pipelines with both tags keep a single copy of the target
+	if got := strings.Join(readPipelineTags(t, "pipelines/main.yaml"), ","); got != "infra" {
+		t.Errorf("main.yaml tags = %s, want infra", got)
+	}
+	if got := strings.Join(readPipelineTags(t, "archive/pipelines/legacy.yaml"), ","); got != "infra" {
+		t.Errorf("legacy.yaml tags = %s, want infra", got)
+	}
+
+	reloaded, _ := NewRegistry()
+	for _, name := range []string{"backend", "server"} {
+		if _, exists := reloaded.GetTag(name); exists {
+			t.Errorf("merged tag %s still in registry", name)
+		}
+	}
+	tag, exists := reloaded.GetTag("infra")
+	if !exists || tag.Color != "#222222" {
+		t.Errorf("target tag = %+v, exists=%v; want metadata of first source", tag, exists)
+	}
+}
+
+func TestMergeTagsEverywhereIntoExisting(t *testing.T) {
+	setupRewriteProject(t)
+
+	registry, _ := NewRegistry()
+	if _, err := registry.MergeTagsEverywhere([]string{"backend"}, "api"); err != nil {
+		t.Fatalf("MergeTagsEverywhere() error = %v", err)
+	}
+
+	if got := strings.Join(readComponentTags(t, "components/prompts/api.md"), ","); got != "api" {
+		t.Errorf("api.md tags = %s, want api", got)
+	}
+
+	reloaded, _ := NewRegistry()
+	tag, _ := reloaded.GetTag("api")
+	if tag.Color != "#111111" {
+		t.Errorf("existing target lost its color: %s", tag.Color)
+	}
+	if len(reloaded.ListTags()) != 3 {
+		t.Errorf("registry has %d tags, want 3", len(reloaded.ListTags()))
+	}
+}
+
+func TestRenameTagEverywhereRollback(t *testing.T) {
+	setupRewriteProject(t)
+
+	original, _ := os.ReadFile(filepath.Join(files.PluqqyDir, "pipelines/main.yaml"))
+
+	// A directory in place of the registry file makes the final save fail
+	registry, _ := NewRegistry()
+	registryPath := filepath.Join(files.PluqqyDir, TagsRegistryFile)
+	os.Remove(registryPath)
+	os.MkdirAll(registryPath, 0755)
+
+	if _, err := registry.RenameTagEverywhere("backend", "server-side"); err == nil {
+		t.Fatal("expected error when the registry can't be saved")
+	}
+
+	current, _ := os.ReadFile(filepath.Join(files.PluqqyDir, "pipelines/main.yaml"))
+	if string(current) != string(original) {
+		t.Error("pipeline was not restored after the failed rename")
+	}
+	if got := strings.Join(readComponentTags(t, "archive/components/contexts/old.md"), ","); got != "backend" {
+		t.Errorf("archived component tags = %s, want backend", got)
+	}
+	if _, exists := registry.GetTag("backend"); !exists {
+		t.Error("in-memory registry was not restored")
+	}
+}
+
+func TestDeleteTagEverywhere(t *testing.T) {
+	setupRewriteProject(t)
+
+	registry, _ := NewRegistry()
+	result, err := registry.DeleteTagEverywhere("backend")
+	if err != nil {
+		t.Fatalf("DeleteTagEverywhere() error = %v", err)
+	}
+	if result.FilesChanged() != 4 {
+		t.Errorf("FilesChanged() = %d, want 4", result.FilesChanged())
+	}
+
+	if got := strings.Join(readComponentTags(t, "components/prompts/api.md"), ","); got != "api" {
+		t.Errorf("api.md tags = %s, want api", got)
+	}
+	if got := readComponentTags(t, "archive/components/contexts/old.md"); len(got) != 0 {
+		t.Errorf("archived old.md tags = %v, want none", got)
+	}
+
+	reloaded, _ := NewRegistry()
+	if _, exists := reloaded.GetTag("backend"); exists {
+		t.Error("deleted tag still in registry")
+	}
+
+	if _, err := registry.DeleteTagEverywhere("backend"); err == nil {
+		t.Error("expected error when deleting a tag twice")
+	}
+}
+
+func TestAddAndRemoveTagItems(t *testing.T) {
+	setupRewriteProject(t)
+
+	paths := []string{"components/contexts/untagged.md", "components/prompts/api.md", "archive/pipelines/legacy.yaml"}
+
+	registry, _ := NewRegistry()
+	result, err := registry.AddTagToItems("API", paths)
+	if err != nil {
+		t.Fatalf("AddTagToItems() error = %v", err)
+	}
+	// api.md already has the tag
+	if result.FilesChanged() != 2 {
+		t.Errorf("FilesChanged() = %d, want 2", result.FilesChanged())
+	}
+	if got := strings.Join(readComponentTags(t, "components/contexts/untagged.md"), ","); got != "api" {
+		t.Errorf("untagged.md tags = %s, want api", got)
+	}
+	if got := strings.Join(readPipelineTags(t, "archive/pipelines/legacy.yaml"), ","); got != "server,api" {
+		t.Errorf("legacy.yaml tags = %s, want server,api", got)
+	}
+
+	if _, err := registry.AddTagToItems("new-tag", paths[:1]); err != nil {
+		t.Fatalf("AddTagToItems() error = %v", err)
+	}
+	reloaded, _ := NewRegistry()
+	if _, exists := reloaded.GetTag("new-tag"); !exists {
+		t.Error("new tag was not registered")
+	}
+
+	result, err = RemoveTagFromItems("api", paths)
+	if err != nil {
+		t.Fatalf("RemoveTagFromItems() error = %v", err)
+	}
+	if result.FilesChanged() != 3 {
+		t.Errorf("FilesChanged() = %d, want 3", result.FilesChanged())
+	}
+	if got := strings.Join(readComponentTags(t, "components/prompts/api.md"), ","); got != "backend" {
+		t.Errorf("api.md tags = %s, want backend", got)
+	}
+
+	if _, err := RemoveTagFromItems("api", []string{"components/prompts/missing.md"}); err == nil {
+		t.Error("expected error for a missing item")
+	}
+}
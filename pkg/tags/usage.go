@@ -20,14 +20,14 @@ func CountTagUsage(tagName string) (*UsageStats, error) {
 	normalized := models.NormalizeTagName(tagName)
 	
 	// Count in components
-	for _, compType := range []string{"prompts", "contexts", "rules"} {
-		components, err := files.ListComponents(compType)
+	for _, compType := range files.ComponentTypes() {
+		components, err := files.ListComponents(compType.Name)
 		if err != nil {
 			continue
 		}
 		
 		for _, compFile := range components {
-			path := "components/" + compType + "/" + compFile
+			path := "components/" + compType.DirName() + "/" + compFile
 			comp, err := files.ReadComponent(path)
 			if err != nil {
 				continue
@@ -81,14 +81,14 @@ func GetAllTagUsage() (map[string]*UsageStats, error) {
 	}
 	
 	// Count component usage
-	for _, compType := range []string{"prompts", "contexts", "rules"} {
-		components, err := files.ListComponents(compType)
+	for _, compType := range files.ComponentTypes() {
+		components, err := files.ListComponents(compType.Name)
 		if err != nil {
 			continue
 		}
 		
 		for _, compFile := range components {
-			path := "components/" + compType + "/" + compFile
+			path := "components/" + compType.DirName() + "/" + compFile
 			comp, err := files.ReadComponent(path)
 			if err != nil {
 				continue
@@ -2,6 +2,8 @@ package tui
 
 import (
 	"time"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
 )
 
 type column int
@@ -42,6 +44,8 @@ type componentItem struct {
 	tokenCount   int
 	tags         []string
 	isArchived   bool
+
+	nameMatches []unified.MatchRange // Spans of name matched by the search, underlined in the table
 }
 
 type clearEditSaveMsg struct{}
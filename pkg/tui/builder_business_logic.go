@@ -18,6 +18,9 @@ import (
 // Component Management Methods
 
 func (m *PipelineBuilderModel) loadAvailableComponents() {
+	// Tags and saved searches may have changed along with the components
+	m.search.Sources.Reload()
+
 	// Check if we should include archived items based on search query
 	includeArchived := m.shouldIncludeArchived()
 
@@ -40,8 +43,10 @@ func (m *PipelineBuilderModel) loadAvailableComponents() {
 	m.data.FilteredContexts = m.data.Contexts
 	m.data.FilteredRules = m.data.Rules
 
-	// Note: Search index rebuilding is now handled by the unified search manager
-	// No explicit index rebuilding needed
+	// Bring the search index up to date with created, renamed and deleted items
+	if m.search.UnifiedManager != nil {
+		m.search.UnifiedManager.RefreshIndex()
+	}
 }
 
 // convertBuilderComponentItems converts unified ComponentItems to local componentItems
@@ -93,6 +98,7 @@ func convertSharedComponentsToTUI(items []unified.ComponentItem) []componentItem
 			tokenCount:   item.TokenCount,
 			tags:         item.Tags,
 			isArchived:   item.IsArchived,
+			nameMatches:  item.NameMatches,
 		}
 	}
 	return result
@@ -100,31 +106,16 @@ func convertSharedComponentsToTUI(items []unified.ComponentItem) []componentItem
 
 // shouldIncludeArchived checks if the current search query requires archived items
 func (m *PipelineBuilderModel) shouldIncludeArchived() bool {
-	return unified.ShouldIncludeArchived(m.search.Query)
+	return unified.ShouldIncludeArchived(m.search.Expanded)
 }
 
 func (m *PipelineBuilderModel) getAllAvailableComponents() []componentItem {
-	// Load settings for section order
-	settings, err := files.ReadSettings()
-	if err != nil || settings == nil {
-		settings = models.DefaultSettings()
-	}
-
-	// Group components by type - use filtered lists when searching
-	typeGroups := make(map[string][]componentItem)
-	typeGroups[models.ComponentTypeContext] = m.data.FilteredContexts
-	typeGroups[models.ComponentTypePrompt] = m.data.FilteredPrompts
-	typeGroups[models.ComponentTypeRules] = m.data.FilteredRules
-
-	// Build ordered list based on sections
-	var all []componentItem
-	for _, section := range settings.Output.Formatting.Sections {
-		if components, exists := typeGroups[section.Type]; exists {
-			all = append(all, components...)
-		}
-	}
-
-	return all
+	// Use filtered lists when searching, ordered by the configured sections
+	all := make([]componentItem, 0, len(m.data.FilteredPrompts)+len(m.data.FilteredContexts)+len(m.data.FilteredRules))
+	all = append(all, m.data.FilteredPrompts...)
+	all = append(all, m.data.FilteredContexts...)
+	all = append(all, m.data.FilteredRules...)
+	return orderComponentsBySections(all)
 }
 
 func (m *PipelineBuilderModel) addSelectedComponent() {
@@ -225,7 +216,7 @@ func (m *PipelineBuilderModel) reorganizeComponentsByType() {
 
 	// Rebuild the array in configured order
 	m.data.SelectedComponents = nil
-	for _, section := range settings.Output.Formatting.Sections {
+	for _, section := range settings.OutputSections() {
 		if components, exists := typeGroups[section.Type]; exists {
 			m.data.SelectedComponents = append(m.data.SelectedComponents, components...)
 		}
@@ -443,6 +434,27 @@ func (m *PipelineBuilderModel) saveAndSetPipeline() tea.Cmd {
 			return StatusMsg(fmt.Sprintf("× Failed to save pipeline: %v", err))
 		}
 
+		// Write every configured output target in one go
+		settings, _ := files.ReadSettings()
+		if settings == nil {
+			settings = models.DefaultSettings()
+		}
+		if len(composer.OutputTargets(m.data.Pipeline, settings)) > 0 {
+			outputs, err := composer.ComposePipelineTargets(m.data.Pipeline, settings)
+			if err != nil {
+				return StatusMsg(fmt.Sprintf("× Failed to generate output: %v", err))
+			}
+			if err := composer.WriteTargetOutputs(outputs); err != nil {
+				return StatusMsg(fmt.Sprintf("× Failed to write output: %v", err))
+			}
+
+			m.data.OriginalComponents = make([]models.ComponentRef, len(m.data.SelectedComponents))
+			copy(m.data.OriginalComponents, m.data.SelectedComponents)
+			m.loadAvailableComponents()
+
+			return StatusMsg(fmt.Sprintf("✓ Saved & Set → %d targets", len(outputs)))
+		}
+
 		// Generate pipeline output
 		output, err := composer.ComposePipeline(m.data.Pipeline)
 		if err != nil {
@@ -462,9 +474,10 @@ func (m *PipelineBuilderModel) saveAndSetPipeline() tea.Cmd {
 
 		// Update preview if showing
 		if m.ui.ShowPreview {
-			m.ui.PreviewContent = output
+			m.ui.PreviewLint = lintWarnings(m.data.Pipeline)
+			m.ui.PreviewContent = m.ui.PreviewLint + output
 			// Preprocess content to handle carriage returns and ensure proper line breaks
-			processedContent := strings.ReplaceAll(output, "\r\r", "\n\n")
+			processedContent := strings.ReplaceAll(m.ui.PreviewContent, "\r\r", "\n\n")
 			processedContent = strings.ReplaceAll(processedContent, "\r", "\n")
 			// Wrap content to viewport width to prevent overflow
 			wrappedContent := wordwrap.String(processedContent, m.viewports.Preview.Width)
@@ -84,7 +84,9 @@ type BuilderSearchComponents struct {
 	// UI components
 	Bar          *SearchBar
 	Query        string
+	Expanded     string // Query with its @name saved searches expanded
 	FilterHelper *SearchFilterHelper
+	Sources      searchSources // Tags and saved searches, refreshed on reload
 }
 
 // BuilderUIComponents groups UI-specific components
@@ -101,6 +103,7 @@ type BuilderUIComponents struct {
 	RightCursor    int
 	ShowPreview    bool
 	PreviewContent string
+	PreviewLint    string // Lint warnings leading PreviewContent, left out of its token count
 	StatusMessage  string
 	SharedLayout   *SharedLayout
 
@@ -251,6 +254,7 @@ func (s *BuilderSearchComponents) IsSearchActive() bool {
 
 func (s *BuilderSearchComponents) ClearSearch() {
 	s.Query = ""
+	s.Expanded = ""
 	if s.Bar != nil {
 		s.Bar.SetValue("")
 	}
@@ -260,17 +264,21 @@ func (s *BuilderSearchComponents) ClearSearch() {
 func (s *BuilderSearchComponents) InitializeUnifiedManager() {
 	if s.UnifiedManager == nil {
 		s.UnifiedManager = unified.NewUnifiedSearchManager()
+		// Search the project's index rather than reading every file per keystroke
+		if index, err := unified.OpenSearchIndex(); err == nil {
+			s.UnifiedManager.SetIndex(index)
+		}
 	}
 }
 
 // ShouldIncludeArchived checks if archived items should be included based on search query
 func (s *BuilderSearchComponents) ShouldIncludeArchived() bool {
 	if s.UnifiedManager != nil {
-		return s.UnifiedManager.IsStructuredQuery(s.Query) && 
-			unified.ShouldIncludeArchived(s.Query)
+		return s.UnifiedManager.IsStructuredQuery(s.Expanded) && 
+			unified.ShouldIncludeArchived(s.Expanded)
 	}
 	// Fallback to existing logic
-	return unified.ShouldIncludeArchived(s.Query)
+	return unified.ShouldIncludeArchived(s.Expanded)
 }
 
 // Helper methods for BuilderUIComponents
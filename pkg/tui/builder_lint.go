@@ -0,0 +1,42 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/lint"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// maxPreviewLintWarnings is how many lint findings the builder preview lists
+// before pointing at pluqqy lint for the rest
+const maxPreviewLintWarnings = 10
+
+// lintWarnings renders what the prompt linter finds in the pipeline's components
+// as a block to show above its preview, the way the composer warns about missing
+// components. It is empty when there is nothing to report.
+func lintWarnings(pipeline *models.Pipeline) string {
+	settings, err := files.ReadSettings()
+	if err != nil {
+		settings = models.DefaultSettings()
+	}
+	findings, _, err := lint.LintPipeline(pipeline, settings.Lint)
+	if err != nil || len(findings) == 0 {
+		return ""
+	}
+
+	var warning strings.Builder
+	warning.WriteString("⚠️ **Warning: Lint**\n\n")
+	for i, finding := range findings {
+		if i == maxPreviewLintWarnings {
+			warning.WriteString(fmt.Sprintf("- ...and %d more\n", len(findings)-i))
+			break
+		}
+		warning.WriteString(fmt.Sprintf("- %s [%s]\n", finding, finding.Rule))
+	}
+	warning.WriteString("\nRun 'pluqqy lint' on the saved pipeline for the full list. Rules are set under lint in settings.yaml.\n\n")
+	warning.WriteString("---\n\n")
+
+	return warning.String()
+}
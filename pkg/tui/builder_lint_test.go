@@ -0,0 +1,89 @@
+package tui
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func TestLintWarnings(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatal(err)
+	}
+	files.WriteComponentWithNameAndTags("components/rules/tabs.md", "Always use tabs.", "", nil)
+	files.WriteComponentWithNameAndTags("components/rules/spaces.md", "Never use tabs.", "", nil)
+	pipeline := &models.Pipeline{
+		Name: "Style",
+		Components: []models.ComponentRef{
+			{Type: "rules", Path: "../components/rules/tabs.md", Order: 1},
+		},
+	}
+
+	// Nothing to report adds nothing
+	if got := lintWarnings(pipeline); got != "" {
+		t.Errorf("clean pipeline warnings = %q", got)
+	}
+
+	pipeline.Components = append(pipeline.Components, models.ComponentRef{Type: "rules", Path: "../components/rules/spaces.md", Order: 2})
+	got := lintWarnings(pipeline)
+	if !strings.Contains(got, "⚠️ **Warning: Lint**") || !strings.Contains(got, `"Never use tabs" contradicts "Always use tabs"`) {
+		t.Errorf("expected the contradiction in the warnings:\n%s", got)
+	}
+
+	// Long lists are cut short
+	var content strings.Builder
+	for i := 0; i < maxPreviewLintWarnings+3; i++ {
+		content.WriteString(fmt.Sprintf("Step %d as needed.\n", i))
+	}
+	files.WriteComponentWithNameAndTags("components/rules/spaces.md", content.String(), "", nil)
+	if got := lintWarnings(pipeline); !strings.Contains(got, "...and 3 more") {
+		t.Errorf("expected the list to be cut at %d:\n%s", maxPreviewLintWarnings, got)
+	}
+}
+
+func TestFindComponentInPreview_BelowLintWarnings(t *testing.T) {
+	m := makeTestBuilderModel()
+	m.data.SelectedComponents = []models.ComponentRef{
+		{Type: "rules", Path: "../components/rules/tabs.md", Order: 1},
+	}
+	// The warning quotes the component's first line, which must not match
+	m.ui.PreviewLint = "⚠️ **Warning: Lint**\n\n- rules/tabs.md:1: \"Always use tabs\" repeats \"Always use tabs\"\n\n"
+	output := "## RULES\n\nintro\nmore\nAlways use tabs\n"
+	m.ui.PreviewContent = m.ui.PreviewLint + output
+
+	lintLines := strings.Count(m.ui.PreviewLint, "\n")
+	want := lintLines + 4 - scrollContextLines
+	if got := m.findComponentInPreview("Always use tabs", "../components/rules/tabs.md"); got != want {
+		t.Errorf("findComponentInPreview() = %d, want %d", got, want)
+	}
+
+	m.data.SelectedComponents = append(m.data.SelectedComponents, models.ComponentRef{Type: "rules", Path: "../components/rules/other.md", Order: 2})
+	m.ui.PreviewContent += strings.Repeat("Other rule\n", 40)
+	m.ui.RightCursor = 1
+	if got := m.estimateComponentPosition(); got <= lintLines {
+		t.Errorf("estimateComponentPosition() = %d, inside the %d lines of lint warnings", got, lintLines)
+	}
+}
+
+func TestUpdatePreview_ClearsLintWarnings(t *testing.T) {
+	m := makeTestBuilderModel()
+	m.data.Pipeline = makeTestPipelineModel("Review", "review.yaml")
+	m.ui.ShowPreview = true
+	m.ui.ActiveColumn = rightColumn
+	m.ui.PreviewLint = "⚠️ **Warning: Lint**\n\n"
+
+	// With nothing selected the preview explains that, without lint warnings
+	m.updatePreview()
+	if m.ui.PreviewLint != "" {
+		t.Errorf("PreviewLint = %q after an empty pipeline preview, want it cleared", m.ui.PreviewLint)
+	}
+}
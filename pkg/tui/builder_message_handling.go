@@ -13,7 +13,6 @@ import (
 	"github.com/muesli/reflow/wordwrap"
 	"github.com/pluqqy/pluqqy-terminal/pkg/composer"
 	"github.com/pluqqy/pluqqy-terminal/pkg/files"
-	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 )
 
 // Main Update Method
@@ -25,8 +24,22 @@ func (m *PipelineBuilderModel) Init() tea.Cmd {
 func (m *PipelineBuilderModel) performSearch() {
 	// Initialize unified manager if needed
 	m.search.InitializeUnifiedManager()
+	m.search.Bar.SetLabel(m.search.FilterHelper.SavedSearchLabel(m.search.Query, m.search.Sources.SavedSearches()))
+	m.search.Bar.SetSuggestions(nil)
+	m.search.Bar.SetMatchCount(-1)
+	
+	// Expand @name saved searches once for everything below
+	expanded, err := m.search.Sources.Expand(m.search.Query)
+	m.search.Expanded = expanded
+	if err != nil {
+		// Keep the current results until the saved search is fixed
+		m.search.Bar.SetError(err)
+		return
+	}
 	
 	if m.search.Query == "" {
+		m.search.Bar.SetError(nil)
+		
 		// No search query, check if we need to reload without archived items
 		hasArchived := false
 		for _, p := range m.data.Prompts {
@@ -94,22 +107,27 @@ func (m *PipelineBuilderModel) performSearch() {
 		m.search.UnifiedManager.SetIncludeArchived(needsArchived)
 		
 		// Perform unified search
-		filteredPrompts, filteredContexts, filteredRules, err := m.search.UnifiedManager.FilterComponentsByQuery(m.search.Query, sharedPrompts, sharedContexts, sharedRules)
-		if err == nil {
-			// Convert results back to TUI format
-			m.data.FilteredPrompts = convertSharedComponentsToTUI(filteredPrompts)
-			m.data.FilteredContexts = convertSharedComponentsToTUI(filteredContexts)
-			m.data.FilteredRules = convertSharedComponentsToTUI(filteredRules)
-			
-			// Reset cursor if it's out of bounds
-			if m.ui.ActiveColumn == leftColumn {
-				totalItems := len(m.data.FilteredPrompts) + len(m.data.FilteredContexts) + len(m.data.FilteredRules)
-				if m.ui.LeftCursor >= totalItems {
-					m.ui.LeftCursor = 0
-				}
-			}
+		filteredPrompts, filteredContexts, filteredRules, err := m.search.UnifiedManager.FilterComponentsByQuery(m.search.Expanded, sharedPrompts, sharedContexts, sharedRules)
+		m.search.Bar.SetError(err)
+		if err != nil {
+			// Keep the current results while the query is malformed, e.g. mid-typing
 			return
 		}
+		
+		// Convert results back to TUI format
+		m.data.FilteredPrompts = convertSharedComponentsToTUI(filteredPrompts)
+		m.data.FilteredContexts = convertSharedComponentsToTUI(filteredContexts)
+		m.data.FilteredRules = convertSharedComponentsToTUI(filteredRules)
+		m.search.Bar.SetMatchCount(len(m.data.FilteredPrompts) + len(m.data.FilteredContexts) + len(m.data.FilteredRules))
+		
+		// Reset cursor if it's out of bounds
+		if m.ui.ActiveColumn == leftColumn {
+			totalItems := len(m.data.FilteredPrompts) + len(m.data.FilteredContexts) + len(m.data.FilteredRules)
+			if m.ui.LeftCursor >= totalItems {
+				m.ui.LeftCursor = 0
+			}
+		}
+		return
 	}
 
 	// Fallback to legacy search engine
@@ -245,6 +263,17 @@ func (m *PipelineBuilderModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case tagRenameCompleteMsg:
+		if m.editors.TagEditor != nil && m.editors.TagEditor.Active {
+			handled, cmd := m.editors.TagEditor.HandleMessage(msg)
+			if handled {
+				// Reload to reflect renamed tags
+				m.loadAvailableComponents()
+				return m, cmd
+			}
+		}
+		return m, nil
+
 	case tagDeletionProgressMsg:
 		// Handle tag deletion progress updates
 		if m.editors.TagEditor != nil && m.editors.TagEditor.Active {
@@ -551,9 +580,31 @@ func (m *PipelineBuilderModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			m.ui.ActiveColumn = leftColumn
 			m.search.Bar.SetActive(false)
 			return m, nil
+		case "up", "down":
+			// Choose a suggestion
+			if m.search.Bar.HasSuggestions() {
+				if msg.String() == "up" {
+					m.search.Bar.MoveSuggestion(-1)
+				} else {
+					m.search.Bar.MoveSuggestion(1)
+				}
+			}
+			return m, nil
+		case "enter":
+			// Settle on the query and close the suggestions
+			rememberSearch(m.search.Query)
+			m.search.Bar.SetSuggestions(nil)
+			return m, nil
 		case "tab":
-			// Let tab be handled by the main navigation logic
-			// Don't process it here
+			// Accept the selected suggestion
+			if m.search.Bar.AcceptSuggestion() {
+				m.search.Query = m.search.Bar.Value()
+				m.performSearch()
+				m.search.Bar.SetSuggestions(searchSuggestions(m.search.UnifiedManager, &m.search.Sources, m.search.Query))
+				return m, nil
+			}
+			// Otherwise let tab be handled by the main navigation logic
+			rememberSearch(m.search.Query)
 		case Shortcuts.ToggleArchived.Get():
 			// Toggle archived filter
 			newQuery := m.search.FilterHelper.ToggleArchivedFilter(m.search.Bar.Value())
@@ -568,6 +619,21 @@ func (m *PipelineBuilderModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			m.search.Query = newQuery
 			m.performSearch()
 			return m, nil
+		case Shortcuts.CycleSort.Get():
+			// Cycle sort order
+			newQuery := m.search.FilterHelper.CycleSortFilter(m.search.Bar.Value())
+			m.search.Bar.SetValue(newQuery)
+			m.search.Query = newQuery
+			m.performSearch()
+			return m, nil
+		case Shortcuts.CycleSaved.Get():
+			// Cycle through saved searches
+			searches := m.search.Sources.SavedSearches()
+			newQuery := m.search.FilterHelper.CycleSavedSearch(m.search.Bar.Value(), searches)
+			m.search.Bar.SetValue(newQuery)
+			m.search.Query = newQuery
+			m.performSearch()
+			return m, nil
 		default:
 			// For all other keys, update the search bar
 			var cmd tea.Cmd
@@ -577,6 +643,7 @@ func (m *PipelineBuilderModel) handleKeyMsg(msg tea.KeyMsg) (tea.Model, tea.Cmd)
 			if m.search.Query != m.search.Bar.Value() {
 				m.search.Query = m.search.Bar.Value()
 				m.performSearch()
+				m.search.Bar.SetSuggestions(searchSuggestions(m.search.UnifiedManager, &m.search.Sources, m.search.Query))
 			}
 
 			return m, cmd
@@ -1096,12 +1163,8 @@ func (m *PipelineBuilderModel) handleNormalModeKeys(msg tea.KeyMsg) (tea.Model,
 
 				// Determine component type
 				compType := ""
-				if strings.Contains(componentPath, "/prompts/") {
-					compType = models.ComponentTypePrompt
-				} else if strings.Contains(componentPath, "/contexts/") {
-					compType = models.ComponentTypeContext
-				} else if strings.Contains(componentPath, "/rules/") {
-					compType = models.ComponentTypeRules
+				if ct, ok := files.ComponentTypeForPath(componentPath); ok {
+					compType = ct.Name
 				}
 
 				// Start enhanced editor
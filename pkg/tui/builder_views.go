@@ -212,6 +212,7 @@ func (m *PipelineBuilderModel) View() string {
 		if err != nil || settings == nil {
 			settings = models.DefaultSettings()
 		}
+		sections := settings.OutputSections()
 
 		// Group components by type
 		typeGroups := make(map[string][]models.ComponentRef)
@@ -224,31 +225,21 @@ func (m *PipelineBuilderModel) View() string {
 		remainingSections := 0
 
 		// Count how many sections we'll actually display
-		for _, section := range settings.Output.Formatting.Sections {
+		for _, section := range sections {
 			if len(typeGroups[section.Type]) > 0 {
 				remainingSections++
 			}
 		}
 
 		// Render sections in the configured order
-		for _, section := range settings.Output.Formatting.Sections {
+		for _, section := range sections {
 			components, exists := typeGroups[section.Type]
 			if !exists || len(components) == 0 {
 				continue
 			}
 
 			// Get the display name for this section type
-			var sectionHeader string
-			switch section.Type {
-			case models.ComponentTypeContext:
-				sectionHeader = "CONTEXTS"
-			case models.ComponentTypePrompt:
-				sectionHeader = "PROMPTS"
-			case models.ComponentTypeRules:
-				sectionHeader = "RULES"
-			default:
-				sectionHeader = strings.ToUpper(section.Type)
-			}
+			sectionHeader := componentTypeHeader(section.Type)
 
 			rightScrollContent.WriteString(typeHeaderStyle.Render("▸ "+sectionHeader) + "\n")
 
@@ -285,6 +276,7 @@ func (m *PipelineBuilderModel) View() string {
 		if err != nil || settings == nil {
 			settings = models.DefaultSettings()
 		}
+		sections := settings.OutputSections()
 
 		// Calculate the line position of the cursor
 		currentLine := 0
@@ -297,7 +289,7 @@ func (m *PipelineBuilderModel) View() string {
 		}
 
 		// Count lines up to cursor position following section order
-		for sectionIdx, section := range settings.Output.Formatting.Sections {
+		for sectionIdx, section := range sections {
 			components, exists := typeGroups[section.Type]
 			if !exists || len(components) == 0 {
 				continue
@@ -320,8 +312,8 @@ func (m *PipelineBuilderModel) View() string {
 
 			// Add empty line if there are more sections
 			hasMoreSections := false
-			for j := sectionIdx + 1; j < len(settings.Output.Formatting.Sections); j++ {
-				if len(typeGroups[settings.Output.Formatting.Sections[j].Type]) > 0 {
+			for j := sectionIdx + 1; j < len(sections); j++ {
+				if len(typeGroups[sections[j].Type]) > 0 {
 					hasMoreSections = true
 					break
 				}
@@ -382,12 +374,13 @@ func (m *PipelineBuilderModel) View() string {
 		PaddingLeft(1).
 		PaddingRight(1)
 
-	s.WriteString(contentStyle.Render(columns))
+	// Drop any search suggestions down over the columns
+	s.WriteString(overlayAt(contentStyle.Render(columns), m.search.Bar.SuggestionsView(), m.search.Bar.SuggestionsOffset(), 0))
 
 	// Add preview if enabled
 	if m.ui.ShowPreview && m.ui.PreviewContent != "" {
 		// Calculate token count
-		tokenCount := utils.EstimateTokens(m.ui.PreviewContent)
+		tokenCount := utils.EstimateTokens(strings.TrimPrefix(m.ui.PreviewContent, m.ui.PreviewLint))
 		_, _, status := utils.GetTokenLimitStatus(tokenCount)
 
 		// Create token badge with appropriate color
@@ -453,9 +446,13 @@ func (m *PipelineBuilderModel) View() string {
 			previewHeading = fmt.Sprintf("PIPELINE PREVIEW (%s)", pipelineName)
 		}
 		tokenInfo := tokenBadge
+		if m.ui.ActiveColumn != leftColumn {
+			// Show how the pipeline fits its token budget
+			tokenInfo = m.renderTokenBudgetBar(tokenCount) + " " + tokenBadge
+		}
 
 		// Calculate the actual rendered width of token info
-		tokenInfoWidth := lipgloss.Width(tokenBadge)
+		tokenInfoWidth := lipgloss.Width(tokenInfo)
 
 		// Calculate total available width inside the border
 		totalWidth := m.viewports.Width - 8 // accounting for border padding and header padding
@@ -509,16 +506,22 @@ func (m *PipelineBuilderModel) View() string {
 		helpRows = [][]string{
 			{
 				fmt.Sprintf("%s switch pane", Shortcuts.SwitchPane.Get()),
+				"tab accept suggestion",
+				"↑↓ pick suggestion",
 				"esc clear+exit search",
 			},
 			{
 				"tag:<name>",
 				"type:<type>",
 				"status:archived",
+				"modified:>30d",
 				"<keyword>",
 				"combine with spaces",
+				"OR NOT -x ( )",
 				fmt.Sprintf("%s toggle archived", FormatShortcutForHelp(Shortcuts.ToggleArchived)),
 				fmt.Sprintf("%s cycle type", FormatShortcutForHelp(Shortcuts.CycleType)),
+				fmt.Sprintf("%s cycle sort", FormatShortcutForHelp(Shortcuts.CycleSort)),
+				fmt.Sprintf("%s saved searches", FormatShortcutForHelp(Shortcuts.CycleSaved)),
 			},
 		}
 	} else {
@@ -732,6 +735,7 @@ func (m *PipelineBuilderModel) adjustRightViewportScroll() {
 	if err != nil || settings == nil {
 		settings = models.DefaultSettings()
 	}
+	sections := settings.OutputSections()
 
 	// Group components by type
 	typeGroups := make(map[string][]models.ComponentRef)
@@ -740,7 +744,7 @@ func (m *PipelineBuilderModel) adjustRightViewportScroll() {
 	}
 
 	// Calculate cursor line position
-	for sectionIdx, section := range settings.Output.Formatting.Sections {
+	for sectionIdx, section := range sections {
 		components := typeGroups[section.Type]
 		if len(components) == 0 {
 			continue
@@ -758,8 +762,8 @@ func (m *PipelineBuilderModel) adjustRightViewportScroll() {
 
 		// Add empty line if there are more sections
 		hasMoreSections := false
-		for j := sectionIdx + 1; j < len(settings.Output.Formatting.Sections); j++ {
-			if len(typeGroups[settings.Output.Formatting.Sections[j].Type]) > 0 {
+		for j := sectionIdx + 1; j < len(sections); j++ {
+			if len(typeGroups[sections[j].Type]) > 0 {
 				hasMoreSections = true
 				break
 			}
@@ -783,6 +787,9 @@ func (m *PipelineBuilderModel) updatePreview() {
 		return
 	}
 
+	// Only a pipeline preview that composes has lint warnings
+	m.ui.PreviewLint = ""
+
 	// Show preview based on active column
 	if m.ui.ActiveColumn == leftColumn {
 		// Show component preview for left column
@@ -812,10 +819,15 @@ func (m *PipelineBuilderModel) updatePreview() {
 			return
 		}
 
-		// Create a temporary pipeline with current components
+		// Create a temporary pipeline with current components. The budget is
+		// disabled so the preview shows everything and the budget bar the real size.
 		tempPipeline := &models.Pipeline{
 			Name:       m.data.Pipeline.Name,
+			Path:       m.data.Pipeline.Path,
+			Extends:    m.data.Pipeline.Extends,
 			Components: m.data.SelectedComponents,
+			Variables:  m.data.Pipeline.Variables,
+			MaxTokens:  -1,
 		}
 
 		// Generate the preview
@@ -825,7 +837,8 @@ func (m *PipelineBuilderModel) updatePreview() {
 			return
 		}
 
-		m.ui.PreviewContent = output
+		m.ui.PreviewLint = lintWarnings(tempPipeline)
+		m.ui.PreviewContent = m.ui.PreviewLint + output
 	}
 }
 
@@ -847,8 +860,8 @@ func (m *PipelineBuilderModel) findComponentInPreview(componentContent, componen
 		return -1
 	}
 
-	// Calculate line position in the preview
-	lines := strings.Split(m.ui.PreviewContent, "\n")
+	// Calculate line position in the composed output, below any lint warnings
+	lines, offset := m.previewOutputLines()
 
 	// Track which occurrence we're looking for (components might repeat)
 	occurrenceCount := 0
@@ -866,7 +879,7 @@ func (m *PipelineBuilderModel) findComponentInPreview(componentContent, componen
 		if strings.Contains(line, firstContentLine) {
 			if occurrenceCount == targetOccurrence {
 				// Found the right occurrence, return line with context
-				targetLine := i - scrollContextLines
+				targetLine := offset + i - scrollContextLines
 				if targetLine < 0 {
 					targetLine = 0
 				}
@@ -879,13 +892,21 @@ func (m *PipelineBuilderModel) findComponentInPreview(componentContent, componen
 	return -1
 }
 
+// previewOutputLines returns the lines of the composed output in the preview and
+// the line it starts on, which is below the lint warnings shown above it
+func (m *PipelineBuilderModel) previewOutputLines() ([]string, int) {
+	output := strings.TrimPrefix(m.ui.PreviewContent, m.ui.PreviewLint)
+	offset := strings.Count(m.ui.PreviewContent[:len(m.ui.PreviewContent)-len(output)], "\n")
+	return strings.Split(output, "\n"), offset
+}
+
 // estimateComponentPosition estimates the line position of a component based on its order
 func (m *PipelineBuilderModel) estimateComponentPosition() int {
 	if m.ui.RightCursor == 0 {
 		return 0
 	}
 
-	lines := strings.Split(m.ui.PreviewContent, "\n")
+	lines, offset := m.previewOutputLines()
 
 	// Calculate average lines per component
 	linesPerComponent := 0
@@ -896,9 +917,9 @@ func (m *PipelineBuilderModel) estimateComponentPosition() int {
 		linesPerComponent = defaultLinesPerComponent
 	}
 
-	targetLine := (m.ui.RightCursor * linesPerComponent) + 5
-	if targetLine >= len(lines)-scrollBottomPadding {
-		targetLine = len(lines) - scrollBottomPadding
+	targetLine := offset + (m.ui.RightCursor * linesPerComponent) + 5
+	if targetLine >= offset+len(lines)-scrollBottomPadding {
+		targetLine = offset + len(lines) - scrollBottomPadding
 	}
 	if targetLine < 0 {
 		targetLine = 0
@@ -1101,4 +1122,53 @@ func (m *PipelineBuilderModel) nameInputView() string {
 	s.WriteString(contentStyle.Render(helpBorderStyle.Render(helpContent)))
 
 	return s.String()
-}
\ No newline at end of file
+}
+
+// renderTokenBudgetBar renders the pipeline's token usage as a bar against its
+// token budget, or against the nearest common context limit when it has none
+func (m *PipelineBuilderModel) renderTokenBudgetBar(tokenCount int) string {
+	const barWidth = 10
+
+	settings, err := files.ReadSettings()
+	if err != nil || settings == nil {
+		settings = models.DefaultSettings()
+	}
+
+	budget, _ := composer.EffectiveTokenBudget(m.data.Pipeline, settings)
+	percentage, status := utils.GetTokenBudgetStatus(tokenCount, budget)
+
+	var label string
+	if budget > 0 {
+		if tokenCount > budget {
+			label = fmt.Sprintf("%d over budget of %d", tokenCount-budget, budget)
+		} else {
+			label = fmt.Sprintf("%d%% of budget %d", percentage, budget)
+		}
+	} else {
+		_, limit, _ := utils.GetTokenLimitStatus(tokenCount)
+		label = fmt.Sprintf("%d%% of %dK", percentage, limit/1024)
+	}
+
+	filled := percentage * barWidth / 100
+	if filled > barWidth {
+		filled = barWidth
+	}
+
+	var color lipgloss.Color
+	switch status {
+	case "good":
+		color = lipgloss.Color("28") // Green
+	case "warning":
+		color = lipgloss.Color("214") // Yellow/Orange
+	default:
+		color = lipgloss.Color("196") // Red
+	}
+
+	filledStyle := lipgloss.NewStyle().Foreground(color)
+	emptyStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+	labelStyle := lipgloss.NewStyle().Foreground(color)
+
+	return filledStyle.Render(strings.Repeat("█", filled)) +
+		emptyStyle.Render(strings.Repeat("░", barWidth-filled)) + " " +
+		labelStyle.Render(label)
+}
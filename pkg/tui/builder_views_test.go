@@ -0,0 +1,40 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// TestPipelineBuilderModel_PreviewHeaderFitsWidth checks that the preview
+// header, with the token budget bar in front of the badge, fits on one line
+func TestPipelineBuilderModel_PreviewHeaderFitsWidth(t *testing.T) {
+	for _, column := range []column{rightColumn, previewColumn} {
+		m := makeTestBuilderModel()
+		m.editors.EditingName = false
+		m.data.Pipeline = makeTestPipelineModel("Review", "review.yaml")
+		m.ui.ActiveColumn = column
+		m.ui.ShowPreview = true
+		m.ui.PreviewContent = "Review the changes."
+		m.updateViewportSizes()
+
+		view := m.View()
+		header := ""
+		for _, line := range strings.Split(view, "\n") {
+			if strings.Contains(line, "PIPELINE PREVIEW") {
+				header = line
+				break
+			}
+		}
+		if header == "" {
+			t.Fatalf("column %d: no preview header in view:\n%s", column, view)
+		}
+		if !strings.Contains(header, "of 4K") || !strings.Contains(header, "::") {
+			t.Errorf("column %d: header should hold the colons and the budget bar: %q", column, header)
+		}
+		if width := lipgloss.Width(header); width > m.viewports.Width {
+			t.Errorf("column %d: header is %d wide, wider than the %d viewport", column, width, m.viewports.Width)
+		}
+	}
+}
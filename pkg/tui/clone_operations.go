@@ -39,16 +39,11 @@ func (co *CloneOperator) ValidateComponentClone(originalPath, newName string, to
 	slugifiedName := files.Slugify(newName)
 
 	// Determine the component type from the path
-	var componentType string
-	if strings.Contains(originalPath, "/prompts/") {
-		componentType = "prompts"
-	} else if strings.Contains(originalPath, "/contexts/") {
-		componentType = "contexts"
-	} else if strings.Contains(originalPath, "/rules/") {
-		componentType = "rules"
-	} else {
+	compType, ok := files.ComponentTypeForPath(originalPath)
+	if !ok {
 		return fmt.Errorf("unknown component type")
 	}
+	componentType := compType.DirName()
 
 	// Build the target path
 	targetFilename := slugifiedName + ".md"
@@ -115,16 +110,11 @@ func (co *CloneOperator) CloneComponent(originalPath, newName string, fromArchiv
 	content.Name = newName
 
 	// Determine the component type from the path
-	var componentType string
-	if strings.Contains(originalPath, "/prompts/") {
-		componentType = "prompts"
-	} else if strings.Contains(originalPath, "/contexts/") {
-		componentType = "contexts"
-	} else if strings.Contains(originalPath, "/rules/") {
-		componentType = "rules"
-	} else {
+	compType, ok := files.ComponentTypeForPath(originalPath)
+	if !ok {
 		return fmt.Errorf("unknown component type")
 	}
+	componentType := compType.DirName()
 
 	// Generate the new filename
 	newFilename := files.Slugify(newName) + ".md"
@@ -196,11 +186,18 @@ func (co *CloneOperator) ClonePipeline(originalPath, newName string, fromArchive
 
 	// Create new pipeline with updated name and path
 	newPipeline := &models.Pipeline{
-		Name:       newName,
-		Path:       targetPath,
-		Components: pipeline.Components,
-		OutputPath: pipeline.OutputPath,
-		Tags:       pipeline.Tags,
+		Name:           newName,
+		Path:           targetPath,
+		Extends:        pipeline.Extends,
+		Components:     pipeline.Components,
+		OutputPath:     pipeline.OutputPath,
+		Tags:           pipeline.Tags,
+		Variables:      pipeline.Variables,
+		MaxTokens:      pipeline.MaxTokens,
+		BudgetStrategy: pipeline.BudgetStrategy,
+		Format:         pipeline.Format,
+		Mode:           pipeline.Mode,
+		Targets:        pipeline.Targets,
 	}
 
 	// Write the pipeline
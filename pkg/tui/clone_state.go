@@ -198,16 +198,11 @@ func (cs *CloneState) nameExistsInternal(name string) bool {
 
 	if cs.ItemType == "component" {
 		// Determine the component type from the path
-		var componentType string
-		if strings.Contains(cs.OriginalPath, "/prompts/") {
-			componentType = "prompts"
-		} else if strings.Contains(cs.OriginalPath, "/contexts/") {
-			componentType = "contexts"
-		} else if strings.Contains(cs.OriginalPath, "/rules/") {
-			componentType = "rules"
-		} else {
+		compType, ok := files.ComponentTypeForPath(cs.OriginalPath)
+		if !ok {
 			return false
 		}
+		componentType := compType.DirName()
 
 		// Build the target path
 		targetFilename := slugifiedName + ".md"
@@ -274,16 +269,11 @@ func (cs *CloneState) validateClone() error {
 	// Check if file already exists
 	if cs.ItemType == "component" {
 		// Determine the component type from the path
-		var componentType string
-		if strings.Contains(cs.OriginalPath, "/prompts/") {
-			componentType = "prompts"
-		} else if strings.Contains(cs.OriginalPath, "/contexts/") {
-			componentType = "contexts"
-		} else if strings.Contains(cs.OriginalPath, "/rules/") {
-			componentType = "rules"
-		} else {
+		compType, ok := files.ComponentTypeForPath(cs.OriginalPath)
+		if !ok {
 			return fmt.Errorf("unknown component type")
 		}
+		componentType := compType.DirName()
 
 		// Build the target path
 		targetFilename := slugifiedName + ".md"
@@ -383,16 +373,11 @@ func (cs *CloneState) cloneComponent() error {
 	}
 
 	// Determine the component type from the path
-	var componentType string
-	if strings.Contains(cs.OriginalPath, "/prompts/") {
-		componentType = "prompts"
-	} else if strings.Contains(cs.OriginalPath, "/contexts/") {
-		componentType = "contexts"
-	} else if strings.Contains(cs.OriginalPath, "/rules/") {
-		componentType = "rules"
-	} else {
+	compType, ok := files.ComponentTypeForPath(cs.OriginalPath)
+	if !ok {
 		return fmt.Errorf("unknown component type")
 	}
+	componentType := compType.DirName()
 
 	// Generate the new filename and path
 	newFilename := files.Slugify(cs.NewName) + ".md"
@@ -448,10 +433,17 @@ func (cs *CloneState) clonePipeline() error {
 
 	// Create a new pipeline with updated name
 	newPipeline := &models.Pipeline{
-		Name:       cs.NewName,
-		Tags:       pipeline.Tags,
-		Components: pipeline.Components,
-		OutputPath: pipeline.OutputPath,
+		Name:           cs.NewName,
+		Tags:           pipeline.Tags,
+		Extends:        pipeline.Extends,
+		Components:     pipeline.Components,
+		OutputPath:     pipeline.OutputPath,
+		Variables:      pipeline.Variables,
+		MaxTokens:      pipeline.MaxTokens,
+		BudgetStrategy: pipeline.BudgetStrategy,
+		Format:         pipeline.Format,
+		Mode:           pipeline.Mode,
+		Targets:        pipeline.Targets,
 	}
 
 	// Generate the new filename
@@ -7,7 +7,7 @@ import (
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pluqqy/pluqqy-terminal/pkg/files"
-	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"github.com/pluqqy/pluqqy-terminal/pkg/tui/shared"
 )
 
 // ComponentCreator manages the state and logic for component creation
@@ -95,13 +95,13 @@ func (c *ComponentCreator) HandleTypeSelection(msg tea.KeyMsg) bool {
 		}
 		return true
 	case "down", "j":
-		if c.typeCursor < 2 {
+		if c.typeCursor < len(shared.CreatableComponentTypes())-1 {
 			c.typeCursor++
 		}
 		return true
 	case "enter":
-		types := []string{models.ComponentTypeContext, models.ComponentTypePrompt, models.ComponentTypeRules}
-		c.componentCreationType = types[c.typeCursor]
+		types := shared.CreatableComponentTypes()
+		c.componentCreationType = types[c.typeCursor].Name
 		c.creationStep = 1
 		return true
 	}
@@ -153,15 +153,7 @@ func (c *ComponentCreator) initializeEnhancedEditor() {
 	}
 
 	// Generate the component path that will be used when saving
-	var subDir string
-	switch c.componentCreationType {
-	case models.ComponentTypeContext:
-		subDir = models.ComponentTypeContext
-	case models.ComponentTypePrompt:
-		subDir = models.ComponentTypePrompt
-	case models.ComponentTypeRules:
-		subDir = models.ComponentTypeRules
-	}
+	subDir, _ := shared.ComponentTypeDir(c.componentCreationType)
 
 	// Ensure directory exists for new components
 	dir := filepath.Join(files.PluqqyDir, files.ComponentsDir, subDir)
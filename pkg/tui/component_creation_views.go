@@ -7,7 +7,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/muesli/reflow/wordwrap"
 	"github.com/pluqqy/pluqqy-terminal/pkg/files"
-	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"github.com/pluqqy/pluqqy-terminal/pkg/tui/shared"
 )
 
 // ComponentCreationViewRenderer handles rendering of component creation views
@@ -73,6 +73,12 @@ func (r *ComponentCreationViewRenderer) RenderTypeSelection(typeCursor int) stri
 		{"PROMPT", "Instructions or questions for the LLM"},
 		{"RULES", "Important constraints or guidelines"},
 	}
+	for _, ct := range shared.CreatableComponentTypes()[len(types):] {
+		types = append(types, struct {
+			name string
+			desc string
+		}{strings.ToUpper(ct.Name), "Custom component type in components/" + ct.DirName()})
+	}
 
 	for i, t := range types {
 		cursor := "  "
@@ -198,17 +204,7 @@ func (r *ComponentCreationViewRenderer) RenderNameInput(componentType, component
 	// Check if component name already exists and show warning
 	if componentName != "" {
 		testFilename := sanitizeFileName(componentName) + ".md"
-		var componentTypeDir string
-		switch componentType {
-		case models.ComponentTypeContext:
-			componentTypeDir = "contexts"
-		case models.ComponentTypePrompt:
-			componentTypeDir = "prompts"
-		case models.ComponentTypeRules:
-			componentTypeDir = "rules"
-		}
-
-		existingComponents, _ := files.ListComponents(componentTypeDir)
+		existingComponents, _ := files.ListComponents(componentType)
 		for _, existing := range existingComponents {
 			if strings.EqualFold(existing, testFilename) {
 				// Show warning
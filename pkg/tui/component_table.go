@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
 	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 )
 
@@ -27,6 +28,12 @@ type ComponentTableRenderer struct {
 	ShowAddedIndicator bool
 	AddedComponents    map[string]bool // For tracking which components are already added
 	Viewport           viewport.Model
+
+	// Folder tree (Main List View only). When set, Tree holds every component
+	// arranged by folder and Components the ones outside collapsed folders.
+	Tree             []componentItem
+	CollapsedFolders map[string]bool
+	cursorLine       int
 }
 
 // NewComponentTableRenderer creates a new component table renderer with the specified dimensions.
@@ -67,6 +74,14 @@ func (r *ComponentTableRenderer) SetComponents(components []componentItem) {
 	r.updateContent()
 }
 
+// SetFolderTree enables folder headers for nested components.
+// The tree should be arranged so each folder's components are contiguous;
+// pass a nil tree to render the components as a flat list.
+func (r *ComponentTableRenderer) SetFolderTree(tree []componentItem, collapsed map[string]bool) {
+	r.Tree = tree
+	r.CollapsedFolders = collapsed
+}
+
 // SetCursor updates the cursor position to highlight a specific component.
 // The viewport will automatically scroll to ensure the cursor is visible.
 // The cursor value should be within the bounds of the component list.
@@ -162,12 +177,10 @@ func (r *ComponentTableRenderer) updateContent() {
 func (r *ComponentTableRenderer) buildTableContent(nameWidth, tagsWidth, tokenWidth, usageWidth int) string {
 	var content strings.Builder
 
-	normalStyle := NormalStyle
 	dimmedStyle := EmptyInactiveStyle
 	typeHeaderStyle := TypeHeaderStyle
-	selectedStyle := SelectedStyle
 
-	if len(r.Components) == 0 {
+	if len(r.Components) == 0 && len(r.Tree) == 0 {
 		if r.IsActive {
 			emptyStyle := EmptyActiveStyle
 			content.WriteString(emptyStyle.Render("No components found.\n\nPress 'n' to create one\nor 'E' to import examples"))
@@ -177,6 +190,10 @@ func (r *ComponentTableRenderer) buildTableContent(nameWidth, tagsWidth, tokenWi
 		return content.String()
 	}
 
+	if r.Tree != nil {
+		return r.buildTreeContent(nameWidth, tagsWidth, tokenWidth, usageWidth)
+	}
+
 	currentType := ""
 	for i, comp := range r.Components {
 		// Add type headers
@@ -185,108 +202,185 @@ func (r *ComponentTableRenderer) buildTableContent(nameWidth, tagsWidth, tokenWi
 				content.WriteString("\n")
 			}
 			currentType = comp.compType
+			content.WriteString(typeHeaderStyle.Render(fmt.Sprintf("▸ %s", componentTypeHeader(currentType))) + "\n")
+		}
 
-			var typeHeader string
-			switch currentType {
-			case models.ComponentTypeContext:
-				typeHeader = "CONTEXTS"
-			case models.ComponentTypePrompt:
-				typeHeader = "PROMPTS"
-			case models.ComponentTypeRules:
-				typeHeader = "RULES"
-			default:
-				typeHeader = strings.ToUpper(currentType)
-			}
-			content.WriteString(typeHeaderStyle.Render(fmt.Sprintf("▸ %s", typeHeader)) + "\n")
+		isSelected := r.IsActive && i == r.Cursor
+		content.WriteString(r.renderRow(comp, isSelected, "", nameWidth, tagsWidth, tokenWidth, usageWidth))
+
+		if i < len(r.Components)-1 {
+			content.WriteString("\n")
 		}
+	}
 
-		// Check if component is added (for Pipeline Builder)
-		isAdded := false
-		if r.ShowAddedIndicator {
-			componentPath := "../" + comp.path
-			isAdded = r.AddedComponents[componentPath]
+	return content.String()
+}
+
+// buildTreeContent renders the folder tree: type headers, a header per folder
+// (▾ expanded, ▸ collapsed with its hidden count) and indented components.
+// Components inside collapsed folders are skipped; the cursor indexes the visible ones.
+func (r *ComponentTableRenderer) buildTreeContent(nameWidth, tagsWidth, tokenWidth, usageWidth int) string {
+	typeHeaderStyle := TypeHeaderStyle
+	folderStyle := EmptyInactiveStyle
+
+	// Count hidden components per collapsed folder for the folder headers
+	hidden := make(map[string]int)
+	for _, comp := range r.Tree {
+		if key := collapsedAncestor(r.CollapsedFolders, comp.compType, componentFolder(comp)); key != "" {
+			hidden[key]++
 		}
+	}
 
-		// Format name with indicators
-		nameStr := comp.name
-		if comp.isArchived {
-			nameStr = "[A] " + nameStr
+	var lines []string
+	currentType, currentFolder := "", ""
+	visibleIndex := 0
+	r.cursorLine = 0
+	for _, comp := range r.Tree {
+		if comp.compType != currentType {
+			if currentType != "" {
+				lines = append(lines, "")
+			}
+			currentType, currentFolder = comp.compType, ""
+			lines = append(lines, typeHeaderStyle.Render(fmt.Sprintf("▸ %s", componentTypeHeader(currentType))))
 		}
-		if isAdded {
-			nameStr = "✓ " + nameStr
+
+		folder := componentFolder(comp)
+		collapsedKey := collapsedAncestor(r.CollapsedFolders, comp.compType, folder)
+		if folder != currentFolder {
+			currentFolder = folder
+			switch {
+			case folder == "":
+				// Top-level components have no folder header
+			case collapsedKey == "":
+				lines = append(lines, folderStyle.Render(fmt.Sprintf("  ▾ %s/", folder)))
+			case collapsedKey == folderKey(comp.compType, folder):
+				lines = append(lines, folderStyle.Render(fmt.Sprintf("  ▸ %s/ (%d)", folder, hidden[collapsedKey])))
+			}
+		}
+		if collapsedKey != "" {
+			continue
 		}
-		nameStr = truncateName(nameStr, nameWidth)
 
-		// Format other columns
-		tokenStr := fmt.Sprintf("%d", comp.tokenCount)
-		tagsStr := renderTagChipsWithWidth(comp.tags, tagsWidth, 2)
+		indent := ""
+		if folder != "" {
+			indent = "  "
+		}
+		isSelected := r.IsActive && visibleIndex == r.Cursor
+		if isSelected {
+			r.cursorLine = len(lines)
+		}
+		lines = append(lines, r.renderRow(comp, isSelected, indent, nameWidth, tagsWidth, tokenWidth, usageWidth))
+		visibleIndex++
+	}
 
-		// Build row parts
-		namePart := fmt.Sprintf("%-*s", nameWidth, nameStr)
+	return strings.Join(lines, "\n")
+}
 
-		// Pad tags based on rendered width
-		tagsPadding := tagsWidth - lipgloss.Width(tagsStr)
-		if tagsPadding < 0 {
-			tagsPadding = 0
+// componentTypeHeader returns the section header shown above a component type.
+// Custom types are shown under the heading configured for them in settings.
+func componentTypeHeader(compType string) string {
+	switch compType {
+	case models.ComponentTypeContext:
+		return "CONTEXTS"
+	case models.ComponentTypePrompt:
+		return "PROMPTS"
+	case models.ComponentTypeRules:
+		return "RULES"
+	default:
+		if ct, ok := files.LookupComponentType(compType); ok {
+			return strings.ToUpper(strings.TrimSpace(strings.TrimLeft(ct.SectionHeading(), "#")))
 		}
-		tagsPart := tagsStr + strings.Repeat(" ", tagsPadding)
+		return strings.ToUpper(compType)
+	}
+}
 
-		tokenPart := fmt.Sprintf("%*s", tokenWidth, tokenStr)
+// renderRow formats a single component row. The indent is placed before the
+// name so components inside folders line up under their folder header.
+func (r *ComponentTableRenderer) renderRow(comp componentItem, isSelected bool, indent string, nameWidth, tagsWidth, tokenWidth, usageWidth int) string {
+	normalStyle := NormalStyle
+	dimmedStyle := EmptyInactiveStyle
+	selectedStyle := SelectedStyle
 
-		// Build complete row
-		var row string
-		rowPrefix := "  "
+	// Check if component is added (for Pipeline Builder)
+	isAdded := false
+	if r.ShowAddedIndicator {
+		componentPath := "../" + comp.path
+		isAdded = r.AddedComponents[componentPath]
+	}
 
-		// Determine styling
-		isSelected := r.IsActive && i == r.Cursor
-		if isSelected {
-			rowPrefix = "▸ "
-		}
+	// Indicators shown before the name
+	namePrefix := indent
+	if comp.isArchived {
+		namePrefix = "[A] " + namePrefix
+	}
+	if isAdded {
+		namePrefix = "✓ " + namePrefix
+	}
+
+	// Format other columns
+	tokenStr := fmt.Sprintf("%d", comp.tokenCount)
+	tagsStr := renderTagChipsWithWidth(comp.tags, tagsWidth, 2)
+
+	// Build row parts, underlining what the search matched in the name
+	renderName := func(style lipgloss.Style) string {
+		return formatMatchedName(namePrefix, comp.name, comp.nameMatches, nameWidth, style)
+	}
+
+	// Pad tags based on rendered width
+	tagsPadding := tagsWidth - lipgloss.Width(tagsStr)
+	if tagsPadding < 0 {
+		tagsPadding = 0
+	}
+	tagsPart := tagsStr + strings.Repeat(" ", tagsPadding)
+
+	tokenPart := fmt.Sprintf("%*s", tokenWidth, tokenStr)
+
+	// Build complete row
+	var row string
+	rowPrefix := "  "
+
+	// Determine styling
+	if isSelected {
+		rowPrefix = "▸ "
+	}
+
+	if r.ShowUsageColumn {
+		usageStr := fmt.Sprintf("%d", comp.usageCount)
+		usagePart := fmt.Sprintf("%*s", usageWidth, usageStr)
 
-		if r.ShowUsageColumn {
-			usageStr := fmt.Sprintf("%d", comp.usageCount)
-			usagePart := fmt.Sprintf("%*s", usageWidth, usageStr)
-
-			if isSelected {
-				if comp.isArchived {
-					row = rowPrefix + dimmedStyle.Render(namePart) + " " + tagsPart + "  " + dimmedStyle.Render(tokenPart+" "+usagePart)
-				} else {
-					row = rowPrefix + selectedStyle.Render(namePart) + " " + tagsPart + "  " + normalStyle.Render(tokenPart+" "+usagePart)
-				}
-			} else if isAdded {
-				addedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("242"))
-				row = rowPrefix + addedStyle.Render(namePart) + " " + tagsPart + "  " + addedStyle.Render(tokenPart+" "+usagePart)
-			} else if comp.isArchived {
-				row = rowPrefix + dimmedStyle.Render(namePart) + " " + tagsPart + "  " + dimmedStyle.Render(tokenPart+" "+usagePart)
+		if isSelected {
+			if comp.isArchived {
+				row = rowPrefix + renderName(dimmedStyle) + " " + tagsPart + "  " + dimmedStyle.Render(tokenPart+" "+usagePart)
 			} else {
-				row = rowPrefix + normalStyle.Render(namePart) + " " + tagsPart + "  " + normalStyle.Render(tokenPart+" "+usagePart)
+				row = rowPrefix + renderName(selectedStyle) + " " + tagsPart + "  " + normalStyle.Render(tokenPart+" "+usagePart)
 			}
+		} else if isAdded {
+			addedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("242"))
+			row = rowPrefix + renderName(addedStyle) + " " + tagsPart + "  " + addedStyle.Render(tokenPart+" "+usagePart)
+		} else if comp.isArchived {
+			row = rowPrefix + renderName(dimmedStyle) + " " + tagsPart + "  " + dimmedStyle.Render(tokenPart+" "+usagePart)
 		} else {
-			// Without usage column
-			if isSelected {
-				if comp.isArchived {
-					row = rowPrefix + dimmedStyle.Render(namePart) + " " + tagsPart + "  " + dimmedStyle.Render(tokenPart)
-				} else {
-					row = rowPrefix + selectedStyle.Render(namePart) + " " + tagsPart + "  " + normalStyle.Render(tokenPart)
-				}
-			} else if isAdded {
-				addedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("242"))
-				row = rowPrefix + addedStyle.Render(namePart) + " " + tagsPart + "  " + addedStyle.Render(tokenPart)
-			} else if comp.isArchived {
-				row = rowPrefix + dimmedStyle.Render(namePart) + " " + tagsPart + "  " + dimmedStyle.Render(tokenPart)
+			row = rowPrefix + renderName(normalStyle) + " " + tagsPart + "  " + normalStyle.Render(tokenPart+" "+usagePart)
+		}
+	} else {
+		// Without usage column
+		if isSelected {
+			if comp.isArchived {
+				row = rowPrefix + renderName(dimmedStyle) + " " + tagsPart + "  " + dimmedStyle.Render(tokenPart)
 			} else {
-				row = rowPrefix + normalStyle.Render(namePart) + " " + tagsPart + "  " + normalStyle.Render(tokenPart)
+				row = rowPrefix + renderName(selectedStyle) + " " + tagsPart + "  " + normalStyle.Render(tokenPart)
 			}
-		}
-
-		content.WriteString(row)
-
-		if i < len(r.Components)-1 {
-			content.WriteString("\n")
+		} else if isAdded {
+			addedStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("242"))
+			row = rowPrefix + renderName(addedStyle) + " " + tagsPart + "  " + addedStyle.Render(tokenPart)
+		} else if comp.isArchived {
+			row = rowPrefix + renderName(dimmedStyle) + " " + tagsPart + "  " + dimmedStyle.Render(tokenPart)
+		} else {
+			row = rowPrefix + renderName(normalStyle) + " " + tagsPart + "  " + normalStyle.Render(tokenPart)
 		}
 	}
 
-	return content.String()
+	return row
 }
 
 // updateViewportScroll adjusts the viewport offset to ensure the cursor is visible.
@@ -298,6 +392,12 @@ func (r *ComponentTableRenderer) updateViewportScroll() {
 		return
 	}
 
+	// The folder tree records the cursor line while rendering
+	if r.Tree != nil {
+		r.scrollToLine(r.cursorLine)
+		return
+	}
+
 	// Calculate the line position of the cursor
 	currentLine := 0
 	for i := 0; i < r.Cursor && i < len(r.Components); i++ {
@@ -311,10 +411,14 @@ func (r *ComponentTableRenderer) updateViewportScroll() {
 		}
 	}
 
-	// Ensure the cursor line is visible
-	if currentLine < r.Viewport.YOffset {
-		r.Viewport.SetYOffset(currentLine)
-	} else if currentLine >= r.Viewport.YOffset+r.Viewport.Height {
-		r.Viewport.SetYOffset(currentLine - r.Viewport.Height + 1)
+	r.scrollToLine(currentLine)
+}
+
+// scrollToLine scrolls the viewport just enough to show the given line
+func (r *ComponentTableRenderer) scrollToLine(line int) {
+	if line < r.Viewport.YOffset {
+		r.Viewport.SetYOffset(line)
+	} else if line >= r.Viewport.YOffset+r.Viewport.Height {
+		r.Viewport.SetYOffset(line - r.Viewport.Height + 1)
 	}
 }
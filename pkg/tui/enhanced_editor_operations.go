@@ -7,6 +7,7 @@ import (
 	"strings"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pluqqy/pluqqy-terminal/pkg/composer"
 	"github.com/pluqqy/pluqqy-terminal/pkg/files"
 )
 
@@ -447,61 +448,25 @@ func ValidateComponentContent(content string) error {
 	return nil
 }
 
-// ExtractFileReferences finds all @file references in content
+// ExtractFileReferences finds all @file references in content, the same way
+// the composer finds them
 func ExtractFileReferences(content string) []string {
-	var refs []string
-	lines := strings.Split(content, "\n")
-
-	for _, line := range lines {
-		// Find all @ references in the line
-		for i := 0; i < len(line); i++ {
-			if line[i] == '@' && i+1 < len(line) {
-				// Find the end of the reference (space or newline)
-				end := i + 1
-				for end < len(line) && line[end] != ' ' && line[end] != '\t' {
-					end++
-				}
-				if end > i+1 {
-					refs = append(refs, line[i:end])
-				}
-			}
-		}
-	}
-
-	return refs
+	return composer.ExtractFileReferences(content)
 }
 
 // ValidateFileReference checks if a file reference is valid
 func ValidateFileReference(ref string) error {
-	if !strings.HasPrefix(ref, "@") {
-		return fmt.Errorf("reference must start with @")
-	}
-
-	path := strings.TrimPrefix(ref, "@")
-	if path == "" {
-		return fmt.Errorf("empty path")
-	}
-
-	// Check for dangerous path elements
-	if strings.Contains(path, "..") {
-		return fmt.Errorf("path traversal not allowed")
-	}
-
-	return nil
+	_, err := composer.ParseFileReference(ref)
+	return err
 }
 
 // ParseFileReference extracts the path from a file reference
 func ParseFileReference(ref string) (string, error) {
-	if !strings.HasPrefix(ref, "@") {
-		return "", fmt.Errorf("invalid reference format")
-	}
-
-	path := strings.TrimPrefix(ref, "@")
-	if path == "" {
-		return "", fmt.Errorf("empty path")
+	parsed, err := composer.ParseFileReference(ref)
+	if err != nil {
+		return "", err
 	}
-
-	return path, nil
+	return parsed.Path, nil
 }
 
 // InsertTextAtCursor inserts text at a specific position
@@ -177,6 +177,16 @@ func TestExtractFileReferences(t *testing.T) {
 			content:  "Line 1 @ref1.md\nLine 2 @ref2.md\nLine 3",
 			expected: []string{"@ref1.md", "@ref2.md"},
 		},
+		{
+			name:     "email address is not a reference",
+			content:  "Mail team@example.com about @notes.md.",
+			expected: []string{"@notes.md"},
+		},
+		{
+			name:     "references in code blocks are ignored",
+			content:  "```\n@skip.md\n```\n@keep.md",
+			expected: []string{"@keep.md"},
+		},
 	}
 
 	for _, tt := range tests {
@@ -278,6 +288,12 @@ func TestParseFileReference(t *testing.T) {
 			wantPath: "a/b/c/d.txt",
 			wantErr:  false,
 		},
+		{
+			name:     "line range",
+			ref:      "@src/main.go#L3-5",
+			wantPath: "src/main.go",
+			wantErr:  false,
+		},
 	}
 
 	for _, tt := range tests {
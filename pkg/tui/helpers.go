@@ -3,6 +3,10 @@ package tui
 import (
 	"fmt"
 	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
 )
 
 // pluralize returns "s" for counts other than 1, empty string for 1
@@ -22,6 +26,44 @@ func truncateName(name string, maxWidth int) string {
 	return name
 }
 
+// formatMatchedName formats a name column: the indicators in prefix followed by
+// the name, truncated and padded to width and rendered in style. Spans of the
+// name matched by a search are underlined; matches are byte offsets into name
+// and are clipped to the part left after truncation.
+func formatMatchedName(prefix, name string, matches []unified.MatchRange, width int, style lipgloss.Style) string {
+	fullName := prefix + name
+	nameStr := truncateName(fullName, width)
+	namePart := fmt.Sprintf("%-*s", width, nameStr)
+	if len(matches) == 0 {
+		return style.Render(namePart)
+	}
+
+	visible := len(nameStr)
+	if nameStr != fullName {
+		visible -= len("...")
+	}
+	underline := style.Underline(true)
+
+	var b strings.Builder
+	pos := 0
+	for _, match := range matches {
+		from, to := len(prefix)+match.Start, len(prefix)+match.End
+		if to > visible {
+			to = visible
+		}
+		if from < pos || from >= to {
+			continue
+		}
+		if from > pos {
+			b.WriteString(style.Render(namePart[pos:from]))
+		}
+		b.WriteString(underline.Render(namePart[from:to]))
+		pos = to
+	}
+	b.WriteString(style.Render(namePart[pos:]))
+	return b.String()
+}
+
 // formatTableRow formats component/pipeline data into aligned columns
 func formatTableRow(name, tags, tokens, usage string, nameWidth, tagsWidth, tokenWidth, usageWidth int) string {
 	namePart := fmt.Sprintf("%-*s", nameWidth, name)
@@ -103,3 +145,27 @@ func overlayViews(base, overlay string) string {
 
 	return strings.Join(result, "\n")
 }
+
+// overlayAt draws overlay on top of base with its top-left corner at column x of
+// line y, keeping the base's content to the left and right of it
+func overlayAt(base, overlay string, x, y int) string {
+	if overlay == "" {
+		return base
+	}
+	baseLines := strings.Split(base, "\n")
+	for i, overlayLine := range strings.Split(overlay, "\n") {
+		row := y + i
+		for row >= len(baseLines) {
+			baseLines = append(baseLines, "")
+		}
+
+		line := baseLines[row]
+		left := ansi.Truncate(line, x, "")
+		if gap := x - ansi.StringWidth(left); gap > 0 {
+			left += strings.Repeat(" ", gap)
+		}
+		right := ansi.TruncateLeft(line, x+ansi.StringWidth(overlayLine), "")
+		baseLines[row] = left + "\x1b[0m" + overlayLine + "\x1b[0m" + right
+	}
+	return strings.Join(baseLines, "\n")
+}
@@ -3,6 +3,10 @@ package tui
 import (
 	"strings"
 	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
 )
 
 // Test helper functions
@@ -162,6 +166,82 @@ func TestTruncateName(t *testing.T) {
 	}
 }
 
+// TestFormatMatchedName tests that matched spans don't change the column text
+func TestFormatMatchedName(t *testing.T) {
+	tests := []struct {
+		name    string
+		prefix  string
+		input   string
+		matches []unified.MatchRange
+		width   int
+		want    string
+	}{
+		{
+			name:  "no matches",
+			input: "API Docs",
+			width: 12,
+			want:  "API Docs    ",
+		},
+		{
+			name:    "matches after a prefix",
+			prefix:  "[A] ",
+			input:   "API Docs",
+			matches: []unified.MatchRange{{Start: 0, End: 3}, {Start: 4, End: 6}},
+			width:   16,
+			want:    "[A] API Docs    ",
+		},
+		{
+			name:    "match cut off by truncation",
+			input:   "verylongname",
+			matches: []unified.MatchRange{{Start: 2, End: 12}},
+			width:   10,
+			want:    "very...   ",
+		},
+		{
+			name:    "match entirely past truncation",
+			input:   "verylongname",
+			matches: []unified.MatchRange{{Start: 8, End: 12}},
+			width:   10,
+			want:    "very...   ",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := formatMatchedName(tt.prefix, tt.input, tt.matches, tt.width, NormalStyle)
+			assertStringEqual(t, ansi.Strip(got), tt.want, "formatMatchedName text")
+		})
+	}
+}
+
+// TestFilterSearchResultsUnified_NameMatches tests that search results carry the
+// matched spans of their names for underlining
+func TestFilterSearchResultsUnified_NameMatches(t *testing.T) {
+	components := []componentItem{
+		{name: "api-docs", path: "components/contexts/api-docs.md", compType: models.ComponentTypeContext},
+		{name: "style", path: "components/rules/style.md", compType: models.ComponentTypeRules},
+	}
+	pipelines := []pipelineItem{
+		{name: "api-review", path: "pipelines/api-review.yaml"},
+	}
+
+	filteredPipelines, filteredComponents, err := FilterSearchResultsUnified(unified.NewUnifiedSearchManager(), "api", pipelines, components)
+	if err != nil {
+		t.Fatalf("FilterSearchResultsUnified() error = %v", err)
+	}
+	if len(filteredComponents) != 1 || len(filteredPipelines) != 1 {
+		t.Fatalf("Expected one component and one pipeline, got %d and %d", len(filteredComponents), len(filteredPipelines))
+	}
+
+	want := []unified.MatchRange{{Start: 0, End: 3}}
+	if got := filteredComponents[0].nameMatches; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("component nameMatches = %v, want %v", got, want)
+	}
+	if got := filteredPipelines[0].nameMatches; len(got) != 1 || got[0] != want[0] {
+		t.Errorf("pipeline nameMatches = %v, want %v", got, want)
+	}
+}
+
 // TestFormatTableRow tests the formatTableRow function
 func TestFormatTableRow(t *testing.T) {
 	tests := []struct {
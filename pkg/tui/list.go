@@ -32,8 +32,22 @@ type MainListModel struct {
 func (m *MainListModel) performSearch() {
 	// Initialize unified manager if needed
 	m.search.InitializeUnifiedManager()
+	m.search.Bar.SetLabel(m.search.FilterHelper.SavedSearchLabel(m.search.Query, m.search.Sources.SavedSearches()))
+	m.search.Bar.SetSuggestions(nil)
+	m.search.Bar.SetMatchCount(-1)
+	
+	// Expand @name saved searches once for everything below
+	expanded, err := m.search.Sources.Expand(m.search.Query)
+	m.search.Expanded = expanded
+	if err != nil {
+		// Keep the current results until the saved search is fixed
+		m.search.Bar.SetError(err)
+		return
+	}
 	
 	if m.search.Query == "" {
+		m.search.Bar.SetError(nil)
+		
 		// No search query, check if we need to reload without archived items
 		currentHasArchived := false
 		for _, p := range m.data.Pipelines {
@@ -51,8 +65,8 @@ func (m *MainListModel) performSearch() {
 		}
 
 		// Show all active items
-		m.data.FilteredPipelines = m.data.Pipelines
-		m.data.FilteredComponents = m.operations.BusinessLogic.GetAllComponents()
+		m.setPipelineTree(m.data.Pipelines)
+		m.setComponentTree(m.operations.BusinessLogic.GetAllComponents())
 
 		// Update state manager with current counts for proper cursor navigation
 		m.stateManager.UpdateCounts(len(m.data.FilteredComponents), len(m.data.FilteredPipelines))
@@ -87,12 +101,21 @@ func (m *MainListModel) performSearch() {
 		// Configure unified manager
 		m.search.UnifiedManager.SetIncludeArchived(needsArchived)
 		
-		// Use the new unified filter function
-		m.data.FilteredPipelines, m.data.FilteredComponents = FilterSearchResultsUnified(
-			m.search.Query,
+		// Use the new unified filter function; results are shown flat, without folders
+		filteredPipelines, filteredComponents, err := FilterSearchResultsUnified(
+			m.search.UnifiedManager,
+			m.search.Expanded,
 			m.data.Pipelines,
 			m.operations.BusinessLogic.GetAllComponents(),
 		)
+		m.search.Bar.SetError(err)
+		if err != nil {
+			// Keep the current results while the query is malformed, e.g. mid-typing
+			return
+		}
+		m.data.ComponentTree, m.data.PipelineTree = nil, nil
+		m.data.FilteredPipelines, m.data.FilteredComponents = filteredPipelines, filteredComponents
+		m.search.Bar.SetMatchCount(len(filteredPipelines) + len(filteredComponents))
 
 		// Update state manager with filtered counts for proper cursor navigation
 		m.stateManager.UpdateCounts(len(m.data.FilteredComponents), len(m.data.FilteredPipelines))
@@ -103,8 +126,8 @@ func (m *MainListModel) performSearch() {
 	}
 
 	// If unified search is not available, show all items
-	m.data.FilteredPipelines = m.data.Pipelines
-	m.data.FilteredComponents = m.operations.BusinessLogic.GetAllComponents()
+	m.setPipelineTree(m.data.Pipelines)
+	m.setComponentTree(m.operations.BusinessLogic.GetAllComponents())
 
 	// Update state manager with current counts for proper cursor navigation
 	m.stateManager.UpdateCounts(len(m.data.FilteredComponents), len(m.data.FilteredPipelines))
@@ -146,9 +169,10 @@ func (m *MainListModel) getVisibleComponents() []componentItem {
 	return m.data.FilteredComponents
 }
 
-// getCurrentPipelines returns either filtered pipelines (if searching) or all pipelines
+// getCurrentPipelines returns either filtered pipelines (if searching or shown as
+// a folder tree) or all pipelines
 func (m *MainListModel) getCurrentPipelines() []pipelineItem {
-	if m.search.Query != "" {
+	if m.search.Query != "" || m.data.PipelineTree != nil {
 		return m.data.FilteredPipelines
 	}
 	return m.data.Pipelines
@@ -160,7 +184,7 @@ func (m *MainListModel) getFilteredPipelines() []pipelineItem {
 }
 
 func (m *MainListModel) getEditingItemName() string {
-	return GetEditingItemName(m.editors.TagEditor, m.stateManager, m.getCurrentComponents(), m.data.Pipelines)
+	return GetEditingItemName(m.editors.TagEditor, m.stateManager, m.getCurrentComponents(), m.getCurrentPipelines())
 }
 
 func (m *MainListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
@@ -200,8 +224,16 @@ func (m *MainListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+		// Handle variable prompt input if active
+		if m.editors.VariablePrompt.State.Active {
+			handled, cmd := m.editors.VariablePrompt.State.HandleInput(msg)
+			if handled {
+				return m, cmd
+			}
+		}
+
 		// Handle search input when search pane is active
-		if m.stateManager.IsInSearchPane() && !m.editors.TagEditor.Active && !m.operations.ComponentCreator.IsActive() && !m.editors.Rename.State.Active && !m.editors.Clone.State.Active {
+		if m.stateManager.IsInSearchPane() && !m.editors.TagEditor.Active && !m.operations.ComponentCreator.IsActive() && !m.editors.Rename.State.Active && !m.editors.Clone.State.Active && !m.editors.VariablePrompt.State.Active {
 			var cmd tea.Cmd
 			m.search.Bar, cmd = m.search.Bar.Update(msg)
 
@@ -209,6 +241,7 @@ func (m *MainListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.search.Query != m.search.Bar.Value() {
 				m.search.Query = m.search.Bar.Value()
 				m.performSearch()
+				m.search.Bar.SetSuggestions(searchSuggestions(m.search.UnifiedManager, &m.search.Sources, m.search.Query))
 			}
 
 			// Handle special keys for search
@@ -235,8 +268,46 @@ func (m *MainListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.search.Query = newQuery
 				m.performSearch()
 				return m, nil
+			case Shortcuts.CycleSort.Get():
+				// Cycle sort order
+				newQuery := m.search.FilterHelper.CycleSortFilter(m.search.Bar.Value())
+				m.search.Bar.SetValue(newQuery)
+				m.search.Query = newQuery
+				m.performSearch()
+				return m, nil
+			case Shortcuts.CycleSaved.Get():
+				// Cycle through saved searches
+				searches := m.search.Sources.SavedSearches()
+				newQuery := m.search.FilterHelper.CycleSavedSearch(m.search.Bar.Value(), searches)
+				m.search.Bar.SetValue(newQuery)
+				m.search.Query = newQuery
+				m.performSearch()
+				return m, nil
+			case "up", "down":
+				// Choose a suggestion
+				if m.search.Bar.HasSuggestions() {
+					if msg.String() == "up" {
+						m.search.Bar.MoveSuggestion(-1)
+					} else {
+						m.search.Bar.MoveSuggestion(1)
+					}
+				}
+				return m, cmd
+			case "enter":
+				// Settle on the query and close the suggestions
+				rememberSearch(m.search.Query)
+				m.search.Bar.SetSuggestions(nil)
+				return m, cmd
 			case "tab":
-				// Let tab handling below take care of navigation
+				// Accept the selected suggestion
+				if m.search.Bar.AcceptSuggestion() {
+					m.search.Query = m.search.Bar.Value()
+					m.performSearch()
+					m.search.Bar.SetSuggestions(searchSuggestions(m.search.UnifiedManager, &m.search.Sources, m.search.Query))
+					return m, nil
+				}
+				// Otherwise let tab handling below take care of navigation
+				rememberSearch(m.search.Query)
 			default:
 				return m, cmd
 			}
@@ -355,6 +426,28 @@ func (m *MainListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.viewports.Preview.ViewDown()
 			}
 
+		case Shortcuts.ToggleFolder.Get():
+			// Collapse or expand the folder of the selected item
+			switch m.stateManager.ActivePane {
+			case componentsPane:
+				m.toggleComponentFolder()
+				m.updatePreview()
+			case pipelinesPane:
+				m.togglePipelineFolder()
+				m.updatePreview()
+			}
+
+		case Shortcuts.ExpandFolders.Get():
+			// Expand all folders in the active pane
+			switch m.stateManager.ActivePane {
+			case componentsPane:
+				m.expandAllComponentFolders()
+				m.updatePreview()
+			case pipelinesPane:
+				m.expandAllPipelineFolders()
+				m.updatePreview()
+			}
+
 		case "p":
 			m.stateManager.ShowPreview = !m.stateManager.ShowPreview
 			m.updateViewportSizes()
@@ -488,7 +581,8 @@ func (m *MainListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 							return StatusMsg("You must unarchive this pipeline before setting it as active")
 						}
 					}
-					return m, m.operations.PipelineOperator.SetPipeline(pipeline.path)
+					// Ask for any template variables that have no value
+					return m, m.operations.PipelineOperator.SetPipelineOrPromptVariables(pipeline.path, pipeline.name)
 				}
 			}
 
@@ -708,6 +802,10 @@ func (m *MainListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 		}
 
+	case VariablesCollectedMsg:
+		// All missing template variables have values - set the pipeline
+		return m, m.operations.PipelineOperator.SetPipelineWithVariables(msg.PipelinePath, msg.Values)
+
 	case RenameSuccessMsg:
 		// Handle successful rename
 		m.editors.Rename.State.Reset()
@@ -753,6 +851,11 @@ func (m *MainListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return StatusMsg(fmt.Sprintf("✗ Clone failed: %v", msg.Error))
 		}
 
+	case pipelineVariablesMsg:
+		// The pipeline being set has template variables without a value
+		m.editors.VariablePrompt.State.Start(msg.path, msg.name, msg.missing)
+		return m, nil
+
 	case StatusMsg:
 		// Handle status messages, especially save confirmations from enhanced editor
 		msgStr := string(msg)
@@ -825,6 +928,21 @@ func (m *MainListModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		}
 		return m, nil
 
+	case tagRenameCompleteMsg:
+		if m.editors.TagEditor != nil && m.editors.TagEditor.Active {
+			handled, cmd := m.editors.TagEditor.HandleMessage(msg)
+			if handled {
+				// Reload to reflect renamed tags
+				m.reloadComponents()
+				m.loadPipelines()
+				if m.search.Query != "" {
+					m.performSearch()
+				}
+				return m, cmd
+			}
+		}
+		return m, nil
+
 	case tagDeletionProgressMsg:
 		// Check if tag editor should handle this
 		if m.editors.TagEditor != nil && m.editors.TagEditor.Active {
@@ -969,6 +1087,7 @@ func (m *MainListModel) View() string {
 	if m.ui.ComponentTableRenderer != nil {
 		componentRenderer.TableRenderer = m.ui.ComponentTableRenderer
 		// Update the table renderer with current state
+		m.ui.ComponentTableRenderer.SetFolderTree(m.data.ComponentTree, m.ui.CollapsedFolders)
 		m.ui.ComponentTableRenderer.SetComponents(m.data.FilteredComponents)
 		m.ui.ComponentTableRenderer.SetCursor(m.stateManager.ComponentCursor)
 		m.ui.ComponentTableRenderer.SetActive(m.stateManager.ActivePane == componentsPane)
@@ -979,6 +1098,7 @@ func (m *MainListModel) View() string {
 	pipelineRenderer.ActivePane = m.stateManager.ActivePane
 	pipelineRenderer.Pipelines = m.data.Pipelines
 	pipelineRenderer.FilteredPipelines = m.data.FilteredPipelines
+	pipelineRenderer.SetFolderTree(m.data.PipelineTree, m.ui.CollapsedFolders)
 	pipelineRenderer.PipelineCursor = m.stateManager.PipelineCursor
 	pipelineRenderer.SearchQuery = m.search.Query
 	pipelineRenderer.Viewport = m.viewports.Pipelines
@@ -1002,8 +1122,8 @@ func (m *MainListModel) View() string {
 	s.WriteString(m.search.Bar.View())
 	s.WriteString("\n")
 
-	// Then add the columns
-	s.WriteString(contentStyle.Render(columns))
+	// Then add the columns, with any search suggestions dropped down over them
+	s.WriteString(overlayAt(contentStyle.Render(columns), m.search.Bar.SuggestionsView(), m.search.Bar.SuggestionsOffset(), 0))
 
 	// Add preview if enabled
 	previewPane := mainRenderer.RenderPreviewPane(m.getCurrentPipelines(), m.data.FilteredComponents, m.stateManager.PipelineCursor, m.stateManager.ComponentCursor)
@@ -1034,6 +1154,12 @@ func (m *MainListModel) View() string {
 		finalView = m.editors.Rename.Renderer.RenderOverlay(finalView, m.editors.Rename.State)
 	}
 
+	// Overlay variable prompt if active
+	if m.editors.VariablePrompt.State.Active {
+		m.editors.VariablePrompt.Renderer.SetSize(m.viewports.Width, m.viewports.Height)
+		finalView = m.editors.VariablePrompt.Renderer.RenderOverlay(finalView, m.editors.VariablePrompt.State)
+	}
+
 	// Overlay component usage modal if active
 	if m.editors.ComponentUsage != nil && m.editors.ComponentUsage.Active {
 		renderer := NewComponentUsageRenderer(m.viewports.Width, m.viewports.Height)
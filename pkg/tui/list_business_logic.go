@@ -26,27 +26,11 @@ func (b *BusinessLogic) SetComponents(prompts, contexts, rules []componentItem)
 
 // GetAllComponents returns all components ordered by settings configuration
 func (b *BusinessLogic) GetAllComponents() []componentItem {
-	// Load settings for section order
-	settings, err := files.ReadSettings()
-	if err != nil || settings == nil {
-		settings = models.DefaultSettings()
-	}
-
-	// Group components by type
-	typeGroups := make(map[string][]componentItem)
-	typeGroups[models.ComponentTypeContext] = b.contexts
-	typeGroups[models.ComponentTypePrompt] = b.prompts
-	typeGroups[models.ComponentTypeRules] = b.rules
-
-	// Build ordered list based on sections
-	var all []componentItem
-	for _, section := range settings.Output.Formatting.Sections {
-		if components, exists := typeGroups[section.Type]; exists {
-			all = append(all, components...)
-		}
-	}
-
-	return all
+	all := make([]componentItem, 0, len(b.prompts)+len(b.contexts)+len(b.rules))
+	all = append(all, b.prompts...)
+	all = append(all, b.contexts...)
+	all = append(all, b.rules...)
+	return orderComponentsBySections(all)
 }
 
 // GetEditingItemName returns the name of the item being edited
@@ -62,3 +46,32 @@ func GetEditingItemName(tagEditor *TagEditor, stateManager *StateManager, compon
 	}
 	return ""
 }
+
+// orderComponentsBySections groups components by their own type, custom types
+// included, in the order the settings place each type's section. Components keep
+// their order within a type, and types without a section come last.
+func orderComponentsBySections(components []componentItem) []componentItem {
+	settings, err := files.ReadSettings()
+	if err != nil || settings == nil {
+		settings = models.DefaultSettings()
+	}
+
+	typeGroups := make(map[string][]componentItem)
+	var types []string
+	for _, comp := range components {
+		if _, exists := typeGroups[comp.compType]; !exists {
+			types = append(types, comp.compType)
+		}
+		typeGroups[comp.compType] = append(typeGroups[comp.compType], comp)
+	}
+
+	ordered := make([]componentItem, 0, len(components))
+	for _, section := range settings.OutputSections() {
+		ordered = append(ordered, typeGroups[section.Type]...)
+		delete(typeGroups, section.Type)
+	}
+	for _, compType := range types {
+		ordered = append(ordered, typeGroups[compType]...)
+	}
+	return ordered
+}
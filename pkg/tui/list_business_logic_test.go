@@ -1,9 +1,14 @@
 package tui
 
 import (
+	"os"
+	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 	"github.com/pluqqy/pluqqy-terminal/pkg/tui/testhelpers"
 )
 
@@ -388,6 +393,51 @@ func TestGetAllComponentsIntegration(t *testing.T) {
 	}
 }
 
+// TestGetAllComponents_CustomTypes tests that custom types, which are loaded
+// with the contexts, get their own section in their configured position
+func TestGetAllComponents_CustomTypes(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	os.Chdir(tempDir)
+
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatal(err)
+	}
+	settings := "component_types:\n  - name: examples\n    heading: \"## Few-shot examples\"\n    order: 1\n"
+	if err := os.WriteFile(filepath.Join(files.PluqqyDir, files.SettingsFile), []byte(settings), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	bl := NewBusinessLogic()
+	bl.SetComponents(
+		[]componentItem{{name: "review", compType: models.ComponentTypePrompt}},
+		[]componentItem{
+			{name: "api", compType: models.ComponentTypeContext},
+			{name: "good-commit", compType: "examples"},
+			{name: "db", compType: models.ComponentTypeContext},
+			{name: "bad-commit", compType: "examples"},
+		},
+		[]componentItem{{name: "style", compType: models.ComponentTypeRules}},
+	)
+
+	var names []string
+	for _, comp := range bl.GetAllComponents() {
+		names = append(names, comp.name)
+	}
+	expected := []string{"good-commit", "bad-commit", "style", "api", "db", "review"}
+	if strings.Join(names, ",") != strings.Join(expected, ",") {
+		t.Errorf("GetAllComponents() = %v, want %v", names, expected)
+	}
+
+	if got := componentTypeHeader("examples"); got != "FEW-SHOT EXAMPLES" {
+		t.Errorf("componentTypeHeader(examples) = %q, want FEW-SHOT EXAMPLES", got)
+	}
+	if got := componentTypeHeader(models.ComponentTypeContext); got != "CONTEXTS" {
+		t.Errorf("componentTypeHeader(contexts) = %q, want CONTEXTS", got)
+	}
+}
+
 // TestGetEditingItemName_EdgeCases tests additional edge cases
 func TestGetEditingItemName_EdgeCases(t *testing.T) {
 	tests := []struct {
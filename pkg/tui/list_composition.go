@@ -16,6 +16,14 @@ type ListDataStore struct {
 	// Filtered data (after search)
 	FilteredPipelines  []pipelineItem
 	FilteredComponents []componentItem
+
+	// Components arranged by folder when no search is active; nil while searching.
+	// FilteredComponents holds the subset outside collapsed folders.
+	ComponentTree []componentItem
+
+	// Pipelines arranged by folder when no search is active; nil while searching.
+	// FilteredPipelines holds the subset outside collapsed folders.
+	PipelineTree []pipelineItem
 }
 
 // ListViewportManager manages all UI viewports and dimensions
@@ -35,6 +43,7 @@ type ListEditorComponents struct {
 	Rename         *ListRenameComponents
 	Clone          *ListCloneComponents
 	ComponentUsage *ComponentUsageState
+	VariablePrompt *ListVariablePromptComponents
 }
 
 // ListRenameComponents groups rename-related components
@@ -51,6 +60,12 @@ type ListCloneComponents struct {
 	Operator *CloneOperator
 }
 
+// ListVariablePromptComponents groups template variable prompt components
+type ListVariablePromptComponents struct {
+	State    *VariablePromptState
+	Renderer *VariablePromptRenderer
+}
+
 // ListSearchComponents groups search-related functionality
 type ListSearchComponents struct {
 	// Unified search manager
@@ -59,7 +74,9 @@ type ListSearchComponents struct {
 	// UI components
 	Bar          *SearchBar
 	Query        string
+	Expanded     string // Query with its @name saved searches expanded
 	FilterHelper *SearchFilterHelper
+	Sources      searchSources // Tags and saved searches, refreshed on reload
 }
 
 // ListOperationComponents groups business operation handlers
@@ -77,6 +94,7 @@ type ListUIComponents struct {
 	ExitConfirmationType   string
 	PreviewContent         string
 	ComponentTableRenderer *ComponentTableRenderer
+	CollapsedFolders       map[string]bool // Collapsed component and pipeline folders keyed by type and folder
 	TagReloadRenderer      *TagReloadRenderer
 	MermaidState           *MermaidState
 }
@@ -152,6 +170,7 @@ func (e *ListEditorComponents) IsAnyEditorActive() bool {
 		e.TagEditor.Active ||
 		e.Rename.State.Active ||
 		e.Clone.State.Active ||
+		(e.VariablePrompt != nil && e.VariablePrompt.State.Active) ||
 		(e.ComponentUsage != nil && e.ComponentUsage.Active)
 }
 
@@ -161,6 +180,9 @@ func (e *ListEditorComponents) DeactivateAll() {
 	e.TagEditor.Active = false
 	e.Rename.State.Active = false
 	e.Clone.State.Active = false
+	if e.VariablePrompt != nil {
+		e.VariablePrompt.State.Active = false
+	}
 	if e.ComponentUsage != nil {
 		e.ComponentUsage.Active = false
 	}
@@ -173,6 +195,7 @@ func (s *ListSearchComponents) IsSearchActive() bool {
 
 func (s *ListSearchComponents) ClearSearch() {
 	s.Query = ""
+	s.Expanded = ""
 	if s.Bar != nil {
 		// Clear the search bar by setting a new empty value
 		s.Bar.SetValue("")
@@ -183,15 +206,19 @@ func (s *ListSearchComponents) ClearSearch() {
 func (s *ListSearchComponents) InitializeUnifiedManager() {
 	if s.UnifiedManager == nil {
 		s.UnifiedManager = unified.NewUnifiedSearchManager()
+		// Search the project's index rather than reading every file per keystroke
+		if index, err := unified.OpenSearchIndex(); err == nil {
+			s.UnifiedManager.SetIndex(index)
+		}
 	}
 }
 
 // ShouldIncludeArchived checks if archived items should be included based on search query
 func (s *ListSearchComponents) ShouldIncludeArchived() bool {
 	if s.UnifiedManager != nil {
-		return s.UnifiedManager.IsStructuredQuery(s.Query) && 
-			unified.ShouldIncludeArchived(s.Query)
+		return s.UnifiedManager.IsStructuredQuery(s.Expanded) && 
+			unified.ShouldIncludeArchived(s.Expanded)
 	}
 	// Fallback to existing logic
-	return unified.ShouldIncludeArchived(s.Query)
+	return unified.ShouldIncludeArchived(s.Expanded)
 }
\ No newline at end of file
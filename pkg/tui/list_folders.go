@@ -0,0 +1,306 @@
+package tui
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+)
+
+// componentFolder returns the folder of a component inside its type directory,
+// e.g. "backend" for components/contexts/backend/db.md and "" for top-level files
+func componentFolder(comp componentItem) string {
+	path := strings.TrimPrefix(comp.path, files.ArchiveDir+"/")
+	parts := strings.SplitN(path, "/", 3)
+	if len(parts) < 3 || parts[0] != files.ComponentsDir {
+		return ""
+	}
+	return files.ItemFolder(parts[2])
+}
+
+// folderKey identifies a folder within a component type for collapse state
+func folderKey(compType, folder string) string {
+	return compType + ":" + folder
+}
+
+// collapsedAncestor returns the key of the outermost collapsed folder containing
+// folder (the folder itself included), or "" when the folder is expanded
+func collapsedAncestor(collapsed map[string]bool, compType, folder string) string {
+	if folder == "" || len(collapsed) == 0 {
+		return ""
+	}
+	parts := strings.Split(folder, "/")
+	for i := range parts {
+		key := folderKey(compType, strings.Join(parts[:i+1], "/"))
+		if collapsed[key] {
+			return key
+		}
+	}
+	return ""
+}
+
+// arrangeComponentFolders groups components by folder within each type so that a
+// folder's items are contiguous. Top-level components come first and the original
+// order is kept inside each folder.
+func arrangeComponentFolders(components []componentItem) []componentItem {
+	arranged := make([]componentItem, len(components))
+	copy(arranged, components)
+
+	// Components arrive grouped by type; remember each type's position
+	typeRank := make(map[string]int)
+	for _, comp := range arranged {
+		if _, exists := typeRank[comp.compType]; !exists {
+			typeRank[comp.compType] = len(typeRank)
+		}
+	}
+
+	sort.SliceStable(arranged, func(i, j int) bool {
+		ri, rj := typeRank[arranged[i].compType], typeRank[arranged[j].compType]
+		if ri != rj {
+			return ri < rj
+		}
+		// Sort "/" first so nested folders follow their parent directly
+		fi := strings.ReplaceAll(componentFolder(arranged[i]), "/", "\x00")
+		fj := strings.ReplaceAll(componentFolder(arranged[j]), "/", "\x00")
+		return fi < fj
+	})
+	return arranged
+}
+
+// visibleComponents drops the components inside collapsed folders
+func visibleComponents(tree []componentItem, collapsed map[string]bool) []componentItem {
+	if len(collapsed) == 0 {
+		return tree
+	}
+	visible := make([]componentItem, 0, len(tree))
+	for _, comp := range tree {
+		if collapsedAncestor(collapsed, comp.compType, componentFolder(comp)) == "" {
+			visible = append(visible, comp)
+		}
+	}
+	return visible
+}
+
+// hasComponentFolders reports whether any component lives in a nested folder
+func hasComponentFolders(components []componentItem) bool {
+	for _, comp := range components {
+		if componentFolder(comp) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// setComponentTree shows components as a folder tree, hiding collapsed folders.
+// Projects without nested folders keep the flat list.
+func (m *MainListModel) setComponentTree(components []componentItem) {
+	if !hasComponentFolders(components) {
+		m.data.ComponentTree = nil
+		m.data.FilteredComponents = components
+		return
+	}
+	m.data.ComponentTree = arrangeComponentFolders(components)
+	m.data.FilteredComponents = visibleComponents(m.data.ComponentTree, m.ui.CollapsedFolders)
+}
+
+// toggleComponentFolder collapses or expands the folder of the selected component.
+// Only the folder tree shown without a search query can be collapsed.
+func (m *MainListModel) toggleComponentFolder() {
+	components := m.getCurrentComponents()
+	if m.data.ComponentTree == nil || m.stateManager.ComponentCursor < 0 || m.stateManager.ComponentCursor >= len(components) {
+		return
+	}
+	comp := components[m.stateManager.ComponentCursor]
+	folder := componentFolder(comp)
+	if folder == "" {
+		return
+	}
+
+	if m.ui.CollapsedFolders == nil {
+		m.ui.CollapsedFolders = make(map[string]bool)
+	}
+	key := folderKey(comp.compType, folder)
+	if m.ui.CollapsedFolders[key] {
+		delete(m.ui.CollapsedFolders, key)
+	} else {
+		m.ui.CollapsedFolders[key] = true
+	}
+	m.refreshComponentTree(comp.path)
+}
+
+// expandAllComponentFolders expands every collapsed component folder
+func (m *MainListModel) expandAllComponentFolders() {
+	pipelinePrefix := pipelineFolderKey("")
+	var selected string
+	components := m.getCurrentComponents()
+	if m.stateManager.ComponentCursor >= 0 && m.stateManager.ComponentCursor < len(components) {
+		selected = components[m.stateManager.ComponentCursor].path
+	}
+	expanded := false
+	for key := range m.ui.CollapsedFolders {
+		if !strings.HasPrefix(key, pipelinePrefix) {
+			delete(m.ui.CollapsedFolders, key)
+			expanded = true
+		}
+	}
+	if expanded {
+		m.refreshComponentTree(selected)
+	}
+}
+
+// refreshComponentTree recomputes the visible components after a collapse change,
+// keeping the cursor on the selected component or the nearest visible one
+func (m *MainListModel) refreshComponentTree(selectedPath string) {
+	m.data.FilteredComponents = visibleComponents(m.data.ComponentTree, m.ui.CollapsedFolders)
+	m.stateManager.UpdateCounts(len(m.data.FilteredComponents), len(m.data.FilteredPipelines))
+
+	cursor := 0
+	for _, comp := range m.data.ComponentTree {
+		if comp.path == selectedPath {
+			break
+		}
+		if collapsedAncestor(m.ui.CollapsedFolders, comp.compType, componentFolder(comp)) == "" {
+			cursor++
+		}
+	}
+	if cursor >= len(m.data.FilteredComponents) {
+		cursor = len(m.data.FilteredComponents) - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	m.stateManager.ComponentCursor = cursor
+}
+
+// pipelineFolder returns the folder of a pipeline inside the pipelines directory,
+// e.g. "release" for pipelines/release/hotfix.yaml and "" for top-level files
+func pipelineFolder(pipeline pipelineItem) string {
+	path := strings.TrimPrefix(pipeline.path, files.ArchiveDir+"/")
+	return files.ItemFolder(strings.TrimPrefix(path, files.PipelinesDir+"/"))
+}
+
+// pipelineFolderKey identifies a pipeline folder for collapse state
+func pipelineFolderKey(folder string) string {
+	return folderKey(files.PipelinesDir, folder)
+}
+
+// arrangePipelineFolders groups pipelines by folder so that a folder's pipelines
+// are contiguous. Top-level pipelines come first and the original order is kept
+// inside each folder.
+func arrangePipelineFolders(pipelines []pipelineItem) []pipelineItem {
+	arranged := make([]pipelineItem, len(pipelines))
+	copy(arranged, pipelines)
+
+	sort.SliceStable(arranged, func(i, j int) bool {
+		// Sort "/" first so nested folders follow their parent directly
+		fi := strings.ReplaceAll(pipelineFolder(arranged[i]), "/", "\x00")
+		fj := strings.ReplaceAll(pipelineFolder(arranged[j]), "/", "\x00")
+		return fi < fj
+	})
+	return arranged
+}
+
+// visiblePipelines drops the pipelines inside collapsed folders
+func visiblePipelines(tree []pipelineItem, collapsed map[string]bool) []pipelineItem {
+	if len(collapsed) == 0 {
+		return tree
+	}
+	visible := make([]pipelineItem, 0, len(tree))
+	for _, pipeline := range tree {
+		if collapsedAncestor(collapsed, files.PipelinesDir, pipelineFolder(pipeline)) == "" {
+			visible = append(visible, pipeline)
+		}
+	}
+	return visible
+}
+
+// hasPipelineFolders reports whether any pipeline lives in a nested folder
+func hasPipelineFolders(pipelines []pipelineItem) bool {
+	for _, pipeline := range pipelines {
+		if pipelineFolder(pipeline) != "" {
+			return true
+		}
+	}
+	return false
+}
+
+// setPipelineTree shows pipelines as a folder tree, hiding collapsed folders.
+// Projects without nested folders keep the flat list.
+func (m *MainListModel) setPipelineTree(pipelines []pipelineItem) {
+	if !hasPipelineFolders(pipelines) {
+		m.data.PipelineTree = nil
+		m.data.FilteredPipelines = pipelines
+		return
+	}
+	m.data.PipelineTree = arrangePipelineFolders(pipelines)
+	m.data.FilteredPipelines = visiblePipelines(m.data.PipelineTree, m.ui.CollapsedFolders)
+}
+
+// togglePipelineFolder collapses or expands the folder of the selected pipeline.
+// Only the folder tree shown without a search query can be collapsed.
+func (m *MainListModel) togglePipelineFolder() {
+	pipelines := m.getCurrentPipelines()
+	if m.data.PipelineTree == nil || m.stateManager.PipelineCursor < 0 || m.stateManager.PipelineCursor >= len(pipelines) {
+		return
+	}
+	pipeline := pipelines[m.stateManager.PipelineCursor]
+	folder := pipelineFolder(pipeline)
+	if folder == "" {
+		return
+	}
+
+	if m.ui.CollapsedFolders == nil {
+		m.ui.CollapsedFolders = make(map[string]bool)
+	}
+	key := pipelineFolderKey(folder)
+	if m.ui.CollapsedFolders[key] {
+		delete(m.ui.CollapsedFolders, key)
+	} else {
+		m.ui.CollapsedFolders[key] = true
+	}
+	m.refreshPipelineTree(pipeline.path)
+}
+
+// expandAllPipelineFolders expands every collapsed pipeline folder
+func (m *MainListModel) expandAllPipelineFolders() {
+	prefix := pipelineFolderKey("")
+	var selected string
+	pipelines := m.getCurrentPipelines()
+	if m.stateManager.PipelineCursor >= 0 && m.stateManager.PipelineCursor < len(pipelines) {
+		selected = pipelines[m.stateManager.PipelineCursor].path
+	}
+	expanded := false
+	for key := range m.ui.CollapsedFolders {
+		if strings.HasPrefix(key, prefix) {
+			delete(m.ui.CollapsedFolders, key)
+			expanded = true
+		}
+	}
+	if expanded {
+		m.refreshPipelineTree(selected)
+	}
+}
+
+// refreshPipelineTree recomputes the visible pipelines after a collapse change,
+// keeping the cursor on the selected pipeline or the nearest visible one
+func (m *MainListModel) refreshPipelineTree(selectedPath string) {
+	m.data.FilteredPipelines = visiblePipelines(m.data.PipelineTree, m.ui.CollapsedFolders)
+	m.stateManager.UpdateCounts(len(m.data.FilteredComponents), len(m.data.FilteredPipelines))
+
+	cursor := 0
+	for _, pipeline := range m.data.PipelineTree {
+		if pipeline.path == selectedPath {
+			break
+		}
+		if collapsedAncestor(m.ui.CollapsedFolders, files.PipelinesDir, pipelineFolder(pipeline)) == "" {
+			cursor++
+		}
+	}
+	if cursor >= len(m.data.FilteredPipelines) {
+		cursor = len(m.data.FilteredPipelines) - 1
+	}
+	if cursor < 0 {
+		cursor = 0
+	}
+	m.stateManager.PipelineCursor = cursor
+}
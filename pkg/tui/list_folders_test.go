@@ -0,0 +1,207 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+func folderTestComponents() []componentItem {
+	return []componentItem{
+		{name: "API", path: "components/contexts/api.md", compType: models.ComponentTypeContext},
+		{name: "Cache", path: "components/contexts/backend/cache/redis.md", compType: models.ComponentTypeContext},
+		{name: "DB", path: "components/contexts/backend/db.md", compType: models.ComponentTypeContext},
+		{name: "Zeta", path: "components/contexts/zeta.md", compType: models.ComponentTypeContext},
+		{name: "Greeting", path: "components/prompts/greeting.md", compType: models.ComponentTypePrompt},
+	}
+}
+
+func folderItemNames(components []componentItem) []string {
+	var names []string
+	for _, comp := range components {
+		names = append(names, comp.name)
+	}
+	return names
+}
+
+func TestComponentFolder(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"components/contexts/api.md", ""},
+		{"components/contexts/backend/db.md", "backend"},
+		{"components/contexts/backend/cache/redis.md", "backend/cache"},
+		{"archive/components/rules/legacy/old.md", "legacy"},
+	}
+
+	for _, tt := range tests {
+		if got := componentFolder(componentItem{path: tt.path}); got != tt.expected {
+			t.Errorf("componentFolder(%q) = %q, want %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestArrangeComponentFolders(t *testing.T) {
+	arranged := arrangeComponentFolders(folderTestComponents())
+
+	// Top-level components first, then each folder followed by its subfolders
+	expected := []string{"API", "Zeta", "DB", "Cache", "Greeting"}
+	if got := folderItemNames(arranged); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("arranged = %v, want %v", got, expected)
+	}
+}
+
+func TestVisibleComponents_CollapsedFolders(t *testing.T) {
+	tree := arrangeComponentFolders(folderTestComponents())
+
+	collapsed := map[string]bool{folderKey(models.ComponentTypeContext, "backend"): true}
+	visible := visibleComponents(tree, collapsed)
+
+	// Collapsing a folder hides its subfolders too
+	expected := []string{"API", "Zeta", "Greeting"}
+	if got := folderItemNames(visible); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("visible = %v, want %v", got, expected)
+	}
+}
+
+func TestComponentTableRenderer_FolderTree(t *testing.T) {
+	tree := arrangeComponentFolders(folderTestComponents())
+	collapsed := map[string]bool{folderKey(models.ComponentTypeContext, "backend/cache"): true}
+
+	renderer := NewComponentTableRenderer(100, 40, true)
+	renderer.SetFolderTree(tree, collapsed)
+	renderer.SetComponents(visibleComponents(tree, collapsed))
+	content := renderer.buildTableContent(40, 20, 8, 6)
+
+	if !strings.Contains(content, "▾ backend/") {
+		t.Error("Expected expanded header for backend folder")
+	}
+	if !strings.Contains(content, "▸ backend/cache/ (1)") {
+		t.Error("Expected collapsed header with hidden count for backend/cache folder")
+	}
+	if strings.Contains(content, "Cache") {
+		t.Error("Components in collapsed folders should not be rendered")
+	}
+}
+
+func TestMainListModel_ToggleComponentFolder(t *testing.T) {
+	m := NewMainListModel()
+	m.setComponentTree(folderTestComponents())
+	m.stateManager.UpdateCounts(len(m.data.FilteredComponents), 0)
+
+	// Select DB (inside backend) and collapse its folder
+	m.stateManager.ComponentCursor = 2
+	m.toggleComponentFolder()
+
+	expected := []string{"API", "Zeta", "Greeting"}
+	if got := folderItemNames(m.getCurrentComponents()); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("visible = %v, want %v", got, expected)
+	}
+	if m.stateManager.ComponentCursor != 2 {
+		t.Errorf("Expected cursor to move to the next visible component, got %d", m.stateManager.ComponentCursor)
+	}
+
+	m.expandAllComponentFolders()
+	if len(m.getCurrentComponents()) != len(folderTestComponents()) {
+		t.Errorf("Expected all components after expanding, got %d", len(m.getCurrentComponents()))
+	}
+}
+
+func folderTestPipelines() []pipelineItem {
+	return []pipelineItem{
+		{name: "Deploy", path: "pipelines/deploy.yaml"},
+		{name: "Hotfix", path: "pipelines/release/hotfix.yaml"},
+		{name: "Review", path: "pipelines/review.yaml"},
+		{name: "Old", path: "archive/pipelines/release/old.yaml", isArchived: true},
+	}
+}
+
+func pipelineNames(pipelines []pipelineItem) []string {
+	var names []string
+	for _, pipeline := range pipelines {
+		names = append(names, pipeline.name)
+	}
+	return names
+}
+
+func TestPipelineFolder(t *testing.T) {
+	tests := []struct {
+		path     string
+		expected string
+	}{
+		{"pipelines/deploy.yaml", ""},
+		{"pipelines/release/hotfix.yaml", "release"},
+		{"pipelines/release/2024/q1.yaml", "release/2024"},
+		{"archive/pipelines/release/old.yaml", "release"},
+	}
+
+	for _, tt := range tests {
+		if got := pipelineFolder(pipelineItem{path: tt.path}); got != tt.expected {
+			t.Errorf("pipelineFolder(%q) = %q, want %q", tt.path, got, tt.expected)
+		}
+	}
+}
+
+func TestPipelineViewRenderer_FolderTree(t *testing.T) {
+	tree := arrangePipelineFolders(folderTestPipelines())
+	collapsed := map[string]bool{pipelineFolderKey("release"): true}
+
+	renderer := NewPipelineViewRenderer(100, 40)
+	renderer.Pipelines = folderTestPipelines()
+	renderer.SetFolderTree(tree, collapsed)
+	renderer.FilteredPipelines = visiblePipelines(tree, collapsed)
+	content := renderer.buildScrollableContent(40, 20, 8)
+
+	if !strings.Contains(content, "▸ release/ (2)") {
+		t.Errorf("Expected collapsed header with hidden count for release folder:\n%s", content)
+	}
+	if strings.Contains(content, "Hotfix") {
+		t.Error("Pipelines in collapsed folders should not be rendered")
+	}
+
+	renderer.SetFolderTree(tree, nil)
+	renderer.FilteredPipelines = tree
+	content = renderer.buildScrollableContent(40, 20, 8)
+	if !strings.Contains(content, "▾ release/") || !strings.Contains(content, "Hotfix") {
+		t.Errorf("Expected expanded release folder with its pipelines:\n%s", content)
+	}
+}
+
+func TestMainListModel_TogglePipelineFolder(t *testing.T) {
+	m := NewMainListModel()
+	m.data.Pipelines = folderTestPipelines()
+	m.setPipelineTree(m.data.Pipelines)
+	m.stateManager.UpdateCounts(0, len(m.data.FilteredPipelines))
+
+	// Top-level pipelines first, then the release folder
+	expected := []string{"Deploy", "Review", "Hotfix", "Old"}
+	if got := pipelineNames(m.getCurrentPipelines()); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Fatalf("pipelines = %v, want %v", got, expected)
+	}
+
+	// Select Hotfix (inside release) and collapse its folder
+	m.stateManager.ActivePane = pipelinesPane
+	m.stateManager.PipelineCursor = 2
+	m.togglePipelineFolder()
+
+	expected = []string{"Deploy", "Review"}
+	if got := pipelineNames(m.getCurrentPipelines()); strings.Join(got, ",") != strings.Join(expected, ",") {
+		t.Errorf("visible = %v, want %v", got, expected)
+	}
+	if m.stateManager.PipelineCursor != 1 {
+		t.Errorf("Expected cursor on the last visible pipeline, got %d", m.stateManager.PipelineCursor)
+	}
+
+	// Expanding component folders leaves pipeline folders alone
+	m.expandAllComponentFolders()
+	if len(m.getCurrentPipelines()) != 2 {
+		t.Errorf("Expected the release folder to stay collapsed, got %d pipelines", len(m.getCurrentPipelines()))
+	}
+
+	m.expandAllPipelineFolders()
+	if len(m.getCurrentPipelines()) != len(folderTestPipelines()) {
+		t.Errorf("Expected all pipelines after expanding, got %d", len(m.getCurrentPipelines()))
+	}
+}
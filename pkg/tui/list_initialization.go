@@ -42,6 +42,10 @@ func NewMainListModel() *MainListModel {
 				Renderer: NewCloneRenderer(),
 				Operator: NewCloneOperator(),
 			},
+			VariablePrompt: &ListVariablePromptComponents{
+				State:    NewVariablePromptState(),
+				Renderer: NewVariablePromptRenderer(),
+			},
 		},
 		
 		search: &ListSearchComponents{
@@ -72,11 +76,11 @@ func NewMainListModel() *MainListModel {
 	m.operations.BusinessLogic.SetComponents(m.data.Prompts, m.data.Contexts, m.data.Rules)
 	m.initializeSearchEngine()
 	// Initialize filtered lists with all items
-	m.data.FilteredPipelines = m.data.Pipelines
-	m.data.FilteredComponents = m.getAllComponents()
+	m.setPipelineTree(m.data.Pipelines)
+	m.setComponentTree(m.getAllComponents())
 
 	// Update state manager with counts after both are loaded
-	m.stateManager.UpdateCounts(len(m.getAllComponents()), len(m.data.Pipelines))
+	m.stateManager.UpdateCounts(len(m.data.FilteredComponents), len(m.data.FilteredPipelines))
 
 	// Create and setup the component creator after the model is initialized
 	m.operations.ComponentCreator = createListComponentCreator(m)
@@ -158,27 +162,34 @@ func (m *MainListModel) loadPipelines() {
 		}
 	}
 
-	// Note: Search index rebuilding is now handled by the unified search manager
-	// No explicit index rebuilding needed
+	// Bring the search index up to date with created, renamed and deleted items
+	if m.search.UnifiedManager != nil {
+		m.search.UnifiedManager.RefreshIndex()
+	}
 
 	// Update filtered list if no active search
+	pipelineCount := len(m.data.Pipelines)
 	if m.search.Query == "" {
-		m.data.FilteredPipelines = m.data.Pipelines
+		m.setPipelineTree(m.data.Pipelines)
+		pipelineCount = len(m.data.FilteredPipelines)
 	}
 
 	// Update state manager counts if components are already loaded
 	if m.data.Prompts != nil || m.data.Contexts != nil || m.data.Rules != nil {
-		m.stateManager.UpdateCounts(len(m.getAllComponents()), len(m.data.Pipelines))
+		m.stateManager.UpdateCounts(len(m.getCurrentComponents()), pipelineCount)
 	}
 }
 
 // shouldIncludeArchived checks if the current search query requires archived items
 func (m *MainListModel) shouldIncludeArchived() bool {
-	return unified.ShouldIncludeArchived(m.search.Query)
+	return unified.ShouldIncludeArchived(m.search.Expanded)
 }
 
 // loadComponents loads all component files and their metadata
 func (m *MainListModel) loadComponents() {
+	// Tags and saved searches may have changed along with the components
+	m.search.Sources.Reload()
+
 	// Check if we should include archived items based on search query
 	includeArchived := m.shouldIncludeArchived()
 
@@ -199,16 +210,20 @@ func (m *MainListModel) loadComponents() {
 	// Update business logic with new components
 	m.operations.BusinessLogic.SetComponents(m.data.Prompts, m.data.Contexts, m.data.Rules)
 
-	// Note: Search index rebuilding is now handled by the unified search manager
-	// No explicit index rebuilding needed
+	// Bring the search index up to date with created, renamed and deleted items
+	if m.search.UnifiedManager != nil {
+		m.search.UnifiedManager.RefreshIndex()
+	}
 
 	// Update filtered list if no active search
+	componentCount := len(m.getAllComponents())
 	if m.search.Query == "" {
-		m.data.FilteredComponents = m.getAllComponents()
+		m.setComponentTree(m.getAllComponents())
+		componentCount = len(m.data.FilteredComponents)
 	}
 
 	// Update state manager counts
-	m.stateManager.UpdateCounts(len(m.getAllComponents()), len(m.data.Pipelines))
+	m.stateManager.UpdateCounts(componentCount, len(m.getCurrentPipelines()))
 }
 
 // convertToComponentItems converts unified ComponentItems to local componentItems
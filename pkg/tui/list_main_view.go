@@ -47,16 +47,22 @@ func (r *MainViewRenderer) RenderHelpPane(searchActive bool) string {
 		helpRows = [][]string{
 			{
 				fmt.Sprintf("%s switch pane", Shortcuts.SwitchPane.Get()),
+				"tab accept suggestion",
+				"↑↓ pick suggestion",
 				"esc clear+exit",
 			},
 			{
 				"tag:<name>",
 				"type:<type>",
 				"status:archived",
+				"modified:>30d",
 				"<keyword>",
 				"combine with spaces",
+				"OR NOT -x ( )",
 				fmt.Sprintf("%s toggle archived", FormatShortcutForHelp(Shortcuts.ToggleArchived)),
 				fmt.Sprintf("%s cycle type", FormatShortcutForHelp(Shortcuts.CycleType)),
+				fmt.Sprintf("%s cycle sort", FormatShortcutForHelp(Shortcuts.CycleSort)),
+				fmt.Sprintf("%s saved searches", FormatShortcutForHelp(Shortcuts.CycleSaved)),
 			},
 		}
 	} else {
@@ -97,6 +103,7 @@ func (r *MainViewRenderer) RenderHelpPane(searchActive bool) string {
 					fmt.Sprintf("%s clone", Shortcuts.Clone.Get()),
 					fmt.Sprintf("%s tag", Shortcuts.Tag.Get()),
 					fmt.Sprintf("%s archive/unarchive", Shortcuts.Archive.Get()),
+					fmt.Sprintf("%s fold/%s unfold all", Shortcuts.ToggleFolder.Get(), Shortcuts.ExpandFolders.Get()),
 				},
 			}
 		} else {
@@ -122,6 +129,7 @@ func (r *MainViewRenderer) RenderHelpPane(searchActive bool) string {
 					fmt.Sprintf("%s tag", Shortcuts.Tag.Get()),
 					fmt.Sprintf("%s usage", Shortcuts.Usage.Get()),
 					fmt.Sprintf("%s archive/unarchive", Shortcuts.Archive.Get()),
+					fmt.Sprintf("%s fold/%s unfold all", Shortcuts.ToggleFolder.Get(), Shortcuts.ExpandFolders.Get()),
 				},
 			}
 		}
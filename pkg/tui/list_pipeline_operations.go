@@ -30,6 +30,47 @@ func NewPipelineOperator() *PipelineOperator {
 
 // SetPipeline generates and writes the pipeline output
 func (po *PipelineOperator) SetPipeline(pipelinePath string) tea.Cmd {
+	return po.SetPipelineWithVariables(pipelinePath, nil)
+}
+
+// UnresolvedVariables returns the template variables in a pipeline that have no value
+func (po *PipelineOperator) UnresolvedVariables(pipelinePath string) ([]string, error) {
+	pipeline, err := files.ReadPipeline(pipelinePath)
+	if err != nil {
+		return nil, err
+	}
+
+	settings, _ := files.ReadSettings()
+	if settings == nil {
+		settings = models.DefaultSettings()
+	}
+
+	return composer.UnresolvedVariables(pipeline, settings)
+}
+
+// pipelineVariablesMsg asks for the template variables a pipeline needs before it is set
+type pipelineVariablesMsg struct {
+	path    string
+	name    string
+	missing []string
+}
+
+// SetPipelineOrPromptVariables sets a pipeline, or asks for its template
+// variables first when some have no value. Finding them reads every
+// component, so it runs as a command rather than in Update.
+func (po *PipelineOperator) SetPipelineOrPromptVariables(pipelinePath, pipelineName string) tea.Cmd {
+	return func() tea.Msg {
+		missing, err := po.UnresolvedVariables(pipelinePath)
+		if err == nil && len(missing) > 0 {
+			return pipelineVariablesMsg{path: pipelinePath, name: pipelineName, missing: missing}
+		}
+		return po.SetPipeline(pipelinePath)()
+	}
+}
+
+// SetPipelineWithVariables generates and writes the pipeline output, filling
+// template variables from the given values before pipeline and project values
+func (po *PipelineOperator) SetPipelineWithVariables(pipelinePath string, variables map[string]string) tea.Cmd {
 	return func() tea.Msg {
 		// Load pipeline
 		pipeline, err := files.ReadPipeline(pipelinePath)
@@ -37,18 +78,24 @@ func (po *PipelineOperator) SetPipeline(pipelinePath string) tea.Cmd {
 			return StatusMsg(fmt.Sprintf("Failed to load pipeline '%s': %v", pipelinePath, err))
 		}
 
-		// Generate pipeline output
-		output, err := composer.ComposePipeline(pipeline)
-		if err != nil {
-			return StatusMsg(fmt.Sprintf("Failed to generate pipeline output for '%s': %v", pipeline.Name, err))
-		}
-
 		// Load settings for output path
 		settings, _ := files.ReadSettings()
 		if settings == nil {
 			settings = models.DefaultSettings()
 		}
 
+		// Write every configured output target in one go
+		if len(composer.OutputTargets(pipeline, settings)) > 0 {
+			settings.VariableOverrides = variables
+			return setPipelineTargets(pipeline, settings)
+		}
+
+		// Generate pipeline output
+		output, report, err := composer.ComposePipelineWithVariablesReport(pipeline, variables)
+		if err != nil {
+			return StatusMsg(fmt.Sprintf("Failed to generate pipeline output for '%s': %v", pipeline.Name, err))
+		}
+
 		// Write to configured output file
 		outputPath := pipeline.OutputPath
 		if outputPath == "" {
@@ -68,10 +115,40 @@ func (po *PipelineOperator) SetPipeline(pipelinePath string) tea.Cmd {
 			return StatusMsg(fmt.Sprintf("Failed to write output file '%s': %v", outputPath, err))
 		}
 
+		if report.Trimmed() {
+			return StatusMsg(fmt.Sprintf("✓ Set pipeline: %s → %s (%d component(s) cut to fit %d tokens)",
+				pipeline.Name, outputPath, len(report.Cuts), report.MaxTokens))
+		}
+
 		return StatusMsg(fmt.Sprintf("✓ Set pipeline: %s → %s", pipeline.Name, outputPath))
 	}
 }
 
+// setPipelineTargets composes and writes all output targets of a pipeline
+func setPipelineTargets(pipeline *models.Pipeline, settings *models.Settings) tea.Msg {
+	outputs, err := composer.ComposePipelineTargets(pipeline, settings)
+	if err != nil {
+		return StatusMsg(fmt.Sprintf("Failed to generate pipeline output for '%s': %v", pipeline.Name, err))
+	}
+	if err := composer.WriteTargetOutputs(outputs); err != nil {
+		return StatusMsg(fmt.Sprintf("Failed to write output targets for '%s': %v", pipeline.Name, err))
+	}
+
+	paths := make([]string, len(outputs))
+	cuts := 0
+	for i, output := range outputs {
+		paths[i] = output.Path
+		if output.Report.Trimmed() {
+			cuts += len(output.Report.Cuts)
+		}
+	}
+	if cuts > 0 {
+		return StatusMsg(fmt.Sprintf("✓ Set pipeline: %s → %s (%d component(s) cut to fit the budget)",
+			pipeline.Name, strings.Join(paths, ", "), cuts))
+	}
+	return StatusMsg(fmt.Sprintf("✓ Set pipeline: %s → %s", pipeline.Name, strings.Join(paths, ", ")))
+}
+
 // DeletePipeline deletes a pipeline and returns a command to reload the list
 func (po *PipelineOperator) DeletePipeline(pipelinePath string, pipelineTags []string, isArchived bool, reloadFunc func()) tea.Cmd {
 	return func() tea.Msg {
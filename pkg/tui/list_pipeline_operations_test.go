@@ -3,11 +3,13 @@ package tui
 import (
 	"os"
 	"path/filepath"
+	"strings"
 	"testing"
 	"time"
 
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 	"github.com/pluqqy/pluqqy-terminal/pkg/tags"
 	"github.com/pluqqy/pluqqy-terminal/pkg/tui/testhelpers"
 )
@@ -475,3 +477,37 @@ func TestPipelineOperator_Confirmations(t *testing.T) {
 		}
 	})
 }
+
+func TestPipelineOperator_SetPipelineOrPromptVariables(t *testing.T) {
+	env := testhelpers.NewTestEnvironment(t)
+	defer env.Cleanup()
+	env.ChangeToTempDir()
+	env.InitProjectStructure()
+
+	env.CreateComponentFile(models.ComponentTypeContext, "ticket", "Ticket: {{ticket}}", nil)
+	env.CreateComponentFile(models.ComponentTypeContext, "plain", "No variables here", nil)
+	withVars := env.CreatePipelineFile("with-vars", []models.ComponentRef{
+		{Type: models.ComponentTypeContext, Path: "../components/contexts/ticket.md", Order: 1},
+	}, nil)
+	plain := env.CreatePipelineFile("plain", []models.ComponentRef{
+		{Type: models.ComponentTypeContext, Path: "../components/contexts/plain.md", Order: 1},
+	}, nil)
+
+	po := NewPipelineOperator()
+
+	// Variables without a value are asked for rather than set
+	msg := po.SetPipelineOrPromptVariables(withVars, "with-vars")()
+	prompt, ok := msg.(pipelineVariablesMsg)
+	if !ok {
+		t.Fatalf("expected pipelineVariablesMsg, got %T: %v", msg, msg)
+	}
+	if prompt.path != withVars || prompt.name != "with-vars" || len(prompt.missing) != 1 || prompt.missing[0] != "ticket" {
+		t.Errorf("pipelineVariablesMsg = %+v, want ticket for %s", prompt, withVars)
+	}
+
+	// A pipeline without any is set straight away
+	msg = po.SetPipelineOrPromptVariables(plain, "plain")()
+	if status, ok := msg.(StatusMsg); !ok || !strings.HasPrefix(string(status), "✓ Set pipeline: plain") {
+		t.Errorf("expected the pipeline to be set, got %T: %v", msg, msg)
+	}
+}
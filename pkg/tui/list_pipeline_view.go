@@ -6,6 +6,7 @@ import (
 
 	"github.com/charmbracelet/bubbles/viewport"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
 )
 
 // PipelineViewRenderer handles rendering of the pipeline pane
@@ -18,6 +19,12 @@ type PipelineViewRenderer struct {
 	PipelineCursor    int
 	SearchQuery       string
 	Viewport          viewport.Model
+
+	// Folder tree. When set, Tree holds every pipeline arranged by folder and
+	// FilteredPipelines the ones outside collapsed folders.
+	Tree             []pipelineItem
+	CollapsedFolders map[string]bool
+	cursorLine       int
 }
 
 // NewPipelineViewRenderer creates a new pipeline view renderer
@@ -28,6 +35,14 @@ func NewPipelineViewRenderer(width, height int) *PipelineViewRenderer {
 	}
 }
 
+// SetFolderTree enables folder headers for nested pipelines.
+// The tree should be arranged so each folder's pipelines are contiguous;
+// pass a nil tree to render the pipelines as a flat list.
+func (r *PipelineViewRenderer) SetFolderTree(tree []pipelineItem, collapsed map[string]bool) {
+	r.Tree = tree
+	r.CollapsedFolders = collapsed
+}
+
 // Render generates the complete pipeline pane view
 func (r *PipelineViewRenderer) Render() string {
 	var content strings.Builder
@@ -112,10 +127,9 @@ func (r *PipelineViewRenderer) Render() string {
 // buildScrollableContent creates the content for the pipelines viewport
 func (r *PipelineViewRenderer) buildScrollableContent(nameWidth, tagsWidth, tokenWidth int) string {
 	var content strings.Builder
-	normalStyle := NormalStyle
 	dimmedStyle := EmptyInactiveStyle
 
-	if len(r.FilteredPipelines) == 0 {
+	if len(r.FilteredPipelines) == 0 && len(r.Tree) == 0 {
 		if r.ActivePane == pipelinesPane {
 			// Active pane - show prominent message
 			emptyStyle := EmptyActiveStyle
@@ -136,69 +150,133 @@ func (r *PipelineViewRenderer) buildScrollableContent(nameWidth, tagsWidth, toke
 				content.WriteString(dimmedStyle.Render("No pipelines found."))
 			}
 		}
+	} else if r.Tree != nil {
+		return r.buildTreeContent(nameWidth, tagsWidth, tokenWidth)
 	} else {
 		for i, pipeline := range r.FilteredPipelines {
-			// Format the pipeline name with archived indicator
-			nameStr := pipeline.name
-			if pipeline.isArchived {
-				nameStr = "[A] " + nameStr
-			}
-			nameStr = truncateName(nameStr, nameWidth)
+			isSelected := r.ActivePane == pipelinesPane && i == r.PipelineCursor
+			content.WriteString(r.renderRow(pipeline, isSelected, "", nameWidth, tagsWidth, tokenWidth))
 
-			// Format tags
-			tagsStr := renderTagChipsWithWidth(pipeline.tags, tagsWidth, 2) // Show max 2 tags inline
+			if i < len(r.FilteredPipelines)-1 {
+				content.WriteString("\n")
+			}
+		}
+	}
 
-			// Format token count - right-aligned
-			tokenStr := fmt.Sprintf("%d", pipeline.tokenCount)
+	return content.String()
+}
 
-			// Build the row components separately for proper styling
-			namePart := fmt.Sprintf("%-*s", nameWidth, nameStr)
+// buildTreeContent renders the folder tree: a header per folder (▾ expanded,
+// ▸ collapsed with its hidden count) and indented pipelines. Pipelines inside
+// collapsed folders are skipped; the cursor indexes the visible ones.
+func (r *PipelineViewRenderer) buildTreeContent(nameWidth, tagsWidth, tokenWidth int) string {
+	folderStyle := EmptyInactiveStyle
+
+	// Count hidden pipelines per collapsed folder for the folder headers
+	hidden := make(map[string]int)
+	for _, pipeline := range r.Tree {
+		if key := collapsedAncestor(r.CollapsedFolders, files.PipelinesDir, pipelineFolder(pipeline)); key != "" {
+			hidden[key]++
+		}
+	}
 
-			// For tags, we need to pad based on rendered width
-			tagsPadding := tagsWidth - lipgloss.Width(tagsStr)
-			if tagsPadding < 0 {
-				tagsPadding = 0
-			}
-			tagsPart := tagsStr + strings.Repeat(" ", tagsPadding)
-
-			tokenPart := fmt.Sprintf("%*s", tokenWidth, tokenStr)
-
-			// Build row with styling
-			var row string
-			if r.ActivePane == pipelinesPane && i == r.PipelineCursor {
-				// Apply selection styling only to name column
-				if pipeline.isArchived {
-					// Dimmed style for archived items
-					row = "▸ " + dimmedStyle.Render(namePart) + " " + tagsPart + " " + dimmedStyle.Render(tokenPart)
-				} else {
-					row = "▸ " + SelectedStyle.Render(namePart) + " " + tagsPart + " " + normalStyle.Render(tokenPart)
-				}
-			} else {
-				// Normal row styling
-				if pipeline.isArchived {
-					// Dimmed style for archived items
-					row = "  " + dimmedStyle.Render(namePart) + " " + tagsPart + " " + dimmedStyle.Render(tokenPart)
-				} else {
-					row = "  " + normalStyle.Render(namePart) + " " + tagsPart + " " + normalStyle.Render(tokenPart)
-				}
+	var lines []string
+	currentFolder := ""
+	visibleIndex := 0
+	r.cursorLine = 0
+	for _, pipeline := range r.Tree {
+		folder := pipelineFolder(pipeline)
+		collapsedKey := collapsedAncestor(r.CollapsedFolders, files.PipelinesDir, folder)
+		if folder != currentFolder {
+			currentFolder = folder
+			switch {
+			case folder == "":
+				// Top-level pipelines have no folder header
+			case collapsedKey == "":
+				lines = append(lines, folderStyle.Render(fmt.Sprintf("  ▾ %s/", folder)))
+			case collapsedKey == pipelineFolderKey(folder):
+				lines = append(lines, folderStyle.Render(fmt.Sprintf("  ▸ %s/ (%d)", folder, hidden[collapsedKey])))
 			}
+		}
+		if collapsedKey != "" {
+			continue
+		}
 
-			content.WriteString(row)
+		indent := ""
+		if folder != "" {
+			indent = "  "
+		}
+		isSelected := r.ActivePane == pipelinesPane && visibleIndex == r.PipelineCursor
+		if isSelected {
+			r.cursorLine = len(lines)
+		}
+		lines = append(lines, r.renderRow(pipeline, isSelected, indent, nameWidth, tagsWidth, tokenWidth))
+		visibleIndex++
+	}
 
-			if i < len(r.FilteredPipelines)-1 {
-				content.WriteString("\n")
-			}
+	return strings.Join(lines, "\n")
+}
+
+// renderRow formats a single pipeline row. The indent is placed before the
+// name so pipelines inside folders line up under their folder header.
+func (r *PipelineViewRenderer) renderRow(pipeline pipelineItem, isSelected bool, indent string, nameWidth, tagsWidth, tokenWidth int) string {
+	normalStyle := NormalStyle
+	dimmedStyle := EmptyInactiveStyle
+
+	// Archived indicator shown before the name
+	namePrefix := indent
+	if pipeline.isArchived {
+		namePrefix = "[A] " + namePrefix
+	}
+
+	// Format tags
+	tagsStr := renderTagChipsWithWidth(pipeline.tags, tagsWidth, 2) // Show max 2 tags inline
+
+	// Format token count - right-aligned
+	tokenStr := fmt.Sprintf("%d", pipeline.tokenCount)
+
+	// Build the row components separately for proper styling, underlining
+	// what the search matched in the name
+	renderName := func(style lipgloss.Style) string {
+		return formatMatchedName(namePrefix, pipeline.name, pipeline.nameMatches, nameWidth, style)
+	}
+
+	// For tags, we need to pad based on rendered width
+	tagsPadding := tagsWidth - lipgloss.Width(tagsStr)
+	if tagsPadding < 0 {
+		tagsPadding = 0
+	}
+	tagsPart := tagsStr + strings.Repeat(" ", tagsPadding)
+
+	tokenPart := fmt.Sprintf("%*s", tokenWidth, tokenStr)
+
+	// Build row with styling
+	if isSelected {
+		// Apply selection styling only to name column
+		if pipeline.isArchived {
+			// Dimmed style for archived items
+			return "▸ " + renderName(dimmedStyle) + " " + tagsPart + " " + dimmedStyle.Render(tokenPart)
 		}
+		return "▸ " + renderName(SelectedStyle) + " " + tagsPart + " " + normalStyle.Render(tokenPart)
 	}
 
-	return content.String()
+	// Normal row styling
+	if pipeline.isArchived {
+		// Dimmed style for archived items
+		return "  " + renderName(dimmedStyle) + " " + tagsPart + " " + dimmedStyle.Render(tokenPart)
+	}
+	return "  " + renderName(normalStyle) + " " + tagsPart + " " + normalStyle.Render(tokenPart)
 }
 
 // updateViewportScroll updates the viewport to follow the cursor
 func (r *PipelineViewRenderer) updateViewportScroll() {
 	if r.ActivePane == pipelinesPane && len(r.Pipelines) > 0 {
-		// For pipelines, each item is one line
+		// For pipelines, each item is one line; the folder tree records
+		// the cursor line while rendering
 		currentLine := r.PipelineCursor
+		if r.Tree != nil {
+			currentLine = r.cursorLine
+		}
 
 		// Ensure the cursor line is visible
 		if currentLine < r.Viewport.YOffset {
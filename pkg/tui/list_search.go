@@ -62,14 +62,16 @@ func (s *SearchManager) GetSearchHelper() *unified.SearchHelper {
 	return s.helper
 }
 
-// FilterSearchResultsUnified uses the new unified search system for better performance
-func FilterSearchResultsUnified(query string, pipelines []pipelineItem, components []componentItem) ([]pipelineItem, []componentItem) {
+// FilterSearchResultsUnified uses the new unified search system for better performance.
+// The items are loaded into manager, which then offers them as suggestions.
+// A malformed query returns the items unfiltered along with the syntax error.
+func FilterSearchResultsUnified(manager *unified.UnifiedSearchManager, query string, pipelines []pipelineItem, components []componentItem) ([]pipelineItem, []componentItem, error) {
 	// Convert TUI types to shared types
 	sharedPipelines := convertTUIPipelinesToShared(pipelines)
 	sharedComponents := convertTUIComponentsToShared(components)
 	
 	// Use unified search helper
-	helper := unified.NewSearchHelper()
+	helper := unified.NewSearchHelperForManager(manager)
 	helper.SetSearchOptions(unified.ShouldIncludeArchived(query), 1000, "relevance")
 	
 	// Separate components by type for the search
@@ -78,10 +80,11 @@ func FilterSearchResultsUnified(query string, pipelines []pipelineItem, componen
 		switch comp.CompType {
 		case models.ComponentTypePrompt:
 			prompts = append(prompts, comp)
-		case models.ComponentTypeContext:
-			contexts = append(contexts, comp)
 		case models.ComponentTypeRules:
 			rules = append(rules, comp)
+		default:
+			// Contexts, plus custom component types which travel with them
+			contexts = append(contexts, comp)
 		}
 	}
 	
@@ -89,15 +92,15 @@ func FilterSearchResultsUnified(query string, pipelines []pipelineItem, componen
 	filteredPrompts, filteredContexts, filteredRules, filteredPipelines, err := helper.UnifiedFilterAll(query, prompts, contexts, rules, sharedPipelines)
 	if err != nil {
 		// Fallback to return all items on error
-		return pipelines, components
+		return pipelines, components, err
 	}
 	
-	// Convert back to TUI types
+	// Convert back to TUI types, with each type under its own section header
 	resultComponents := unified.CombineComponentsByType(filteredPrompts, filteredContexts, filteredRules)
-	tuiComponents := convertSharedComponentsToTUIList(resultComponents)
+	tuiComponents := orderComponentsBySections(convertSharedComponentsToTUIList(resultComponents))
 	tuiPipelines := convertSharedPipelinesToTUI(filteredPipelines)
 	
-	return tuiPipelines, tuiComponents
+	return tuiPipelines, tuiComponents, nil
 }
 
 
@@ -126,11 +129,12 @@ func convertSharedPipelinesToTUI(pipelines []unified.PipelineItem) []pipelineIte
 	tui_pipelines := make([]pipelineItem, len(pipelines))
 	for i, p := range pipelines {
 		tui_pipelines[i] = pipelineItem{
-			name:       p.Name,
-			path:       p.Path,
-			tags:       p.Tags,
-			tokenCount: p.TokenCount,
-			isArchived: p.IsArchived,
+			name:        p.Name,
+			path:        p.Path,
+			tags:        p.Tags,
+			tokenCount:  p.TokenCount,
+			isArchived:  p.IsArchived,
+			nameMatches: p.NameMatches,
 		}
 	}
 	return tui_pipelines
@@ -149,6 +153,7 @@ func convertSharedComponentsToTUIList(components []unified.ComponentItem) []comp
 			tokenCount:   c.TokenCount,
 			tags:         c.Tags,
 			isArchived:   c.IsArchived,
+			nameMatches:  c.NameMatches,
 		}
 	}
 	return tui_components
@@ -1,5 +1,7 @@
 package tui
 
+import "github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
+
 // pane represents the different sections of the list view
 type pane int
 
@@ -18,4 +20,6 @@ type pipelineItem struct {
 	tags       []string
 	tokenCount int
 	isArchived bool
+
+	nameMatches []unified.MatchRange // Spans of name matched by the search, underlined in the list
 }
@@ -154,7 +154,7 @@ func (mo *MermaidOperator) generateMermaidGraph(pipeline *models.Pipeline) strin
 	// Generate subgraphs according to user's section order
 	prevGroup := "Pipeline"
 
-	for _, section := range settings.Output.Formatting.Sections {
+	for _, section := range settings.OutputSections() {
 		var components []models.ComponentRef
 		var cssClass string
 		var groupID string
@@ -315,7 +315,7 @@ func (mo *MermaidOperator) generateComponentData(pipeline *models.Pipeline) (str
 	// Process components in the same order as the graph
 	contextCount, promptCount, rulesCount := 0, 0, 0
 
-	for _, section := range settings.Output.Formatting.Sections {
+	for _, section := range settings.OutputSections() {
 		var components []models.ComponentRef
 		var prefix string
 		var typeName string
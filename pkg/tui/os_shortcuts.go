@@ -115,12 +115,16 @@ var Shortcuts = struct {
 	// Filter operations
 	ToggleArchived ShortcutKey
 	CycleType      ShortcutKey
+	CycleSort      ShortcutKey
+	CycleSaved     ShortcutKey
 	
 	// Pipeline operations
 	ReorderUp      ShortcutKey
 	ReorderDown    ShortcutKey
 	Diagram        ShortcutKey
 	SetPipeline    ShortcutKey
+	ToggleFolder   ShortcutKey
+	ExpandFolders  ShortcutKey
 	
 	// System
 	Quit           ShortcutKey
@@ -232,6 +236,18 @@ var Shortcuts = struct {
 		Windows: "alt+t",   // Consistent with Linux
 		Default: "ctrl+t",
 	},
+	CycleSort: ShortcutKey{
+		Mac:     "ctrl+o",
+		Linux:   "alt+o",   // Avoid readline operate-and-get-next
+		Windows: "alt+o",   // Consistent with Linux
+		Default: "ctrl+o",
+	},
+	CycleSaved: ShortcutKey{
+		Mac:     "ctrl+g",
+		Linux:   "alt+g",   // Avoid readline abort
+		Windows: "alt+g",   // Consistent with Linux
+		Default: "ctrl+g",
+	},
 	
 	// Pipeline operations
 	ReorderUp: ShortcutKey{
@@ -269,6 +285,12 @@ var Shortcuts = struct {
 	Preview: ShortcutKey{
 		Default: "p",
 	},
+	ToggleFolder: ShortcutKey{
+		Default: "z",
+	},
+	ExpandFolders: ShortcutKey{
+		Default: "Z",
+	},
 	Copy: ShortcutKey{
 		Default: "y",
 	},
@@ -1,9 +1,14 @@
 package tui
 
 import (
+	"fmt"
+	"strings"
+
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
 )
 
 // SearchBar is a reusable search component with consistent styling
@@ -12,6 +17,12 @@ type SearchBar struct {
 	isActive   bool
 	width      int
 	searchText string
+	err        string // Problem with the current query, shown next to the input
+	label      string // What the query's saved search stands for, shown next to the input
+	count      int    // How many items the query matches; negative when not searching
+
+	suggestions []unified.Suggestion // Completions for the query, shown below the bar
+	selected    int                  // The suggestion Tab accepts
 }
 
 // NewSearchBar creates a new search bar component
@@ -23,6 +34,7 @@ func NewSearchBar() *SearchBar {
 
 	return &SearchBar{
 		input: ti,
+		count: -1,
 	}
 }
 
@@ -54,6 +66,129 @@ func (s *SearchBar) SetValue(value string) {
 	s.input.SetValue(value)
 }
 
+// SetError shows a problem with the current query, or clears it when err is nil
+func (s *SearchBar) SetError(err error) {
+	if s == nil {
+		return
+	}
+	s.err = ""
+	if err != nil {
+		s.err = err.Error()
+	}
+}
+
+// Error returns the problem shown for the current query, if any
+func (s *SearchBar) Error() string {
+	return s.err
+}
+
+// SetLabel shows what the current query's saved search stands for, or clears it when empty
+func (s *SearchBar) SetLabel(label string) {
+	if s == nil {
+		return
+	}
+	s.label = label
+}
+
+// Label returns the saved search description shown for the current query, if any
+func (s *SearchBar) Label() string {
+	return s.label
+}
+
+// SetMatchCount shows how many items the query matches; a negative count hides it
+func (s *SearchBar) SetMatchCount(count int) {
+	if s == nil {
+		return
+	}
+	s.count = count
+}
+
+// SetSuggestions replaces the completions offered for the query
+func (s *SearchBar) SetSuggestions(suggestions []unified.Suggestion) {
+	if s == nil {
+		return
+	}
+	s.suggestions = suggestions
+	s.selected = 0
+}
+
+// HasSuggestions reports whether the dropdown has completions to offer
+func (s *SearchBar) HasSuggestions() bool {
+	return s != nil && s.isActive && len(s.suggestions) > 0
+}
+
+// MoveSuggestion moves the dropdown selection by delta, wrapping at either end
+func (s *SearchBar) MoveSuggestion(delta int) {
+	if n := len(s.suggestions); n > 0 {
+		s.selected = ((s.selected+delta)%n + n) % n
+	}
+}
+
+// AcceptSuggestion replaces the query with the selected suggestion and closes
+// the dropdown. It returns false if there was nothing to accept.
+func (s *SearchBar) AcceptSuggestion() bool {
+	if !s.HasSuggestions() {
+		return false
+	}
+	s.input.SetValue(s.suggestions[s.selected].Query)
+	s.input.CursorEnd()
+	s.suggestions = nil
+	s.selected = 0
+	return true
+}
+
+// SuggestionsView renders the dropdown of suggestions, or an empty string when
+// there are none. It is drawn over the content below the bar, lined up with the
+// input.
+func (s *SearchBar) SuggestionsView() string {
+	if !s.HasSuggestions() {
+		return ""
+	}
+
+	textWidth := 0
+	for _, suggestion := range s.suggestions {
+		textWidth = max(textWidth, lipgloss.Width(suggestion.Text))
+	}
+	// Leave room for the kind column, padding and borders
+	textWidth = min(textWidth, s.input.Width-14)
+	if textWidth < 10 {
+		textWidth = 10
+	}
+
+	textStyle := lipgloss.NewStyle().Width(textWidth)
+	kindStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorDim))
+	selectedStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color("170")).
+		Foreground(lipgloss.Color("255"))
+
+	var lines []string
+	for i, suggestion := range s.suggestions {
+		text := []rune(suggestion.Text)
+		if len(text) > textWidth {
+			text = append(text[:textWidth-3], []rune("...")...)
+		}
+		kind := fmt.Sprintf("%-6s", suggestion.Kind)
+		if i == s.selected {
+			lines = append(lines, selectedStyle.Render(textStyle.Render(string(text))+"  "+kind))
+			continue
+		}
+		lines = append(lines, textStyle.Render(string(text))+"  "+kindStyle.Render(kind))
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("170")).
+		Render(strings.Join(lines, "\n"))
+}
+
+// SuggestionsOffset is the column the dropdown is drawn at, so its text lines up
+// with the input's
+func (s *SearchBar) SuggestionsOffset() int {
+	// Outer padding, border, padding, icon and the space after it, less the
+	// dropdown's own border
+	return 1 + 1 + 1 + 3 + 1 - 1
+}
+
 // Update handles tea messages for the search bar
 func (s *SearchBar) Update(msg tea.Msg) (*SearchBar, tea.Cmd) {
 	var cmd tea.Cmd
@@ -96,6 +231,39 @@ func (s *SearchBar) View() string {
 
 	// Add spacing after icon before search input
 	searchContent := lipgloss.JoinHorizontal(lipgloss.Center, searchIcon, " ", s.input.View())
+	
+	// Show query errors, or else the match count and the saved search being used,
+	// at the end of the bar, shrinking the input to make room
+	note, noteColor := s.label, ColorDim
+	if s.count >= 0 && s.input.Value() != "" {
+		matches := fmt.Sprintf("%d matches", s.count)
+		if s.count == 1 {
+			matches = "1 match"
+		}
+		if note != "" {
+			matches += " · "
+		}
+		note = matches + note
+	}
+	if s.err != "" {
+		note, noteColor = "✗ "+s.err, ColorError
+	}
+	if note != "" {
+		noteStyle := lipgloss.NewStyle().
+			Foreground(lipgloss.Color(noteColor))
+		message := []rune(note)
+		if maxWidth := s.input.Width - 11; len(message) > maxWidth && maxWidth > 3 {
+			message = append(message[:maxWidth-3], []rune("...")...)
+		}
+		noteText := noteStyle.Render(string(message))
+		
+		input := s.input
+		input.Width = s.input.Width - lipgloss.Width(noteText) - 2
+		if input.Width < 10 {
+			input.Width = 10
+		}
+		searchContent = lipgloss.JoinHorizontal(lipgloss.Center, searchIcon, " ", input.View(), " ", noteText)
+	}
 
 	// Apply outer padding
 	outerPadding := lipgloss.NewStyle().
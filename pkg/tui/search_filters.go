@@ -3,6 +3,10 @@ package tui
 import (
 	"regexp"
 	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
 )
 
 // SearchFilterHelper provides functions to manipulate search query filters
@@ -29,7 +33,7 @@ func (sfh *SearchFilterHelper) CycleTypeFilter(query string) string {
 	currentType := sfh.extractTypeFilter(query)
 	
 	// Define the cycle order (empty string means "all"/no filter)
-	typeOrder := []string{"", "pipelines", "prompts", "contexts", "rules"}
+	typeOrder := append([]string{"", "pipelines", "prompts", "contexts", "rules"}, customComponentTypeNames()...)
 	
 	// Find current index and get next
 	currentIndex := 0
@@ -61,7 +65,7 @@ func (sfh *SearchFilterHelper) CycleTypeFilterForComponents(query string) string
 	currentType := sfh.extractTypeFilter(query)
 	
 	// Define the cycle order without pipelines (empty string means "all"/no filter)
-	typeOrder := []string{"", "prompts", "contexts", "rules"}
+	typeOrder := append([]string{"", "prompts", "contexts", "rules"}, customComponentTypeNames()...)
 	
 	// Find current index and get next
 	currentIndex := 0
@@ -92,6 +96,113 @@ func (sfh *SearchFilterHelper) CycleTypeFilterForComponents(query string) string
 	return strings.TrimSpace(query)
 }
 
+// CycleSortFilter cycles through sort orders: relevance -> newest modified -> oldest modified -> newest created -> name -> relevance
+func (sfh *SearchFilterHelper) CycleSortFilter(query string) string {
+	currentSort := sfh.extractSortFilter(query)
+	
+	// Define the cycle order (empty string means the default relevance order)
+	sortOrder := []string{"", "modified", "-modified", "created", "name"}
+	
+	currentIndex := 0
+	for i, s := range sortOrder {
+		if s == currentSort {
+			currentIndex = i
+			break
+		}
+	}
+	
+	nextSort := sortOrder[(currentIndex+1)%len(sortOrder)]
+	
+	// Replace the existing sort filter
+	query = sfh.removeSortFilter(query)
+	if nextSort != "" {
+		query = sfh.appendFilter(query, "sort:"+nextSort)
+	}
+	
+	return strings.TrimSpace(query)
+}
+
+// CycleSavedSearch cycles through the project's saved searches: none -> @first -> ... -> @last -> none.
+// The saved search leads the query so the rest of it narrows the collection.
+func (sfh *SearchFilterHelper) CycleSavedSearch(query string, searches []unified.SavedSearch) string {
+	if len(searches) == 0 {
+		return query
+	}
+	currentName := sfh.extractSavedSearch(query)
+	
+	// Define the cycle order (empty string means no saved search)
+	order := []string{""}
+	for _, search := range searches {
+		order = append(order, search.Name)
+	}
+	
+	currentIndex := 0
+	for i, name := range order {
+		if name == currentName {
+			currentIndex = i
+			break
+		}
+	}
+	
+	nextName := order[(currentIndex+1)%len(order)]
+	
+	// Replace the existing saved search
+	query = sfh.removeSavedSearch(query)
+	if nextName != "" {
+		query = strings.TrimSpace("@" + nextName + " " + query)
+	}
+	
+	return query
+}
+
+// SavedSearchLabel describes the saved search in a query for display next to the search bar,
+// e.g. "@api-contexts = tag:api type:context" (or empty string if none)
+func (sfh *SearchFilterHelper) SavedSearchLabel(query string, searches []unified.SavedSearch) string {
+	name := sfh.extractSavedSearch(query)
+	if name == "" {
+		return ""
+	}
+	search, ok := unified.FindSavedSearch(searches, name)
+	if !ok {
+		return ""
+	}
+	return "@" + search.Name + " = " + search.Query
+}
+
+// extractSavedSearch returns the name of the @name saved search leading the query (or empty string if none)
+func (sfh *SearchFilterHelper) extractSavedSearch(query string) string {
+	re := regexp.MustCompile(`^\s*@([\w-]+)`)
+	matches := re.FindStringSubmatch(query)
+	if len(matches) > 1 {
+		return strings.ToLower(matches[1])
+	}
+	return ""
+}
+
+// removeSavedSearch removes the leading @name saved search from the query
+func (sfh *SearchFilterHelper) removeSavedSearch(query string) string {
+	re := regexp.MustCompile(`^\s*@[\w-]+\s*`)
+	result := re.ReplaceAllString(query, " ")
+	return sfh.cleanupSpaces(result)
+}
+
+// extractSortFilter returns the current sort:xxx value (or empty string if none)
+func (sfh *SearchFilterHelper) extractSortFilter(query string) string {
+	re := regexp.MustCompile(`sort:(-?\w+)`)
+	matches := re.FindStringSubmatch(query)
+	if len(matches) > 1 {
+		return matches[1]
+	}
+	return ""
+}
+
+// removeSortFilter removes any sort:xxx filter from the query
+func (sfh *SearchFilterHelper) removeSortFilter(query string) string {
+	re := regexp.MustCompile(`\s*sort:-?\w+\s*`)
+	result := re.ReplaceAllString(query, " ")
+	return sfh.cleanupSpaces(result)
+}
+
 // extractTypeFilter finds and returns the current type filter value (or empty string if none)
 func (sfh *SearchFilterHelper) extractTypeFilter(query string) string {
 	re := regexp.MustCompile(`type:(\w+)`)
@@ -145,5 +256,21 @@ func (sfh *SearchFilterHelper) GetCurrentFilters(query string) []string {
 		filters = append(filters, "Type: "+typeFilter)
 	}
 	
+	sortFilter := sfh.extractSortFilter(query)
+	if sortFilter != "" {
+		filters = append(filters, "Sort: "+sortFilter)
+	}
+	
 	return filters
-}
\ No newline at end of file
+}
+
+// customComponentTypeNames returns the project's custom component types, which follow rules in the type cycle
+func customComponentTypeNames() []string {
+	var names []string
+	for _, ct := range files.ComponentTypes() {
+		if !models.IsBuiltinComponentType(ct.Name) {
+			names = append(names, ct.Name)
+		}
+	}
+	return names
+}
@@ -2,6 +2,8 @@ package tui
 
 import (
 	"testing"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
 )
 
 func TestSearchFilterHelper_ToggleArchivedFilter(t *testing.T) {
@@ -246,4 +248,106 @@ func TestSearchFilterHelper_CycleTypeFilterForComponents(t *testing.T) {
 			}
 		})
 	}
-}
\ No newline at end of file
+}
+func TestSearchFilterHelper_CycleSortFilter(t *testing.T) {
+	sfh := NewSearchFilterHelper()
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "add modified sort to empty query",
+			input: "",
+			want:  "sort:modified",
+		},
+		{
+			name:  "cycle from newest to oldest modified",
+			input: "sort:modified",
+			want:  "sort:-modified",
+		},
+		{
+			name:  "cycle from oldest modified to created",
+			input: "sort:-modified",
+			want:  "sort:created",
+		},
+		{
+			name:  "cycle from created to name",
+			input: "sort:created",
+			want:  "sort:name",
+		},
+		{
+			name:  "cycle from name back to relevance (no sort)",
+			input: "sort:name",
+			want:  "",
+		},
+		{
+			name:  "cycle sort with other filters",
+			input: "tag:api sort:-modified modified:>90d",
+			want:  "tag:api modified:>90d sort:created",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sfh.CycleSortFilter(tt.input)
+			if got != tt.want {
+				t.Errorf("CycleSortFilter(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchFilterHelper_CycleSavedSearch(t *testing.T) {
+	sfh := NewSearchFilterHelper()
+	searches := []unified.SavedSearch{
+		{Name: "api-contexts", Query: "tag:api type:context"},
+		{Name: "drafts", Query: "tag:draft"},
+	}
+
+	tests := []struct {
+		name  string
+		input string
+		want  string
+	}{
+		{
+			name:  "add first saved search to empty query",
+			input: "",
+			want:  "@api-contexts",
+		},
+		{
+			name:  "cycle to next saved search",
+			input: "@api-contexts",
+			want:  "@drafts",
+		},
+		{
+			name:  "cycle from last back to none",
+			input: "@drafts",
+			want:  "",
+		},
+		{
+			name:  "saved search leads other terms",
+			input: "sort:name auth",
+			want:  "@api-contexts sort:name auth",
+		},
+		{
+			name:  "keep other terms when cycling",
+			input: "@api-contexts -tag:old",
+			want:  "@drafts -tag:old",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := sfh.CycleSavedSearch(tt.input, searches)
+			if got != tt.want {
+				t.Errorf("CycleSavedSearch(%q) = %q, want %q", tt.input, got, tt.want)
+			}
+		})
+	}
+
+	if got := sfh.CycleSavedSearch("tag:api", nil); got != "tag:api" {
+		t.Errorf("CycleSavedSearch without saved searches = %q, want the query unchanged", got)
+	}
+}
@@ -0,0 +1,84 @@
+package tui
+
+import (
+	"strings"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
+	"github.com/pluqqy/pluqqy-terminal/pkg/tags"
+)
+
+const (
+	// maxSuggestions is how many suggestions the search bar's dropdown shows
+	maxSuggestions = 6
+	// maxRecentSearches is how many of this session's searches are remembered
+	maxRecentSearches = 10
+)
+
+// recentSearches are the searches made this session, newest first. They are
+// shared by the main list and the pipeline builder.
+var recentSearches []string
+
+// rememberSearch records a search the user settled on, moving it to the front if
+// it was already there
+func rememberSearch(query string) {
+	query = strings.TrimSpace(query)
+	if query == "" {
+		return
+	}
+
+	recent := []string{query}
+	for _, previous := range recentSearches {
+		if previous != query && len(recent) < maxRecentSearches {
+			recent = append(recent, previous)
+		}
+	}
+	recentSearches = recent
+}
+
+// searchSources holds the registered tags and saved searches the search bar
+// draws on, so tags.yaml and searches.yaml are read when the view reloads rather
+// than on every keystroke
+type searchSources struct {
+	tags          []string
+	savedSearches []unified.SavedSearch
+	savedErr      error // Why searches.yaml couldn't be read, if it couldn't
+}
+
+// Reload reads the tag registry and saved searches again
+func (s *searchSources) Reload() {
+	s.tags = nil
+	if registry, err := tags.NewRegistry(); err == nil {
+		for _, tag := range registry.ListTags() {
+			s.tags = append(s.tags, tag.Name)
+		}
+	}
+	s.savedSearches, s.savedErr = unified.LoadSavedSearches()
+}
+
+// SavedSearches returns the saved searches as of the last reload
+func (s *searchSources) SavedSearches() []unified.SavedSearch {
+	return s.savedSearches
+}
+
+// Expand expands the query's @name saved searches. When searches.yaml couldn't
+// be read the file is tried again, so that its error is reported.
+func (s *searchSources) Expand(query string) (string, error) {
+	if s.savedErr != nil {
+		return unified.ExpandSavedSearches(query)
+	}
+	return unified.ExpandSavedSearchesWith(query, s.savedSearches)
+}
+
+// searchSuggestions completes the query from the items loaded into manager, the
+// tag registry, saved searches and recent searches
+func searchSuggestions(manager *unified.UnifiedSearchManager, sources *searchSources, query string) []unified.Suggestion {
+	if manager == nil {
+		return nil
+	}
+
+	return manager.Suggest(query, unified.SuggestionSources{
+		Tags:          sources.tags,
+		SavedSearches: sources.savedSearches,
+		Recent:        recentSearches,
+	}, maxSuggestions)
+}
@@ -0,0 +1,138 @@
+package tui
+
+import (
+	"os"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/charmbracelet/x/ansi"
+
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
+	"github.com/pluqqy/pluqqy-terminal/pkg/search/unified"
+)
+
+func TestRememberSearch(t *testing.T) {
+	saved := recentSearches
+	t.Cleanup(func() { recentSearches = saved })
+	recentSearches = nil
+
+	rememberSearch("tag:api")
+	rememberSearch("  ")
+	rememberSearch("auth")
+	rememberSearch("tag:api")
+
+	if want := []string{"tag:api", "auth"}; !reflect.DeepEqual(recentSearches, want) {
+		t.Errorf("recentSearches = %q, want %q", recentSearches, want)
+	}
+
+	for i := 0; i < maxRecentSearches+5; i++ {
+		rememberSearch(strings.Repeat("x", i+1))
+	}
+	if len(recentSearches) != maxRecentSearches {
+		t.Errorf("remembered %d searches, want at most %d", len(recentSearches), maxRecentSearches)
+	}
+}
+
+func TestSearchBar_Suggestions(t *testing.T) {
+	bar := NewSearchBar()
+	bar.SetWidth(80)
+	bar.SetValue("tag:a")
+	bar.SetSuggestions([]unified.Suggestion{
+		{Query: "tag:api", Text: "tag:api", Kind: "tag"},
+		{Query: "tag:auth", Text: "tag:auth", Kind: "tag"},
+	})
+
+	// The dropdown only shows while the bar is active
+	if bar.HasSuggestions() || bar.SuggestionsView() != "" {
+		t.Error("an inactive search bar should not show suggestions")
+	}
+	bar.SetActive(true)
+	if !bar.HasSuggestions() {
+		t.Fatal("expected suggestions once active")
+	}
+	view := ansi.Strip(bar.SuggestionsView())
+	if !strings.Contains(view, "tag:api") || !strings.Contains(view, "tag:auth") {
+		t.Errorf("dropdown should list both suggestions:\n%s", view)
+	}
+
+	bar.MoveSuggestion(-1) // Wraps to the last suggestion
+	if !bar.AcceptSuggestion() {
+		t.Fatal("AcceptSuggestion() = false, want true")
+	}
+	if got := bar.Value(); got != "tag:auth" {
+		t.Errorf("accepted query = %q, want tag:auth", got)
+	}
+	if bar.HasSuggestions() || bar.AcceptSuggestion() {
+		t.Error("accepting a suggestion should close the dropdown")
+	}
+}
+
+func TestSearchBar_MatchCount(t *testing.T) {
+	bar := NewSearchBar()
+	bar.SetWidth(100)
+	bar.SetValue("tag:api")
+
+	if strings.Contains(ansi.Strip(bar.View()), "match") {
+		t.Error("no count should show before a search")
+	}
+
+	bar.SetMatchCount(12)
+	if view := ansi.Strip(bar.View()); !strings.Contains(view, "12 matches") {
+		t.Errorf("expected the match count in the bar:\n%s", view)
+	}
+	bar.SetMatchCount(1)
+	bar.SetLabel("@api = tag:api")
+	if view := ansi.Strip(bar.View()); !strings.Contains(view, "1 match · @api") {
+		t.Errorf("expected the count before the saved search:\n%s", view)
+	}
+}
+
+func TestOverlayAt(t *testing.T) {
+	base := "0123456789\nabcdefghij\nABCDEFGHIJ"
+	got := ansi.Strip(overlayAt(base, "xx\nyy", 3, 1))
+	want := "0123456789\nabcxxfghij\nABCyyFGHIJ"
+	if got != want {
+		t.Errorf("overlayAt =\n%s\nwant\n%s", got, want)
+	}
+
+	// Short lines are padded and missing lines added
+	got = ansi.Strip(overlayAt("ab", "xy\nzz", 4, 0))
+	if want := "ab  xy\n    zz"; got != want {
+		t.Errorf("overlayAt past the end = %q, want %q", got, want)
+	}
+	if got := overlayAt(base, "", 0, 0); got != base {
+		t.Error("an empty overlay should leave the base alone")
+	}
+}
+
+func TestSearchSources_Reload(t *testing.T) {
+	tempDir := t.TempDir()
+	oldWd, _ := os.Getwd()
+	t.Cleanup(func() { os.Chdir(oldWd) })
+	os.Chdir(tempDir)
+	if err := files.InitProjectStructure(); err != nil {
+		t.Fatalf("InitProjectStructure() error = %v", err)
+	}
+	if err := unified.SaveSearch(unified.SavedSearch{Name: "api", Query: "tag:api"}); err != nil {
+		t.Fatalf("SaveSearch() error = %v", err)
+	}
+
+	var sources searchSources
+	sources.Reload()
+	if got, err := sources.Expand("@api"); err != nil || got != "(tag:api)" {
+		t.Errorf("Expand(@api) = %q, %v; want (tag:api)", got, err)
+	}
+
+	// Changes on disk are picked up on the next reload, not while typing
+	if err := unified.SaveSearch(unified.SavedSearch{Name: "api", Query: "tag:api -tag:draft"}); err != nil {
+		t.Fatalf("SaveSearch() error = %v", err)
+	}
+	if got, _ := sources.Expand("@api"); got != "(tag:api)" {
+		t.Errorf("Expand(@api) before reload = %q, want the cached (tag:api)", got)
+	}
+	sources.Reload()
+	if got, _ := sources.Expand("@api"); got != "(tag:api -tag:draft)" {
+		t.Errorf("Expand(@api) after reload = %q, want (tag:api -tag:draft)", got)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pluqqy/pluqqy-terminal/pkg/composer"
 	"github.com/pluqqy/pluqqy-terminal/pkg/files"
 	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 )
@@ -25,6 +26,7 @@ const (
 	fieldExportPath
 	fieldOutputPath
 	fieldShowHeadings
+	fieldOutputFormat
 	fieldSections // This is where sections list starts
 )
 
@@ -94,6 +96,7 @@ func (m *SettingsEditorModel) loadSettings() tea.Cmd {
 				DefaultFilename: settings.Output.DefaultFilename,
 				ExportPath:      settings.Output.ExportPath,
 				OutputPath:      settings.Output.OutputPath,
+				Format:          settings.Output.Format,
 				Formatting: models.FormattingSettings{
 					ShowHeadings: settings.Output.Formatting.ShowHeadings,
 					Sections:     make([]models.Section, len(settings.Output.Formatting.Sections)),
@@ -121,7 +124,7 @@ type settingsLoadedMsg struct {
 func (m *SettingsEditorModel) updateFocus() {
 	// Calculate total fields
 	if m.settings != nil {
-		m.totalFields = 4 + len(m.settings.Output.Formatting.Sections) // 4 basic fields + sections
+		m.totalFields = 5 + len(m.settings.Output.Formatting.Sections) // 5 basic fields + sections
 	}
 
 	// Disable all inputs first
@@ -345,6 +348,12 @@ func (m *SettingsEditorModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.hasChanges = true
 				m.updateViewportContent()
 			}
+			// Cycle through the available output formats
+			if m.focusIndex == fieldOutputFormat {
+				m.settings.Output.Format = nextOutputFormat(m.settings.Output.Format)
+				m.hasChanges = true
+				m.updateViewportContent()
+			}
 
 		case "enter":
 			if m.focusIndex >= fieldSections {
@@ -584,6 +593,22 @@ func (m *SettingsEditorModel) updateViewportContent() {
 	content.WriteString(commentStyle.Render("  # Whether to include section headers in the output"))
 	content.WriteString("\n\n")
 
+	// Output format selector
+	format := m.settings.Output.Format
+	if format == "" {
+		format = models.OutputFormatMarkdown
+	}
+	label = labelStyle.Render("Output Format:")
+	fieldLine = label + " " + format
+	if m.focusIndex == fieldOutputFormat {
+		content.WriteString(focusedStyle.Render("▸ " + fieldLine))
+	} else {
+		content.WriteString(normalStyle.Render("  " + fieldLine))
+	}
+	content.WriteString("\n\n")
+	content.WriteString(commentStyle.Render("  # Renderer for composed output: " + strings.Join(composer.RendererNames(), ", ") + " (space to change)"))
+	content.WriteString("\n\n")
+
 	// Sections
 	content.WriteString(sectionStyle.Render("SECTIONS"))
 	content.WriteString("\n\n")
@@ -683,6 +708,7 @@ func (m *SettingsEditorModel) saveSettings() tea.Cmd {
 				DefaultFilename: m.settings.Output.DefaultFilename,
 				ExportPath:      m.settings.Output.ExportPath,
 				OutputPath:      m.settings.Output.OutputPath,
+				Format:          m.settings.Output.Format,
 				Formatting: models.FormattingSettings{
 					ShowHeadings: m.settings.Output.Formatting.ShowHeadings,
 					Sections:     make([]models.Section, len(m.settings.Output.Formatting.Sections)),
@@ -715,3 +741,17 @@ func (m *SettingsEditorModel) SetSize(width, height int) {
 	m.height = height
 	m.updateViewportSize()
 }
+
+// nextOutputFormat returns the format after current in the list of available renderers
+func nextOutputFormat(current string) string {
+	if current == "" {
+		current = models.OutputFormatMarkdown
+	}
+	names := composer.RendererNames()
+	for i, name := range names {
+		if name == current {
+			return names[(i+1)%len(names)]
+		}
+	}
+	return names[0]
+}
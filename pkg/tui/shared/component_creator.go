@@ -134,13 +134,13 @@ func (c *ComponentCreator) HandleTypeSelection(msg tea.KeyMsg) bool {
 		}
 		return true
 	case "down", "j":
-		if c.typeCursor < 2 {
+		if c.typeCursor < len(CreatableComponentTypes())-1 {
 			c.typeCursor++
 		}
 		return true
 	case "enter":
-		types := []string{models.ComponentTypeContext, models.ComponentTypePrompt, models.ComponentTypeRules}
-		c.componentCreationType = types[c.typeCursor]
+		types := CreatableComponentTypes()
+		c.componentCreationType = types[c.typeCursor].Name
 		c.creationStep = 1
 		c.validationError = ""
 		return true
@@ -219,15 +219,8 @@ func (c *ComponentCreator) validateComponentName(name string) error {
 // checkForDuplicates checks if a component with the same name already exists
 func (c *ComponentCreator) checkForDuplicates(name, componentType string) error {
 	// Convert component type to directory name
-	var subDir string
-	switch componentType {
-	case models.ComponentTypeContext:
-		subDir = models.ComponentTypeContext
-	case models.ComponentTypePrompt:
-		subDir = models.ComponentTypePrompt
-	case models.ComponentTypeRules:
-		subDir = models.ComponentTypeRules
-	default:
+	subDir, ok := ComponentTypeDir(componentType)
+	if !ok {
 		return fmt.Errorf("invalid component type: %s", componentType)
 	}
 
@@ -296,15 +289,7 @@ func (c *ComponentCreator) initializeEnhancedEditor() {
 	}
 
 	// Generate the component path that will be used when saving
-	var subDir string
-	switch c.componentCreationType {
-	case models.ComponentTypeContext:
-		subDir = models.ComponentTypeContext
-	case models.ComponentTypePrompt:
-		subDir = models.ComponentTypePrompt
-	case models.ComponentTypeRules:
-		subDir = models.ComponentTypeRules
-	}
+	subDir, _ := ComponentTypeDir(c.componentCreationType)
 
 	// Ensure directory exists for new components
 	dir := filepath.Join(files.PluqqyDir, files.ComponentsDir, subDir)
@@ -356,4 +341,28 @@ func (c *ComponentCreator) IsEnhancedEditorActive() bool {
 // GetEnhancedEditor returns the enhanced editor instance
 func (c *ComponentCreator) GetEnhancedEditor() EnhancedEditorInterface {
 	return c.enhancedEditor
-}
\ No newline at end of file
+}
+// CreatableComponentTypes returns the component types offered when creating a
+// component: contexts, prompts and rules, followed by the project's custom types
+func CreatableComponentTypes() []models.ComponentTypeConfig {
+	types := []models.ComponentTypeConfig{
+		{Name: models.ComponentTypeContext},
+		{Name: models.ComponentTypePrompt},
+		{Name: models.ComponentTypeRules},
+	}
+	for _, ct := range files.ComponentTypes() {
+		if !models.IsBuiltinComponentType(ct.Name) {
+			types = append(types, ct)
+		}
+	}
+	return types
+}
+
+// ComponentTypeDir returns the directory under components/ for a component type
+func ComponentTypeDir(componentType string) (string, bool) {
+	ct, ok := files.LookupComponentType(componentType)
+	if !ok {
+		return "", false
+	}
+	return ct.DirName(), true
+}
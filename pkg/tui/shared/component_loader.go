@@ -33,7 +33,8 @@ func NewComponentLoader(projectPath string) *ComponentLoader {
 	}
 }
 
-// LoadComponents loads all components (prompts, contexts, rules) from the project
+// LoadComponents loads all components (prompts, contexts, rules) from the project.
+// Components of custom types are returned with the contexts and keep their own CompType.
 func (cl *ComponentLoader) LoadComponents(includeArchived bool) ([]ComponentItem, []ComponentItem, []ComponentItem, error) {
 	// Get usage counts for all components
 	usageMap, _ := files.CountComponentUsage()
@@ -43,6 +44,12 @@ func (cl *ComponentLoader) LoadComponents(includeArchived bool) ([]ComponentItem
 	contexts := cl.loadComponentsOfType("contexts", files.ContextsDir, models.ComponentTypeContext, usageMap, includeArchived)
 	rules := cl.loadComponentsOfType("rules", files.RulesDir, models.ComponentTypeRules, usageMap, includeArchived)
 
+	for _, ct := range files.ComponentTypes() {
+		if !models.IsBuiltinComponentType(ct.Name) {
+			contexts = append(contexts, cl.loadComponentsOfType(ct.Name, ct.DirName(), ct.Name, usageMap, includeArchived)...)
+		}
+	}
+
 	return prompts, contexts, rules, nil
 }
 
@@ -17,6 +17,9 @@ type TagEditorDataStore struct {
 	// Deletion state data
 	DeletingTag      string
 	DeletingTagUsage *tags.UsageStats
+	
+	// Rename state data
+	RenamingTag string
 }
 
 // TagEditorStateManager manages core editor state and context information
@@ -43,6 +46,9 @@ type TagEditorInputComponents struct {
 	ShowSuggestions         bool
 	SuggestionCursor        int
 	HasNavigatedSuggestions bool
+	
+	// New name typed while renaming a tag from the cloud
+	RenameInput             string
 }
 
 // TagEditorUIComponents manages UI state including tag cloud navigation,
@@ -51,6 +57,7 @@ type TagEditorUIComponents struct {
 	// Tag cloud navigation
 	TagCloudActive bool
 	TagCloudCursor int
+	CollapsedTags  map[string]bool // Collapsed parent tags in the cloud tree
 	
 	// Dimensions
 	Width  int
@@ -113,6 +120,7 @@ func (d *TagEditorDataStore) ResetTags() {
 	d.OriginalTags = []string{}
 	d.DeletingTag = ""
 	d.DeletingTagUsage = nil
+	d.RenamingTag = ""
 }
 
 func (d *TagEditorDataStore) UpdateOriginalTags() {
@@ -145,6 +153,7 @@ func (i *TagEditorInputComponents) Reset() {
 	i.ShowSuggestions = false
 	i.SuggestionCursor = 0
 	i.HasNavigatedSuggestions = false
+	i.RenameInput = ""
 }
 
 func (i *TagEditorInputComponents) UpdateSuggestions() {
@@ -108,14 +108,10 @@ func DeleteTagCompletely(tagToDelete string, showProgress func(string, int, int)
 		}
 		
 		// 1. Process active components
-		componentTypes := []string{
-			models.ComponentTypePrompt,
-			models.ComponentTypeContext,
-			models.ComponentTypeRules,
-		}
+		componentTypes := files.ComponentTypes()
 		
 		for _, compType := range componentTypes {
-			components, err := files.ListComponents(compType)
+			components, err := files.ListComponents(compType.Name)
 			if err != nil {
 				continue
 			}
@@ -123,7 +119,7 @@ func DeleteTagCompletely(tagToDelete string, showProgress func(string, int, int)
 			totalEstimate += len(components)
 			
 			for _, compFile := range components {
-				compPath := filepath.Join(files.ComponentsDir, compType, compFile)
+				compPath := filepath.Join(files.ComponentsDir, compType.DirName(), compFile)
 				result.FilesScanned++
 				
 				if showProgress != nil {
@@ -182,17 +178,9 @@ func DeleteTagCompletely(tagToDelete string, showProgress func(string, int, int)
 		// 3. Process archived components
 		for _, compType := range componentTypes {
 			// Get the correct subdirectory name for this component type
-			var subDir string
-			switch compType {
-			case models.ComponentTypePrompt:
-				subDir = files.PromptsDir
-			case models.ComponentTypeContext:
-				subDir = files.ContextsDir
-			case models.ComponentTypeRules:
-				subDir = files.RulesDir
-			}
+			subDir := compType.DirName()
 			
-			archivedComps, err := files.ListArchivedComponents(compType)
+			archivedComps, err := files.ListArchivedComponents(compType.Name)
 			if err != nil {
 				continue
 			}
@@ -288,6 +276,48 @@ func DeleteTagCompletely(tagToDelete string, showProgress func(string, int, int)
 	}
 }
 
+// RenameTagCompletely renames a tag in all files and the registry. When newName is
+// already a registered tag the two are merged instead. Either every file is
+// updated or none is.
+func RenameTagCompletely(oldName, newName string, merge bool) tea.Cmd {
+	return func() tea.Msg {
+		msg := tagRenameCompleteMsg{OldName: oldName, NewName: newName, Merged: merge}
+		
+		registry, err := tags.NewRegistry()
+		if err != nil {
+			msg.Err = fmt.Errorf("failed to load registry: %w", err)
+			return msg
+		}
+		
+		if merge {
+			msg.Result, msg.Err = registry.MergeTagsEverywhere([]string{oldName}, newName)
+		} else {
+			msg.Result, msg.Err = registry.RenameTagEverywhere(oldName, newName)
+		}
+		return msg
+	}
+}
+
+// formatRenameResult creates a user-friendly message from a rename or merge
+func formatRenameResult(msg tagRenameCompleteMsg) string {
+	verb := "renamed to"
+	if msg.Merged {
+		verb = "merged into"
+	}
+	
+	if msg.Err != nil {
+		return fmt.Sprintf("× Tag '%s' not %s '%s': %v", msg.OldName, verb, msg.NewName, msg.Err)
+	}
+	
+	changed := msg.Result.FilesChanged()
+	return fmt.Sprintf("✓ Tag '%s' %s '%s' in %d file%s",
+		msg.OldName,
+		verb,
+		msg.NewName,
+		changed,
+		pluralS(changed))
+}
+
 // formatDeletionResult creates a user-friendly message from deletion results
 func formatDeletionResult(result TagDeletionResult) string {
 	if len(result.Errors) > 0 {
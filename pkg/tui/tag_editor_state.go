@@ -110,6 +110,11 @@ func (te *TagEditor) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
 		return true, cmd
 	}
 	
+	// The rename prompt captures all input until confirmed or cancelled
+	if te.Mode == TagEditorModeRenaming {
+		return true, te.handleRenameInput(msg)
+	}
+	
 	// Handle normal input based on current mode
 	switch msg.String() {
 	case "esc":
@@ -173,6 +178,17 @@ func (te *TagEditor) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
 		}
 		return true, nil
 		
+	case Shortcuts.Rename.Get():
+		if te.TagCloudActive {
+			// Rename or merge the tag under the cloud cursor
+			te.StartTagRename()
+		} else {
+			// Otherwise it's just a letter being typed
+			te.TagInput += msg.String()
+			te.UpdateSuggestions()
+		}
+		return true, nil
+		
 	case "enter":
 		if te.TagCloudActive {
 			// Add tag from cloud
@@ -202,8 +218,20 @@ func (te *TagEditor) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
 		}
 		return true, nil
 		
+	case " ":
+		if te.TagCloudActive {
+			// Expand or collapse the tag under the cursor
+			te.ToggleTagCollapse()
+		}
+		return true, nil
+		
 	case "up":
-		if !te.TagCloudActive && te.ShowSuggestions && te.TagInput != "" {
+		if te.TagCloudActive {
+			// Move up the tag tree
+			if te.TagCloudCursor > 0 {
+				te.TagCloudCursor--
+			}
+		} else if te.ShowSuggestions && te.TagInput != "" {
 			// Navigate up in suggestions
 			if te.SuggestionCursor > 0 {
 				te.SuggestionCursor--
@@ -213,7 +241,12 @@ func (te *TagEditor) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
 		return true, nil
 		
 	case "down":
-		if !te.TagCloudActive && te.ShowSuggestions && te.TagInput != "" {
+		if te.TagCloudActive {
+			// Move down the tag tree
+			if te.TagCloudCursor < len(te.GetAvailableTagsForCloud())-1 {
+				te.TagCloudCursor++
+			}
+		} else if te.ShowSuggestions && te.TagInput != "" {
 			// Navigate down in suggestions
 			suggestions := te.GetSuggestions()
 			maxSuggestions := len(suggestions)
@@ -402,9 +435,15 @@ func (te *TagEditor) RemoveTagAtCursor() {
 	}
 }
 
-// GetAvailableTagsForCloud returns tags available for selection in the cloud
+// GetAvailableTagsForCloud returns the tags visible in the cloud in tree order,
+// leaving out the descendants of collapsed tags
 func (te *TagEditor) GetAvailableTagsForCloud() []string {
-	return te.TagEditorDataStore.GetAvailableTagsForCloud()
+	nodes := te.TagCloudNodes()
+	available := make([]string, 0, len(nodes))
+	for _, node := range nodes {
+		available = append(available, node.Name)
+	}
+	return available
 }
 
 // StartTagDeletion starts the process of deleting a tag from the registry
@@ -472,6 +511,102 @@ func (te *TagEditor) DeleteTagFromRegistry() tea.Cmd {
 	return DeleteTagCompletely(te.DeletingTag, progressCallback)
 }
 
+// StartTagRename prompts for a new name for the tag under the cloud cursor
+func (te *TagEditor) StartTagRename() {
+	availableForCloud := te.GetAvailableTagsForCloud()
+	if te.TagCloudCursor >= len(availableForCloud) {
+		return
+	}
+	
+	te.RenamingTag = availableForCloud[te.TagCloudCursor]
+	te.RenameInput = te.RenamingTag
+	te.Mode = TagEditorModeRenaming
+}
+
+// CancelTagRename leaves the rename prompt without changing anything
+func (te *TagEditor) CancelTagRename() {
+	te.RenamingTag = ""
+	te.RenameInput = ""
+	te.Mode = TagEditorModeNormal
+}
+
+// handleRenameInput processes keyboard input while the rename prompt is open
+func (te *TagEditor) handleRenameInput(msg tea.KeyMsg) tea.Cmd {
+	switch msg.String() {
+	case "esc":
+		te.CancelTagRename()
+		
+	case "enter":
+		return te.ConfirmTagRename()
+		
+	case "backspace":
+		if len(te.RenameInput) > 0 {
+			te.RenameInput = te.RenameInput[:len(te.RenameInput)-1]
+		}
+		
+	default:
+		if msg.Type == tea.KeyRunes {
+			te.RenameInput += string(msg.Runes)
+		}
+	}
+	return nil
+}
+
+// ConfirmTagRename renames the tag to the typed name. Renaming onto an existing
+// tag asks for confirmation to merge the two.
+func (te *TagEditor) ConfirmTagRename() tea.Cmd {
+	oldName := te.RenamingTag
+	newName := models.NormalizeTagName(te.RenameInput)
+	if newName == "" || newName == models.NormalizeTagName(oldName) {
+		te.CancelTagRename()
+		return nil
+	}
+	
+	merge := false
+	for _, tag := range te.AvailableTags {
+		if models.NormalizeTagName(tag) == newName {
+			merge = true
+			break
+		}
+	}
+	
+	te.Mode = TagEditorModeNormal
+	if !merge {
+		tick := te.TagDeletionState.Start()
+		te.TagDeletionState.Progress = "Renaming tag..."
+		return tea.Batch(tick, RenameTagCompletely(oldName, newName, false))
+	}
+	
+	usage, _ := tags.CountTagUsage(oldName)
+	usageCount := 0
+	if usage != nil {
+		usageCount = usage.ComponentCount + usage.PipelineCount
+	}
+	message := fmt.Sprintf(
+		"Tag '%s' already exists.\n\nMerge '%s' into it? '%s' is used in %d item%s\nand will be replaced in all files and the registry.",
+		newName, oldName, oldName, usageCount, pluralS(usageCount),
+	)
+	
+	te.TagDeleteConfirm.Show(ConfirmationConfig{
+		Title:       "⚠️  Merge Tags",
+		Message:     message,
+		YesLabel:    "Merge",
+		NoLabel:     "Cancel",
+		Destructive: true,
+		Type:        ConfirmTypeDialog,
+		Width:       te.Width - 4,
+		Height:      10,
+	}, func() tea.Cmd {
+		tick := te.TagDeletionState.Start()
+		te.TagDeletionState.Progress = "Merging tags..."
+		return tea.Batch(tick, RenameTagCompletely(oldName, newName, true))
+	}, func() tea.Cmd {
+		te.CancelTagRename()
+		return nil
+	})
+	return nil
+}
+
 // HandleMessage processes messages related to tag editing
 func (te *TagEditor) HandleMessage(msg tea.Msg) (handled bool, cmd tea.Cmd) {
 	if !te.Active {
@@ -504,6 +639,29 @@ func (te *TagEditor) HandleMessage(msg tea.Msg) (handled bool, cmd tea.Cmd) {
 		// Return nil to trigger re-render
 		return true, nil
 		
+	case tagRenameCompleteMsg:
+		te.TagDeletionState.Active = false
+		te.Mode = TagEditorModeNormal
+		te.RenamingTag = ""
+		te.RenameInput = ""
+		
+		if msg.Err == nil {
+			// The item being edited was rewritten on disk too
+			te.CurrentTags = replaceTagName(te.CurrentTags, msg.OldName, msg.NewName)
+			te.OriginalTags = replaceTagName(te.OriginalTags, msg.OldName, msg.NewName)
+		}
+		
+		te.AvailableTags = []string{}
+		te.LoadAvailableTags()
+		
+		availableForCloud := te.GetAvailableTagsForCloud()
+		if te.TagCloudCursor >= len(availableForCloud) && te.TagCloudCursor > 0 {
+			te.TagCloudCursor = len(availableForCloud) - 1
+		}
+		
+		status := formatRenameResult(msg)
+		return true, func() tea.Msg { return StatusMsg(status) }
+		
 	case tagDeletionProgressMsg:
 		if te.TagDeletionState != nil {
 			te.TagDeletionState.Update(msg)
@@ -545,4 +703,26 @@ func (te *TagEditor) HandleMessage(msg tea.Msg) (handled bool, cmd tea.Cmd) {
 // SetSize updates the dimensions of the tag editor
 func (te *TagEditor) SetSize(width, height int) {
 	te.TagEditorUIComponents.SetSize(width, height)
-}
\ No newline at end of file
+}
+
+// replaceTagName replaces oldName with newName in a tag list, keeping a single
+// copy when the list already had newName
+func replaceTagName(tagList []string, oldName, newName string) []string {
+	oldNormalized := models.NormalizeTagName(oldName)
+	newNormalized := models.NormalizeTagName(newName)
+	
+	result := make([]string, 0, len(tagList))
+	seen := make(map[string]bool, len(tagList))
+	for _, tag := range tagList {
+		name := tag
+		if models.NormalizeTagName(tag) == oldNormalized {
+			name = newNormalized
+		}
+		if seen[models.NormalizeTagName(name)] {
+			continue
+		}
+		seen[models.NormalizeTagName(name)] = true
+		result = append(result, name)
+	}
+	return result
+}
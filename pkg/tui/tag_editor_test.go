@@ -1,9 +1,12 @@
 package tui
 
 import (
+	"errors"
+	"strings"
 	"testing"
 
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
 	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 	"github.com/stretchr/testify/assert"
 )
@@ -453,6 +456,29 @@ func TestTagEditor_HandleMessage(t *testing.T) {
 				assert.Equal(t, TagEditorModeNormal, te.Mode)
 			},
 		},
+		{
+			name: "Handle rename complete",
+			msg: tagRenameCompleteMsg{
+				OldName: "old",
+				NewName: "new",
+				Result:  &files.TagRewriteResult{Components: []string{"components/prompts/a.md"}},
+			},
+			setup: func(te *TagEditor) {
+				te.Active = true
+				te.TagDeletionState.Active = true
+				te.CurrentTags = []string{"old", "other"}
+				te.OriginalTags = []string{"old", "other"}
+				te.RenamingTag = "old"
+			},
+			wantHandled: true,
+			check: func(t *testing.T, te *TagEditor) {
+				assert.False(t, te.TagDeletionState.Active)
+				assert.Equal(t, TagEditorModeNormal, te.Mode)
+				assert.Empty(t, te.RenamingTag)
+				assert.Equal(t, []string{"new", "other"}, te.CurrentTags)
+				assert.False(t, te.HasChanges())
+			},
+		},
 		{
 			name: "Ignore when inactive",
 			msg:  tagDeletionCompleteMsg{},
@@ -516,6 +542,146 @@ func TestTagEditor_AddTagFromCloud(t *testing.T) {
 	assert.Equal(t, 1, te.TagCursor) // Cursor moves to new tag
 }
 
+func TestTagEditor_TagRename(t *testing.T) {
+	newEditor := func() *TagEditor {
+		te := NewTagEditor()
+		te.Active = true
+		te.AvailableTags = []string{"api", "backend"}
+		te.TagCloudActive = true
+		return te
+	}
+	runes := func(s string) tea.KeyMsg {
+		return tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(s)}
+	}
+
+	t.Run("R starts rename in the cloud", func(t *testing.T) {
+		te := newEditor()
+		te.HandleInput(runes("R"))
+
+		assert.Equal(t, TagEditorModeRenaming, te.Mode)
+		assert.Equal(t, "api", te.RenamingTag)
+		assert.Equal(t, "api", te.RenameInput)
+	})
+
+	t.Run("R is typed in the main pane", func(t *testing.T) {
+		te := newEditor()
+		te.TagCloudActive = false
+		te.HandleInput(runes("R"))
+
+		assert.Equal(t, TagEditorModeNormal, te.Mode)
+		assert.Equal(t, "R", te.TagInput)
+	})
+
+	t.Run("Typing edits the new name and esc cancels", func(t *testing.T) {
+		te := newEditor()
+		te.HandleInput(runes("R"))
+		te.HandleInput(runes("-v2"))
+		assert.Equal(t, "api-v2", te.RenameInput)
+
+		te.HandleInput(tea.KeyMsg{Type: tea.KeyBackspace})
+		assert.Equal(t, "api-v", te.RenameInput)
+
+		te.HandleInput(tea.KeyMsg{Type: tea.KeyEsc})
+		assert.Equal(t, TagEditorModeNormal, te.Mode)
+		assert.Empty(t, te.RenamingTag)
+		assert.True(t, te.Active)
+	})
+
+	t.Run("Unchanged name does nothing", func(t *testing.T) {
+		te := newEditor()
+		te.HandleInput(runes("R"))
+		_, cmd := te.HandleInput(tea.KeyMsg{Type: tea.KeyEnter})
+
+		assert.Nil(t, cmd)
+		assert.Equal(t, TagEditorModeNormal, te.Mode)
+		assert.False(t, te.TagDeletionState.Active)
+	})
+
+	t.Run("New name starts rename", func(t *testing.T) {
+		te := newEditor()
+		te.HandleInput(runes("R"))
+		te.RenameInput = "service"
+		_, cmd := te.HandleInput(tea.KeyMsg{Type: tea.KeyEnter})
+
+		assert.NotNil(t, cmd)
+		assert.True(t, te.TagDeletionState.Active)
+		assert.False(t, te.TagDeleteConfirm.Active())
+	})
+
+	t.Run("Existing name asks to merge", func(t *testing.T) {
+		te := newEditor()
+		te.HandleInput(runes("R"))
+		te.RenameInput = "Backend"
+		te.HandleInput(tea.KeyMsg{Type: tea.KeyEnter})
+
+		assert.True(t, te.TagDeleteConfirm.Active())
+		assert.False(t, te.TagDeletionState.Active)
+	})
+}
+
+func TestBuildTagCloudTree(t *testing.T) {
+	available := []string{"api", "project/backend/db", "project", "project/frontend", "team/a", "team/b"}
+
+	names := func(nodes []tagCloudNode) []string {
+		var result []string
+		for _, node := range nodes {
+			result = append(result, strings.Repeat(" ", node.Depth)+node.Name)
+		}
+		return result
+	}
+
+	nodes := buildTagCloudTree(available, nil)
+	// project/backend/db nests directly under project since project/backend isn't available
+	assert.Equal(t, []string{"api", "project", " project/backend/db", " project/frontend", "team/a", "team/b"}, names(nodes))
+	assert.Equal(t, 2, nodes[1].Descendants)
+
+	nodes = buildTagCloudTree(available, map[string]bool{"project": true})
+	assert.Equal(t, []string{"api", "project", "team/a", "team/b"}, names(nodes))
+	assert.True(t, nodes[1].Collapsed)
+}
+
+func TestTagEditor_TagCloudTree(t *testing.T) {
+	te := NewTagEditor()
+	te.Active = true
+	te.AvailableTags = []string{"project", "project/frontend", "project/backend", "zeta"}
+	te.TagCloudActive = true
+
+	assert.Equal(t, []string{"project", "project/frontend", "project/backend", "zeta"}, te.GetAvailableTagsForCloud())
+
+	te.HandleInput(tea.KeyMsg{Type: tea.KeyDown})
+	assert.Equal(t, 1, te.TagCloudCursor)
+	te.HandleInput(tea.KeyMsg{Type: tea.KeyUp})
+	assert.Equal(t, 0, te.TagCloudCursor)
+
+	// Space collapses the parent and hides its children from navigation
+	te.HandleInput(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	assert.Equal(t, []string{"project", "zeta"}, te.GetAvailableTagsForCloud())
+	te.HandleInput(tea.KeyMsg{Type: tea.KeyDown})
+	te.AddTagFromCloud()
+	assert.Equal(t, []string{"zeta"}, te.CurrentTags)
+
+	// Leaf tags can't be collapsed
+	te.HandleInput(tea.KeyMsg{Type: tea.KeyUp})
+	te.HandleInput(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	assert.Equal(t, []string{"project", "project/frontend", "project/backend"}, te.GetAvailableTagsForCloud())
+	te.HandleInput(tea.KeyMsg{Type: tea.KeyDown})
+	te.HandleInput(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+	assert.Empty(t, te.CollapsedTags)
+}
+
+func TestFormatRenameResult(t *testing.T) {
+	result := &files.TagRewriteResult{
+		Components: []string{"components/prompts/a.md", "components/rules/b.md"},
+		Pipelines:  []string{"pipelines/p.yaml"},
+	}
+
+	assert.Equal(t, "✓ Tag 'old' renamed to 'new' in 3 files",
+		formatRenameResult(tagRenameCompleteMsg{OldName: "old", NewName: "new", Result: result}))
+	assert.Equal(t, "✓ Tag 'old' merged into 'new' in 3 files",
+		formatRenameResult(tagRenameCompleteMsg{OldName: "old", NewName: "new", Merged: true, Result: result}))
+	assert.Equal(t, "× Tag 'old' not renamed to 'new': tag 'old' not found",
+		formatRenameResult(tagRenameCompleteMsg{OldName: "old", NewName: "new", Err: errors.New("tag 'old' not found")}))
+}
 
 func TestFormatDeletionResult(t *testing.T) {
 	tests := []struct {
@@ -0,0 +1,97 @@
+package tui
+
+import (
+	"github.com/pluqqy/pluqqy-terminal/pkg/models"
+)
+
+// tagCloudNode is a tag as shown in the tag cloud tree
+type tagCloudNode struct {
+	Name        string
+	Depth       int
+	Descendants int  // Number of tags nested below this one
+	Collapsed   bool // Whether the descendants are hidden
+}
+
+// buildTagCloudTree arranges tags into a tree by their slash-separated names and
+// returns the visible nodes in display order. A tag is nested under its nearest
+// ancestor present in tagNames, so project/api/v2 sits directly under project
+// when project/api isn't available. Sibling order follows tagNames.
+func buildTagCloudTree(tagNames []string, collapsed map[string]bool) []tagCloudNode {
+	present := make(map[string]bool, len(tagNames))
+	for _, name := range tagNames {
+		present[models.NormalizeTagName(name)] = true
+	}
+
+	var roots []string
+	children := make(map[string][]string)
+	for _, name := range tagNames {
+		parent := ""
+		for ancestor := models.GetTagParent(models.NormalizeTagName(name)); ancestor != ""; ancestor = models.GetTagParent(ancestor) {
+			if present[ancestor] {
+				parent = ancestor
+				break
+			}
+		}
+		if parent == "" {
+			roots = append(roots, name)
+		} else {
+			children[parent] = append(children[parent], name)
+		}
+	}
+
+	var countDescendants func(name string) int
+	countDescendants = func(name string) int {
+		count := 0
+		for _, child := range children[models.NormalizeTagName(name)] {
+			count += 1 + countDescendants(child)
+		}
+		return count
+	}
+
+	var nodes []tagCloudNode
+	var visit func(name string, depth int)
+	visit = func(name string, depth int) {
+		normalized := models.NormalizeTagName(name)
+		node := tagCloudNode{
+			Name:        name,
+			Depth:       depth,
+			Descendants: countDescendants(name),
+			Collapsed:   collapsed[normalized],
+		}
+		nodes = append(nodes, node)
+		if node.Collapsed {
+			return
+		}
+		for _, child := range children[normalized] {
+			visit(child, depth+1)
+		}
+	}
+	for _, root := range roots {
+		visit(root, 0)
+	}
+
+	return nodes
+}
+
+// TagCloudNodes returns the visible tags of the tag cloud as a tree
+func (te *TagEditor) TagCloudNodes() []tagCloudNode {
+	return buildTagCloudTree(te.TagEditorDataStore.GetAvailableTagsForCloud(), te.CollapsedTags)
+}
+
+// ToggleTagCollapse expands or collapses the tag under the cloud cursor
+func (te *TagEditor) ToggleTagCollapse() {
+	nodes := te.TagCloudNodes()
+	if te.TagCloudCursor >= len(nodes) || nodes[te.TagCloudCursor].Descendants == 0 {
+		return
+	}
+
+	name := models.NormalizeTagName(nodes[te.TagCloudCursor].Name)
+	if te.CollapsedTags == nil {
+		te.CollapsedTags = make(map[string]bool)
+	}
+	if te.CollapsedTags[name] {
+		delete(te.CollapsedTags, name)
+	} else {
+		te.CollapsedTags[name] = true
+	}
+}
@@ -6,6 +6,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/bubbles/spinner"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/pluqqy/pluqqy-terminal/pkg/files"
 )
 
 // TagEditorConfig contains configuration for the tag editor
@@ -22,6 +23,7 @@ const (
 	TagEditorModeCloud
 	TagEditorModeDeleting
 	TagEditorModeReloading
+	TagEditorModeRenaming
 )
 
 // TagEditResult represents the result of a tag editing session
@@ -72,6 +74,15 @@ type tagDeletionCompleteMsg struct {
 	Result TagDeletionResult
 }
 
+// tagRenameCompleteMsg is sent when a tag rename or merge is complete
+type tagRenameCompleteMsg struct {
+	OldName string
+	NewName string
+	Merged  bool
+	Result  *files.TagRewriteResult
+	Err     error
+}
+
 // TagDeletionState manages the spinner and progress for tag deletion
 type TagDeletionState struct {
 	Active       bool
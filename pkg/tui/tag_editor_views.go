@@ -146,9 +146,7 @@ func (ter *TagEditorRenderer) renderMainPane(width, height int) string {
 			registry, _ := tags.NewRegistry()
 			color := models.GetTagColor(tag, "")
 			if registry != nil {
-				if t, exists := registry.GetTag(tag); exists && t.Color != "" {
-					color = t.Color
-				}
+				color = registry.TagColor(tag)
 			}
 			
 			tagStyle := lipgloss.NewStyle().
@@ -283,27 +281,30 @@ func (ter *TagEditorRenderer) renderCloudPane(width, height int) string {
 	content.WriteString(headerPadding.Render(headerStyle.Render(heading) + " " + colonStyle.Render(strings.Repeat(":", remainingWidth))))
 	content.WriteString("\n\n")
 	
-	// Get available tags that haven't been added yet
-	availableForCloud := ter.Editor.GetAvailableTagsForCloud()
+	// Get available tags that haven't been added yet, arranged as a tree
+	cloudNodes := ter.Editor.TagCloudNodes()
 	
-	if len(availableForCloud) == 0 {
+	if len(cloudNodes) == 0 {
 		dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorVeryDim))
 		content.WriteString(headerPadding.Render(dimStyle.Render("  (no available tags)")))
 	} else {
-		// Group tags in rows for better display
+		// Top-level tags without children flow in rows; parent tags start their
+		// own line with their children indented below them
+		registry, _ := tags.NewRegistry()
+		dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorDim))
+		
 		var tagRows strings.Builder
 		rowTags := 0
 		currentRowWidth := 0
 		maxRowWidth := width - 6
 		
-		for i, tag := range availableForCloud {
-			// Get tag color
-			registry, _ := tags.NewRegistry()
+		for i, node := range cloudNodes {
+			tag := node.Name
+			
+			// Get tag color, inherited from the parent tag when it has none
 			color := models.GetTagColor(tag, "")
 			if registry != nil {
-				if t, exists := registry.GetTag(tag); exists && t.Color != "" {
-					color = t.Color
-				}
+				color = registry.TagColor(tag)
 			}
 			
 			tagStyle := lipgloss.NewStyle().
@@ -322,28 +323,77 @@ func (ter *TagEditorRenderer) renderCloudPane(width, height int) string {
 				tagDisplay = "  " + tagStyle.Render(tag) + "  "
 			}
 			
-			tagWidth := lipgloss.Width(tagDisplay) + 2
+			if node.Depth == 0 && node.Descendants == 0 {
+				tagWidth := lipgloss.Width(tagDisplay) + 2
+				
+				// Check if we need to start a new row
+				if rowTags > 0 && currentRowWidth+tagWidth+2 > maxRowWidth {
+					tagRows.WriteString("\n\n")
+					rowTags = 0
+					currentRowWidth = 0
+				}
+				
+				// Add the tag
+				if rowTags > 0 {
+					tagRows.WriteString("  ")
+					currentRowWidth += 2
+				}
+				tagRows.WriteString(tagDisplay)
+				currentRowWidth += tagWidth
+				rowTags++
+				continue
+			}
 			
-			// Check if we need to start a new row
-			if rowTags > 0 && currentRowWidth+tagWidth+2 > maxRowWidth {
-				tagRows.WriteString("\n\n")
-				rowTags = 0
-				currentRowWidth = 0
+			// Tree nodes get a line of their own
+			if i > 0 {
+				if node.Depth == 0 {
+					tagRows.WriteString("\n\n")
+				} else {
+					tagRows.WriteString("\n")
+				}
 			}
+			rowTags = 0
+			currentRowWidth = 0
 			
-			// Add the tag
-			if rowTags > 0 {
-				tagRows.WriteString("  ")
-				currentRowWidth += 2
+			marker := "  "
+			if node.Descendants > 0 {
+				marker = "▾ "
+				if node.Collapsed {
+					marker = "▸ "
+				}
+			}
+			tagRows.WriteString(strings.Repeat("    ", node.Depth) + dimStyle.Render(marker) + tagDisplay)
+			if node.Collapsed {
+				tagRows.WriteString(dimStyle.Render(fmt.Sprintf("+%d", node.Descendants)))
+			}
+			
+			// Following top-level leaves start a fresh row
+			if i+1 < len(cloudNodes) && cloudNodes[i+1].Depth == 0 && cloudNodes[i+1].Descendants == 0 {
+				tagRows.WriteString("\n\n")
 			}
-			tagRows.WriteString(tagDisplay)
-			currentRowWidth += tagWidth
-			rowTags++
 		}
 		
 		content.WriteString(headerPadding.Render(tagRows.String()))
 	}
 	
+	// Rename prompt
+	if ter.Editor.Mode == TagEditorModeRenaming {
+		content.WriteString("\n\n")
+		content.WriteString(headerPadding.Render(fmt.Sprintf("Rename '%s' to:", ter.Editor.RenamingTag)))
+		content.WriteString("\n")
+		
+		inputStyle := lipgloss.NewStyle().
+			Border(lipgloss.RoundedBorder()).
+			BorderForeground(lipgloss.Color(ColorActive)).
+			Padding(0, 1).
+			Width(width - 4)
+		content.WriteString(headerPadding.Render(inputStyle.Render(ter.Editor.RenameInput)))
+		content.WriteString("\n")
+		
+		dimStyle := lipgloss.NewStyle().Foreground(lipgloss.Color(ColorVeryDim))
+		content.WriteString(headerPadding.Render(dimStyle.Render("an existing tag name merges the two")))
+	}
+	
 	// Border style
 	borderStyle := lipgloss.NewStyle().
 		Border(lipgloss.RoundedBorder()).
@@ -363,11 +413,18 @@ func (ter *TagEditorRenderer) renderCloudPane(width, height int) string {
 func (ter *TagEditorRenderer) renderHelp() string {
 	var help []string
 	
-	if ter.Editor.TagCloudActive {
+	if ter.Editor.Mode == TagEditorModeRenaming {
+		help = []string{
+			"enter rename",
+			"esc cancel",
+		}
+	} else if ter.Editor.TagCloudActive {
 		help = []string{
 			"tab switch pane",
 			"enter add tag",
-			"←→ navigate",
+			"←↑↓→ navigate",
+			"space expand/collapse",
+			"R rename/merge tag",
 			"^d delete tag",
 			"^s save",
 			"esc cancel",
@@ -5,7 +5,6 @@ import (
 	"strings"
 
 	"github.com/charmbracelet/lipgloss"
-	"github.com/pluqqy/pluqqy-terminal/pkg/models"
 	"github.com/pluqqy/pluqqy-terminal/pkg/tags"
 )
 
@@ -30,11 +29,7 @@ func renderTags(tagNames []string, maxWidth int) string {
 
 	var tagStyles []tagStyle
 	for _, tagName := range tagNames {
-		tag, exists := registry.GetTag(tagName)
-		color := models.GetTagColor(tagName, "")
-		if exists && tag.Color != "" {
-			color = tag.Color
-		}
+		color := registry.TagColor(tagName)
 
 		// Create tag style with background color
 		style := lipgloss.NewStyle().
@@ -110,11 +105,7 @@ func renderTagChips(tagNames []string, maxTags int) string {
 
 	var chips []string
 	for _, tagName := range tagsToShow {
-		tag, exists := registry.GetTag(tagName)
-		color := models.GetTagColor(tagName, "")
-		if exists && tag.Color != "" {
-			color = tag.Color
-		}
+		color := registry.TagColor(tagName)
 
 		// Create compact chip style
 		chipStyle := lipgloss.NewStyle().
@@ -173,11 +164,7 @@ func renderTagChipsWithWidth(tagNames []string, maxWidth int, maxTags int) strin
 			break
 		}
 
-		tag, exists := registry.GetTag(tagName)
-		color := models.GetTagColor(tagName, "")
-		if exists && tag.Color != "" {
-			color = tag.Color
-		}
+		color := registry.TagColor(tagName)
 
 		// Create compact chip style
 		chipStyle := lipgloss.NewStyle().
@@ -86,21 +86,15 @@ func (tr *TagReloader) performReload() tea.Msg {
 	}
 
 	// Scan all component types
-	componentTypes := []string{
-		models.ComponentTypePrompt,
-		models.ComponentTypeContext,
-		models.ComponentTypeRules,
-	}
-
-	for _, compType := range componentTypes {
-		components, err := files.ListComponents(compType)
+	for _, compType := range files.ComponentTypes() {
+		components, err := files.ListComponents(compType.Name)
 		if err != nil {
 			// Log error but continue with other types
 			continue
 		}
 
 		for _, compFile := range components {
-			compPath := filepath.Join(files.ComponentsDir, compType, compFile)
+			compPath := filepath.Join(files.ComponentsDir, compType.DirName(), compFile)
 			comp, err := files.ReadComponent(compPath)
 			if err != nil {
 				result.FailedFiles = append(result.FailedFiles, compPath)
@@ -0,0 +1,145 @@
+package tui
+
+import (
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// VariablePromptState collects values for template variables before a pipeline is set
+type VariablePromptState struct {
+	Active       bool              // Whether the prompt is showing
+	PipelinePath string            // Pipeline that will be set once all values are entered
+	PipelineName string            // Display name of the pipeline
+	Names        []string          // Variables that have no value yet
+	Values       map[string]string // Values entered so far
+	Current      int               // Index into Names of the variable being entered
+	Input        string            // User input for the current variable
+	CursorPos    int               // Cursor position in the input
+}
+
+// NewVariablePromptState creates a new variable prompt state instance
+func NewVariablePromptState() *VariablePromptState {
+	return &VariablePromptState{}
+}
+
+// Start shows the prompt for the given unresolved variables
+func (vs *VariablePromptState) Start(pipelinePath, pipelineName string, names []string) {
+	vs.Active = true
+	vs.PipelinePath = pipelinePath
+	vs.PipelineName = pipelineName
+	vs.Names = names
+	vs.Values = make(map[string]string)
+	vs.Current = 0
+	vs.Input = ""
+	vs.CursorPos = 0
+}
+
+// Reset clears the prompt state
+func (vs *VariablePromptState) Reset() {
+	vs.Active = false
+	vs.PipelinePath = ""
+	vs.PipelineName = ""
+	vs.Names = nil
+	vs.Values = nil
+	vs.Current = 0
+	vs.Input = ""
+	vs.CursorPos = 0
+}
+
+// CurrentName returns the name of the variable being entered
+func (vs *VariablePromptState) CurrentName() string {
+	if vs.Current < 0 || vs.Current >= len(vs.Names) {
+		return ""
+	}
+	return vs.Names[vs.Current]
+}
+
+// HandleInput processes keyboard input for the variable prompt
+func (vs *VariablePromptState) HandleInput(msg tea.KeyMsg) (handled bool, cmd tea.Cmd) {
+	if !vs.Active {
+		return false, nil
+	}
+
+	switch msg.String() {
+	case "esc":
+		vs.Reset()
+		return true, func() tea.Msg {
+			return StatusMsg("Set pipeline cancelled")
+		}
+
+	case "enter":
+		vs.Values[vs.CurrentName()] = vs.Input
+		vs.Current++
+		vs.Input = ""
+		vs.CursorPos = 0
+
+		if vs.Current < len(vs.Names) {
+			return true, nil
+		}
+
+		// All values collected - hand them off and close the prompt
+		collected := VariablesCollectedMsg{
+			PipelinePath: vs.PipelinePath,
+			Values:       vs.Values,
+		}
+		vs.Reset()
+		return true, func() tea.Msg {
+			return collected
+		}
+
+	case "backspace":
+		if vs.CursorPos > 0 {
+			runes := []rune(vs.Input)
+			vs.Input = string(runes[:vs.CursorPos-1]) + string(runes[vs.CursorPos:])
+			vs.CursorPos--
+		}
+		return true, nil
+
+	case "left", "ctrl+b":
+		if vs.CursorPos > 0 {
+			vs.CursorPos--
+		}
+		return true, nil
+
+	case "right", "ctrl+f":
+		if vs.CursorPos < len([]rune(vs.Input)) {
+			vs.CursorPos++
+		}
+		return true, nil
+
+	case "home", "ctrl+a":
+		vs.CursorPos = 0
+		return true, nil
+
+	case "end", "ctrl+e":
+		vs.CursorPos = len([]rune(vs.Input))
+		return true, nil
+
+	case " ":
+		runes := []rune(vs.Input)
+		vs.Input = string(runes[:vs.CursorPos]) + " " + string(runes[vs.CursorPos:])
+		vs.CursorPos++
+		return true, nil
+
+	case "tab":
+		// Ignore tab while entering values
+		return true, nil
+
+	default:
+		if msg.Type == tea.KeyRunes {
+			runes := []rune(vs.Input)
+			newRunes := string(msg.Runes)
+			vs.Input = string(runes[:vs.CursorPos]) + newRunes + string(runes[vs.CursorPos:])
+			vs.CursorPos += len([]rune(newRunes))
+			return true, nil
+		}
+	}
+
+	// Swallow everything else while the prompt is open
+	return true, nil
+}
+
+// VariablesCollectedMsg is sent when the user has entered every missing variable
+type VariablesCollectedMsg struct {
+	PipelinePath string
+	Values       map[string]string
+}
@@ -0,0 +1,69 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestVariablePromptState_CollectsAllValues(t *testing.T) {
+	state := NewVariablePromptState()
+	state.Start("my-pipeline.yaml", "My Pipeline", []string{"project_name", "ticket"})
+
+	typeText := func(text string) {
+		for _, r := range text {
+			state.HandleInput(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	if state.CurrentName() != "project_name" {
+		t.Fatalf("CurrentName() = %q, want project_name", state.CurrentName())
+	}
+
+	typeText("pluqqx")
+	state.HandleInput(tea.KeyMsg{Type: tea.KeyBackspace})
+	typeText("y")
+	handled, cmd := state.HandleInput(tea.KeyMsg{Type: tea.KeyEnter})
+	if !handled || cmd != nil {
+		t.Fatal("first enter should advance without a command")
+	}
+	if state.CurrentName() != "ticket" {
+		t.Fatalf("CurrentName() = %q, want ticket", state.CurrentName())
+	}
+
+	typeText("PLQ-42")
+	_, cmd = state.HandleInput(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("final enter should return a command")
+	}
+	if state.Active {
+		t.Error("prompt should close after the last value")
+	}
+
+	msg, ok := cmd().(VariablesCollectedMsg)
+	if !ok {
+		t.Fatalf("expected VariablesCollectedMsg, got %T", cmd())
+	}
+	if msg.PipelinePath != "my-pipeline.yaml" {
+		t.Errorf("PipelinePath = %q", msg.PipelinePath)
+	}
+	if msg.Values["project_name"] != "pluqqy" || msg.Values["ticket"] != "PLQ-42" {
+		t.Errorf("Values = %v", msg.Values)
+	}
+}
+
+func TestVariablePromptState_EscCancels(t *testing.T) {
+	state := NewVariablePromptState()
+	state.Start("p.yaml", "P", []string{"ticket"})
+
+	handled, cmd := state.HandleInput(tea.KeyMsg{Type: tea.KeyEsc})
+	if !handled || cmd == nil {
+		t.Fatal("esc should be handled and report cancellation")
+	}
+	if state.Active {
+		t.Error("prompt should be inactive after esc")
+	}
+	if _, ok := cmd().(StatusMsg); !ok {
+		t.Errorf("expected StatusMsg, got %T", cmd())
+	}
+}
@@ -0,0 +1,113 @@
+package tui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// VariablePromptRenderer handles the visual rendering of the variable prompt dialog
+type VariablePromptRenderer struct {
+	Width  int
+	Height int
+}
+
+// NewVariablePromptRenderer creates a new variable prompt renderer
+func NewVariablePromptRenderer() *VariablePromptRenderer {
+	return &VariablePromptRenderer{}
+}
+
+// SetSize updates the renderer dimensions
+func (vr *VariablePromptRenderer) SetSize(width, height int) {
+	vr.Width = width
+	vr.Height = height
+}
+
+// Render creates the visual representation of the variable prompt dialog
+func (vr *VariablePromptRenderer) Render(state *VariablePromptState) string {
+	if !state.Active {
+		return ""
+	}
+
+	dialogWidth := vr.Width / 2
+	if dialogWidth < 50 {
+		dialogWidth = 50
+	}
+	if dialogWidth > 80 {
+		dialogWidth = 80
+	}
+
+	var b strings.Builder
+
+	b.WriteString(TypeHeaderStyle.Render("SET PIPELINE VARIABLES"))
+	b.WriteString("\n\n")
+
+	b.WriteString(HeaderStyle.Render("Pipeline:"))
+	b.WriteString(" ")
+	b.WriteString(state.PipelineName)
+	b.WriteString("\n")
+	b.WriteString(DescriptionStyle.Render(fmt.Sprintf("Variable %d of %d has no value", state.Current+1, len(state.Names))))
+	b.WriteString("\n\n")
+
+	// Values already entered
+	for i := 0; i < state.Current && i < len(state.Names); i++ {
+		name := state.Names[i]
+		b.WriteString(DescriptionStyle.Render(fmt.Sprintf("  {{%s}} = %s", name, state.Values[name])))
+		b.WriteString("\n")
+	}
+	if state.Current > 0 {
+		b.WriteString("\n")
+	}
+
+	inputFieldWidth := dialogWidth - 8
+	if inputFieldWidth < 40 {
+		inputFieldWidth = 40
+	}
+	inputRenderer := NewInputRenderer(inputFieldWidth)
+	b.WriteString(inputRenderer.RenderInputFieldWithLabel(
+		fmt.Sprintf("{{%s}}:", state.CurrentName()),
+		state.Input,
+		state.CursorPos,
+		"Enter value...",
+		true, // show cursor
+		true, // cursor always visible (no blinking)
+	))
+	b.WriteString("\n\n")
+
+	enterKeyStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorSuccess)).
+		Foreground(lipgloss.Color(ColorWhite)).
+		Bold(true)
+
+	escKeyStyle := lipgloss.NewStyle().
+		Background(lipgloss.Color(ColorDanger)).
+		Foreground(lipgloss.Color(ColorWhite)).
+		Bold(true)
+
+	action := "Next"
+	if state.Current == len(state.Names)-1 {
+		action = "Set"
+	}
+	b.WriteString("[" + enterKeyStyle.Render("Enter") + "] " + action + "  ")
+	b.WriteString("[" + escKeyStyle.Render("Esc") + "] Cancel")
+
+	dialogStyle := ActiveBorderStyle.
+		Width(dialogWidth).
+		Padding(1, 2)
+
+	centeredStyle := lipgloss.NewStyle().
+		Width(vr.Width).
+		Height(vr.Height).
+		Align(lipgloss.Center, lipgloss.Center)
+
+	return centeredStyle.Render(dialogStyle.Render(b.String()))
+}
+
+// RenderOverlay creates an overlay view for the variable prompt dialog
+func (vr *VariablePromptRenderer) RenderOverlay(baseView string, state *VariablePromptState) string {
+	if !state.Active {
+		return baseView
+	}
+	return overlayViews(baseView, vr.Render(state))
+}
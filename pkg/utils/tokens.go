@@ -78,13 +78,26 @@ func GetTokenLimitStatus(tokens int) (percentage int, limit int, status string)
 	percentage = (tokens * 100) / selectedLimit
 	
 	// Determine status based on percentage of selected limit
+	return percentage, selectedLimit, tokenStatus(percentage)
+}
+
+// GetTokenBudgetStatus returns the status of a token count against an explicit budget
+func GetTokenBudgetStatus(tokens int, budget int) (percentage int, status string) {
+	if budget <= 0 {
+		percentage, _, status = GetTokenLimitStatus(tokens)
+		return percentage, status
+	}
+	
+	percentage = (tokens * 100) / budget
+	return percentage, tokenStatus(percentage)
+}
+
+// tokenStatus maps a percentage of a limit to good, warning or danger
+func tokenStatus(percentage int) string {
 	if percentage < 50 {
-		status = "good"
+		return "good"
 	} else if percentage < 80 {
-		status = "warning"
-	} else {
-		status = "danger"
+		return "warning"
 	}
-	
-	return percentage, selectedLimit, status
+	return "danger"
 }
\ No newline at end of file
@@ -128,6 +128,28 @@ func TestGetTokenLimitStatus(t *testing.T) {
 	}
 }
 
+func TestGetTokenBudgetStatus(t *testing.T) {
+	tests := []struct {
+		tokens             int
+		budget             int
+		expectedPercentage int
+		expectedStatus     string
+	}{
+		{1000, 4000, 25, "good"},
+		{2400, 4000, 60, "warning"},
+		{5000, 4000, 125, "danger"},
+		{1000, 0, 24, "good"}, // No budget falls back to common limits
+	}
+
+	for _, tt := range tests {
+		percentage, status := GetTokenBudgetStatus(tt.tokens, tt.budget)
+		if percentage != tt.expectedPercentage || status != tt.expectedStatus {
+			t.Errorf("GetTokenBudgetStatus(%d, %d) = %d, %s, expected %d, %s",
+				tt.tokens, tt.budget, percentage, status, tt.expectedPercentage, tt.expectedStatus)
+		}
+	}
+}
+
 func abs(n int) int {
 	if n < 0 {
 		return -n